@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/reporting"
+)
+
+func createEvent(ctx context.Context, app *app, args []string) error {
+	params := booking.EventCreateParams{
+		Tiers: make(map[string]booking.CreateTierParams),
+	}
+
+	fs := newFlagSet(app, "events create", "NAME")
+	fs.Func("currency", "Event settlement currency (default USD)", func(v string) error {
+		params.Currency = booking.Currency(strings.ToUpper(v))
+		return nil
+	})
+	fs.UintVar(&params.TaxRateBps, "tax-rate-bps", 0, "Sales tax rate in basis points included in prices")
+	fs.Func("organizer", "Organizer ID", func(v string) error {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return fmt.Errorf("invalid organizer ID: %w", err)
+		}
+
+		params.OrganizerID = &id
+		return nil
+	})
+	fs.BoolVar(&params.TransfersBlocked, "transfers-blocked", false, "Forbid ticket transfers between users")
+	fs.Func("tier", "Ticket tier as NAME:PRICE_CENTS:TICKETS_COUNT. Can be repeated", func(v string) error {
+		name, tier, err := parseTier(v)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := params.Tiers[name]; ok {
+			return fmt.Errorf("duplicate tier %q", name)
+		}
+
+		params.Tiers[name] = tier
+		return nil
+	})
+	if err := app.parseArgs(ctx, fs, args, 1); err != nil {
+		return err
+	}
+
+	params.EventName = fs.Arg(0)
+	result, err := app.svc.CreateEvent(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	return app.out.print(result, func(t *table) {
+		t.row("EVENT", result.EventID)
+		t.section("Tiers")
+		t.row("NAME", "TIER ID")
+		for _, name := range sortedKeys(result.Tiers) {
+			t.row(name, result.Tiers[name])
+		}
+	})
+}
+
+// parseTier parses tier flag value in NAME:PRICE_CENTS:TICKETS_COUNT format.
+//
+// Tier name may contain colons.
+func parseTier(v string) (string, booking.CreateTierParams, error) {
+	var tier booking.CreateTierParams
+	parts := strings.Split(v, ":")
+	if len(parts) < 3 {
+		return "", tier, fmt.Errorf("invalid tier %q, expected NAME:PRICE_CENTS:TICKETS_COUNT", v)
+	}
+
+	n := len(parts)
+	name := strings.TrimSpace(strings.Join(parts[:n-2], ":"))
+	if name == "" {
+		return "", tier, fmt.Errorf("invalid tier %q: name is required", v)
+	}
+
+	price, err := strconv.Atoi(parts[n-2])
+	if err != nil || price < 0 {
+		return "", tier, fmt.Errorf("invalid tier %q: invalid price %q", v, parts[n-2])
+	}
+
+	count, err := strconv.Atoi(parts[n-1])
+	if err != nil || count <= 0 {
+		return "", tier, fmt.Errorf("invalid tier %q: invalid tickets count %q", v, parts[n-1])
+	}
+
+	tier.PriceCents = price
+	tier.TicketsCount = count
+	return name, tier, nil
+}
+
+func listEvents(ctx context.Context, app *app, args []string) error {
+	fs := newFlagSet(app, "events list", "")
+	if err := app.parseArgs(ctx, fs, args, 0); err != nil {
+		return err
+	}
+
+	events, err := app.svc.GetEvents(ctx)
+	if err != nil {
+		return err
+	}
+
+	if events == nil {
+		events = []*booking.Event{}
+	}
+
+	return app.out.print(events, func(t *table) {
+		t.row("ID", "NAME", "CURRENCY", "TAX BPS", "ORGANIZER", "TRANSFERS", "CANCELLED AT")
+		for _, e := range events {
+			transfers := "allowed"
+			if e.TransfersBlocked {
+				transfers = "blocked"
+			}
+
+			t.row(e.ID, e.Name, e.Currency, e.TaxRateBps, e.OrganizerID, transfers, e.CancelledAt)
+		}
+	})
+}
+
+func inspectEvent(ctx context.Context, app *app, args []string) error {
+	report, err := getEventReport(ctx, app, "events inspect", args)
+	if err != nil {
+		return err
+	}
+
+	return app.out.print(report, func(t *table) {
+		t.row("EVENT", report.EventID)
+		t.row("NAME", report.Name)
+		t.row("CURRENCY", report.Currency)
+		t.row("CANCELLED AT", report.CancelledAt)
+
+		t.section("Tiers")
+		writeTiers(t, report)
+
+		r := report.Revenue
+		t.section("Revenue")
+		t.row("GROSS", "REFUNDS", "FEES", "NET", "TAX")
+		t.row(r.Gross, r.Refunds, r.Fees, r.Net, r.Tax)
+	})
+}
+
+func listTiers(ctx context.Context, app *app, args []string) error {
+	report, err := getEventReport(ctx, app, "events tiers", args)
+	if err != nil {
+		return err
+	}
+
+	return app.out.print(report.Tiers, func(t *table) {
+		writeTiers(t, report)
+	})
+}
+
+// getEventReport parses event ID argument and returns event report with tier inventory.
+func getEventReport(ctx context.Context, app *app, name string, args []string) (*reporting.EventReport, error) {
+	fs := newFlagSet(app, name, "EVENT_ID")
+	if err := app.parseArgs(ctx, fs, args, 1); err != nil {
+		return nil, err
+	}
+
+	eventID, err := uuid.Parse(fs.Arg(0))
+	if err != nil {
+		return nil, fmt.Errorf("invalid event ID: %w", err)
+	}
+
+	return app.reports.GetEventReport(ctx, reporting.EventReportParams{EventID: eventID})
+}
+
+func writeTiers(t *table, report *reporting.EventReport) {
+	t.row("TIER ID", "NAME", "PRICE", "CAPACITY", "SOLD", "HELD", "AVAILABLE", "SALES")
+	for _, tier := range report.Tiers {
+		t.row(
+			tier.TierID, tier.Name, cents(int64(tier.PriceCents), report.Currency),
+			tier.Capacity, tier.Sold, tier.Held, tier.Available, cents(tier.SalesCents, report.Currency),
+		)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTier(t *testing.T) {
+	name, tier, err := parseTier("VIP:15000:50")
+	require.NoError(t, err)
+	require.Equal(t, "VIP", name)
+	require.Equal(t, 15000, tier.PriceCents)
+	require.Equal(t, 50, tier.TicketsCount)
+
+	name, _, err = parseTier("Zone A: Front:1000:1")
+	require.NoError(t, err)
+	require.Equal(t, "Zone A: Front", name)
+
+	for _, v := range []string{"VIP", "VIP:100", ":100:1", "VIP:-1:1", "VIP:100:0", "VIP:abc:1"} {
+		_, _, err := parseTier(v)
+		require.Error(t, err, v)
+	}
+}
@@ -0,0 +1,168 @@
+// Command bookingctl is an admin CLI for operating the booking system.
+//
+// It connects to the database directly using the same environment config as the server.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/config"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/reporting"
+)
+
+// command is a CLI subcommand.
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, app *app, args []string) error
+}
+
+var commands = []command{
+	{name: "events create", usage: "Create an event with ticket tiers", run: createEvent},
+	{name: "events list", usage: "List events", run: listEvents},
+	{name: "events inspect", usage: "Show event with tier inventory and revenue", run: inspectEvent},
+	{name: "events tiers", usage: "Show tier inventory of an event", run: listTiers},
+	{name: "reservations get", usage: "Show reservation with its tickets and payments", run: getReservation},
+	{name: "reservations expire", usage: "Force-expire a pending reservation", run: expireReservation},
+	{name: "reservations cancel", usage: "Cancel a pending reservation", run: cancelReservation},
+	{name: "reservations refund", usage: "Refund a paid reservation", run: refundReservation},
+}
+
+// app is a shared state of commands.
+type app struct {
+	db      *pgxpool.Pool
+	out     printer
+	svc     *booking.Service
+	reports *reporting.Service
+}
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func run() error {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() < 2 {
+		flag.Usage()
+		return errors.New("missing command")
+	}
+
+	name := flag.Arg(0) + " " + flag.Arg(1)
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+
+		ctx, cancelFn := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancelFn()
+
+		return runCommand(ctx, cmd, flag.Args()[2:])
+	}
+
+	flag.Usage()
+	return fmt.Errorf("unknown command %q", name)
+}
+
+func usage() {
+	w := flag.CommandLine.Output()
+	fmt.Fprintf(w, "Usage: %s COMMAND [flags] [args]\n\nCommands:\n", os.Args[0])
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-22s %s\n", cmd.name, cmd.usage)
+	}
+
+	fmt.Fprintln(w, "\nRun with -h after command name to see command flags.")
+	fmt.Fprintln(w, "Config is loaded from environment variables, see ENV_FILE.")
+}
+
+func runCommand(ctx context.Context, cmd command, args []string) error {
+	app := &app{}
+	defer app.close()
+
+	err := cmd.run(ctx, app, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+
+	return err
+}
+
+// connect loads config and initializes services.
+func (app *app) connect(ctx context.Context) error {
+	if err := config.LoadEnvFile(); err != nil {
+		return err
+	}
+
+	cfg, err := config.FromEnv()
+	if err != nil {
+		return err
+	}
+
+	db, err := cfg.DB.NewPgxPool(ctx)
+	if err != nil {
+		return err
+	}
+
+	app.db = db
+	var rates booking.RateSource = booking.NewDBRates(db)
+	if cfg.FX.RatesFile != "" {
+		rates, err = booking.LoadRatesFile(cfg.FX.RatesFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Commands don't issue tickets or publish to Redis, domain events are delivered by the server outbox relay.
+	app.svc = booking.NewService(booking.NewPostgresStore(db), nil, rates, nil, booking.ResalePolicy{
+		PriceCapBps: cfg.Resale.PriceCapBps,
+		FeeBps:      cfg.Resale.FeeBps,
+	})
+	app.reports = reporting.NewService(db, reporting.Config{
+		FeeBps: cfg.Reporting.FeeBps,
+	})
+	return nil
+}
+
+func (app *app) close() {
+	if app.db != nil {
+		app.db.Close()
+	}
+}
+
+// newFlagSet returns command flag set with common output flag.
+func newFlagSet(app *app, name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Var(&app.out, "o", "Output format: table or json")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s [flags] %s\n\nFlags:\n", os.Args[0], name, args)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// parseArgs parses command flags, checks number of positional args and connects to database.
+func (app *app) parseArgs(ctx context.Context, fs *flag.FlagSet, args []string, nargs int) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != nargs {
+		fs.Usage()
+		return fmt.Errorf("expected %d arguments, got %d", nargs, fs.NArg())
+	}
+
+	return app.connect(ctx)
+}
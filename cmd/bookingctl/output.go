@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+// printer writes command results as a table or JSON.
+type printer struct {
+	format string
+	w      io.Writer
+}
+
+func (p *printer) String() string {
+	if p.format == "" {
+		return formatTable
+	}
+
+	return p.format
+}
+
+func (p *printer) Set(v string) error {
+	switch v {
+	case formatTable, formatJSON:
+		p.format = v
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format %q", v)
+	}
+}
+
+func (p *printer) writer() io.Writer {
+	if p.w == nil {
+		return os.Stdout
+	}
+
+	return p.w
+}
+
+// print writes v as JSON or calls writeTable to write table output.
+func (p *printer) print(v any, writeTable func(t *table)) error {
+	if p.format == formatJSON {
+		enc := json.NewEncoder(p.writer())
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	t := &table{w: tabwriter.NewWriter(p.writer(), 0, 0, 2, ' ', 0)}
+	writeTable(t)
+	return t.w.Flush()
+}
+
+// table is a tab-aligned table writer.
+type table struct {
+	w *tabwriter.Writer
+}
+
+// row writes a table row. Cells are formatted with %v.
+func (t *table) row(cells ...any) {
+	strs := make([]string, len(cells))
+	for i, c := range cells {
+		strs[i] = formatCell(c)
+	}
+
+	fmt.Fprintln(t.w, strings.Join(strs, "\t"))
+}
+
+// section writes empty line and a section title.
+func (t *table) section(title string) {
+	fmt.Fprintf(t.w, "\n%s:\n", title)
+}
+
+func formatCell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "-"
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return "-"
+		}
+
+		return v.Format(time.RFC3339)
+	case *uuid.UUID:
+		if v == nil {
+			return "-"
+		}
+
+		return v.String()
+	case fmt.Stringer:
+		if s := v.String(); s != "" {
+			return s
+		}
+
+		return "-"
+	case string:
+		if v == "" {
+			return "-"
+		}
+
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// cents formats amount in minor units with currency.
+func cents(amount int64, currency booking.Currency) string {
+	return booking.NewMoney(amount, currency).String()
+}
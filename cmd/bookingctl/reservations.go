@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+// reservationDetails is a reservation with its tickets and payments.
+type reservationDetails struct {
+	*booking.ReservationMeta
+
+	// Tickets are issued only for paid reservations.
+	Tickets  []*booking.IssuedTicket  `json:"tickets"`
+	Payments []*booking.PaymentRecord `json:"payments"`
+}
+
+func getReservation(ctx context.Context, app *app, args []string) error {
+	reservationID, err := parseReservationID(ctx, app, "reservations get", args)
+	if err != nil {
+		return err
+	}
+
+	meta, err := app.svc.GetReservationEntries(ctx, reservationID)
+	if err != nil {
+		return err
+	}
+
+	tickets, err := app.svc.GetReservationTickets(ctx, reservationID)
+	if err != nil && !errors.Is(err, booking.ErrInvalidStatus) {
+		return err
+	}
+
+	payments, err := app.svc.GetReservationPayments(ctx, reservationID)
+	if err != nil {
+		return err
+	}
+
+	details := &reservationDetails{
+		ReservationMeta: meta,
+		Tickets:         tickets,
+		Payments:        payments,
+	}
+	if details.Tickets == nil {
+		details.Tickets = []*booking.IssuedTicket{}
+	}
+
+	return app.out.print(details, func(t *table) {
+		t.row("RESERVATION", meta.ID)
+		t.row("EVENT", fmt.Sprintf("%s (%s)", meta.EventName, meta.EventID))
+		t.row("STATUS", meta.Status)
+		t.row("TOTAL", cents(int64(meta.TotalCents), meta.Currency))
+		t.row("EXPIRES AT", meta.ExpiresAt)
+
+		t.section("Items")
+		t.row("TIER ID", "TIER", "QUANTITY", "UNIT PRICE")
+		for _, item := range meta.Items {
+			t.row(item.TierID, item.TierName, item.Quantity, cents(int64(item.UnitPriceCents), meta.Currency))
+		}
+
+		t.section("Tickets")
+		t.row("TICKET ID", "TIER", "ISSUED AT")
+		for _, ticket := range details.Tickets {
+			t.row(ticket.TicketID, ticket.TierName, ticket.IssuedAt)
+		}
+
+		t.section("Payments")
+		t.row("TX ID", "PAID AT", "SETTLEMENT", "PRESENTMENT", "RATE", "REFUNDED AT")
+		for _, p := range payments {
+			t.row(p.TxID, p.PaidAt, p.Settlement, p.Presentment, p.ExchangeRate, p.RefundedAt)
+		}
+	})
+}
+
+func expireReservation(ctx context.Context, app *app, args []string) error {
+	reservationID, err := parseReservationID(ctx, app, "reservations expire", args)
+	if err != nil {
+		return err
+	}
+
+	if err := app.svc.ExpireReservation(ctx, reservationID); err != nil {
+		return err
+	}
+
+	return printStatus(app, reservationID, booking.ReservationExpired)
+}
+
+func cancelReservation(ctx context.Context, app *app, args []string) error {
+	reservationID, err := parseReservationID(ctx, app, "reservations cancel", args)
+	if err != nil {
+		return err
+	}
+
+	if err := app.svc.CancelReservation(ctx, reservationID); err != nil {
+		return err
+	}
+
+	return printStatus(app, reservationID, booking.ReservationCancelled)
+}
+
+func refundReservation(ctx context.Context, app *app, args []string) error {
+	reservationID, err := parseReservationID(ctx, app, "reservations refund", args)
+	if err != nil {
+		return err
+	}
+
+	result, err := app.svc.RefundReservation(ctx, reservationID)
+	if err != nil {
+		return err
+	}
+
+	return app.out.print(result, func(t *table) {
+		t.row("RESERVATION", reservationID)
+		t.row("STATUS", booking.ReservationRefunded)
+		t.row("TX ID", result.TxID)
+		t.row("REFUNDED", result.Settlement)
+	})
+}
+
+func parseReservationID(ctx context.Context, app *app, name string, args []string) (uuid.UUID, error) {
+	fs := newFlagSet(app, name, "RESERVATION_ID")
+	if err := app.parseArgs(ctx, fs, args, 1); err != nil {
+		return uuid.Nil, err
+	}
+
+	reservationID, err := uuid.Parse(fs.Arg(0))
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid reservation ID: %w", err)
+	}
+
+	return reservationID, nil
+}
+
+// reservationStatus is a result of reservation status change.
+type reservationStatus struct {
+	ReservationID uuid.UUID                 `json:"reservationID"`
+	Status        booking.ReservationStatus `json:"status"`
+}
+
+func printStatus(app *app, reservationID uuid.UUID, status booking.ReservationStatus) error {
+	result := reservationStatus{ReservationID: reservationID, Status: status}
+	return app.out.print(result, func(t *table) {
+		t.row("RESERVATION", reservationID)
+		t.row("STATUS", status)
+	})
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/config"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/importer"
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func run() error {
+	dryRun := flag.Bool("dry-run", false, "Validate manifest and show what would be created")
+	format := flag.String("format", "", "Manifest format: csv or yaml. Detected by file extension by default")
+	asJSON := flag.Bool("json", false, "Print result as JSON")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] MANIFEST\n\nFlags:\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		return errors.New("missing manifest file")
+	}
+
+	path := flag.Arg(0)
+	manifestFormat, err := parseFormat(*format, path)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+	manifest, err := importer.Parse(f, manifestFormat)
+	if err != nil {
+		return reportError(err)
+	}
+
+	ctx, cancelFn := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancelFn()
+
+	result, err := importManifest(ctx, manifest, *dryRun)
+
+	if result != nil {
+		if *asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			_ = enc.Encode(result)
+		} else {
+			printResult(os.Stdout, result)
+		}
+	}
+
+	if err != nil {
+		return reportError(err)
+	}
+
+	if result.Failed > 0 {
+		return fmt.Errorf("failed to import %d of %d events", result.Failed, len(result.Events))
+	}
+
+	return nil
+}
+
+// importManifest validates manifest before connecting to database and creates its events.
+//
+// Dry run only reads existing events to report which events would be created.
+func importManifest(ctx context.Context, manifest *importer.Manifest, dryRun bool) (*importer.Result, error) {
+	if _, err := importer.Validate(manifest); err != nil {
+		return nil, err
+	}
+
+	if err := config.LoadEnvFile(); err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.DBConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := cfg.NewPgxPool(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer db.Close()
+
+	// Import only creates events, so Redis and ticket code signer are not needed.
+	svc := booking.NewService(booking.NewPostgresStore(db), nil, booking.NewDBRates(db), nil, booking.DefaultResalePolicy)
+	return importer.New(svc).Import(ctx, manifest, dryRun)
+}
+
+func parseFormat(format, path string) (importer.Format, error) {
+	if format != "" {
+		return importer.ParseFormat(format)
+	}
+
+	return importer.FormatFromPath(path)
+}
+
+// reportError prints all invalid rows of a manifest.
+func reportError(err error) error {
+	var verr *importer.ValidationError
+	if !errors.As(err, &verr) {
+		return err
+	}
+
+	for _, row := range verr.Rows {
+		fmt.Fprintln(os.Stderr, row)
+	}
+
+	return fmt.Errorf("manifest is invalid: %d errors", len(verr.Rows))
+}
+
+func printResult(w io.Writer, result *importer.Result) {
+	for _, e := range result.Events {
+		switch {
+		case e.Error != "":
+			fmt.Fprintf(w, "FAILED  %s (row %d): %s\n", e.Name, e.Row, e.Error)
+		case e.Exists:
+			fmt.Fprintf(w, "EXISTS  %s (row %d): %s\n", e.Name, e.Row, e.EventID)
+		case e.EventID != nil:
+			fmt.Fprintf(w, "CREATED %s (row %d): %s\n", e.Name, e.Row, e.EventID)
+		default:
+			fmt.Fprintf(w, "PLANNED %s (row %d)\n", e.Name, e.Row)
+		}
+
+		for _, t := range e.Tiers {
+			fmt.Fprintf(w, "  %-20s %6d tickets  %10d cents  %d pricing rules\n",
+				t.Name, t.TicketsCount, t.PriceCents, t.PricingRules)
+		}
+	}
+
+	if result.DryRun {
+		fmt.Fprintf(w, "\nDry run: %d events would be created, %d already exist\n",
+			len(result.Events)-result.Existing, result.Existing)
+		return
+	}
+
+	fmt.Fprintf(w, "\n%d events created, %d already exist, %d failed\n", result.Created, result.Existing, result.Failed)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/reporting"
+)
+
+// ledger records tickets sold to virtual users and detects tickets sold twice.
+type ledger struct {
+	mu sync.Mutex
+
+	// owners maps ticket ID to reservation which bought it.
+	owners map[uuid.UUID]uuid.UUID
+
+	// soldByTier is number of tickets bought by virtual users per tier.
+	soldByTier map[uuid.UUID]int
+
+	// ambiguous is number of payments with unknown outcome, e.g. timed out.
+	ambiguous int
+
+	violations []string
+}
+
+func newLedger() *ledger {
+	return &ledger{
+		owners:     make(map[uuid.UUID]uuid.UUID),
+		soldByTier: make(map[uuid.UUID]int),
+	}
+}
+
+// violate records a correctness violation.
+func (l *ledger) violate(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.violations = append(l.violations, fmt.Sprintf(format, args...))
+}
+
+// markAmbiguous records a payment with unknown outcome.
+func (l *ledger) markAmbiguous() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ambiguous++
+}
+
+// recordSale records tickets issued for a paid reservation.
+func (l *ledger) recordSale(reservationID uuid.UUID, tickets map[uuid.UUID]uuid.UUID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for ticketID, tierID := range tickets {
+		if owner, ok := l.owners[ticketID]; ok && owner != reservationID {
+			l.violations = append(l.violations, fmt.Sprintf(
+				"ticket %s is sold twice: to reservations %s and %s", ticketID, owner, reservationID,
+			))
+			continue
+		}
+
+		l.owners[ticketID] = reservationID
+		l.soldByTier[tierID]++
+	}
+}
+
+// ticketsSold returns number of unique tickets sold to virtual users.
+func (l *ledger) ticketsSold() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.owners)
+}
+
+// reconcile compares sold tickets with server-side event report.
+//
+// Only valid when virtual users are the only buyers of the event.
+func (l *ledger) reconcile(report *reporting.EventReport) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, tier := range report.Tiers {
+		sold := l.soldByTier[tier.TierID]
+		switch {
+		case tier.Sold > tier.Capacity:
+			l.violations = append(l.violations, fmt.Sprintf(
+				"tier %q is oversold: %d sold of %d", tier.Name, tier.Sold, tier.Capacity,
+			))
+		case tier.Sold < sold:
+			l.violations = append(l.violations, fmt.Sprintf(
+				"tier %q: server reports %d sold tickets, but users received %d", tier.Name, tier.Sold, sold,
+			))
+		case tier.Sold > sold && l.ambiguous == 0:
+			// Payments with unknown outcome might have succeeded, in that case their tickets are not in ledger.
+			l.violations = append(l.violations, fmt.Sprintf(
+				"tier %q: server reports %d sold tickets, but users received only %d", tier.Name, tier.Sold, sold,
+			))
+		}
+	}
+}
+
+// result returns sorted list of violations.
+func (l *ledger) result() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	violations := slices.Clone(l.violations)
+	slices.Sort(violations)
+	return violations
+}
@@ -0,0 +1,248 @@
+// Command loadgen generates load on booking API and checks that no ticket was sold twice.
+//
+// Virtual users run browse → tiers → reserve → pay flow in a loop using the same client as integration tests.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/tests"
+)
+
+const hotTierName = "Hot"
+
+type options struct {
+	addr     string
+	users    int
+	duration time.Duration
+	seed     uint64
+	asJSON   bool
+
+	// eventID is existing event to book. If empty, a new event is created.
+	eventID     string
+	tiers       int
+	tierTickets int
+	hotTickets  int
+
+	// maxP95 is latency objective of booking endpoints.
+	maxP95 time.Duration
+
+	scenario scenarioConfig
+}
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func run() error {
+	opts := options{}
+	flag.StringVar(&opts.addr, "addr", "localhost:8000", "Booking API address")
+	flag.IntVar(&opts.users, "users", 50, "Number of concurrent virtual users")
+	flag.DurationVar(&opts.duration, "duration", 30*time.Second, "Test duration. Test stops earlier if event is sold out")
+	flag.Uint64Var(&opts.seed, "seed", 0, "Random seed. Random by default")
+	flag.BoolVar(&opts.asJSON, "json", false, "Print report as JSON")
+	flag.StringVar(&opts.eventID, "event", "", "Existing event ID. Tier with least available tickets is used as hot tier. New event is created by default")
+	flag.IntVar(&opts.tiers, "tiers", 3, "Number of regular tiers in created event")
+	flag.IntVar(&opts.tierTickets, "tier-tickets", 500, "Number of tickets in each regular tier of created event")
+	flag.IntVar(&opts.hotTickets, "hot-tickets", 20, "Number of tickets in hot tier of created event")
+	flag.Float64Var(&opts.scenario.HotRatio, "hot-ratio", 0.5, "Share of reservations which target hot tier")
+	flag.IntVar(&opts.scenario.MaxTickets, "max-tickets", 4, "Max number of tickets in a reservation")
+	flag.DurationVar(&opts.scenario.ThinkTime, "think", 200*time.Millisecond, "Mean think time between user steps")
+	flag.Float64Var(&opts.scenario.Abandon, "abandon", 0.05, "Probability to abandon reservation until hold expires")
+	flag.Float64Var(&opts.scenario.Cancel, "cancel", 0.05, "Probability to cancel reservation instead of payment")
+	flag.Float64Var(&opts.scenario.Decline, "decline", 0.02, "Probability to pay with declined card")
+	flag.Float64Var(&opts.scenario.Retry, "retry", 0.05, "Probability to retry reservation with the same idempotency key")
+	flag.DurationVar(&opts.maxP95, "max-p95", 500*time.Millisecond, "Max p95 latency of reserve and pay requests. Zero disables the check")
+	flag.Parse()
+
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	ctx, cancelFn := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancelFn()
+
+	client, err := tests.NewClient(opts.addr)
+	if err != nil {
+		return err
+	}
+
+	// Default transport keeps only 2 idle connections per host, which causes reconnects under load.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = opts.users
+	client = client.WithHTTPClient(&http.Client{Transport: transport, Timeout: 30 * time.Second})
+	if err := client.WaitForServer(5, time.Second); err != nil {
+		return fmt.Errorf("server is not available: %w", err)
+	}
+
+	created, err := setupEvent(client, &opts)
+	if err != nil {
+		return err
+	}
+
+	report := runUsers(ctx, client, &opts)
+	if created {
+		// Users of this run are the only buyers, so server-side sales should match received tickets.
+		eventReport, err := client.GetEventReport(opts.scenario.EventID, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get event report: %w", err)
+		}
+
+		report.ledger.reconcile(eventReport)
+	}
+
+	report.finalize(opts.maxP95)
+	if opts.asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(report)
+	} else {
+		report.print(os.Stdout)
+	}
+
+	if len(report.Violations) > 0 {
+		return fmt.Errorf("found %d correctness violations", len(report.Violations))
+	}
+
+	if len(report.SLOBreaches) > 0 {
+		return errors.New("latency objective is not met")
+	}
+
+	return nil
+}
+
+func (opts *options) validate() error {
+	switch {
+	case opts.users <= 0:
+		return errors.New("number of users should be positive")
+	case opts.scenario.MaxTickets <= 0:
+		return errors.New("max tickets should be positive")
+	case opts.eventID == "" && (opts.tiers < 0 || opts.tierTickets <= 0 || opts.hotTickets <= 0):
+		return errors.New("tiers count should not be negative and tickets count should be positive")
+	}
+
+	for _, p := range []float64{
+		opts.scenario.HotRatio, opts.scenario.Abandon, opts.scenario.Cancel,
+		opts.scenario.Decline, opts.scenario.Retry,
+	} {
+		if p < 0 || p > 1 {
+			return fmt.Errorf("probability should be between 0 and 1, got %v", p)
+		}
+	}
+
+	return nil
+}
+
+// setupEvent creates event or picks hot tier of existing event. Returns true if event was created.
+func setupEvent(client *tests.Client, opts *options) (bool, error) {
+	if opts.eventID != "" {
+		eventID, err := uuid.Parse(opts.eventID)
+		if err != nil {
+			return false, fmt.Errorf("invalid event ID: %w", err)
+		}
+
+		tiers, err := client.ListTicketTiers(eventID)
+		if err != nil {
+			return false, fmt.Errorf("failed to get event tiers: %w", err)
+		}
+
+		opts.scenario.EventID = eventID
+		var hot *booking.TicketTier
+		for _, t := range tiers.Tiers {
+			if t.AvailableCount > 0 && (hot == nil || t.AvailableCount < hot.AvailableCount) {
+				hot = t
+			}
+		}
+
+		if hot == nil {
+			return false, errors.New("event has no available tickets")
+		}
+
+		opts.scenario.HotTierID = hot.TierID
+		return false, nil
+	}
+
+	params := booking.EventCreateParams{
+		EventName: fmt.Sprintf("Load test %s", time.Now().Format(time.RFC3339)),
+		Tiers: map[string]booking.CreateTierParams{
+			hotTierName: {PriceCents: 25000, TicketsCount: opts.hotTickets},
+		},
+	}
+
+	for i := range opts.tiers {
+		params.Tiers[fmt.Sprintf("Tier %d", i+1)] = booking.CreateTierParams{
+			PriceCents:   5000 * (i + 1),
+			TicketsCount: opts.tierTickets,
+		}
+	}
+
+	event, err := client.PostEvent(params)
+	if err != nil {
+		return false, fmt.Errorf("failed to create event: %w", err)
+	}
+
+	opts.scenario.EventID = event.EventID
+	opts.scenario.HotTierID = event.Tiers[hotTierName]
+	return true, nil
+}
+
+// runUsers runs virtual users until duration elapses or all users stop.
+func runUsers(ctx context.Context, client *tests.Client, opts *options) *Report {
+	ctx, cancelFn := context.WithTimeout(ctx, opts.duration)
+	defer cancelFn()
+
+	seed := opts.seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+
+	rec := newRecorder()
+	sales := newLedger()
+	counts := &counters{}
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := range opts.users {
+		vu := &virtualUser{
+			id:     uuid.New(),
+			cfg:    &opts.scenario,
+			client: client,
+			rec:    rec,
+			ledger: sales,
+			counts: counts,
+			rnd:    rand.New(rand.NewPCG(seed, uint64(i))),
+		}
+
+		wg.Go(func() {
+			vu.run(ctx)
+		})
+	}
+
+	wg.Wait()
+	elapsed := time.Since(start)
+	return &Report{
+		EventID:   opts.scenario.EventID,
+		Users:     opts.users,
+		Seed:      seed,
+		Elapsed:   elapsed,
+		Endpoints: rec.summarize(elapsed),
+		Flow:      counts.summary(),
+		ledger:    sales,
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Report is load test result.
+type Report struct {
+	EventID uuid.UUID     `json:"eventID"`
+	Users   int           `json:"users"`
+	Seed    uint64        `json:"seed"`
+	Elapsed time.Duration `json:"elapsed"`
+
+	// Throughput is number of requests per second of all endpoints.
+	Throughput float64 `json:"throughput"`
+
+	// Bookings is number of paid reservations per second.
+	Bookings float64 `json:"bookings"`
+
+	Endpoints   []*EndpointStats `json:"endpoints"`
+	Flow        FlowSummary      `json:"flow"`
+	TicketsSold int              `json:"ticketsSold"`
+
+	// Violations are correctness check failures, e.g. tickets sold twice.
+	Violations []string `json:"violations"`
+
+	// SLOBreaches are endpoints which exceed p95 latency objective.
+	SLOBreaches []string `json:"sloBreaches,omitempty"`
+
+	ledger *ledger
+}
+
+// sloEndpoints are booking endpoints covered by latency objective.
+var sloEndpoints = []string{endpointReserve, endpointPay}
+
+// finalize computes totals and checks latency objective.
+func (r *Report) finalize(maxP95 time.Duration) {
+	r.TicketsSold = r.ledger.ticketsSold()
+	r.Violations = r.ledger.result()
+
+	requests := 0
+	for _, e := range r.Endpoints {
+		requests += e.Requests
+		if maxP95 > 0 && slices.Contains(sloEndpoints, e.Endpoint) && e.P95 > maxP95 {
+			r.SLOBreaches = append(r.SLOBreaches, fmt.Sprintf("%s p95 %s > %s", e.Endpoint, e.P95, maxP95))
+		}
+	}
+
+	if r.Elapsed > 0 {
+		r.Throughput = float64(requests) / r.Elapsed.Seconds()
+		r.Bookings = float64(r.Flow.Paid) / r.Elapsed.Seconds()
+	}
+}
+
+func (r *Report) print(w io.Writer) {
+	fmt.Fprintf(w, "Event %s, %d users, %s elapsed, seed %d\n\n", r.EventID, r.Users, r.Elapsed.Round(time.Millisecond), r.Seed)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "ENDPOINT\tREQUESTS\tERRORS\tREQ/S\tP50\tP90\tP95\tP99\tMAX\t")
+	for _, e := range r.Endpoints {
+		fmt.Fprintf(
+			tw, "%s\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\t%s\t\n",
+			e.Endpoint, e.Requests, e.Errors, e.Throughput,
+			ms(e.P50), ms(e.P90), ms(e.P95), ms(e.P99), ms(e.Max),
+		)
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintf(w, "\nThroughput: %.1f req/s, %.1f bookings/s\n", r.Throughput, r.Bookings)
+
+	f := r.Flow
+	fmt.Fprintf(
+		w, "Flow: %d iterations, %d reservations, %d paid, %d abandoned, %d cancelled, %d declined, %d retried\n",
+		f.Iterations, f.Reservations, f.Paid, f.Abandoned, f.Cancelled, f.Declined, f.Retried,
+	)
+	fmt.Fprintf(w, "Tickets sold: %d, users stopped on sold out: %d\n", r.TicketsSold, f.SoldOut)
+
+	if hasErrors(r.Endpoints) {
+		fmt.Fprintln(w, "\nErrors:")
+		for _, e := range r.Endpoints {
+			for _, class := range slices.Sorted(maps.Keys(e.ErrorsBy)) {
+				fmt.Fprintf(w, "  %-8s %-10s %d\n", e.Endpoint, class, e.ErrorsBy[class])
+			}
+		}
+	}
+
+	fmt.Fprintln(w)
+	if len(r.Violations) == 0 {
+		fmt.Fprintln(w, "Correctness: OK, no ticket was sold twice")
+	} else {
+		fmt.Fprintf(w, "Correctness: FAILED, %d violations:\n", len(r.Violations))
+		for _, v := range r.Violations {
+			fmt.Fprintln(w, "  "+v)
+		}
+	}
+
+	for _, b := range r.SLOBreaches {
+		fmt.Fprintln(w, "Latency objective breached: "+b)
+	}
+}
+
+func hasErrors(endpoints []*EndpointStats) bool {
+	for _, e := range endpoints {
+		if e.Errors > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func ms(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+}
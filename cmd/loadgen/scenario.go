@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/server"
+	"github.com/x1unix/thoughtly-ticket-booking/tests"
+)
+
+// declinedCard is a card number rejected by mock payment provider.
+const declinedCard = "4000000000000002"
+
+// scenarioConfig is virtual user behavior.
+type scenarioConfig struct {
+	EventID uuid.UUID
+
+	// HotTierID is a tier which gets HotRatio share of reservations.
+	HotTierID uuid.UUID
+	HotRatio  float64
+
+	// MaxTickets is max number of tickets in a reservation.
+	MaxTickets int
+
+	// ThinkTime is mean pause between user steps. Actual pause is randomized in ±50% range.
+	ThinkTime time.Duration
+
+	// Failure injection probabilities.
+	//
+	// Abandon leaves reservation unpaid until hold expires, Cancel cancels it instead of payment,
+	// Decline pays with a declined card and then cancels,
+	// Retry repeats reservation request with the same idempotency key like a client which lost a response.
+	Abandon float64
+	Cancel  float64
+	Decline float64
+	Retry   float64
+}
+
+// counters are booking flow outcomes.
+type counters struct {
+	iterations   atomic.Int64
+	reservations atomic.Int64
+	paid         atomic.Int64
+	abandoned    atomic.Int64
+	cancelled    atomic.Int64
+	declined     atomic.Int64
+	retried      atomic.Int64
+	soldOut      atomic.Int64
+}
+
+// FlowSummary is a snapshot of booking flow outcomes.
+type FlowSummary struct {
+	Iterations   int64 `json:"iterations"`
+	Reservations int64 `json:"reservations"`
+	Paid         int64 `json:"paid"`
+	Abandoned    int64 `json:"abandoned"`
+	Cancelled    int64 `json:"cancelled"`
+	Declined     int64 `json:"declined"`
+	Retried      int64 `json:"retried"`
+
+	// SoldOut is number of users which stopped because all tiers were sold out or held.
+	SoldOut int64 `json:"soldOut"`
+}
+
+func (c *counters) summary() FlowSummary {
+	return FlowSummary{
+		Iterations:   c.iterations.Load(),
+		Reservations: c.reservations.Load(),
+		Paid:         c.paid.Load(),
+		Abandoned:    c.abandoned.Load(),
+		Cancelled:    c.cancelled.Load(),
+		Declined:     c.declined.Load(),
+		Retried:      c.retried.Load(),
+		SoldOut:      c.soldOut.Load(),
+	}
+}
+
+// virtualUser runs booking flow in a loop until context is cancelled or event is sold out.
+type virtualUser struct {
+	id     uuid.UUID
+	cfg    *scenarioConfig
+	client *tests.Client
+	rec    *recorder
+	ledger *ledger
+	counts *counters
+	rnd    *rand.Rand
+}
+
+func (vu *virtualUser) run(ctx context.Context) {
+	for ctx.Err() == nil {
+		vu.counts.iterations.Add(1)
+		if done := vu.iterate(ctx); done {
+			return
+		}
+	}
+}
+
+// timed calls fn and records its latency.
+func timed[T any](vu *virtualUser, endpoint string, fn func() (T, error)) (T, error) {
+	start := time.Now()
+	v, err := fn()
+	vu.rec.observe(endpoint, time.Since(start), err)
+	return v, err
+}
+
+// iterate runs a single browse → tiers → reserve → pay flow. Returns true if there are no more tickets.
+func (vu *virtualUser) iterate(ctx context.Context) bool {
+	_, err := timed(vu, endpointEvents, vu.client.ListEvents)
+	if err != nil || !vu.think(ctx) {
+		return false
+	}
+
+	tiers, err := timed(vu, endpointTiers, func() (*server.ListTiersResponse, error) {
+		return vu.client.ListTicketTiers(vu.cfg.EventID)
+	})
+	if err != nil || !vu.think(ctx) {
+		return false
+	}
+
+	tier := vu.pickTier(tiers.Tiers)
+	if tier == nil {
+		vu.counts.soldOut.Add(1)
+		return true
+	}
+
+	count := 1 + vu.rnd.IntN(min(vu.cfg.MaxTickets, tier.AvailableCount))
+	req := server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        vu.id,
+		TicketsCount:   map[uuid.UUID]uint{tier.TierID: uint(count)},
+	}
+
+	reserve := func() (*booking.ReservationResult, error) {
+		return vu.client.ReserveTickets(vu.cfg.EventID, req)
+	}
+
+	reservation, err := timed(vu, endpointReserve, reserve)
+	if err != nil {
+		return false
+	}
+
+	vu.counts.reservations.Add(1)
+	if vu.chance(vu.cfg.Retry) {
+		vu.counts.retried.Add(1)
+		retried, err := timed(vu, endpointReserve, reserve)
+		if err == nil && retried.ReservationID != reservation.ReservationID {
+			vu.ledger.violate(
+				"idempotent reserve retry returned reservation %s instead of %s",
+				retried.ReservationID, reservation.ReservationID,
+			)
+		}
+	}
+
+	if !vu.think(ctx) {
+		return false
+	}
+
+	switch {
+	case vu.chance(vu.cfg.Abandon):
+		vu.counts.abandoned.Add(1)
+		return false
+	case vu.chance(vu.cfg.Cancel):
+		vu.counts.cancelled.Add(1)
+		vu.cancel(reservation.ReservationID)
+		return false
+	case vu.chance(vu.cfg.Decline):
+		vu.counts.declined.Add(1)
+		_, err := vu.pay(reservation.ReservationID, declinedCard)
+		if err == nil {
+			vu.ledger.violate("payment of reservation %s with declined card succeeded", reservation.ReservationID)
+			return false
+		}
+
+		vu.cancel(reservation.ReservationID)
+		return false
+	}
+
+	if _, err := vu.pay(reservation.ReservationID, booking.KnownFakeCard); err != nil {
+		var rspErr *tests.ResponseError
+		if !errors.As(err, &rspErr) {
+			vu.ledger.markAmbiguous()
+		}
+
+		return false
+	}
+
+	vu.counts.paid.Add(1)
+	vu.collectTickets(reservation.ReservationID, count)
+	return false
+}
+
+func (vu *virtualUser) pay(reservationID uuid.UUID, card string) (*booking.PaymentResult, error) {
+	return timed(vu, endpointPay, func() (*booking.PaymentResult, error) {
+		return vu.client.PayReservation(reservationID, booking.PaymentParams{
+			ReservationID: reservationID,
+			CardNumber:    card,
+		})
+	})
+}
+
+func (vu *virtualUser) cancel(reservationID uuid.UUID) {
+	_, _ = timed(vu, endpointCancel, func() (struct{}, error) {
+		return struct{}{}, vu.client.CancelReservation(reservationID)
+	})
+}
+
+// collectTickets reads issued tickets of a paid reservation and records them in ledger.
+func (vu *virtualUser) collectTickets(reservationID uuid.UUID, count int) {
+	rsp, err := timed(vu, endpointTickets, func() (*server.ListTicketsResponse, error) {
+		return vu.client.GetReservationTickets(reservationID)
+	})
+	if err != nil {
+		vu.ledger.violate("failed to get tickets of paid reservation %s: %s", reservationID, err)
+		return
+	}
+
+	if len(rsp.Tickets) != count {
+		vu.ledger.violate("reservation %s has %d tickets, expected %d", reservationID, len(rsp.Tickets), count)
+	}
+
+	tickets := make(map[uuid.UUID]uuid.UUID, len(rsp.Tickets))
+	for _, t := range rsp.Tickets {
+		tickets[t.TicketID] = t.TierID
+	}
+
+	vu.ledger.recordSale(reservationID, tickets)
+}
+
+// pickTier selects a tier with available tickets, hot tier is preferred with configured ratio.
+//
+// Returns nil if all tiers are sold out.
+func (vu *virtualUser) pickTier(tiers []*booking.TicketTier) *booking.TicketTier {
+	available := make([]*booking.TicketTier, 0, len(tiers))
+	for _, t := range tiers {
+		if t.AvailableCount <= 0 {
+			continue
+		}
+
+		if t.TierID == vu.cfg.HotTierID && vu.chance(vu.cfg.HotRatio) {
+			return t
+		}
+
+		available = append(available, t)
+	}
+
+	if len(available) == 0 {
+		return nil
+	}
+
+	return available[vu.rnd.IntN(len(available))]
+}
+
+func (vu *virtualUser) chance(p float64) bool {
+	return p > 0 && vu.rnd.Float64() < p
+}
+
+// think pauses for randomized think time. Returns false if context is cancelled.
+func (vu *virtualUser) think(ctx context.Context) bool {
+	if vu.cfg.ThinkTime <= 0 {
+		return ctx.Err() == nil
+	}
+
+	d := time.Duration(float64(vu.cfg.ThinkTime) * (0.5 + vu.rnd.Float64()))
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
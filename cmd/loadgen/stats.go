@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/x1unix/thoughtly-ticket-booking/tests"
+)
+
+// Endpoint names used in report.
+const (
+	endpointEvents  = "events"
+	endpointTiers   = "tiers"
+	endpointReserve = "reserve"
+	endpointPay     = "pay"
+	endpointCancel  = "cancel"
+	endpointTickets = "tickets"
+)
+
+var endpoints = []string{
+	endpointEvents, endpointTiers, endpointReserve, endpointPay, endpointCancel, endpointTickets,
+}
+
+// Error classes.
+const (
+	errClassSoldOut   = "sold_out"
+	errClassTransport = "transport"
+)
+
+// recorder collects request latencies and errors of all virtual users.
+type recorder struct {
+	mu        sync.Mutex
+	latencies map[string][]time.Duration
+	errors    map[string]map[string]int
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		latencies: make(map[string][]time.Duration),
+		errors:    make(map[string]map[string]int),
+	}
+}
+
+// observe records request latency and error class, if any.
+func (r *recorder) observe(endpoint string, d time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.latencies[endpoint] = append(r.latencies[endpoint], d)
+	if err == nil {
+		return
+	}
+
+	classes, ok := r.errors[endpoint]
+	if !ok {
+		classes = make(map[string]int)
+		r.errors[endpoint] = classes
+	}
+
+	classes[errorClass(err)]++
+}
+
+// errorClass returns error class for report: response status code, sold out or transport error.
+func errorClass(err error) string {
+	var rspErr *tests.ResponseError
+	if !errors.As(err, &rspErr) {
+		return errClassTransport
+	}
+
+	if rspErr.Response != nil && strings.HasPrefix(rspErr.Response.Error, "not enough tickets") {
+		return errClassSoldOut
+	}
+
+	return strconv.Itoa(rspErr.Code)
+}
+
+// EndpointStats is latency summary of an endpoint.
+type EndpointStats struct {
+	Endpoint   string         `json:"endpoint"`
+	Requests   int            `json:"requests"`
+	Errors     int            `json:"errors"`
+	Throughput float64        `json:"throughput"`
+	P50        time.Duration  `json:"p50"`
+	P90        time.Duration  `json:"p90"`
+	P95        time.Duration  `json:"p95"`
+	P99        time.Duration  `json:"p99"`
+	Max        time.Duration  `json:"max"`
+	ErrorsBy   map[string]int `json:"errorsBy,omitempty"`
+}
+
+// summarize returns per-endpoint stats. Throughput is computed over elapsed time.
+func (r *recorder) summarize(elapsed time.Duration) []*EndpointStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]*EndpointStats, 0, len(r.latencies))
+	for _, endpoint := range endpoints {
+		latencies, ok := r.latencies[endpoint]
+		if !ok {
+			continue
+		}
+
+		sorted := slices.Clone(latencies)
+		slices.Sort(sorted)
+
+		stats := &EndpointStats{
+			Endpoint: endpoint,
+			Requests: len(sorted),
+			P50:      percentile(sorted, 50),
+			P90:      percentile(sorted, 90),
+			P95:      percentile(sorted, 95),
+			P99:      percentile(sorted, 99),
+			Max:      sorted[len(sorted)-1],
+			ErrorsBy: maps.Clone(r.errors[endpoint]),
+		}
+		if elapsed > 0 {
+			stats.Throughput = float64(stats.Requests) / elapsed.Seconds()
+		}
+
+		for _, n := range stats.ErrorsBy {
+			stats.Errors += n
+		}
+
+		result = append(result, stats)
+	}
+
+	return result
+}
+
+// percentile returns p-th percentile of sorted values using nearest-rank method.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/server"
+	"github.com/x1unix/thoughtly-ticket-booking/tests"
+)
+
+func TestPercentile(t *testing.T) {
+	values := make([]time.Duration, 100)
+	for i := range values {
+		values[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	require.Equal(t, 50*time.Millisecond, percentile(values, 50))
+	require.Equal(t, 95*time.Millisecond, percentile(values, 95))
+	require.Equal(t, 100*time.Millisecond, percentile(values, 100))
+	require.Equal(t, time.Millisecond, percentile(values, 0))
+	require.Zero(t, percentile(nil, 95))
+}
+
+func TestRecorderSummarize(t *testing.T) {
+	rec := newRecorder()
+	rec.observe(endpointReserve, 10*time.Millisecond, nil)
+	rec.observe(endpointReserve, 30*time.Millisecond, &tests.ResponseError{
+		Code:     http.StatusBadRequest,
+		Response: &server.ErrorResponse{Error: `not enough tickets available of tier "x"`},
+	})
+	rec.observe(endpointReserve, 20*time.Millisecond, &tests.ResponseError{
+		Code:     http.StatusConflict,
+		Response: &server.ErrorResponse{Error: "conflict"},
+	})
+	rec.observe(endpointPay, 5*time.Millisecond, errors.New("connection reset"))
+
+	stats := rec.summarize(time.Second)
+	require.Len(t, stats, 2)
+
+	reserve := stats[0]
+	require.Equal(t, endpointReserve, reserve.Endpoint)
+	require.Equal(t, 3, reserve.Requests)
+	require.Equal(t, 2, reserve.Errors)
+	require.Equal(t, 20*time.Millisecond, reserve.P50)
+	require.Equal(t, 30*time.Millisecond, reserve.Max)
+	require.Equal(t, map[string]int{errClassSoldOut: 1, "409": 1}, reserve.ErrorsBy)
+	require.InDelta(t, 3.0, reserve.Throughput, 0.001)
+
+	require.Equal(t, map[string]int{errClassTransport: 1}, stats[1].ErrorsBy)
+}
+
+func TestLedgerDetectsDoubleSale(t *testing.T) {
+	l := newLedger()
+	tierID, ticketID := uuid.New(), uuid.New()
+	l.recordSale(uuid.New(), map[uuid.UUID]uuid.UUID{ticketID: tierID})
+	require.Empty(t, l.result())
+
+	l.recordSale(uuid.New(), map[uuid.UUID]uuid.UUID{ticketID: tierID})
+	require.Len(t, l.result(), 1)
+	require.Equal(t, 1, l.ticketsSold())
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/pressly/goose/v3"
 
 	"github.com/x1unix/thoughtly-ticket-booking/internal/config"
+	"github.com/x1unix/thoughtly-ticket-booking/migrations"
 )
 
 func main() {
@@ -44,8 +45,17 @@ func run() error {
 
 	cmd := args[0]
 	args = args[1:]
+
+	// Migrations are embedded into binary, except for commands which write new migration files.
+	dir := "."
+	if cmd == "create" || cmd == "fix" {
+		dir = "migrations"
+	} else {
+		goose.SetBaseFS(migrations.FS)
+	}
+
 	ctx := context.Background()
-	err = goose.RunContext(ctx, cmd, db, "migrations", args...)
+	err = goose.RunContext(ctx, cmd, db, dir, args...)
 	if err != nil {
 		return fmt.Errorf("migration failed: %w", err)
 	}
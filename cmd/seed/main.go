@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/config"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/seed"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/ticketcode"
+)
+
+// defaultStart is a fixed date of the first event, so the same seed produces the same catalog on any day.
+const defaultStart = "2026-11-06"
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func run() error {
+	opts := seed.Options{}
+	start := flag.String("start", defaultStart, "Date of the first event in YYYY-MM-DD format")
+	flag.Uint64Var(&opts.Seed, "seed", 1, "Seed value. The same seed always produces the same catalog")
+	flag.IntVar(&opts.Events, "events", 8, "Number of events")
+	flag.IntVar(&opts.Reservations, "reservations", 12, "Number of reservations per event")
+	flag.IntVar(&opts.Users, "users", 10, "Number of demo users")
+	asJSON := flag.Bool("json", false, "Print result as JSON")
+	flag.Parse()
+
+	var err error
+	opts.Start, err = time.Parse(time.DateOnly, *start)
+	if err != nil {
+		return fmt.Errorf("invalid start date: %w", err)
+	}
+
+	catalog, err := seed.Generate(opts)
+	if err != nil {
+		return err
+	}
+
+	if err := config.LoadEnvFile(); err != nil {
+		return err
+	}
+
+	cfg, err := config.FromEnv()
+	if err != nil {
+		return err
+	}
+
+	// Random key would make issued ticket codes invalid for the server.
+	if cfg.Tickets.SigningKey == "" {
+		return errors.New("APP_TICKETS_SIGNING_KEY is required to issue tickets of paid reservations")
+	}
+
+	codes, err := ticketcode.NewSigner([]byte(cfg.Tickets.SigningKey))
+	if err != nil {
+		return fmt.Errorf("invalid ticket signing key: %w", err)
+	}
+
+	ctx, cancelFn := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancelFn()
+
+	db, err := cfg.DB.NewPgxPool(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer db.Close()
+
+	// Domain events of seeded reservations are delivered by the server outbox relay.
+	svc := booking.NewService(booking.NewPostgresStore(db), nil, booking.NewDBRates(db), codes, booking.DefaultResalePolicy)
+	result, err := seed.NewSeeder(svc).Seed(ctx, catalog)
+	if result != nil {
+		if *asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			_ = enc.Encode(result)
+		} else {
+			printResult(os.Stdout, result)
+		}
+	}
+
+	return err
+}
+
+func printResult(w io.Writer, result *seed.Result) {
+	for _, e := range result.Events {
+		status := "EXISTS "
+		if e.Created {
+			status = "CREATED"
+		}
+
+		fmt.Fprintf(w, "%s %s: %s\n", status, e.EventID, e.Name)
+		fmt.Fprintf(
+			w, "        reservations: %d paid, %d held, %d expired, %d skipped\n",
+			e.Paid, e.Held, e.Expired, e.Skipped,
+		)
+	}
+
+	fmt.Fprintln(w, "\nDemo users:")
+	for _, u := range result.Users {
+		fmt.Fprintf(w, "  %s\n", u)
+	}
+}
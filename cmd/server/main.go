@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
@@ -14,6 +15,9 @@ import (
 )
 
 func main() {
+	migrate := flag.Bool("migrate", false, "Apply pending database migrations before start")
+	flag.Parse()
+
 	if err := config.LoadEnvFile(); err != nil {
 		die(err)
 	}
@@ -23,6 +27,10 @@ func main() {
 		die(err)
 	}
 
+	if *migrate {
+		cfg.DB.Migrate = true
+	}
+
 	logger, err := cfg.Log.BuildZapLogger()
 	if err != nil {
 		die(err)
@@ -55,6 +63,5 @@ func run(logger *zap.Logger, cfg *config.Config) error {
 		return fmt.Errorf("failed to build server: %w", err)
 	}
 
-	srv.ListenAndWait(ctx)
-	return nil
+	return srv.ListenAndWait(ctx)
 }
@@ -0,0 +1,345 @@
+package bookingtest
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"pgregory.net/rapid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+// modelTiers are tiers of events created by model test. Capacity is small, so sequences hit sold out quickly.
+var modelTiers = map[string]booking.CreateTierParams{
+	"A": {PriceCents: 20_00, TicketsCount: 4},
+	"B": {PriceCents: 10_00, TicketsCount: 3},
+}
+
+// maxExtensions is max number of reservation hold extensions.
+const maxExtensions = 3
+
+// RunLifecycleModel runs randomized sequences of reservation lifecycle operations against repository
+// and compares results with a reference model after each step.
+//
+// Failing sequences are shrunk to a minimal reproduction by rapid.
+// Use -rapid.checks and -rapid.steps flags to change number and length of sequences.
+func RunLifecycleModel(t *testing.T, newRepo RepositoryFactory) {
+	repo := newRepo(t)
+	rapid.Check(t, func(t *rapid.T) {
+		m := newLifecycleModel(t, repo)
+		t.Repeat(map[string]func(*rapid.T){
+			"":            m.check,
+			"reserve":     m.reserve,
+			"retry":       m.retry,
+			"pay":         m.pay,
+			"payDeclined": m.payDeclined,
+			"extend":      m.extend,
+			"modify":      m.modify,
+			"cancel":      m.cancel,
+			"expire":      m.expire,
+			"refund":      m.refund,
+		})
+	})
+}
+
+// modelReservation is expected reservation state.
+type modelReservation struct {
+	params     booking.ReservationParams
+	id         uuid.UUID
+	status     booking.ReservationStatus
+	extensions uint
+
+	// tickets are held or bought tickets per tier. Differ from requested ones after modification.
+	tickets map[uuid.UUID]uint
+}
+
+// lifecycleModel is a reference model of a single event inventory.
+type lifecycleModel struct {
+	repo    booking.Repository
+	eventID uuid.UUID
+
+	// tiers are tier IDs in stable order.
+	tiers     []uuid.UUID
+	tierNames map[uuid.UUID]string
+	available map[uuid.UUID]int
+
+	reservations []*modelReservation
+}
+
+func newLifecycleModel(t *rapid.T, repo booking.Repository) *lifecycleModel {
+	event, err := repo.CreateEvent(t.Context(), booking.EventCreateParams{
+		EventName: "Lifecycle model " + uuid.NewString(),
+		Tiers:     modelTiers,
+	})
+	require.NoError(t, err)
+
+	m := &lifecycleModel{
+		repo:      repo,
+		eventID:   event.EventID,
+		tierNames: make(map[uuid.UUID]string, len(event.Tiers)),
+		available: make(map[uuid.UUID]int, len(event.Tiers)),
+	}
+
+	for _, name := range []string{"A", "B"} {
+		tierID := event.Tiers[name]
+		m.tiers = append(m.tiers, tierID)
+		m.tierNames[tierID] = name
+		m.available[tierID] = modelTiers[name].TicketsCount
+	}
+
+	return m
+}
+
+// pick draws an existing reservation. Action is skipped if there are no reservations yet.
+func (m *lifecycleModel) pick(t *rapid.T) *modelReservation {
+	if len(m.reservations) == 0 {
+		t.Skip("no reservations")
+	}
+
+	return m.reservations[rapid.IntRange(0, len(m.reservations)-1).Draw(t, "reservation")]
+}
+
+// release returns reservation tickets to model inventory.
+func (m *lifecycleModel) release(r *modelReservation) {
+	for tierID, qty := range r.tickets {
+		m.available[tierID] += int(qty)
+	}
+}
+
+func (m *lifecycleModel) reserve(t *rapid.T) {
+	params := booking.ReservationParams{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		EventID:        m.eventID,
+		TicketsCount:   make(map[uuid.UUID]uint, len(m.tiers)),
+	}
+
+	enough := true
+	for _, tierID := range m.tiers {
+		qty := rapid.IntRange(0, 3).Draw(t, "qty "+m.tierNames[tierID])
+		if qty == 0 {
+			continue
+		}
+
+		params.TicketsCount[tierID] = uint(qty)
+		if qty > m.available[tierID] {
+			enough = false
+		}
+	}
+
+	if len(params.TicketsCount) == 0 {
+		t.Skip("empty reservation")
+	}
+
+	res, err := m.repo.ReserveTickets(t.Context(), params)
+	if !enough {
+		require.Error(t, err, "reservation should fail, available: %s", m.describeAvailable())
+		require.True(t, booking.IsInsufficientTicketsError(err), "unexpected error: %s", err)
+		return
+	}
+
+	require.NoError(t, err, "available: %s", m.describeAvailable())
+	for tierID, qty := range params.TicketsCount {
+		m.available[tierID] -= int(qty)
+	}
+
+	m.reservations = append(m.reservations, &modelReservation{
+		params:  params,
+		id:      res.ReservationID,
+		status:  booking.ReservationPending,
+		tickets: maps.Clone(params.TicketsCount),
+	})
+}
+
+// retry repeats reservation request with the same idempotency key.
+func (m *lifecycleModel) retry(t *rapid.T) {
+	r := m.pick(t)
+	res, err := m.repo.ReserveTickets(t.Context(), r.params)
+	require.NoError(t, err)
+	require.Equal(t, r.id, res.ReservationID, "retry should return the same reservation")
+}
+
+func (m *lifecycleModel) pay(t *rapid.T) {
+	r := m.pick(t)
+	_, err := m.repo.PayReservation(t.Context(), booking.PaymentParams{
+		ReservationID: r.id,
+		CardNumber:    booking.KnownFakeCard,
+	})
+
+	switch r.status {
+	case booking.ReservationPending:
+		require.NoError(t, err)
+		r.status = booking.ReservationPaid
+	case booking.ReservationExpired:
+		require.ErrorIs(t, err, booking.ErrReservationExpired)
+	default:
+		require.ErrorIs(t, err, booking.ErrInvalidStatus)
+	}
+}
+
+func (m *lifecycleModel) payDeclined(t *rapid.T) {
+	r := m.pick(t)
+	_, err := m.repo.PayReservation(t.Context(), booking.PaymentParams{
+		ReservationID: r.id,
+		CardNumber:    declinedCard,
+	})
+
+	switch r.status {
+	case booking.ReservationPending:
+		// Declined payment keeps the hold.
+		require.Error(t, err)
+		require.False(t, errors.Is(err, booking.ErrInvalidStatus), "unexpected error: %s", err)
+	case booking.ReservationExpired:
+		require.ErrorIs(t, err, booking.ErrReservationExpired)
+	default:
+		require.ErrorIs(t, err, booking.ErrInvalidStatus)
+	}
+}
+
+// extend extends reservation hold. Holds never reach max duration within a test run.
+func (m *lifecycleModel) extend(t *rapid.T) {
+	r := m.pick(t)
+	_, err := m.repo.ExtendReservation(t.Context(), r.id)
+
+	switch {
+	case r.status == booking.ReservationExpired:
+		require.ErrorIs(t, err, booking.ErrReservationExpired)
+	case r.status != booking.ReservationPending || r.extensions >= maxExtensions:
+		require.ErrorIs(t, err, booking.ErrInvalidStatus)
+	default:
+		require.NoError(t, err)
+		r.extensions++
+	}
+}
+
+// modify changes reservation quantities of all tiers.
+func (m *lifecycleModel) modify(t *rapid.T) {
+	r := m.pick(t)
+	target := make(map[uuid.UUID]uint, len(m.tiers))
+	enough := true
+	for _, tierID := range m.tiers {
+		qty := uint(rapid.IntRange(0, 3).Draw(t, "new qty "+m.tierNames[tierID]))
+		target[tierID] = qty
+		if qty > r.tickets[tierID] && int(qty-r.tickets[tierID]) > m.available[tierID] {
+			enough = false
+		}
+	}
+
+	_, err := m.repo.ModifyReservation(t.Context(), booking.ReservationModifyParams{
+		ReservationID: r.id,
+		TicketsCount:  target,
+	})
+
+	switch {
+	case r.status == booking.ReservationExpired:
+		require.ErrorIs(t, err, booking.ErrReservationExpired)
+	case r.status != booking.ReservationPending:
+		require.ErrorIs(t, err, booking.ErrInvalidStatus)
+	case target[m.tiers[0]]+target[m.tiers[1]] == 0:
+		require.ErrorIs(t, err, booking.ErrInvalidParams)
+	case !enough:
+		require.Error(t, err, "modification should fail, available: %s", m.describeAvailable())
+		require.True(t, booking.IsInsufficientTicketsError(err), "unexpected error: %s", err)
+	default:
+		require.NoError(t, err, "available: %s", m.describeAvailable())
+		m.release(r)
+		for tierID, qty := range target {
+			m.available[tierID] -= int(qty)
+		}
+
+		r.tickets = make(map[uuid.UUID]uint, len(target))
+		for tierID, qty := range target {
+			if qty > 0 {
+				r.tickets[tierID] = qty
+			}
+		}
+	}
+}
+
+func (m *lifecycleModel) cancel(t *rapid.T) {
+	r := m.pick(t)
+	err := m.repo.CancelReservation(t.Context(), r.id)
+	m.close(t, r, err, booking.ReservationCancelled)
+}
+
+func (m *lifecycleModel) expire(t *rapid.T) {
+	r := m.pick(t)
+	err := m.repo.ExpireReservation(t.Context(), r.id)
+	m.close(t, r, err, booking.ReservationExpired)
+}
+
+// close checks result of pending reservation cancellation or expiry.
+func (m *lifecycleModel) close(t *rapid.T, r *modelReservation, err error, status booking.ReservationStatus) {
+	if r.status != booking.ReservationPending {
+		require.ErrorIs(t, err, booking.ErrInvalidStatus)
+		return
+	}
+
+	require.NoError(t, err)
+	r.status = status
+	m.release(r)
+}
+
+func (m *lifecycleModel) refund(t *rapid.T) {
+	r := m.pick(t)
+	_, err := m.repo.RefundReservation(t.Context(), r.id)
+	if r.status != booking.ReservationPaid {
+		require.ErrorIs(t, err, booking.ErrInvalidStatus)
+		return
+	}
+
+	require.NoError(t, err)
+	r.status = booking.ReservationRefunded
+	m.release(r)
+}
+
+// check compares repository state with the model.
+func (m *lifecycleModel) check(t *rapid.T) {
+	tiers, err := m.repo.GetTicketTiers(t.Context(), m.eventID, "")
+	require.NoError(t, err)
+	require.Len(t, tiers, len(m.tiers))
+
+	for _, tier := range tiers {
+		require.Equal(
+			t, m.available[tier.TierID], tier.AvailableCount,
+			"available tickets of tier %s, model: %s", tier.Name, m.describeAvailable(),
+		)
+	}
+
+	for i, r := range m.reservations {
+		meta, err := m.repo.GetReservationEntries(t.Context(), r.id)
+		require.NoError(t, err)
+		require.Equal(t, r.status, meta.Status, "status of reservation #%d", i)
+		require.Equal(t, r.status == booking.ReservationPaid, meta.IsPaid, "payment flag of reservation #%d", i)
+		require.Equal(t, r.extensions, meta.Extensions, "extensions of reservation #%d", i)
+
+		items := make(map[uuid.UUID]uint, len(meta.Items))
+		for _, item := range meta.Items {
+			items[item.TierID] += item.Quantity
+		}
+
+		require.Equal(t, r.tickets, items, "line items of reservation #%d", i)
+
+		if r.status != booking.ReservationPaid {
+			continue
+		}
+
+		tickets, err := m.repo.GetReservationTickets(t.Context(), r.id)
+		require.NoError(t, err)
+
+		perTier := make(map[uuid.UUID]uint, len(r.tickets))
+		for _, ticket := range tickets {
+			perTier[ticket.TierID]++
+		}
+
+		require.Equal(t, r.tickets, perTier, "tickets of reservation #%d", i)
+	}
+}
+
+func (m *lifecycleModel) describeAvailable() string {
+	return fmt.Sprintf("A=%d B=%d", m.available[m.tiers[0]], m.available[m.tiers[1]])
+}
@@ -0,0 +1,827 @@
+// Package bookingtest provides conformance tests for booking.Repository implementations.
+package bookingtest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/pricing"
+)
+
+// declinedCard is a card number rejected by mock payment provider.
+const declinedCard = "4000000000000002"
+
+// RepositoryFactory returns repository under test.
+//
+// Repository might be shared between tests, each test creates its own events.
+type RepositoryFactory func(t *testing.T) booking.Repository
+
+// RunRepositoryTests runs conformance suite against a repository implementation.
+func RunRepositoryTests(t *testing.T, newRepo RepositoryFactory) {
+	tests := map[string]func(t *testing.T, repo booking.Repository){
+		"CreateEvent":            testCreateEvent,
+		"ReserveHoldsTickets":    testReserveHoldsTickets,
+		"ReserveIsIdempotent":    testReserveIsIdempotent,
+		"ReserveIsAllOrNothing":  testReserveIsAllOrNothing,
+		"ReserveUnknownTarget":   testReserveUnknownTarget,
+		"PayIssuesTickets":       testPayIssuesTickets,
+		"PayDeclinedCard":        testPayDeclinedCard,
+		"CancelReleasesTickets":  testCancelReleasesTickets,
+		"ExpireReleasesTickets":  testExpireReleasesTickets,
+		"ExtendMovesHold":        testExtendMovesHold,
+		"ModifyQuantities":       testModifyQuantities,
+		"ModifyKeepsPrices":      testModifyKeepsPrices,
+		"ConcurrentModify":       testConcurrentModify,
+		"RefundReturnsTickets":   testRefundReturnsTickets,
+		"UnknownReservation":     testUnknownReservation,
+		"ConcurrentReservations": testConcurrentReservations,
+		"ConcurrentRetries":      testConcurrentRetries,
+		"ConcurrentPayAndCancel": testConcurrentPayAndCancel,
+		"ExpireSweepKeepsActive": testExpireSweepKeepsActive,
+	}
+
+	for name, fn := range tests {
+		t.Run(name, func(t *testing.T) {
+			fn(t, newRepo(t))
+		})
+	}
+}
+
+// createEvent creates an event with specified tiers and returns event ID with tier IDs by name.
+func createEvent(t *testing.T, repo booking.Repository, tiers map[string]booking.CreateTierParams) *booking.EventCreateResult {
+	t.Helper()
+	event, err := repo.CreateEvent(t.Context(), booking.EventCreateParams{
+		EventName: fmt.Sprintf("%s %s", t.Name(), uuid.NewString()),
+		Tiers:     tiers,
+	})
+	require.NoError(t, err)
+	require.Len(t, event.Tiers, len(tiers))
+	return event
+}
+
+func availability(t *testing.T, repo booking.Repository, eventID uuid.UUID) map[uuid.UUID]int {
+	t.Helper()
+	tiers, err := repo.GetTicketTiers(t.Context(), eventID, "")
+	require.NoError(t, err)
+
+	result := make(map[uuid.UUID]int, len(tiers))
+	for _, tier := range tiers {
+		result[tier.TierID] = tier.AvailableCount
+	}
+
+	return result
+}
+
+func reserve(
+	ctx context.Context, repo booking.Repository, eventID uuid.UUID, tickets map[uuid.UUID]uint,
+) (*booking.ReservationResult, error) {
+	return repo.ReserveTickets(ctx, booking.ReservationParams{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		EventID:        eventID,
+		TicketsCount:   tickets,
+	})
+}
+
+func pay(ctx context.Context, repo booking.Repository, reservationID uuid.UUID) (*booking.PaymentResult, error) {
+	return repo.PayReservation(ctx, booking.PaymentParams{
+		ReservationID: reservationID,
+		CardNumber:    booking.KnownFakeCard,
+	})
+}
+
+func requireStatus(t *testing.T, repo booking.Repository, reservationID uuid.UUID, want booking.ReservationStatus) {
+	t.Helper()
+	meta, err := repo.GetReservationEntries(t.Context(), reservationID)
+	require.NoError(t, err)
+	require.Equal(t, want, meta.Status)
+}
+
+func testCreateEvent(t *testing.T, repo booking.Repository) {
+	event := createEvent(t, repo, map[string]booking.CreateTierParams{
+		"VIP": {PriceCents: 100_00, TicketsCount: 2},
+		"GA":  {PriceCents: 10_00, TicketsCount: 5},
+	})
+
+	events, err := repo.GetEvents(t.Context())
+	require.NoError(t, err)
+	require.True(t, containsEvent(events, event.EventID))
+
+	tiers, err := repo.GetTicketTiers(t.Context(), event.EventID, "")
+	require.NoError(t, err)
+	require.Len(t, tiers, 2)
+
+	// Tiers are ordered by price.
+	require.Equal(t, "GA", tiers[0].Name)
+	require.Equal(t, event.Tiers["GA"], tiers[0].TierID)
+	require.Equal(t, 10_00, tiers[0].PriceCents)
+	require.Equal(t, 5, tiers[0].AvailableCount)
+	require.Equal(t, booking.DefaultCurrency, tiers[0].Currency)
+	require.Equal(t, "VIP", tiers[1].Name)
+	require.Equal(t, 2, tiers[1].AvailableCount)
+
+	tiers, err = repo.GetTicketTiers(t.Context(), uuid.New(), "")
+	require.NoError(t, err)
+	require.Empty(t, tiers)
+
+	_, err = repo.CreateEvent(t.Context(), booking.EventCreateParams{
+		EventName: "Invalid currency",
+		Currency:  "XX",
+	})
+	require.ErrorIs(t, err, booking.ErrInvalidParams)
+}
+
+func containsEvent(events []*booking.Event, eventID uuid.UUID) bool {
+	for _, e := range events {
+		if e.ID == eventID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func testReserveHoldsTickets(t *testing.T, repo booking.Repository) {
+	event := createEvent(t, repo, map[string]booking.CreateTierParams{
+		"VIP": {PriceCents: 100_00, TicketsCount: 2},
+		"GA":  {PriceCents: 10_00, TicketsCount: 5},
+	})
+	vip, ga := event.Tiers["VIP"], event.Tiers["GA"]
+
+	res, err := reserve(t.Context(), repo, event.EventID, map[uuid.UUID]uint{vip: 1, ga: 3})
+	require.NoError(t, err)
+	require.NotEqual(t, uuid.Nil, res.ReservationID)
+	require.False(t, res.ExpiresAt.IsZero())
+	require.Equal(t, map[uuid.UUID]int{vip: 1, ga: 2}, availability(t, repo, event.EventID))
+
+	meta, err := repo.GetReservationEntries(t.Context(), res.ReservationID)
+	require.NoError(t, err)
+	require.Equal(t, event.EventID, meta.EventID)
+	require.Equal(t, booking.ReservationPending, meta.Status)
+	require.False(t, meta.IsPaid)
+	require.Equal(t, uint(130_00), meta.TotalCents)
+	require.Len(t, meta.Items, 2)
+	require.Equal(t, "VIP", meta.Items[0].TierName)
+	require.Equal(t, uint(1), meta.Items[0].Quantity)
+	require.Equal(t, uint(100_00), meta.Items[0].UnitPriceCents)
+	require.Equal(t, "GA", meta.Items[1].TierName)
+	require.Equal(t, uint(3), meta.Items[1].Quantity)
+
+	// Tickets are not issued until reservation is paid.
+	_, err = repo.GetReservationTickets(t.Context(), res.ReservationID)
+	require.ErrorIs(t, err, booking.ErrInvalidStatus)
+
+	_, err = repo.ReserveTickets(t.Context(), booking.ReservationParams{
+		IdempotencyKey: uuid.New(),
+		EventID:        event.EventID,
+		TicketsCount:   map[uuid.UUID]uint{ga: 1},
+		Email:          "not an email",
+	})
+	require.ErrorIs(t, err, booking.ErrInvalidParams)
+}
+
+func testReserveIsIdempotent(t *testing.T, repo booking.Repository) {
+	event := createEvent(t, repo, map[string]booking.CreateTierParams{
+		"GA": {PriceCents: 10_00, TicketsCount: 5},
+	})
+	ga := event.Tiers["GA"]
+
+	params := booking.ReservationParams{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		EventID:        event.EventID,
+		TicketsCount:   map[uuid.UUID]uint{ga: 2},
+	}
+
+	first, err := repo.ReserveTickets(t.Context(), params)
+	require.NoError(t, err)
+
+	second, err := repo.ReserveTickets(t.Context(), params)
+	require.NoError(t, err)
+	require.Equal(t, first.ReservationID, second.ReservationID)
+	require.Equal(t, 3, availability(t, repo, event.EventID)[ga])
+
+	// Retry returns the same reservation even after it's paid.
+	_, err = pay(t.Context(), repo, first.ReservationID)
+	require.NoError(t, err)
+
+	third, err := repo.ReserveTickets(t.Context(), params)
+	require.NoError(t, err)
+	require.Equal(t, first.ReservationID, third.ReservationID)
+	require.Equal(t, 3, availability(t, repo, event.EventID)[ga])
+}
+
+func testReserveIsAllOrNothing(t *testing.T, repo booking.Repository) {
+	event := createEvent(t, repo, map[string]booking.CreateTierParams{
+		"VIP": {PriceCents: 100_00, TicketsCount: 2},
+		"GA":  {PriceCents: 10_00, TicketsCount: 5},
+	})
+	vip, ga := event.Tiers["VIP"], event.Tiers["GA"]
+
+	params := booking.ReservationParams{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		EventID:        event.EventID,
+		TicketsCount:   map[uuid.UUID]uint{vip: 3, ga: 5},
+	}
+
+	_, err := repo.ReserveTickets(t.Context(), params)
+	require.Error(t, err)
+	require.True(t, booking.IsInsufficientTicketsError(err), err)
+	require.Equal(t, map[uuid.UUID]int{vip: 2, ga: 5}, availability(t, repo, event.EventID))
+
+	// Failed request doesn't consume idempotency key.
+	params.TicketsCount = map[uuid.UUID]uint{vip: 2, ga: 5}
+	_, err = repo.ReserveTickets(t.Context(), params)
+	require.NoError(t, err)
+	require.Equal(t, map[uuid.UUID]int{vip: 0, ga: 0}, availability(t, repo, event.EventID))
+
+	_, err = reserve(t.Context(), repo, event.EventID, map[uuid.UUID]uint{ga: 1})
+	require.True(t, booking.IsInsufficientTicketsError(err), err)
+}
+
+func testReserveUnknownTarget(t *testing.T, repo booking.Repository) {
+	event := createEvent(t, repo, map[string]booking.CreateTierParams{
+		"GA": {PriceCents: 10_00, TicketsCount: 5},
+	})
+	other := createEvent(t, repo, map[string]booking.CreateTierParams{
+		"GA": {PriceCents: 10_00, TicketsCount: 5},
+	})
+
+	_, err := reserve(t.Context(), repo, uuid.New(), map[uuid.UUID]uint{event.Tiers["GA"]: 1})
+	require.ErrorIs(t, err, booking.ErrNotFound)
+
+	_, err = reserve(t.Context(), repo, event.EventID, map[uuid.UUID]uint{uuid.New(): 1})
+	require.True(t, booking.IsInsufficientTicketsError(err), err)
+
+	// Tier of another event can't be booked.
+	_, err = reserve(t.Context(), repo, event.EventID, map[uuid.UUID]uint{other.Tiers["GA"]: 1})
+	require.True(t, booking.IsInsufficientTicketsError(err), err)
+	require.Equal(t, 5, availability(t, repo, other.EventID)[other.Tiers["GA"]])
+}
+
+func testPayIssuesTickets(t *testing.T, repo booking.Repository) {
+	event := createEvent(t, repo, map[string]booking.CreateTierParams{
+		"VIP": {PriceCents: 100_00, TicketsCount: 2},
+		"GA":  {PriceCents: 10_00, TicketsCount: 5},
+	})
+	vip, ga := event.Tiers["VIP"], event.Tiers["GA"]
+
+	res, err := reserve(t.Context(), repo, event.EventID, map[uuid.UUID]uint{vip: 1, ga: 2})
+	require.NoError(t, err)
+
+	payment, err := pay(t.Context(), repo, res.ReservationID)
+	require.NoError(t, err)
+	require.NotEqual(t, uuid.Nil, payment.TxID)
+	require.Equal(t, uint(120_00), payment.AmountCents)
+	require.Equal(t, booking.NewMoney(120_00, booking.DefaultCurrency), payment.Settlement)
+	require.Equal(t, payment.Settlement, payment.Presentment)
+
+	requireStatus(t, repo, res.ReservationID, booking.ReservationPaid)
+	require.Equal(t, map[uuid.UUID]int{vip: 1, ga: 3}, availability(t, repo, event.EventID))
+
+	tickets, err := repo.GetReservationTickets(t.Context(), res.ReservationID)
+	require.NoError(t, err)
+	require.Len(t, tickets, 3)
+
+	perTier := make(map[uuid.UUID]int)
+	seen := make(map[uuid.UUID]bool)
+	for _, ticket := range tickets {
+		require.False(t, seen[ticket.TicketID], "duplicate ticket %s", ticket.TicketID)
+		seen[ticket.TicketID] = true
+		perTier[ticket.TierID]++
+		require.Equal(t, event.EventID, ticket.EventID)
+		require.NotEmpty(t, ticket.Code)
+	}
+	require.Equal(t, map[uuid.UUID]int{vip: 1, ga: 2}, perTier)
+
+	_, err = pay(t.Context(), repo, res.ReservationID)
+	require.ErrorIs(t, err, booking.ErrInvalidStatus)
+
+	err = repo.CancelReservation(t.Context(), res.ReservationID)
+	require.ErrorIs(t, err, booking.ErrInvalidStatus)
+}
+
+func testPayDeclinedCard(t *testing.T, repo booking.Repository) {
+	event := createEvent(t, repo, map[string]booking.CreateTierParams{
+		"GA": {PriceCents: 10_00, TicketsCount: 5},
+	})
+
+	res, err := reserve(t.Context(), repo, event.EventID, map[uuid.UUID]uint{event.Tiers["GA"]: 2})
+	require.NoError(t, err)
+
+	_, err = repo.PayReservation(t.Context(), booking.PaymentParams{
+		ReservationID: res.ReservationID,
+		CardNumber:    declinedCard,
+	})
+	require.Error(t, err)
+
+	// Declined payment keeps the hold, so customer can retry with another card.
+	requireStatus(t, repo, res.ReservationID, booking.ReservationPending)
+	require.Equal(t, 3, availability(t, repo, event.EventID)[event.Tiers["GA"]])
+
+	_, err = pay(t.Context(), repo, res.ReservationID)
+	require.NoError(t, err)
+}
+
+func testCancelReleasesTickets(t *testing.T, repo booking.Repository) {
+	event := createEvent(t, repo, map[string]booking.CreateTierParams{
+		"GA": {PriceCents: 10_00, TicketsCount: 2},
+	})
+	ga := event.Tiers["GA"]
+
+	res, err := reserve(t.Context(), repo, event.EventID, map[uuid.UUID]uint{ga: 2})
+	require.NoError(t, err)
+	require.Zero(t, availability(t, repo, event.EventID)[ga])
+
+	require.NoError(t, repo.CancelReservation(t.Context(), res.ReservationID))
+	requireStatus(t, repo, res.ReservationID, booking.ReservationCancelled)
+	require.Equal(t, 2, availability(t, repo, event.EventID)[ga])
+
+	err = repo.CancelReservation(t.Context(), res.ReservationID)
+	require.ErrorIs(t, err, booking.ErrInvalidStatus)
+
+	_, err = pay(t.Context(), repo, res.ReservationID)
+	require.ErrorIs(t, err, booking.ErrInvalidStatus)
+
+	// Released tickets can be booked again.
+	_, err = reserve(t.Context(), repo, event.EventID, map[uuid.UUID]uint{ga: 2})
+	require.NoError(t, err)
+}
+
+func testExpireReleasesTickets(t *testing.T, repo booking.Repository) {
+	event := createEvent(t, repo, map[string]booking.CreateTierParams{
+		"GA": {PriceCents: 10_00, TicketsCount: 2},
+	})
+	ga := event.Tiers["GA"]
+
+	res, err := reserve(t.Context(), repo, event.EventID, map[uuid.UUID]uint{ga: 2})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.ExpireReservation(t.Context(), res.ReservationID))
+	requireStatus(t, repo, res.ReservationID, booking.ReservationExpired)
+	require.Equal(t, 2, availability(t, repo, event.EventID)[ga])
+
+	_, err = pay(t.Context(), repo, res.ReservationID)
+	require.ErrorIs(t, err, booking.ErrReservationExpired)
+
+	err = repo.ExpireReservation(t.Context(), res.ReservationID)
+	require.ErrorIs(t, err, booking.ErrInvalidStatus)
+}
+
+func testExtendMovesHold(t *testing.T, repo booking.Repository) {
+	const holdTTL = 2 * time.Minute
+
+	_, err := repo.CreateEvent(t.Context(), booking.EventCreateParams{
+		EventName:      t.Name(),
+		HoldTTLSeconds: 1,
+	})
+	require.ErrorIs(t, err, booking.ErrInvalidParams)
+
+	event, err := repo.CreateEvent(t.Context(), booking.EventCreateParams{
+		EventName:      fmt.Sprintf("%s %s", t.Name(), uuid.NewString()),
+		HoldTTLSeconds: uint(holdTTL.Seconds()),
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {PriceCents: 10_00, TicketsCount: 3},
+		},
+	})
+	require.NoError(t, err)
+	ga := event.Tiers["GA"]
+
+	res, err := reserve(t.Context(), repo, event.EventID, map[uuid.UUID]uint{ga: 2})
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(holdTTL), res.ExpiresAt, 5*time.Second)
+
+	expiresAt := res.ExpiresAt
+	for i := range 3 {
+		ext, err := repo.ExtendReservation(t.Context(), res.ReservationID)
+		require.NoError(t, err)
+		require.Equal(t, res.ReservationID, ext.ReservationID)
+		require.True(t, ext.ExpiresAt.After(expiresAt), "hold should be moved forward")
+		require.EqualValues(t, 2-i, ext.ExtensionsLeft)
+		expiresAt = ext.ExpiresAt
+
+		meta, err := repo.GetReservationEntries(t.Context(), res.ReservationID)
+		require.NoError(t, err)
+		require.WithinDuration(t, expiresAt, meta.ExpiresAt, time.Millisecond)
+		require.EqualValues(t, i+1, meta.Extensions)
+	}
+
+	_, err = repo.ExtendReservation(t.Context(), res.ReservationID)
+	require.ErrorIs(t, err, booking.ErrInvalidStatus, "number of extensions is limited")
+	require.Equal(t, 1, availability(t, repo, event.EventID)[ga], "tickets should stay held")
+
+	_, err = pay(t.Context(), repo, res.ReservationID)
+	require.NoError(t, err)
+
+	_, err = repo.ExtendReservation(t.Context(), res.ReservationID)
+	require.ErrorIs(t, err, booking.ErrInvalidStatus)
+
+	expired, err := reserve(t.Context(), repo, event.EventID, map[uuid.UUID]uint{ga: 1})
+	require.NoError(t, err)
+	require.NoError(t, repo.ExpireReservation(t.Context(), expired.ReservationID))
+
+	_, err = repo.ExtendReservation(t.Context(), expired.ReservationID)
+	require.ErrorIs(t, err, booking.ErrReservationExpired)
+}
+
+func testModifyQuantities(t *testing.T, repo booking.Repository) {
+	event := createEvent(t, repo, map[string]booking.CreateTierParams{
+		"VIP":       {PriceCents: 100_00, TicketsCount: 2},
+		"Front Row": {PriceCents: 50_00, TicketsCount: 3},
+		"GA":        {PriceCents: 10_00, TicketsCount: 5},
+	})
+	vip, frontRow, ga := event.Tiers["VIP"], event.Tiers["Front Row"], event.Tiers["GA"]
+
+	res, err := reserve(t.Context(), repo, event.EventID, map[uuid.UUID]uint{ga: 2})
+	require.NoError(t, err)
+
+	modify := func(tickets map[uuid.UUID]uint) (*booking.ReservationMeta, error) {
+		return repo.ModifyReservation(t.Context(), booking.ReservationModifyParams{
+			ReservationID: res.ReservationID,
+			TicketsCount:  tickets,
+		})
+	}
+
+	quantities := func(meta *booking.ReservationMeta) map[uuid.UUID]uint {
+		result := make(map[uuid.UUID]uint, len(meta.Items))
+		for _, item := range meta.Items {
+			result[item.TierID] += item.Quantity
+		}
+
+		return result
+	}
+
+	meta, err := modify(map[uuid.UUID]uint{ga: 3})
+	require.NoError(t, err)
+	require.Equal(t, map[uuid.UUID]uint{ga: 3}, quantities(meta))
+	require.EqualValues(t, 3*10_00, meta.TotalCents)
+	require.WithinDuration(t, res.ExpiresAt, meta.ExpiresAt, time.Millisecond, "expiry should not change")
+	require.Equal(t, 2, availability(t, repo, event.EventID)[ga])
+
+	// Switch tiers in a single change.
+	meta, err = modify(map[uuid.UUID]uint{ga: 0, vip: 2})
+	require.NoError(t, err)
+	require.Equal(t, map[uuid.UUID]uint{vip: 2}, quantities(meta))
+	require.EqualValues(t, 2*100_00, meta.TotalCents)
+
+	available := availability(t, repo, event.EventID)
+	require.Equal(t, 5, available[ga], "removed tickets should be released")
+	require.Zero(t, available[vip])
+
+	// Change is all-or-nothing.
+	_, err = modify(map[uuid.UUID]uint{vip: 1, frontRow: 4})
+	require.True(t, booking.IsInsufficientTicketsError(err), "unexpected error: %v", err)
+
+	_, err = modify(map[uuid.UUID]uint{uuid.New(): 1})
+	require.True(t, booking.IsInsufficientTicketsError(err), "unexpected error: %v", err)
+
+	meta, err = repo.GetReservationEntries(t.Context(), res.ReservationID)
+	require.NoError(t, err)
+	require.Equal(t, map[uuid.UUID]uint{vip: 2}, quantities(meta))
+	require.Equal(t, available, availability(t, repo, event.EventID))
+
+	_, err = modify(map[uuid.UUID]uint{vip: 0})
+	require.ErrorIs(t, err, booking.ErrInvalidParams, "reservation can't be emptied")
+
+	_, err = modify(nil)
+	require.ErrorIs(t, err, booking.ErrInvalidParams)
+
+	payment, err := pay(t.Context(), repo, res.ReservationID)
+	require.NoError(t, err)
+	require.EqualValues(t, 2*100_00, payment.AmountCents)
+
+	tickets, err := repo.GetReservationTickets(t.Context(), res.ReservationID)
+	require.NoError(t, err)
+	require.Len(t, tickets, 2)
+	for _, ticket := range tickets {
+		require.Equal(t, vip, ticket.TierID)
+	}
+
+	_, err = modify(map[uuid.UUID]uint{vip: 1})
+	require.ErrorIs(t, err, booking.ErrInvalidStatus)
+
+	expired, err := reserve(t.Context(), repo, event.EventID, map[uuid.UUID]uint{ga: 1})
+	require.NoError(t, err)
+	require.NoError(t, repo.ExpireReservation(t.Context(), expired.ReservationID))
+
+	_, err = repo.ModifyReservation(t.Context(), booking.ReservationModifyParams{
+		ReservationID: expired.ReservationID,
+		TicketsCount:  map[uuid.UUID]uint{ga: 2},
+	})
+	require.ErrorIs(t, err, booking.ErrReservationExpired)
+}
+
+func testRefundReturnsTickets(t *testing.T, repo booking.Repository) {
+	event := createEvent(t, repo, map[string]booking.CreateTierParams{
+		"GA": {PriceCents: 10_00, TicketsCount: 2},
+	})
+	ga := event.Tiers["GA"]
+
+	res, err := reserve(t.Context(), repo, event.EventID, map[uuid.UUID]uint{ga: 2})
+	require.NoError(t, err)
+
+	_, err = repo.RefundReservation(t.Context(), res.ReservationID)
+	require.ErrorIs(t, err, booking.ErrInvalidStatus)
+
+	payment, err := pay(t.Context(), repo, res.ReservationID)
+	require.NoError(t, err)
+
+	refund, err := repo.RefundReservation(t.Context(), res.ReservationID)
+	require.NoError(t, err)
+	require.Equal(t, payment.TxID, refund.TxID)
+	require.Equal(t, payment.Settlement, refund.Settlement)
+	requireStatus(t, repo, res.ReservationID, booking.ReservationRefunded)
+	require.Equal(t, 2, availability(t, repo, event.EventID)[ga])
+
+	_, err = repo.GetReservationTickets(t.Context(), res.ReservationID)
+	require.ErrorIs(t, err, booking.ErrInvalidStatus)
+
+	_, err = repo.RefundReservation(t.Context(), res.ReservationID)
+	require.ErrorIs(t, err, booking.ErrInvalidStatus)
+
+	// Returned tickets can be sold again.
+	again, err := reserve(t.Context(), repo, event.EventID, map[uuid.UUID]uint{ga: 2})
+	require.NoError(t, err)
+
+	_, err = pay(t.Context(), repo, again.ReservationID)
+	require.NoError(t, err)
+}
+
+func testUnknownReservation(t *testing.T, repo booking.Repository) {
+	ctx, id := t.Context(), uuid.New()
+
+	_, err := repo.GetReservationEntries(ctx, id)
+	require.ErrorIs(t, err, booking.ErrNotFound)
+
+	_, err = pay(ctx, repo, id)
+	require.ErrorIs(t, err, booking.ErrNotFound)
+
+	_, err = repo.GetReservationTickets(ctx, id)
+	require.ErrorIs(t, err, booking.ErrNotFound)
+
+	_, err = repo.ExtendReservation(ctx, id)
+	require.ErrorIs(t, err, booking.ErrNotFound)
+
+	_, err = repo.ModifyReservation(ctx, booking.ReservationModifyParams{
+		ReservationID: id,
+		TicketsCount:  map[uuid.UUID]uint{uuid.New(): 1},
+	})
+	require.ErrorIs(t, err, booking.ErrNotFound)
+
+	require.ErrorIs(t, repo.CancelReservation(ctx, id), booking.ErrNotFound)
+	require.ErrorIs(t, repo.ExpireReservation(ctx, id), booking.ErrNotFound)
+
+	_, err = repo.RefundReservation(ctx, id)
+	require.ErrorIs(t, err, booking.ErrNotFound)
+}
+
+func testConcurrentReservations(t *testing.T, repo booking.Repository) {
+	const (
+		capacity = 10
+		buyers   = 40
+	)
+
+	event := createEvent(t, repo, map[string]booking.CreateTierParams{
+		"GA": {PriceCents: 10_00, TicketsCount: capacity},
+	})
+	ga := event.Tiers["GA"]
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		paid []uuid.UUID
+	)
+
+	for range buyers {
+		wg.Go(func() {
+			res, err := reserve(t.Context(), repo, event.EventID, map[uuid.UUID]uint{ga: 1})
+			if err != nil {
+				if !booking.IsInsufficientTicketsError(err) {
+					t.Errorf("unexpected reservation error: %s", err)
+				}
+
+				return
+			}
+
+			if _, err := pay(t.Context(), repo, res.ReservationID); err != nil {
+				t.Errorf("failed to pay reservation %s: %s", res.ReservationID, err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			paid = append(paid, res.ReservationID)
+		})
+	}
+
+	wg.Wait()
+	require.Len(t, paid, capacity)
+	require.Zero(t, availability(t, repo, event.EventID)[ga])
+
+	sold := make(map[uuid.UUID]uuid.UUID)
+	for _, reservationID := range paid {
+		tickets, err := repo.GetReservationTickets(t.Context(), reservationID)
+		require.NoError(t, err)
+		require.Len(t, tickets, 1)
+
+		for _, ticket := range tickets {
+			owner, ok := sold[ticket.TicketID]
+			require.False(t, ok, "ticket %s is sold to %s and %s", ticket.TicketID, owner, reservationID)
+			sold[ticket.TicketID] = reservationID
+		}
+	}
+}
+
+func testModifyKeepsPrices(t *testing.T, repo booking.Repository) {
+	soldPercent := 30
+	event := createEvent(t, repo, map[string]booking.CreateTierParams{
+		"GA": {
+			PriceCents:   10_00,
+			TicketsCount: 10,
+			PricingRules: []pricing.Rule{
+				{Kind: pricing.RuleSoldPercent, SoldPercent: &soldPercent, AdjustmentBps: 5000},
+			},
+		},
+	})
+	ga := event.Tiers["GA"]
+
+	res, err := reserve(t.Context(), repo, event.EventID, map[uuid.UUID]uint{ga: 2})
+	require.NoError(t, err)
+
+	// Another reservation reaches the threshold, so the next tickets are more expensive.
+	_, err = reserve(t.Context(), repo, event.EventID, map[uuid.UUID]uint{ga: 1})
+	require.NoError(t, err)
+
+	meta, err := repo.ModifyReservation(t.Context(), booking.ReservationModifyParams{
+		ReservationID: res.ReservationID,
+		TicketsCount:  map[uuid.UUID]uint{ga: 4},
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 2*10_00+2*15_00, meta.TotalCents)
+	require.Len(t, meta.Items, 2)
+
+	// Held tickets are trimmed starting from the most expensive line.
+	meta, err = repo.ModifyReservation(t.Context(), booking.ReservationModifyParams{
+		ReservationID: res.ReservationID,
+		TicketsCount:  map[uuid.UUID]uint{ga: 1},
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 10_00, meta.TotalCents)
+	require.Len(t, meta.Items, 1)
+	require.EqualValues(t, 1, meta.Items[0].Quantity)
+}
+
+func testConcurrentModify(t *testing.T, repo booking.Repository) {
+	const (
+		holders  = 10
+		capacity = holders + 2
+	)
+
+	event := createEvent(t, repo, map[string]booking.CreateTierParams{
+		"GA": {PriceCents: 10_00, TicketsCount: capacity},
+	})
+	ga := event.Tiers["GA"]
+
+	ids := make([]uuid.UUID, 0, holders)
+	for range holders {
+		res, err := reserve(t.Context(), repo, event.EventID, map[uuid.UUID]uint{ga: 1})
+		require.NoError(t, err)
+		ids = append(ids, res.ReservationID)
+	}
+
+	// Every holder wants one more ticket, but only two are left.
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		upgraded int
+	)
+	for _, id := range ids {
+		wg.Go(func() {
+			_, err := repo.ModifyReservation(t.Context(), booking.ReservationModifyParams{
+				ReservationID: id,
+				TicketsCount:  map[uuid.UUID]uint{ga: 2},
+			})
+			if err != nil {
+				if !booking.IsInsufficientTicketsError(err) {
+					t.Errorf("unexpected modification error: %s", err)
+				}
+
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			upgraded++
+		})
+	}
+
+	wg.Wait()
+	require.Equal(t, capacity-holders, upgraded)
+	require.Zero(t, availability(t, repo, event.EventID)[ga])
+
+	var held uint
+	for _, id := range ids {
+		meta, err := repo.GetReservationEntries(t.Context(), id)
+		require.NoError(t, err)
+		require.Len(t, meta.Items, 1)
+		held += meta.Items[0].Quantity
+	}
+
+	require.EqualValues(t, capacity, held)
+}
+
+func testConcurrentRetries(t *testing.T, repo booking.Repository) {
+	const retries = 20
+
+	event := createEvent(t, repo, map[string]booking.CreateTierParams{
+		"GA": {PriceCents: 10_00, TicketsCount: 5},
+	})
+	ga := event.Tiers["GA"]
+
+	params := booking.ReservationParams{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		EventID:        event.EventID,
+		TicketsCount:   map[uuid.UUID]uint{ga: 2},
+	}
+
+	ids := make([]uuid.UUID, retries)
+	var wg sync.WaitGroup
+	for i := range retries {
+		wg.Go(func() {
+			res, err := repo.ReserveTickets(t.Context(), params)
+			if err != nil {
+				t.Errorf("retry %d failed: %s", i, err)
+				return
+			}
+
+			ids[i] = res.ReservationID
+		})
+	}
+
+	wg.Wait()
+	for _, id := range ids {
+		require.Equal(t, ids[0], id)
+	}
+
+	require.Equal(t, 3, availability(t, repo, event.EventID)[ga])
+}
+
+func testConcurrentPayAndCancel(t *testing.T, repo booking.Repository) {
+	event := createEvent(t, repo, map[string]booking.CreateTierParams{
+		"GA": {PriceCents: 10_00, TicketsCount: 20},
+	})
+	ga := event.Tiers["GA"]
+
+	for range 10 {
+		res, err := reserve(t.Context(), repo, event.EventID, map[uuid.UUID]uint{ga: 1})
+		require.NoError(t, err)
+
+		var payErr, cancelErr error
+		var wg sync.WaitGroup
+		wg.Go(func() {
+			_, payErr = pay(t.Context(), repo, res.ReservationID)
+		})
+		wg.Go(func() {
+			cancelErr = repo.CancelReservation(t.Context(), res.ReservationID)
+		})
+		wg.Wait()
+
+		// Exactly one of operations wins, the other one sees changed status.
+		meta, err := repo.GetReservationEntries(t.Context(), res.ReservationID)
+		require.NoError(t, err)
+		switch meta.Status {
+		case booking.ReservationPaid:
+			require.NoError(t, payErr)
+			require.ErrorIs(t, cancelErr, booking.ErrInvalidStatus)
+		case booking.ReservationCancelled:
+			require.NoError(t, cancelErr)
+			require.ErrorIs(t, payErr, booking.ErrInvalidStatus)
+		default:
+			t.Fatalf("unexpected reservation status %s", meta.Status)
+		}
+	}
+}
+
+func testExpireSweepKeepsActive(t *testing.T, repo booking.Repository) {
+	event := createEvent(t, repo, map[string]booking.CreateTierParams{
+		"GA": {PriceCents: 10_00, TicketsCount: 2},
+	})
+
+	res, err := reserve(t.Context(), repo, event.EventID, map[uuid.UUID]uint{event.Tiers["GA"]: 1})
+	require.NoError(t, err)
+
+	// Active holds are not affected by periodic expiry.
+	_, err = repo.ExpireReservations(t.Context())
+	require.NoError(t, err)
+	requireStatus(t, repo, res.ReservationID, booking.ReservationPending)
+	require.Equal(t, 1, availability(t, repo, event.EventID)[event.Tiers["GA"]])
+}
@@ -0,0 +1,298 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/ticketcode"
+)
+
+// maxCheckInBatchSize is max number of offline scans accepted in a single upload.
+const maxCheckInBatchSize = 1000
+
+type ticketScan struct {
+	scanID    *uuid.UUID
+	code      string
+	gateID    string
+	eventID   uuid.UUID
+	scannedAt time.Time
+	isOffline bool
+}
+
+// ticketCodeState is a scanned code with check-in state of its ticket.
+type ticketCodeState struct {
+	ID            uuid.UUID  `db:"id"`
+	TicketID      uuid.UUID  `db:"ticket_id"`
+	EventID       uuid.UUID  `db:"event_id"`
+	TierName      string     `db:"tier_name"`
+	RevokedAt     *time.Time `db:"revoked_at"`
+	Reissued      bool       `db:"reissued"`
+	CheckedInAt   *time.Time `db:"checked_in_at"`
+	CheckedInGate *string    `db:"checked_in_gate"`
+}
+
+// validAt reports whether code was valid at scan time.
+//
+// Code reissued to a new owner is valid for offline scans made before it was reissued.
+func (s ticketCodeState) validAt(t time.Time) bool {
+	return s.RevokedAt == nil || (s.Reissued && t.Before(*s.RevokedAt))
+}
+
+func (s ticketCodeState) result(status CheckInStatus) *CheckInResult {
+	r := &CheckInResult{
+		Status:      status,
+		TicketID:    &s.TicketID,
+		EventID:     &s.EventID,
+		TierName:    s.TierName,
+		CheckedInAt: s.CheckedInAt,
+	}
+
+	if s.CheckedInGate != nil {
+		r.GateID = *s.CheckedInGate
+	}
+
+	return r
+}
+
+// CheckIn validates ticket code and admits ticket holder.
+//
+// Ticket can be checked-in only once, repeated scans are reported as duplicates.
+func (svc Service) CheckIn(ctx context.Context, params CheckInParams) (*CheckInResult, error) {
+	if params.GateID == "" {
+		return nil, fmt.Errorf("%w: gate ID is required", ErrInvalidParams)
+	}
+
+	return svc.checkIn(ctx, ticketScan{
+		code:      params.Code,
+		gateID:    params.GateID,
+		eventID:   params.EventID,
+		scannedAt: time.Now(),
+	})
+}
+
+// CheckInBatch imports scans made by a gate while it was offline.
+//
+// Scans are applied in chronological order. When the same ticket was admitted by several gates,
+// the earliest scan wins and the rest are reported as duplicates. Already accepted later scan
+// is marked as superseded in scans log.
+//
+// Scans of codes which were reissued to a new owner afterwards are applied to the same ticket,
+// so the ticket can't be admitted again under the new code.
+// Repeated upload of the same scan returns its original result.
+//
+// Results are returned in the same order as passed scans.
+func (svc Service) CheckInBatch(ctx context.Context, params CheckInBatchParams) ([]*CheckInResult, error) {
+	if params.GateID == "" {
+		return nil, fmt.Errorf("%w: gate ID is required", ErrInvalidParams)
+	}
+
+	if len(params.Scans) > maxCheckInBatchSize {
+		return nil, fmt.Errorf("%w: too many scans, max batch size is %d", ErrInvalidParams, maxCheckInBatchSize)
+	}
+
+	now := time.Now()
+	order := make([]int, len(params.Scans))
+	for i, scan := range params.Scans {
+		if scan.ScanID == uuid.Nil {
+			return nil, fmt.Errorf("%w: scan #%d: scan ID is required", ErrInvalidParams, i)
+		}
+
+		if scan.ScannedAt.IsZero() {
+			return nil, fmt.Errorf("%w: scan #%d: scan time is required", ErrInvalidParams, i)
+		}
+
+		order[i] = i
+	}
+
+	slices.SortStableFunc(order, func(a, b int) int {
+		return params.Scans[a].ScannedAt.Compare(params.Scans[b].ScannedAt)
+	})
+
+	results := make([]*CheckInResult, len(params.Scans))
+	for _, i := range order {
+		scan := params.Scans[i]
+
+		// Device clock might be ahead of server.
+		scannedAt := scan.ScannedAt
+		if scannedAt.After(now) {
+			scannedAt = now
+		}
+
+		result, err := svc.checkIn(ctx, ticketScan{
+			scanID:    &scan.ScanID,
+			code:      scan.Code,
+			gateID:    params.GateID,
+			eventID:   params.EventID,
+			scannedAt: scannedAt,
+			isOffline: true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("scan %s: %w", scan.ScanID, err)
+		}
+
+		results[i] = result
+	}
+
+	return results, nil
+}
+
+// checkIn applies a single scan in a separate transaction.
+func (svc Service) checkIn(ctx context.Context, scan ticketScan) (*CheckInResult, error) {
+	tx, err := svc.store.begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	if scan.scanID != nil {
+		// Concurrent uploads of the same scan wait for each other, so retry gets result stored by the first one.
+		if err := tx.lockScan(ctx, *scan.scanID); err != nil {
+			return nil, err
+		}
+
+		result, err := getProcessedScan(ctx, tx, *scan.scanID)
+		if err != nil {
+			return nil, err
+		}
+
+		if result != nil {
+			return result, nil
+		}
+	}
+
+	result, codeID, err := svc.applyScan(ctx, tx, scan)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.insertScan(ctx, &checkInScan{
+		ScanID:    scan.scanID,
+		CodeID:    codeID,
+		GateID:    scan.gateID,
+		Status:    result.Status,
+		IsOffline: scan.isOffline,
+		ScannedAt: scan.scannedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	result.ScanID = scan.scanID
+	return result, nil
+}
+
+func (svc Service) applyScan(ctx context.Context, tx storeTx, scan ticketScan) (*CheckInResult, *uuid.UUID, error) {
+	claims, err := svc.codes.Verify(scan.code)
+	if err != nil {
+		if errors.Is(err, ticketcode.ErrInvalidCode) {
+			return &CheckInResult{Status: CheckInInvalid}, nil, nil
+		}
+
+		return nil, nil, err
+	}
+
+	// Ticket row is locked to serialize concurrent scans of all ticket codes.
+	state, err := tx.lockTicketCode(ctx, claims.ID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			// Signature is valid, but code was never issued.
+			return &CheckInResult{Status: CheckInInvalid}, nil, nil
+		}
+
+		return nil, nil, err
+	}
+
+	isValid := state.validAt(scan.scannedAt)
+	if isValid && state.RevokedAt != nil {
+		// Ticket might be refunded since the code was reissued, then its state belongs to another sale.
+		refunded, err := tx.isRefundedSince(ctx, state.TicketID, scan.scannedAt)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		isValid = !refunded
+	}
+
+	switch {
+	case scan.eventID != uuid.Nil && state.EventID != scan.eventID:
+		return state.result(CheckInWrongEvent), &state.ID, nil
+	case !isValid:
+		return state.result(CheckInRevoked), &state.ID, nil
+	case state.CheckedInAt != nil && !scan.scannedAt.Before(*state.CheckedInAt):
+		return state.result(CheckInDuplicate), &state.ID, nil
+	}
+
+	// Either first scan, or offline scan made before already recorded check-in.
+	if state.CheckedInAt != nil {
+		if err := tx.supersedeScans(ctx, state.TicketID, *state.CheckedInAt); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := tx.checkInTicket(ctx, state.TicketID, scan.scannedAt, scan.gateID); err != nil {
+		return nil, nil, err
+	}
+
+	state.CheckedInAt = &scan.scannedAt
+	state.CheckedInGate = &scan.gateID
+	return state.result(CheckInAccepted), &state.ID, nil
+}
+
+// getProcessedScan returns result of already uploaded scan or nil if scan is new.
+func getProcessedScan(ctx context.Context, q storeQueries, scanID uuid.UUID) (*CheckInResult, error) {
+	scan, err := q.getScan(ctx, scanID)
+	if err != nil || scan == nil {
+		return nil, err
+	}
+
+	result := &CheckInResult{Status: scan.Status}
+	if scan.CodeID != nil {
+		state, err := q.getTicketCodeState(ctx, *scan.CodeID)
+		if err != nil {
+			return nil, err
+		}
+
+		result = state.result(scan.Status)
+	}
+
+	result.ScanID = &scanID
+	return result, nil
+}
+
+// GetCheckInStats returns live check-in counters of an event.
+func (svc Service) GetCheckInStats(ctx context.Context, eventID uuid.UUID) (*CheckInStats, error) {
+	if _, err := svc.store.getEvent(ctx, eventID); err != nil {
+		return nil, err
+	}
+
+	rows, err := svc.store.getCheckInCounts(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &CheckInStats{
+		EventID: eventID,
+		Gates:   map[string]int{},
+	}
+	for _, row := range rows {
+		stats.Issued += row.Count
+		if row.GateID == nil {
+			continue
+		}
+
+		stats.CheckedIn += row.Count
+		stats.Gates[*row.GateID] = row.Count
+	}
+
+	return stats, nil
+}
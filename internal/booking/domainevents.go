@@ -0,0 +1,98 @@
+package booking
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DomainEventType is a type of booking state change.
+type DomainEventType string
+
+const (
+	ReservationCreatedEvent   DomainEventType = "reservation.created"
+	ReservationExpiringEvent  DomainEventType = "reservation.expiring"
+	ReservationExtendedEvent  DomainEventType = "reservation.extended"
+	ReservationModifiedEvent  DomainEventType = "reservation.modified"
+	ReservationExpiredEvent   DomainEventType = "reservation.expired"
+	ReservationCancelledEvent DomainEventType = "reservation.cancelled"
+	ReservationRefundedEvent  DomainEventType = "reservation.refunded"
+	PaymentSucceededEvent     DomainEventType = "payment.succeeded"
+	WaitlistOfferedEvent      DomainEventType = "waitlist.offered"
+	TransferCreatedEvent      DomainEventType = "transfer.created"
+	TransferAcceptedEvent     DomainEventType = "transfer.accepted"
+
+	// ResaleSoldEvent is emitted for seller when resale listing is paid by a buyer.
+	ResaleSoldEvent DomainEventType = "resale.sold"
+
+	// EventCancelledEvent is emitted for each active reservation of a cancelled event.
+	EventCancelledEvent DomainEventType = "event.cancelled"
+)
+
+// IsValid reports whether event type is known.
+func (t DomainEventType) IsValid() bool {
+	switch t {
+	case ReservationCreatedEvent, ReservationExpiringEvent, ReservationExtendedEvent, ReservationModifiedEvent,
+		ReservationExpiredEvent, ReservationCancelledEvent, ReservationRefundedEvent, PaymentSucceededEvent,
+		WaitlistOfferedEvent, EventCancelledEvent, TransferCreatedEvent, TransferAcceptedEvent, ResaleSoldEvent:
+		return true
+	default:
+		return false
+	}
+}
+
+// DomainEvent describes a state change of a reservation.
+//
+// Events are written to the outbox in the same transaction as the change and are
+// delivered at least once, so consumers should deduplicate them by ID.
+type DomainEvent struct {
+	ID         uuid.UUID       `json:"id"`
+	Type       DomainEventType `json:"type"`
+	OccurredAt time.Time       `json:"occurredAt"`
+
+	EventID       uuid.UUID `json:"eventID"`
+	ReservationID uuid.UUID `json:"reservationID"`
+	ActorID       uuid.UUID `json:"actorID"`
+
+	// Email is optional customer contact email.
+	Email string `json:"email,omitempty"`
+
+	// Amount is charged or refunded amount for payment and refund events
+	// and new reservation total for modification events.
+	Amount *Money `json:"amount,omitempty"`
+
+	// ExpiresAt is reservation hold expiration time for pending reservations.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	// TransferID is ticket transfer ID for transfer events.
+	TransferID *uuid.UUID `json:"transferID,omitempty"`
+
+	// ListingID is resale listing ID for resale events.
+	ListingID *uuid.UUID `json:"listingID,omitempty"`
+}
+
+// reservationRef contains reservation fields used to build domain events.
+type reservationRef struct {
+	ID        uuid.UUID `db:"id"`
+	EventID   uuid.UUID `db:"event_id"`
+	ActorID   uuid.UUID `db:"actor_id"`
+	Email     *string   `db:"contact_email"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+func (r reservationRef) event(t DomainEventType) DomainEvent {
+	e := DomainEvent{
+		ID:            uuid.New(),
+		Type:          t,
+		OccurredAt:    time.Now(),
+		EventID:       r.EventID,
+		ReservationID: r.ID,
+		ActorID:       r.ActorID,
+	}
+
+	if r.Email != nil {
+		e.Email = *r.Email
+	}
+
+	return e
+}
@@ -10,6 +10,10 @@ import (
 var (
 	ErrNotFound           = errors.New("not found")
 	ErrReservationExpired = errors.New("reservation is expired")
+	ErrInvalidParams      = errors.New("invalid parameters")
+	ErrRateNotFound       = errors.New("exchange rate not found")
+	ErrInvalidStatus      = errors.New("operation is not allowed in current status")
+	ErrAlreadyWaitlisted  = errors.New("already in waitlist")
 )
 
 type InsufficientTicketsError struct {
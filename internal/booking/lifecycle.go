@@ -0,0 +1,392 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// expireBatchSize is max number of reservations processed by a single expiry sweep.
+	expireBatchSize = 500
+
+	// maxHoldExtensions is max number of times reservation hold can be extended.
+	maxHoldExtensions = 3
+
+	// maxHoldDuration is a hard cap on total reservation hold time since reservation was created.
+	maxHoldDuration = time.Hour
+)
+
+// CancelReservation cancels pending reservation and releases held tickets.
+//
+// Released tickets are offered to tier waitlist first.
+func (svc Service) CancelReservation(ctx context.Context, reservationID uuid.UUID) error {
+	return svc.closeReservation(ctx, reservationID, ReservationCancelled)
+}
+
+// ExpireReservation expires pending reservation immediately, regardless of its hold expiration time.
+//
+// Used by operators to release stuck holds. Released tickets are offered to tier waitlist first.
+func (svc Service) ExpireReservation(ctx context.Context, reservationID uuid.UUID) error {
+	return svc.closeReservation(ctx, reservationID, ReservationExpired)
+}
+
+// ExtendReservation extends hold of pending reservation by event hold TTL starting from now.
+//
+// Hold can be extended up to maxHoldExtensions times and never past maxHoldDuration since reservation was created.
+// Reservation and held tickets expiration times are updated in a single transaction.
+func (svc Service) ExtendReservation(ctx context.Context, reservationID uuid.UUID) (*ExtensionResult, error) {
+	tx, err := svc.store.begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// Reservation row lock serializes extension with payment, cancellation and expiry.
+	h, err := tx.lockReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := tx.getEvent(ctx, h.EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(event.HoldTTLSeconds) * time.Second
+	expireAt, err := extendHold(h.Status, h.CreatedAt, h.ExpiresAt, h.Extensions, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.setHoldExpiration(ctx, reservationID, expireAt); err != nil {
+		return nil, err
+	}
+
+	extendedEvent := h.event(ReservationExtendedEvent)
+	extendedEvent.ExpiresAt = &expireAt
+	if err := tx.writeOutbox(ctx, extendedEvent); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &ExtensionResult{
+		ReservationID:  reservationID,
+		ExpiresAt:      expireAt,
+		ExtensionsLeft: maxHoldExtensions - h.Extensions - 1,
+	}, nil
+}
+
+// extendHold checks whether reservation hold can be extended and returns new hold expiration time.
+func extendHold(
+	status ReservationStatus, createdAt, expiresAt time.Time, extensions uint, ttl time.Duration,
+) (time.Time, error) {
+	now := time.Now()
+	if err := checkPendingHold(status, expiresAt, now); err != nil {
+		return time.Time{}, err
+	}
+
+	if extensions >= maxHoldExtensions {
+		return time.Time{}, fmt.Errorf("%w: hold can be extended at most %d times", ErrInvalidStatus, maxHoldExtensions)
+	}
+
+	expireAt := now.Add(ttl)
+	if deadline := createdAt.Add(maxHoldDuration); expireAt.After(deadline) {
+		expireAt = deadline
+	}
+
+	if !expireAt.After(expiresAt) {
+		return time.Time{}, fmt.Errorf("%w: hold reached max duration", ErrInvalidStatus)
+	}
+
+	return expireAt, nil
+}
+
+// checkPendingHold checks that reservation is pending and its hold didn't lapse yet.
+func checkPendingHold(status ReservationStatus, expiresAt, now time.Time) error {
+	switch status {
+	case ReservationPending:
+	case ReservationExpired:
+		return ErrReservationExpired
+	default:
+		return fmt.Errorf("%w: reservation is %s", ErrInvalidStatus, status)
+	}
+
+	if now.After(expiresAt) {
+		return ErrReservationExpired
+	}
+
+	return nil
+}
+
+// closeReservation moves pending reservation to cancelled or expired status and releases held tickets.
+func (svc Service) closeReservation(ctx context.Context, reservationID uuid.UUID, status ReservationStatus) error {
+	eventType, waitlistStatus := ReservationCancelledEvent, WaitlistCancelled
+	if status == ReservationExpired {
+		eventType, waitlistStatus = ReservationExpiredEvent, WaitlistExpired
+	}
+
+	tx, err := svc.store.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	h, err := tx.lockReservation(ctx, reservationID)
+	if err != nil {
+		return err
+	}
+
+	if h.Status != ReservationPending {
+		return fmt.Errorf("%w: reservation is %s", ErrInvalidStatus, h.Status)
+	}
+
+	tierIDs, err := tx.releaseHeldTickets(ctx, reservationID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.releaseHeldListings(ctx, reservationID); err != nil {
+		return err
+	}
+
+	if err := tx.setReservationStatus(ctx, reservationID, status); err != nil {
+		return err
+	}
+
+	if err := tx.resolveWaitlistOffers(ctx, waitlistStatus, reservationID); err != nil {
+		return err
+	}
+
+	if err := repriceTiers(ctx, tx, tierIDs, priceReasonRelease); err != nil {
+		return err
+	}
+
+	offers, err := svc.offerWaitlist(ctx, tx, tierIDs)
+	if err != nil {
+		return err
+	}
+
+	events := append([]DomainEvent{h.event(eventType)}, waitlistOfferEvents(offers)...)
+	if err := tx.writeOutbox(ctx, events...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// RefundReservation refunds paid reservation and returns sold tickets back to inventory.
+//
+// Returned tickets are offered to tier waitlist first.
+// Resale purchases are not refundable, as seller is already paid out.
+// Payment provider is called after reservation is refunded, failed refunds are retried later.
+func (svc Service) RefundReservation(ctx context.Context, reservationID uuid.UUID) (*RefundResult, error) {
+	tx, err := svc.store.begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	h, err := tx.lockReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.Status != ReservationPaid {
+		return nil, fmt.Errorf("%w: reservation is %s", ErrInvalidStatus, h.Status)
+	}
+
+	// Seller is already paid out, so resold tickets can't be returned to inventory.
+	if h.ResaleListingID != nil {
+		return nil, fmt.Errorf("%w: resale reservation can't be refunded", ErrInvalidStatus)
+	}
+
+	// Refund would invalidate tickets which now belong to other users.
+	isTransferred, err := tx.hasForeignCodes(ctx, reservationID, h.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if isTransferred {
+		return nil, fmt.Errorf("%w: reservation tickets were transferred", ErrInvalidStatus)
+	}
+
+	// Resold tickets are moved to buyer's reservation.
+	isResold, err := isResold(ctx, tx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if isResold {
+		return nil, fmt.Errorf("%w: reservation tickets were resold", ErrInvalidStatus)
+	}
+
+	if err := tx.cancelReservationTransfers(ctx, reservationID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.cancelReservationListings(ctx, reservationID); err != nil {
+		return nil, err
+	}
+
+	payment, err := tx.lockRefundablePayment(ctx, reservationID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("%w: no payment found for reservation", ErrInvalidStatus)
+		}
+
+		return nil, err
+	}
+
+	tierIDs, err := tx.returnSoldTickets(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.setReservationStatus(ctx, reservationID, ReservationRefunded); err != nil {
+		return nil, err
+	}
+
+	if err := tx.setPaymentRefunded(ctx, payment.TxID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.revokeReservationCodes(ctx, reservationID); err != nil {
+		return nil, err
+	}
+
+	if err := repriceTiers(ctx, tx, tierIDs, priceReasonRelease); err != nil {
+		return nil, err
+	}
+
+	offers, err := svc.offerWaitlist(ctx, tx, tierIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	refundEvent := h.event(ReservationRefundedEvent)
+	refundEvent.Amount = &payment.Settlement
+	events := append([]DomainEvent{refundEvent}, waitlistOfferEvents(offers)...)
+	if err := tx.writeOutbox(ctx, events...); err != nil {
+		return nil, err
+	}
+
+	if err := tx.insertPendingRefund(ctx, payment.TxID, reservationID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Money is returned only after refund is committed, so failed commit never refunds still sold tickets.
+	// If payment provider fails, refund stays pending and is retried by RetryRefunds.
+	result := &RefundResult{
+		TxID:       payment.TxID,
+		Settlement: payment.Settlement,
+	}
+	if _, err := svc.issueRefund(ctx, payment.TxID); err != nil {
+		result.IsPending = true
+	}
+
+	return result, nil
+}
+
+// isResold reports whether some of reservation tickets were resold to another reservation.
+func isResold(ctx context.Context, q storeQueries, reservationID uuid.UUID) (bool, error) {
+	items, err := q.getReservationItems(ctx, reservationID)
+	if err != nil {
+		return false, err
+	}
+
+	tickets, err := q.getSoldTickets(ctx, reservationID)
+	if err != nil {
+		return false, err
+	}
+
+	var quantity uint
+	for _, item := range items {
+		quantity += item.Quantity
+	}
+
+	return quantity > uint(len(tickets)), nil
+}
+
+// ExpireReservations marks outdated pending reservations as expired and releases their tickets.
+//
+// Released tickets are offered to tier waitlist. Method is called periodically by a background worker.
+// Returns number of expired reservations.
+func (svc Service) ExpireReservations(ctx context.Context) (int, error) {
+	tx, err := svc.store.begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// Reservations locked by concurrent payment or cancellation are skipped till the next run.
+	expired, err := tx.expireReservations(ctx, expireBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	expiredIDs := make([]uuid.UUID, 0, len(expired))
+	for _, r := range expired {
+		expiredIDs = append(expiredIDs, r.ID)
+	}
+
+	tierIDs, err := tx.releaseHeldTickets(ctx, expiredIDs...)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.releaseHeldListings(ctx, expiredIDs...); err != nil {
+		return 0, err
+	}
+
+	if err := tx.resolveWaitlistOffers(ctx, WaitlistExpired, expiredIDs...); err != nil {
+		return 0, err
+	}
+
+	if err := repriceTiers(ctx, tx, tierIDs, priceReasonRelease); err != nil {
+		return 0, err
+	}
+
+	offers, err := svc.offerWaitlist(ctx, tx, tierIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	events := make([]DomainEvent, 0, len(expired)+len(offers))
+	for _, r := range expired {
+		events = append(events, r.event(ReservationExpiredEvent))
+	}
+
+	events = append(events, waitlistOfferEvents(offers)...)
+	if err := tx.writeOutbox(ctx, events...); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(expired), nil
+}
@@ -0,0 +1,258 @@
+package booking
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// quantityChange is a change of reservation tier quantity.
+type quantityChange struct {
+	tierID  uuid.UUID
+	current uint
+	target  uint
+}
+
+// planQuantityChanges returns tier quantity changes which should be applied to reservation items.
+//
+// Tiers with unchanged quantity are omitted.
+func planQuantityChanges(current, target map[uuid.UUID]uint) ([]quantityChange, error) {
+	if len(target) == 0 {
+		return nil, fmt.Errorf("%w: no tickets count specified", ErrInvalidParams)
+	}
+
+	var (
+		changes []quantityChange
+		total   uint
+	)
+	for tierID, qty := range current {
+		if _, ok := target[tierID]; !ok {
+			total += qty
+		}
+	}
+
+	for tierID, qty := range target {
+		total += qty
+		if qty != current[tierID] {
+			changes = append(changes, quantityChange{tierID: tierID, current: current[tierID], target: qty})
+		}
+	}
+
+	if total == 0 {
+		return nil, fmt.Errorf("%w: reservation should keep at least one ticket, cancel it instead", ErrInvalidParams)
+	}
+
+	// Changes are applied in stable order, so concurrent modifications lock tickets in the same order.
+	slices.SortFunc(changes, func(a, b quantityChange) int {
+		return slices.Compare(a.tierID[:], b.tierID[:])
+	})
+
+	return changes, nil
+}
+
+// ModifyReservation changes ticket quantities of a pending reservation.
+//
+// Added tickets are held the same way as by ReserveTickets, removed tickets are released immediately
+// and offered to tier waitlist first. Hold expiration time is not changed.
+// Change is all-or-nothing: if any tier can't be satisfied, reservation is left intact.
+func (svc Service) ModifyReservation(ctx context.Context, params ReservationModifyParams) (*ReservationMeta, error) {
+	// Reservation event never changes, so it's safe to read it without a lock.
+	ref, err := svc.store.getReservationHeader(ctx, params.ReservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := svc.store.begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// Event is locked before reservation, in the same order as by CancelEvent,
+	// so event cancellation waits for in-flight modifications.
+	event, err := tx.shareEvent(ctx, ref.EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reservation row lock serializes modification with payment, cancellation and expiry.
+	h, err := tx.lockReservation(ctx, params.ReservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := checkPendingHold(h.Status, h.ExpiresAt, now); err != nil {
+		return nil, err
+	}
+
+	if event.CancelledAt != nil {
+		return nil, fmt.Errorf("%w: event is cancelled", ErrInvalidStatus)
+	}
+
+	if h.ResaleListingID != nil {
+		return nil, fmt.Errorf("%w: resale reservation can't be modified", ErrInvalidStatus)
+	}
+
+	isOffer, err := tx.isWaitlistOffer(ctx, h.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if isOffer {
+		return nil, fmt.Errorf("%w: waitlist offer can't be modified", ErrInvalidStatus)
+	}
+
+	items, err := tx.getReservationItems(ctx, h.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := planQuantityChanges(itemQuantities(items), params.TicketsCount)
+	if err != nil {
+		return nil, err
+	}
+
+	var addedTierIDs []uuid.UUID
+	for _, c := range changes {
+		if c.target > c.current {
+			addedTierIDs = append(addedTierIDs, c.tierID)
+		}
+	}
+
+	tiers, err := tx.getTierPricing(ctx, addedTierIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	waitlisted, err := tx.getWaitlistedTiers(ctx, addedTierIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var releasedTierIDs []uuid.UUID
+	for _, c := range changes {
+		if c.target < c.current {
+			if err := releaseTierTickets(ctx, tx, h.ID, items, c); err != nil {
+				return nil, err
+			}
+
+			releasedTierIDs = append(releasedTierIDs, c.tierID)
+			continue
+		}
+
+		tier, ok := tiers[c.tierID]
+		if !ok || tier.EventID != h.EventID || waitlisted[c.tierID] {
+			return nil, NewInsufficientTicketsError(c.tierID)
+		}
+
+		_, ok, err := holdTierTickets(ctx, tx, tier, h.ID, c.target-c.current, h.ExpiresAt, now)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			return nil, NewInsufficientTicketsError(c.tierID)
+		}
+	}
+
+	items, err = tx.getReservationItems(ctx, h.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var totalCents uint
+	for _, item := range items {
+		totalCents += item.Quantity * item.UnitPriceCents
+	}
+
+	if err := tx.setReservationTotal(ctx, h.ID, totalCents); err != nil {
+		return nil, err
+	}
+
+	if err := repriceTiers(ctx, tx, releasedTierIDs, priceReasonRelease); err != nil {
+		return nil, err
+	}
+
+	offers, err := svc.offerWaitlist(ctx, tx, releasedTierIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	total := NewMoney(int64(totalCents), h.Currency)
+	modifiedEvent := h.event(ReservationModifiedEvent)
+	modifiedEvent.Amount = &total
+	events := append([]DomainEvent{modifiedEvent}, waitlistOfferEvents(offers)...)
+	if err := tx.writeOutbox(ctx, events...); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return svc.GetReservationEntries(ctx, h.ID)
+}
+
+// releaseTierTickets releases part of tier tickets held by reservation and updates reservation line items.
+func releaseTierTickets(
+	ctx context.Context, tx storeTx, reservationID uuid.UUID, items []*ReservationItem, c quantityChange,
+) error {
+	if err := tx.releaseTierTickets(ctx, reservationID, c.tierID, c.current-c.target); err != nil {
+		return err
+	}
+
+	for _, line := range trimTierLines(items, c.tierID, c.current-c.target) {
+		if err := tx.setReservationItem(ctx, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// itemQuantities returns reservation quantities by tier.
+func itemQuantities(items []*ReservationItem) map[uuid.UUID]uint {
+	result := make(map[uuid.UUID]uint, len(items))
+	for _, item := range items {
+		result[item.TierID] += item.Quantity
+	}
+
+	return result
+}
+
+// trimTierLines removes count tickets from tier line items and returns changed items.
+//
+// Tier has a line per locked unit price. The most expensive lines are trimmed first,
+// so reservation keeps the cheapest tickets. Items left with zero quantity should be deleted.
+func trimTierLines(items []*ReservationItem, tierID uuid.UUID, count uint) []*ReservationItem {
+	var lines []*ReservationItem
+	for _, item := range items {
+		if item.TierID == tierID {
+			lines = append(lines, item)
+		}
+	}
+
+	slices.SortFunc(lines, func(a, b *ReservationItem) int {
+		return cmp.Compare(b.UnitPriceCents, a.UnitPriceCents)
+	})
+
+	changed := make([]*ReservationItem, 0, len(lines))
+	for _, line := range lines {
+		if count == 0 {
+			break
+		}
+
+		n := min(count, line.Quantity)
+		line.Quantity -= n
+		count -= n
+		changed = append(changed, line)
+	}
+
+	return changed
+}
@@ -0,0 +1,253 @@
+package booking
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// DefaultCurrency is a settlement currency used when event currency is not specified.
+const DefaultCurrency Currency = "USD"
+
+// Currency is ISO 4217 alphabetic currency code.
+type Currency string
+
+// currencyExponents contains number of minor units of currencies which don't use cents.
+//
+// All other currencies are assumed to have 2 decimal places.
+var currencyExponents = map[Currency]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// knownCurrencies are active ISO 4217 currency codes.
+//
+// Fund codes, precious metals and testing codes are not accepted.
+var knownCurrencies = newCurrencySet(`
+	AED AFN ALL AMD AOA ARS AUD AWG AZN BAM BBD BDT BGN BHD BIF BMD BND BOB BRL BSD BTN BWP BYN BZD
+	CAD CDF CHF CLP CNY COP CRC CUP CVE CZK DJF DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS GIP
+	GMD GNF GTQ GYD HKD HNL HTG HUF IDR ILS INR IQD IRR ISK JMD JOD JPY KES KGS KHR KMF KPW KRW KWD
+	KYD KZT LAK LBP LKR LRD LSL LYD MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MYR MZN NAD NGN
+	NIO NOK NPR NZD OMR PAB PEN PGK PHP PKR PLN PYG QAR RON RSD RUB RWF SAR SBD SCR SDG SEK SGD SHP
+	SLE SOS SRD SSP STN SVC SYP SZL THB TJS TMT TND TOP TRY TTD TWD TZS UAH UGX USD UYI UYU UZS VED
+	VES VND VUV WST XAF XCD XCG XOF XPF YER ZAR ZMW ZWG
+`)
+
+func newCurrencySet(codes string) map[Currency]bool {
+	result := make(map[Currency]bool)
+	for _, code := range strings.Fields(codes) {
+		result[Currency(code)] = true
+	}
+
+	return result
+}
+
+// ParseCurrency parses and validates currency code.
+//
+// Code is case-insensitive and should be a known ISO 4217 currency.
+func ParseCurrency(s string) (Currency, error) {
+	code := strings.ToUpper(strings.TrimSpace(s))
+	if len(code) != 3 {
+		return "", fmt.Errorf("%w: invalid currency code %q", ErrInvalidParams, s)
+	}
+
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return "", fmt.Errorf("%w: invalid currency code %q", ErrInvalidParams, s)
+		}
+	}
+
+	if !knownCurrencies[Currency(code)] {
+		return "", fmt.Errorf("%w: unknown currency %q", ErrInvalidParams, s)
+	}
+
+	return Currency(code), nil
+}
+
+// Exponent returns number of digits after decimal separator of a currency minor unit.
+func (c Currency) Exponent() int {
+	exp, ok := currencyExponents[c]
+	if !ok {
+		return 2
+	}
+
+	return exp
+}
+
+// Money is an amount in currency minor units (e.g. cents).
+type Money struct {
+	Amount   int64    `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+// NewMoney returns a new money amount.
+func NewMoney(amount int64, currency Currency) Money {
+	return Money{
+		Amount:   amount,
+		Currency: currency,
+	}
+}
+
+// String returns amount formatted as decimal with currency code, e.g. "10.50 USD".
+func (m Money) String() string {
+	exp := m.Currency.Exponent()
+	if exp == 0 {
+		return fmt.Sprintf("%d %s", m.Amount, m.Currency)
+	}
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	div := pow10(exp)
+	return fmt.Sprintf("%s%d.%0*d %s", sign, amount/div, exp, amount%div, m.Currency)
+}
+
+// IncludedTax returns tax amount included in a tax-inclusive amount.
+//
+// Rate is in basis points. Result is rounded to the nearest minor unit, halves are rounded up.
+func (m Money) IncludedTax(rateBps uint) Money {
+	if rateBps == 0 {
+		return NewMoney(0, m.Currency)
+	}
+
+	// tax = amount * rate / (1 + rate)
+	rate := int64(rateBps)
+	denom := 10_000 + rate
+	return NewMoney((2*m.Amount*rate+denom)/(2*denom), m.Currency)
+}
+
+// rateDecimals is number of decimal places of stored exchange rates.
+//
+// Should match scale of "payments.exchange_rate" column.
+const rateDecimals = 12
+
+// Rate is an exchange rate between two currencies.
+//
+// Value is the amount of To currency major units for one major unit of From currency.
+type Rate struct {
+	From  Currency
+	To    Currency
+	Value *big.Rat
+}
+
+// ParseRate parses decimal exchange rate value, e.g. "0.9215".
+func ParseRate(from, to Currency, value string) (*Rate, error) {
+	v, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return nil, fmt.Errorf("invalid exchange rate %q for %s/%s", value, from, to)
+	}
+
+	if v.Sign() <= 0 {
+		return nil, fmt.Errorf("exchange rate for %s/%s should be positive", from, to)
+	}
+
+	return &Rate{
+		From:  from,
+		To:    to,
+		Value: v,
+	}, nil
+}
+
+// identityRate returns a rate used to convert currency to itself.
+func identityRate(c Currency) *Rate {
+	return &Rate{
+		From:  c,
+		To:    c,
+		Value: big.NewRat(1, 1),
+	}
+}
+
+// Inverse returns a reversed exchange rate.
+func (r Rate) Inverse() *Rate {
+	return &Rate{
+		From:  r.To,
+		To:    r.From,
+		Value: new(big.Rat).Inv(r.Value),
+	}
+}
+
+// Rounded returns rate rounded to precision of stored exchange rates.
+//
+// Cross and inverse rates may have infinite decimal expansion,
+// so payments are converted by rounded rate to be reproducible from the stored one.
+func (r Rate) Rounded() *Rate {
+	v, _ := new(big.Rat).SetString(r.Value.FloatString(rateDecimals))
+	return &Rate{
+		From:  r.From,
+		To:    r.To,
+		Value: v,
+	}
+}
+
+// String returns rate value as decimal string with stored rates precision.
+func (r Rate) String() string {
+	return strings.TrimRight(strings.TrimRight(r.Value.FloatString(rateDecimals), "0"), ".")
+}
+
+// Convert converts money amount to target currency of the rate.
+//
+// Rounding rules:
+//   - Conversion is done with exact rational arithmetic, amount is never converted through floats.
+//   - Difference in minor units between currencies is applied (e.g. USD cents to JPY yen).
+//   - Result is rounded once, to the nearest minor unit of target currency.
+//     Halves are rounded away from zero (e.g. 0.5 -> 1, -0.5 -> -1).
+func (r Rate) Convert(m Money) (Money, error) {
+	if m.Currency != r.From {
+		return Money{}, fmt.Errorf("can't convert %s using %s/%s rate", m.Currency, r.From, r.To)
+	}
+
+	v := new(big.Rat).SetInt64(m.Amount)
+	v.Mul(v, r.Value)
+
+	expDiff := r.To.Exponent() - r.From.Exponent()
+	scale := new(big.Rat).SetInt64(pow10(abs(expDiff)))
+	if expDiff >= 0 {
+		v.Mul(v, scale)
+	} else {
+		v.Quo(v, scale)
+	}
+
+	return NewMoney(roundHalfAwayFromZero(v), r.To), nil
+}
+
+func roundHalfAwayFromZero(v *big.Rat) int64 {
+	num := new(big.Int).Set(v.Num())
+	den := v.Denom()
+
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+
+	// Compare doubled remainder with denominator to check whether fraction is >= 0.5.
+	rem.Abs(rem)
+	rem.Lsh(rem, 1)
+	if rem.Cmp(den) >= 0 {
+		if num.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+
+	return q.Int64()
+}
+
+func pow10(n int) int64 {
+	result := int64(1)
+	for range n {
+		result *= 10
+	}
+
+	return result
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
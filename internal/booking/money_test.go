@@ -0,0 +1,252 @@
+package booking
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCurrency(t *testing.T) {
+	cases := map[string]struct {
+		input   string
+		want    Currency
+		wantErr bool
+	}{
+		"uppercase":      {input: "EUR", want: "EUR"},
+		"lowercase":      {input: "usd", want: "USD"},
+		"spaces":         {input: " jpy ", want: "JPY"},
+		"too short":      {input: "US", wantErr: true},
+		"too long":       {input: "USDT", wantErr: true},
+		"non-alphabetic": {input: "U1D", wantErr: true},
+		"unknown code":   {input: "XYZ", wantErr: true},
+		"metal code":     {input: "XAU", wantErr: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseCurrency(c.input)
+			if c.wantErr {
+				require.ErrorIs(t, err, ErrInvalidParams)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, c.want, got)
+		})
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	cases := map[Money]string{
+		NewMoney(1050, "USD"):  "10.50 USD",
+		NewMoney(5, "USD"):     "0.05 USD",
+		NewMoney(-5, "USD"):    "-0.05 USD",
+		NewMoney(1500, "JPY"):  "1500 JPY",
+		NewMoney(1234, "KWD"):  "1.234 KWD",
+		NewMoney(0, "EUR"):     "0.00 EUR",
+		NewMoney(10000, "EUR"): "100.00 EUR",
+	}
+
+	for m, want := range cases {
+		require.Equal(t, want, m.String())
+	}
+}
+
+func TestMoneyIncludedTax(t *testing.T) {
+	cases := []struct {
+		amount  Money
+		rateBps uint
+		want    int64
+	}{
+		{amount: NewMoney(120_00, "USD"), rateBps: 2000, want: 20_00},
+		{amount: NewMoney(100, "USD"), rateBps: 2000, want: 17},
+		{amount: NewMoney(1050, "JPY"), rateBps: 1000, want: 95},
+		{amount: NewMoney(200, "USD"), rateBps: 10_000, want: 100},
+		{amount: NewMoney(999, "USD"), rateBps: 0, want: 0},
+	}
+
+	for _, c := range cases {
+		got := c.amount.IncludedTax(c.rateBps)
+		require.Equal(t, NewMoney(c.want, c.amount.Currency), got, "%s at %d bps", c.amount, c.rateBps)
+	}
+}
+
+func TestRateConvert(t *testing.T) {
+	cases := map[string]struct {
+		from, to Currency
+		rate     string
+		inverse  bool
+		amount   int64
+		want     int64
+	}{
+		"identity": {
+			from: "USD", to: "USD", rate: "1",
+			amount: 12345, want: 12345,
+		},
+		"round half up": {
+			// 10.00 USD * 0.9215 = 9.215 EUR
+			from: "USD", to: "EUR", rate: "0.9215",
+			amount: 1000, want: 922,
+		},
+		"round down": {
+			// 10.00 USD * 0.9214 = 9.214 EUR
+			from: "USD", to: "EUR", rate: "0.9214",
+			amount: 1000, want: 921,
+		},
+		"exact half": {
+			from: "USD", to: "EUR", rate: "0.5",
+			amount: 1, want: 1,
+		},
+		"negative half is rounded away from zero": {
+			from: "USD", to: "EUR", rate: "0.5",
+			amount: -1, want: -1,
+		},
+		"to zero decimal currency": {
+			// 10.99 USD * 151.37 = 1663.5563 JPY
+			from: "USD", to: "JPY", rate: "151.37",
+			amount: 1099, want: 1664,
+		},
+		"from zero decimal currency using inverse rate": {
+			// 1000 JPY / 151.37 = 6.6063...
+			from: "USD", to: "JPY", rate: "151.37", inverse: true,
+			amount: 1000, want: 661,
+		},
+		"to three decimal currency": {
+			// 10.00 USD * 0.30705 = 3.0705 KWD
+			from: "USD", to: "KWD", rate: "0.30705",
+			amount: 1000, want: 3071,
+		},
+		"large amount has no float precision loss": {
+			// 90,071,992,547,409.93 USD * 1.1 = 99,079,191,802,150.923 EUR
+			from: "USD", to: "EUR", rate: "1.1",
+			amount: 9007199254740993, want: 9907919180215092,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			rate, err := ParseRate(c.from, c.to, c.rate)
+			require.NoError(t, err)
+
+			src := c.from
+			if c.inverse {
+				rate = rate.Inverse()
+				src = c.to
+			}
+
+			got, err := rate.Convert(NewMoney(c.amount, src))
+			require.NoError(t, err)
+			require.Equal(t, NewMoney(c.want, rate.To), got)
+		})
+	}
+}
+
+func TestRateRounded(t *testing.T) {
+	jpy, err := ParseRate("USD", "JPY", "151.37")
+	require.NoError(t, err)
+
+	// 1 / 151.37 = 0.006606328863...
+	rate := jpy.Inverse().Rounded()
+	require.Equal(t, "0.006606328863", rate.String())
+
+	stored, err := ParseRate("JPY", "USD", rate.String())
+	require.NoError(t, err)
+
+	for _, amount := range []int64{1, 999, 123_456_789, 9_999_999_999} {
+		want, err := rate.Convert(NewMoney(amount, "JPY"))
+		require.NoError(t, err)
+
+		got, err := stored.Convert(NewMoney(amount, "JPY"))
+		require.NoError(t, err)
+		require.Equal(t, want, got, "stored rate should reproduce converted amount")
+	}
+}
+
+func TestRateConvertCurrencyMismatch(t *testing.T) {
+	rate, err := ParseRate("USD", "EUR", "0.92")
+	require.NoError(t, err)
+
+	_, err = rate.Convert(NewMoney(100, "GBP"))
+	require.Error(t, err)
+}
+
+func TestParseRateInvalid(t *testing.T) {
+	for _, v := range []string{"", "abc", "0", "-1.5"} {
+		_, err := ParseRate("USD", "EUR", v)
+		require.Errorf(t, err, "rate %q should be rejected", v)
+	}
+}
+
+func TestRoundingPerItemVsTotal(t *testing.T) {
+	// Documented behavior: displayed unit prices are converted individually,
+	// while charged amount converts reservation total once.
+	rate, err := ParseRate("USD", "EUR", "0.5")
+	require.NoError(t, err)
+
+	unit, err := rate.Convert(NewMoney(1, "USD"))
+	require.NoError(t, err)
+
+	total, err := rate.Convert(NewMoney(3, "USD"))
+	require.NoError(t, err)
+
+	require.Equal(t, int64(3), unit.Amount*3)
+	require.Equal(t, int64(2), total.Amount)
+}
+
+func TestStaticRates(t *testing.T) {
+	eur, err := ParseRate("USD", "EUR", "0.9")
+	require.NoError(t, err)
+
+	gbp, err := ParseRate("USD", "GBP", "0.8")
+	require.NoError(t, err)
+
+	rates := NewStaticRates("USD", map[Currency]*Rate{
+		"EUR": eur,
+		"GBP": gbp,
+	})
+
+	ctx := t.Context()
+	cases := map[string]struct {
+		from, to Currency
+		amount   int64
+		want     int64
+	}{
+		"same currency":  {from: "GBP", to: "GBP", amount: 100, want: 100},
+		"from base":      {from: "USD", to: "EUR", amount: 1000, want: 900},
+		"to base":        {from: "EUR", to: "USD", amount: 900, want: 1000},
+		"cross currency": {from: "EUR", to: "GBP", amount: 900, want: 800},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			rate, err := rates.GetRate(ctx, c.from, c.to)
+			require.NoError(t, err)
+
+			got, err := rate.Convert(NewMoney(c.amount, c.from))
+			require.NoError(t, err)
+			require.Equal(t, NewMoney(c.want, c.to), got)
+		})
+	}
+
+	_, err = rates.GetRate(ctx, "USD", "CHF")
+	require.ErrorIs(t, err, ErrRateNotFound)
+}
+
+func TestLoadRatesFile(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "rates.json")
+	err := os.WriteFile(fileName, []byte(`{"base": "usd", "rates": {"eur": "0.9215", "JPY": "151.37"}}`), 0o600)
+	require.NoError(t, err)
+
+	rates, err := LoadRatesFile(fileName)
+	require.NoError(t, err)
+
+	rate, err := rates.GetRate(t.Context(), "USD", "JPY")
+	require.NoError(t, err)
+	require.Equal(t, "151.37", rate.String())
+
+	rate, err = rates.GetRate(t.Context(), "USD", "EUR")
+	require.NoError(t, err)
+	require.Equal(t, "0.9215", rate.String())
+}
@@ -0,0 +1,128 @@
+package booking
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// holdReminderLead is how long before hold expiration customer is reminded to pay.
+const holdReminderLead = 5 * time.Minute
+
+// parseContactEmail validates optional customer email.
+func parseContactEmail(email string) (*string, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil, nil
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid email %q", ErrInvalidParams, email)
+	}
+
+	return &addr.Address, nil
+}
+
+// RemindExpiringHolds publishes reminders for pending reservations which are about to expire.
+//
+// Each reservation is reminded only once. Method is called periodically by a background worker.
+// Returns number of reminded reservations.
+func (svc Service) RemindExpiringHolds(ctx context.Context) (int, error) {
+	tx, err := svc.store.begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	reminded, err := tx.markExpiringReservations(ctx, holdReminderLead, expireBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	events := make([]DomainEvent, 0, len(reminded))
+	for _, r := range reminded {
+		e := r.event(ReservationExpiringEvent)
+		e.ExpiresAt = &r.ExpiresAt
+		events = append(events, e)
+	}
+
+	if err := tx.writeOutbox(ctx, events...); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(reminded), nil
+}
+
+// CancelEvent cancels an event.
+//
+// Pending reservations and waitlist entries are cancelled and tickets become unavailable for booking.
+// Paid reservations are kept and should be refunded separately.
+// Holders of all affected reservations are notified.
+func (svc Service) CancelEvent(ctx context.Context, eventID uuid.UUID) error {
+	tx, err := svc.store.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// Reservations hold a share lock on event row, so cancellation waits for in-flight reservations.
+	event, err := tx.lockEvent(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	if event.CancelledAt != nil {
+		return fmt.Errorf("%w: event is already cancelled", ErrInvalidStatus)
+	}
+
+	if err := tx.cancelEvent(ctx, eventID); err != nil {
+		return err
+	}
+
+	affected, err := tx.cancelEventReservations(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.releaseEventTickets(ctx, eventID); err != nil {
+		return err
+	}
+
+	if err := tx.cancelEventWaitlist(ctx, eventID); err != nil {
+		return err
+	}
+
+	if err := tx.cancelEventTransfers(ctx, eventID); err != nil {
+		return err
+	}
+
+	if err := tx.cancelEventListings(ctx, eventID); err != nil {
+		return err
+	}
+
+	events := make([]DomainEvent, 0, len(affected))
+	for _, r := range affected {
+		events = append(events, r.event(EventCancelledEvent))
+	}
+
+	if err := tx.writeOutbox(ctx, events...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
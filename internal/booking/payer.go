@@ -11,13 +11,28 @@ type PayResult struct {
 }
 
 type PayParams struct {
-	ReservID    uuid.UUID
-	Card        string
-	AmountCents uint
+	ReservID uuid.UUID
+	Card     string
+
+	// Settlement is amount in event currency received by merchant.
+	Settlement Money
+
+	// Presentment is amount in customer currency which is charged from a card.
+	Presentment Money
+}
+
+// PayoutParams is a transfer of funds to a reseller.
+type PayoutParams struct {
+	ListingID uuid.UUID
+	SellerID  uuid.UUID
+	Amount    Money
 }
 
 type Payer interface {
 	Pay(p PayParams) (*PayResult, error)
+	Payout(p PayoutParams) (*PayResult, error)
+
+	// Rollback refunds a transaction. Should be idempotent, as failed refunds are retried.
 	Rollback(txID uuid.UUID) error
 }
 
@@ -35,6 +50,12 @@ func (*MockPayer) Pay(p PayParams) (*PayResult, error) {
 	return nil, fmt.Errorf("card is not in allowlist")
 }
 
+func (*MockPayer) Payout(p PayoutParams) (*PayResult, error) {
+	return &PayResult{
+		TXID: uuid.New(),
+	}, nil
+}
+
 func (*MockPayer) Rollback(txID uuid.UUID) error {
 	return nil
 }
@@ -0,0 +1,217 @@
+package booking
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/pricing"
+)
+
+// Price change reasons stored in price history.
+const (
+	priceReasonReservation = "reservation"
+	priceReasonRelease     = "release"
+	priceReasonSchedule    = "schedule"
+	priceReasonManual      = "manual"
+)
+
+// tierPricing is tier pricing snapshot used to evaluate price.
+type tierPricing struct {
+	TierID         uuid.UUID `db:"tier_id"`
+	EventID        uuid.UUID `db:"event_id"`
+	PriceCents     int       `db:"price_cents"`
+	BasePriceCents int       `db:"base_price_cents"`
+	MinPriceCents  *int      `db:"min_price_cents"`
+	MaxPriceCents  *int      `db:"max_price_cents"`
+	Capacity       int       `db:"capacity"`
+	Taken          int       `db:"taken"`
+	Sold           int       `db:"sold"`
+
+	Rules []pricing.Rule `db:"-"`
+}
+
+func (tp tierPricing) state() pricing.TierState {
+	return pricing.TierState{
+		BasePriceCents: tp.BasePriceCents,
+		Capacity:       tp.Capacity,
+		Taken:          tp.Taken,
+		Limits: pricing.Limits{
+			MinPriceCents: tp.MinPriceCents,
+			MaxPriceCents: tp.MaxPriceCents,
+		},
+	}
+}
+
+func validatePricing(limits pricing.Limits, rules []pricing.Rule) error {
+	if err := limits.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidParams, err)
+	}
+
+	for i, r := range rules {
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("%w: pricing rule %d: %s", ErrInvalidParams, i, err)
+		}
+	}
+
+	return nil
+}
+
+// GetTierPricing returns tier pricing configuration with current effective price.
+func (svc Service) GetTierPricing(ctx context.Context, tierID uuid.UUID) (*TierPricing, error) {
+	tiers, err := svc.store.getTierPricing(ctx, []uuid.UUID{tierID})
+	if err != nil {
+		return nil, err
+	}
+
+	tp, ok := tiers[tierID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	quote := pricing.Evaluate(tp.state(), tp.Rules, time.Now())
+	rules := tp.Rules
+	if rules == nil {
+		rules = []pricing.Rule{}
+	}
+
+	return &TierPricing{
+		TierID:         tierID,
+		BasePriceCents: tp.BasePriceCents,
+		PriceCents:     quote.PriceCents,
+		NextThreshold:  quote.Next,
+		Rules:          rules,
+		Limits: pricing.Limits{
+			MinPriceCents: tp.MinPriceCents,
+			MaxPriceCents: tp.MaxPriceCents,
+		},
+	}, nil
+}
+
+// SetTierPricing replaces tier base price, price limits and pricing rules.
+//
+// Effective price is recomputed immediately and change is recorded in price history.
+func (svc Service) SetTierPricing(ctx context.Context, tierID uuid.UUID, params TierPricingParams) (*TierPricing, error) {
+	if params.BasePriceCents < 0 {
+		return nil, fmt.Errorf("%w: base price can't be negative", ErrInvalidParams)
+	}
+
+	if err := validatePricing(params.Limits, params.Rules); err != nil {
+		return nil, err
+	}
+
+	tx, err := svc.store.begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err := tx.setTierPricing(ctx, tierID, params); err != nil {
+		return nil, err
+	}
+
+	tiers, err := tx.getTierPricing(ctx, []uuid.UUID{tierID})
+	if err != nil {
+		return nil, err
+	}
+
+	tp := tiers[tierID]
+	newPrice := pricing.PriceOf(tp.state(), tp.Rules, time.Now())
+	if err := tx.updateTierPrice(ctx, tierID, newPrice, priceReasonManual); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return svc.GetTierPricing(ctx, tierID)
+}
+
+// GetTierPriceHistory returns tier price changes in chronological order.
+func (svc Service) GetTierPriceHistory(ctx context.Context, tierID uuid.UUID) ([]*PriceChange, error) {
+	tiers, err := svc.store.getTierPricing(ctx, []uuid.UUID{tierID})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := tiers[tierID]; !ok {
+		return nil, ErrNotFound
+	}
+
+	return svc.store.getPriceHistory(ctx, tierID)
+}
+
+// RepriceTiers recomputes effective price of tiers with pricing rules.
+//
+// Time windows open and close and holds run out without any activity, so this method is called periodically.
+func (svc Service) RepriceTiers(ctx context.Context) error {
+	tierIDs, err := svc.store.getPricedTierIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(tierIDs) == 0 {
+		return nil
+	}
+
+	tx, err := svc.store.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err := repriceTiers(ctx, tx, tierIDs, priceReasonSchedule); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// repriceTiers updates effective price of passed tiers if it has changed.
+//
+// Called when tickets are released, as sold percentage drops and price should follow it back down.
+func repriceTiers(ctx context.Context, tx storeTx, tierIDs []uuid.UUID, reason string) error {
+	if len(tierIDs) == 0 {
+		return nil
+	}
+
+	tiers, err := tx.getTierPricing(ctx, tierIDs)
+	if err != nil {
+		return err
+	}
+
+	// Tiers are locked in stable order, so concurrent releases don't deadlock.
+	tierIDs = slices.Clone(tierIDs)
+	slices.SortFunc(tierIDs, func(a, b uuid.UUID) int {
+		return slices.Compare(a[:], b[:])
+	})
+
+	now := time.Now()
+	for _, tierID := range slices.Compact(tierIDs) {
+		tp, ok := tiers[tierID]
+		if !ok {
+			continue
+		}
+
+		newPrice := pricing.PriceOf(tp.state(), tp.Rules, now)
+		if newPrice == tp.PriceCents {
+			continue
+		}
+
+		if err := tx.updateTierPrice(ctx, tierID, newPrice, reason); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package booking
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RateSource provides currency exchange rates.
+type RateSource interface {
+	// GetRate returns exchange rate between two currencies.
+	//
+	// Returns ErrRateNotFound if rate is not available.
+	GetRate(ctx context.Context, from, to Currency) (*Rate, error)
+}
+
+// StaticRates is exchange rates table relative to a single base currency.
+//
+// Rates between two non-base currencies are computed through base currency.
+type StaticRates struct {
+	base  Currency
+	rates map[Currency]*Rate
+}
+
+// ratesFile is exchange rates file format.
+//
+// Example:
+//
+//	{"base": "USD", "rates": {"EUR": "0.9215", "JPY": "151.37"}}
+type ratesFile struct {
+	Base  string            `json:"base"`
+	Rates map[string]string `json:"rates"`
+}
+
+// LoadRatesFile reads exchange rates from a JSON file.
+func LoadRatesFile(fileName string) (*StaticRates, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rates file: %w", err)
+	}
+
+	var f ratesFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse rates file %q: %w", fileName, err)
+	}
+
+	base, err := ParseCurrency(f.Base)
+	if err != nil {
+		return nil, fmt.Errorf("bad base currency in rates file %q: %w", fileName, err)
+	}
+
+	rates := make(map[Currency]*Rate, len(f.Rates))
+	for code, value := range f.Rates {
+		quote, err := ParseCurrency(code)
+		if err != nil {
+			return nil, fmt.Errorf("bad currency in rates file %q: %w", fileName, err)
+		}
+
+		rate, err := ParseRate(base, quote, value)
+		if err != nil {
+			return nil, fmt.Errorf("bad rate in rates file %q: %w", fileName, err)
+		}
+
+		rates[quote] = rate
+	}
+
+	return NewStaticRates(base, rates), nil
+}
+
+// NewStaticRates returns exchange rates relative to base currency.
+func NewStaticRates(base Currency, rates map[Currency]*Rate) *StaticRates {
+	return &StaticRates{
+		base:  base,
+		rates: rates,
+	}
+}
+
+// GetRate implements RateSource.
+func (s *StaticRates) GetRate(_ context.Context, from, to Currency) (*Rate, error) {
+	if from == to {
+		return identityRate(from), nil
+	}
+
+	fromBase, err := s.baseRate(from)
+	if err != nil {
+		return nil, err
+	}
+
+	toBase, err := s.baseRate(to)
+	if err != nil {
+		return nil, err
+	}
+
+	// from -> base -> to
+	rate := fromBase.Inverse()
+	rate.Value.Mul(rate.Value, toBase.Value)
+	rate.To = to
+	return rate, nil
+}
+
+// baseRate returns rate from base currency to specified one.
+func (s *StaticRates) baseRate(c Currency) (*Rate, error) {
+	if c == s.base {
+		return identityRate(c), nil
+	}
+
+	rate, ok := s.rates[c]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s", ErrRateNotFound, s.base, c)
+	}
+
+	return rate, nil
+}
+
+// DBRates reads exchange rates from "exchange_rates" table.
+//
+// Inverse rate is used if there is no direct rate for a currency pair.
+type DBRates struct {
+	db *pgxpool.Pool
+}
+
+func NewDBRates(db *pgxpool.Pool) *DBRates {
+	return &DBRates{db: db}
+}
+
+// GetRate implements RateSource.
+func (s *DBRates) GetRate(ctx context.Context, from, to Currency) (*Rate, error) {
+	if from == to {
+		return identityRate(from), nil
+	}
+
+	var (
+		base  Currency
+		value string
+	)
+
+	err := s.db.QueryRow(ctx, `
+		SELECT base, rate::TEXT
+		FROM exchange_rates
+		WHERE (base = $1 AND quote = $2) OR (base = $2 AND quote = $1)
+		ORDER BY base = $1 DESC
+		LIMIT 1
+	`, from, to).Scan(&base, &value)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s/%s", ErrRateNotFound, from, to)
+		}
+
+		return nil, fmt.Errorf("failed to query exchange rate: %w", err)
+	}
+
+	if base == from {
+		return ParseRate(from, to, value)
+	}
+
+	rate, err := ParseRate(to, from, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return rate.Inverse(), nil
+}
@@ -0,0 +1,47 @@
+package booking
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// GetReceipt returns payment receipt of a paid or refunded reservation.
+func (svc Service) GetReceipt(ctx context.Context, reservationID uuid.UUID) (*Receipt, error) {
+	meta, err := svc.GetReservationEntries(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if meta.Status != ReservationPaid && meta.Status != ReservationRefunded {
+		return nil, fmt.Errorf("%w: reservation is %s", ErrInvalidStatus, meta.Status)
+	}
+
+	payments, err := svc.store.getPayments(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(payments) == 0 {
+		return nil, fmt.Errorf("%w: no payment found for reservation", ErrInvalidStatus)
+	}
+
+	event, err := svc.store.getEvent(ctx, meta.EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := payments[len(payments)-1]
+	return &Receipt{
+		Reservation: meta,
+		Payment:     *p,
+		TaxRateBps:  event.TaxRateBps,
+		Tax:         p.Settlement.IncludedTax(event.TaxRateBps),
+	}, nil
+}
+
+// GetReservationPayments returns all payments of a reservation in chronological order.
+func (svc Service) GetReservationPayments(ctx context.Context, reservationID uuid.UUID) ([]*PaymentRecord, error) {
+	return svc.store.getPayments(ctx, reservationID)
+}
@@ -0,0 +1,86 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// refundBatchSize is max number of pending refunds retried by a single run.
+	refundBatchSize = 100
+
+	// maxRefundBackoff is max delay between refund attempts.
+	maxRefundBackoff = time.Hour
+)
+
+// issueRefund calls payment provider to refund pending refund of a payment.
+//
+// Pending refund row is locked, so the same refund is not issued concurrently.
+// Returns false if refund is already issued or is being issued by another caller.
+func (svc Service) issueRefund(ctx context.Context, txID uuid.UUID) (bool, error) {
+	tx, err := svc.store.begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	attempts, ok, err := tx.lockPendingRefund(ctx, txID)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	if refundErr := svc.payer.Rollback(txID); refundErr != nil {
+		backoff := min(time.Duration(1<<min(attempts, 16))*time.Second, maxRefundBackoff)
+		if err := tx.deferPendingRefund(ctx, txID, refundErr.Error(), backoff); err != nil {
+			return false, err
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			return false, fmt.Errorf("failed to commit transaction: %w", err)
+		}
+
+		return false, fmt.Errorf("refund of %s failed: %w", txID, refundErr)
+	}
+
+	if err := tx.deletePendingRefund(ctx, txID); err != nil {
+		return false, err
+	}
+
+	// Payer refunds are idempotent, so refund is safely retried if commit fails.
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// RetryRefunds issues refunds which payment provider failed to process when reservation was refunded.
+//
+// Method is called periodically by a background worker. Returns number of issued refunds.
+func (svc Service) RetryRefunds(ctx context.Context) (int, error) {
+	txIDs, err := svc.store.getDueRefunds(ctx, refundBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	issued := 0
+	var errs []error
+	for _, txID := range txIDs {
+		ok, err := svc.issueRefund(ctx, txID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if ok {
+			issued++
+		}
+	}
+
+	return issued, errors.Join(errs...)
+}
@@ -0,0 +1,41 @@
+package booking
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Repository is the API contract of event inventory and reservation lifecycle.
+//
+// Service is the only implementation. Persistence is abstracted by Store,
+// so the same Service runs on top of PostgresStore in production and MemoryStore in unit tests.
+//
+// Implementations should guarantee that:
+//   - a ticket is never held or sold to two reservations at the same time;
+//   - reservation holds either all requested tickets or none of them, the same applies to modification;
+//   - reservation requests with the same idempotency key create a single reservation;
+//   - reservation status transitions are serialized, e.g. payment can't race with cancellation;
+//   - hold extension moves reservation and all held tickets expiration at once.
+//
+// Service runs the shared conformance suite from bookingtest package with both stores,
+// which checks these properties only as far as its scenarios cover them.
+type Repository interface {
+	CreateEvent(ctx context.Context, opts EventCreateParams) (*EventCreateResult, error)
+	GetEvents(ctx context.Context) ([]*Event, error)
+	GetTicketTiers(ctx context.Context, eventID uuid.UUID, presentment Currency) ([]*TicketTier, error)
+
+	ReserveTickets(ctx context.Context, params ReservationParams) (*ReservationResult, error)
+	GetReservationEntries(ctx context.Context, reservationID uuid.UUID) (*ReservationMeta, error)
+	PayReservation(ctx context.Context, params PaymentParams) (*PaymentResult, error)
+	GetReservationTickets(ctx context.Context, reservationID uuid.UUID) ([]*IssuedTicket, error)
+
+	ExtendReservation(ctx context.Context, reservationID uuid.UUID) (*ExtensionResult, error)
+	ModifyReservation(ctx context.Context, params ReservationModifyParams) (*ReservationMeta, error)
+	CancelReservation(ctx context.Context, reservationID uuid.UUID) error
+	ExpireReservation(ctx context.Context, reservationID uuid.UUID) error
+	ExpireReservations(ctx context.Context) (int, error)
+	RefundReservation(ctx context.Context, reservationID uuid.UUID) (*RefundResult, error)
+}
+
+var _ Repository = (*Service)(nil)
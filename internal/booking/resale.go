@@ -0,0 +1,403 @@
+package booking
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ResalePolicy defines resale marketplace limits.
+type ResalePolicy struct {
+	// PriceCapBps is max resale price in basis points of face value (e.g. 11000 is 110%).
+	PriceCapBps uint
+
+	// FeeBps is marketplace fee in basis points of sale amount withheld from seller payout.
+	FeeBps uint
+}
+
+// DefaultResalePolicy caps resale price at face value plus 10% and takes 10% fee.
+var DefaultResalePolicy = ResalePolicy{
+	PriceCapBps: 11_000,
+	FeeBps:      1_000,
+}
+
+// MaxPrice returns max allowed resale price for a face value. Result is rounded down.
+func (p ResalePolicy) MaxPrice(faceValueCents uint) uint {
+	return uint(uint64(faceValueCents) * uint64(p.PriceCapBps) / 10_000)
+}
+
+// Split splits sale amount into marketplace fee and seller payout.
+//
+// Fee is rounded to the nearest minor unit, halves are rounded up.
+func (p ResalePolicy) Split(amount int64) (fee, payout int64) {
+	fee = (2*amount*int64(p.FeeBps) + 10_000) / 20_000
+	return fee, amount - fee
+}
+
+// listedTicket is a ticket with face value paid by its original buyer.
+type listedTicket struct {
+	TicketID       uuid.UUID `db:"ticket_id"`
+	TierID         uuid.UUID `db:"tier_id"`
+	FaceValueCents uint      `db:"face_value_cents"`
+}
+
+func validateResaleListing(params ResaleListingParams) error {
+	if len(params.TicketIDs) == 0 || len(params.TicketIDs) > maxTransferTickets {
+		return fmt.Errorf("%w: listing should contain from 1 to %d tickets", ErrInvalidParams, maxTransferTickets)
+	}
+
+	seen := make(map[uuid.UUID]struct{}, len(params.TicketIDs))
+	for _, id := range params.TicketIDs {
+		if _, ok := seen[id]; ok {
+			return fmt.Errorf("%w: duplicate ticket %s", ErrInvalidParams, id)
+		}
+
+		seen[id] = struct{}{}
+	}
+
+	if params.PriceCents == 0 {
+		return fmt.Errorf("%w: price should be positive", ErrInvalidParams)
+	}
+
+	return nil
+}
+
+// CreateResaleListing puts owned tickets of a single tier on resale.
+//
+// Tickets stay valid for the seller until a buyer pays for the listing.
+func (svc Service) CreateResaleListing(ctx context.Context, params ResaleListingParams) (*ResaleListing, error) {
+	if err := validateResaleListing(params); err != nil {
+		return nil, err
+	}
+
+	tx, err := svc.store.begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// Code lock serializes listing with check-in, transfers and concurrent listings of the same ticket.
+	tickets, err := tx.lockTicketCodes(ctx, params.TicketIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTransferable(params.TicketIDs, tickets, params.ActorID); err != nil {
+		return nil, err
+	}
+
+	if err := checkTicketsAvailable(ctx, tx, params.TicketIDs); err != nil {
+		return nil, err
+	}
+
+	// Face value of a resold ticket is taken from the listing it was bought from,
+	// so price cap doesn't grow with each resale.
+	faceValues, err := tx.getFaceValues(ctx, params.TicketIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	tierID := faceValues[0].TierID
+	faceValue := faceValues[0].FaceValueCents
+	for _, t := range faceValues {
+		if t.TierID != tierID {
+			return nil, fmt.Errorf("%w: all listed tickets should belong to the same tier", ErrInvalidParams)
+		}
+
+		faceValue = min(faceValue, t.FaceValueCents)
+	}
+
+	if maxPrice := svc.resale.MaxPrice(faceValue); params.PriceCents > maxPrice {
+		return nil, fmt.Errorf(
+			"%w: price exceeds %d%% of face value, max price is %d",
+			ErrInvalidParams, svc.resale.PriceCapBps/100, maxPrice,
+		)
+	}
+
+	event, err := tx.getEvent(ctx, tickets[0].EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	listingID := uuid.New()
+	err = tx.insertResaleListing(ctx, &ResaleListing{
+		ID:             listingID,
+		EventID:        event.ID,
+		TierID:         tierID,
+		SellerID:       params.ActorID,
+		Status:         ResaleActive,
+		Currency:       event.Currency,
+		PriceCents:     params.PriceCents,
+		FaceValueCents: faceValue,
+		TicketIDs:      params.TicketIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	listing, err := tx.getResaleListing(ctx, listingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return listing, nil
+}
+
+// CancelResaleListing withdraws active listing. Listing held by a buyer can't be withdrawn.
+func (svc Service) CancelResaleListing(ctx context.Context, params ResaleCancelParams) error {
+	tx, err := svc.store.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	listing, err := tx.lockResaleListing(ctx, params.ListingID)
+	if err != nil {
+		return err
+	}
+
+	if listing.SellerID != params.ActorID {
+		return fmt.Errorf("%w: listing can be cancelled only by seller", ErrInvalidParams)
+	}
+
+	if listing.Status != ResaleActive {
+		return fmt.Errorf("%w: listing is %s", ErrInvalidStatus, listing.Status)
+	}
+
+	if listing.HeldUntil != nil {
+		return fmt.Errorf("%w: listing is held by a buyer", ErrInvalidStatus)
+	}
+
+	if err := tx.setListingStatus(ctx, listing.ID, ResaleCancelled); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetResaleListing returns listing by ID.
+func (svc Service) GetResaleListing(ctx context.Context, listingID uuid.UUID) (*ResaleListing, error) {
+	return svc.store.getResaleListing(ctx, listingID)
+}
+
+// GetResaleListings returns listings of an event available for purchase, cheapest first.
+//
+// Listings held by buyers and listings with checked-in tickets are not included.
+func (svc Service) GetResaleListings(ctx context.Context, eventID uuid.UUID) ([]*ResaleListing, error) {
+	return svc.store.getEventResaleListings(ctx, eventID)
+}
+
+// GetUserResaleListings returns all listings created by a seller.
+func (svc Service) GetUserResaleListings(ctx context.Context, actorID uuid.UUID) ([]*ResaleListing, error) {
+	return svc.store.getUserResaleListings(ctx, actorID)
+}
+
+// ReserveResaleListing holds all tickets of a listing for a buyer.
+//
+// Hold follows ReserveTickets semantics: reservation expires after event hold TTL,
+// requests are deduplicated by idempotency key and reservation is paid with PayReservation.
+// Held listing is hidden from other buyers until the hold expires or is cancelled.
+func (svc Service) ReserveResaleListing(ctx context.Context, params ResaleReserveParams) (*ReservationResult, error) {
+	email, err := parseContactEmail(params.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	// Listing event never changes, so it's safe to read it before the transaction.
+	listing, err := svc.store.getResaleListing(ctx, params.ListingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if listing.SellerID == params.ActorID {
+		return nil, fmt.Errorf("%w: can't buy own listing", ErrInvalidParams)
+	}
+
+	reservationID := uuid.New()
+
+	tx, err := svc.store.begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	event, err := tx.shareEvent(ctx, listing.EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.CancelledAt != nil {
+		return nil, fmt.Errorf("%w: event is cancelled", ErrInvalidStatus)
+	}
+
+	expireAt := time.Now().Add(time.Duration(event.HoldTTLSeconds) * time.Second)
+	created, err := tx.insertReservation(ctx, &newReservation{
+		reservationRef: reservationRef{
+			ID:        reservationID,
+			EventID:   listing.EventID,
+			ActorID:   params.ActorID,
+			Email:     email,
+			ExpiresAt: expireAt,
+		},
+		Currency:       listing.Currency,
+		IdempotencyKey: &params.IdempotencyKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !created {
+		// Request with the same idempotency key was already processed.
+		_ = tx.Rollback(ctx)
+		return svc.store.getReservationByIdempotencyKey(ctx, params.IdempotencyKey)
+	}
+
+	listing, err = tx.lockResaleListing(ctx, params.ListingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if listing.Status != ResaleActive || listing.HeldUntil != nil {
+		return nil, fmt.Errorf("%w: listing is not available", ErrInvalidStatus)
+	}
+
+	// Seller could check-in or lose tickets since listing was created.
+	tickets, err := tx.lockTicketCodes(ctx, listing.TicketIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTransferable(listing.TicketIDs, tickets, listing.SellerID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.holdListing(ctx, listing.ID, reservationID, expireAt); err != nil {
+		return nil, err
+	}
+
+	qty := uint(len(listing.TicketIDs))
+	if err := tx.addReservationItem(ctx, reservationID, listing.TierID, qty, listing.PriceCents); err != nil {
+		return nil, err
+	}
+
+	if err := tx.setReservationTotal(ctx, reservationID, qty*listing.PriceCents); err != nil {
+		return nil, err
+	}
+
+	if err := tx.linkResaleListing(ctx, reservationID, listing.ID); err != nil {
+		return nil, err
+	}
+
+	createdEvent := reservationRef{
+		ID:      reservationID,
+		EventID: listing.EventID,
+		ActorID: params.ActorID,
+		Email:   email,
+	}.event(ReservationCreatedEvent)
+	createdEvent.ExpiresAt = &expireAt
+	if err := tx.writeOutbox(ctx, createdEvent); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &ReservationResult{
+		ReservationID: reservationID,
+		ExpiresAt:     expireAt,
+	}, nil
+}
+
+// settleResale reissues listing tickets to a buyer of a paid resale reservation and pays out the seller.
+//
+// Returns payout transaction ID, so caller can roll it back if the transaction fails.
+func (svc Service) settleResale(
+	ctx context.Context, tx storeTx, h *reservationHeader, listingID uuid.UUID,
+) (payoutTxID uuid.UUID, soldEvent DomainEvent, err error) {
+	// Listing might be cancelled by seller's refund or held by another buyer after this hold expired.
+	ok, err := tx.sellListing(ctx, listingID, h.ID)
+	if err != nil {
+		return uuid.Nil, soldEvent, err
+	}
+
+	if !ok {
+		return uuid.Nil, soldEvent, fmt.Errorf("%w: listing is no longer available", ErrInvalidStatus)
+	}
+
+	listing, err := tx.getResaleListing(ctx, listingID)
+	if err != nil {
+		return uuid.Nil, soldEvent, err
+	}
+
+	tickets, err := tx.lockTicketCodes(ctx, listing.TicketIDs)
+	if err != nil {
+		return uuid.Nil, soldEvent, err
+	}
+
+	if err := checkTransferable(listing.TicketIDs, tickets, listing.SellerID); err != nil {
+		return uuid.Nil, soldEvent, err
+	}
+
+	codeIDs := make([]uuid.UUID, 0, len(tickets))
+	reissued := make([]soldTicket, 0, len(tickets))
+	for _, t := range tickets {
+		codeIDs = append(codeIDs, t.CodeID)
+		reissued = append(reissued, soldTicket{
+			ID:            t.TicketID,
+			EventID:       t.EventID,
+			ReservationID: h.ID,
+		})
+	}
+
+	if err := tx.reissueCodes(ctx, codeIDs); err != nil {
+		return uuid.Nil, soldEvent, err
+	}
+
+	// Tickets now belong to buyer's reservation, so seller's refund doesn't return them to inventory.
+	if err := tx.moveTickets(ctx, listing.TicketIDs, h.ID); err != nil {
+		return uuid.Nil, soldEvent, err
+	}
+
+	if err := svc.storeTicketCodes(ctx, tx, reissued, h.ActorID, nil); err != nil {
+		return uuid.Nil, soldEvent, err
+	}
+
+	fee, payout := svc.resale.Split(int64(h.TotalCents))
+	payoutAmount := NewMoney(payout, h.Currency)
+	payoutResult, err := svc.payer.Payout(PayoutParams{
+		ListingID: listing.ID,
+		SellerID:  listing.SellerID,
+		Amount:    payoutAmount,
+	})
+	if err != nil {
+		return uuid.Nil, soldEvent, fmt.Errorf("payout failed: %w", err)
+	}
+
+	if err := tx.setListingPayout(ctx, listing.ID, fee, payout, payoutResult.TXID); err != nil {
+		_ = svc.payer.Rollback(payoutResult.TXID)
+		return uuid.Nil, soldEvent, err
+	}
+
+	soldEvent = reservationRef{
+		ID:      h.ID,
+		EventID: h.EventID,
+		ActorID: listing.SellerID,
+	}.event(ResaleSoldEvent)
+	soldEvent.Amount = &payoutAmount
+	soldEvent.ListingID = &listing.ID
+	return payoutResult.TXID, soldEvent, nil
+}
@@ -0,0 +1,49 @@
+package booking
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResalePolicyMaxPrice(t *testing.T) {
+	cases := map[string]struct {
+		policy    ResalePolicy
+		faceValue uint
+		want      uint
+	}{
+		"default cap":     {policy: DefaultResalePolicy, faceValue: 100_00, want: 110_00},
+		"rounded down":    {policy: DefaultResalePolicy, faceValue: 9_99, want: 10_98},
+		"face value only": {policy: ResalePolicy{PriceCapBps: 10_000}, faceValue: 25_00, want: 25_00},
+		"free ticket":     {policy: DefaultResalePolicy, faceValue: 0, want: 0},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.want, c.policy.MaxPrice(c.faceValue))
+		})
+	}
+}
+
+func TestResalePolicySplit(t *testing.T) {
+	cases := map[string]struct {
+		policy     ResalePolicy
+		amount     int64
+		wantFee    int64
+		wantPayout int64
+	}{
+		"default fee":     {policy: DefaultResalePolicy, amount: 110_00, wantFee: 11_00, wantPayout: 99_00},
+		"half rounded up": {policy: DefaultResalePolicy, amount: 1_05, wantFee: 11, wantPayout: 94},
+		"rounded down":    {policy: DefaultResalePolicy, amount: 1_04, wantFee: 10, wantPayout: 94},
+		"no fee":          {policy: ResalePolicy{}, amount: 50_00, wantFee: 0, wantPayout: 50_00},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			fee, payout := c.policy.Split(c.amount)
+			require.Equal(t, c.wantFee, fee)
+			require.Equal(t, c.wantPayout, payout)
+			require.Equal(t, c.amount, fee+payout)
+		})
+	}
+}
@@ -6,75 +6,107 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/georgysavva/scany/v2/pgxscan"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/pricing"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/ticketcode"
 )
 
 const (
+	// reservationTTL is default reservation hold duration.
 	reservationTTL = 15 * time.Minute
+
+	// minHoldTTL and maxHoldTTL are bounds of event reservation hold duration.
+	minHoldTTL = time.Minute
+	maxHoldTTL = 30 * time.Minute
+
+	// maxTaxRateBps is 100% tax rate.
+	maxTaxRateBps = 10_000
 )
 
 type Service struct {
-	db    *pgxpool.Pool
-	rdb   redis.UniversalClient
-	payer Payer
+	store  Store
+	rdb    redis.UniversalClient
+	payer  Payer
+	rates  RateSource
+	codes  *ticketcode.Signer
+	resale ResalePolicy
 }
 
-func NewService(db *pgxpool.Pool, rdb redis.UniversalClient) *Service {
+func NewService(
+	store Store, rdb redis.UniversalClient, rates RateSource, codes *ticketcode.Signer, resale ResalePolicy,
+) *Service {
 	return &Service{
-		db:    db,
-		rdb:   rdb,
-		payer: &MockPayer{},
+		store:  store,
+		rdb:    rdb,
+		payer:  &MockPayer{},
+		rates:  rates,
+		codes:  codes,
+		resale: resale,
 	}
 }
 
+// WithPayer returns a copy of service which uses passed payment provider.
+func (svc Service) WithPayer(payer Payer) *Service {
+	svc.payer = payer
+	return &svc
+}
+
 // CreateEvent is test method used to create test events with tickets.
-func (svc Service) CreateEvent(ctx context.Context, opts EventCreateParams) (result *EventCreateResult, err error) {
-	eventID := uuid.New()
-	tiers := make(map[string]uuid.UUID, len(opts.Tiers))
+func (svc Service) CreateEvent(ctx context.Context, opts EventCreateParams) (*EventCreateResult, error) {
+	currency, err := opts.validate()
+	if err != nil {
+		return nil, err
+	}
 
-	tx, txErr := svc.db.BeginTx(ctx, pgx.TxOptions{
-		IsoLevel:   pgx.ReadCommitted,
-		AccessMode: pgx.ReadWrite,
-	})
-	if txErr != nil {
-		return nil, fmt.Errorf("can't open tx: %w", txErr)
+	tx, err := svc.store.begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't open tx: %w", err)
 	}
 
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback(ctx)
-		}
-	}()
+	defer tx.Rollback(ctx)
 
-	_, err = tx.Exec(ctx, `INSERT INTO events (id, name) VALUES ($1, $2)`, eventID, opts.EventName)
-	if err != nil {
+	event := &Event{
+		ID:               uuid.New(),
+		Name:             opts.EventName,
+		Currency:         currency,
+		TaxRateBps:       opts.TaxRateBps,
+		OrganizerID:      opts.OrganizerID,
+		TransfersBlocked: opts.TransfersBlocked,
+		HoldTTLSeconds:   uint(opts.holdTTL().Seconds()),
+	}
+	if err := tx.insertEvent(ctx, event); err != nil {
 		return nil, fmt.Errorf("cannot insert event %q: %w", opts.EventName, err)
 	}
 
+	now := time.Now()
+	tiers := make(map[string]uuid.UUID, len(opts.Tiers))
 	for k, v := range opts.Tiers {
-		tierID := uuid.New()
-		tiers[k] = tierID
-
-		_, err := tx.Exec(
-			ctx, `INSERT INTO ticket_tiers (id, event_id, name, price_cents) VALUES ($1, $2, $3, $4)`,
-			tierID, eventID, k, v.PriceCents,
-		)
-		if err != nil {
+		initialPrice := pricing.PriceOf(pricing.TierState{
+			BasePriceCents: v.PriceCents,
+			Limits:         v.Limits,
+			Capacity:       v.TicketsCount,
+		}, v.PricingRules, now)
+
+		tier := &eventTier{
+			tierPricing: tierPricing{
+				TierID:         uuid.New(),
+				EventID:        event.ID,
+				PriceCents:     initialPrice,
+				BasePriceCents: v.PriceCents,
+				MinPriceCents:  v.MinPriceCents,
+				MaxPriceCents:  v.MaxPriceCents,
+				Rules:          v.PricingRules,
+			},
+			Name:     k,
+			Currency: currency,
+		}
+		if err := tx.insertTier(ctx, tier, v.TicketsCount); err != nil {
 			return nil, fmt.Errorf("can't create tier %q: %w", k, err)
 		}
 
-		_, err = tx.Exec(
-			ctx,
-			`INSERT INTO tickets (event_id, tier_id) SELECT $1::UUID, $2::UUID FROM generate_series(1, $3)`,
-			eventID, tierID, v.TicketsCount,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("can't create tickets for tier %q: %w", k, err)
-		}
+		tiers[k] = tier.TierID
 	}
 
 	if err := tx.Commit(ctx); err != nil {
@@ -82,99 +114,184 @@ func (svc Service) CreateEvent(ctx context.Context, opts EventCreateParams) (res
 	}
 
 	return &EventCreateResult{
-		EventID: eventID,
+		EventID: event.ID,
 		Tiers:   tiers,
 	}, nil
 }
 
+// Validate checks event params without creating an event.
+func (opts EventCreateParams) Validate() error {
+	_, err := opts.validate()
+	return err
+}
+
+// validate checks event params and returns event settlement currency.
+func (opts EventCreateParams) validate() (Currency, error) {
+	currency := DefaultCurrency
+	if opts.Currency != "" {
+		var err error
+		currency, err = ParseCurrency(string(opts.Currency))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if opts.TaxRateBps > maxTaxRateBps {
+		return "", fmt.Errorf("%w: tax rate should not exceed %d bps", ErrInvalidParams, maxTaxRateBps)
+	}
+
+	if ttl := opts.holdTTL(); ttl < minHoldTTL || ttl > maxHoldTTL {
+		return "", fmt.Errorf(
+			"%w: hold TTL should be between %d and %d seconds",
+			ErrInvalidParams, int(minHoldTTL.Seconds()), int(maxHoldTTL.Seconds()),
+		)
+	}
+
+	for k, v := range opts.Tiers {
+		if err := validatePricing(v.Limits, v.PricingRules); err != nil {
+			return "", fmt.Errorf("tier %q: %w", k, err)
+		}
+
+		// Tier prices are always denominated in event currency.
+		if v.Currency == "" {
+			continue
+		}
+
+		tierCurrency, err := ParseCurrency(string(v.Currency))
+		if err != nil {
+			return "", fmt.Errorf("tier %q: %w", k, err)
+		}
+
+		if tierCurrency != currency {
+			return "", fmt.Errorf(
+				"%w: tier %q currency %s doesn't match event currency %s",
+				ErrInvalidParams, k, tierCurrency, currency,
+			)
+		}
+	}
+
+	return currency, nil
+}
+
+// holdTTL returns reservation hold duration of an event.
+func (opts EventCreateParams) holdTTL() time.Duration {
+	if opts.HoldTTLSeconds == 0 {
+		return reservationTTL
+	}
+
+	return time.Duration(opts.HoldTTLSeconds) * time.Second
+}
+
 func (svc Service) GetEvents(ctx context.Context) ([]*Event, error) {
-	var result []*Event
-	err := pgxscan.Select(ctx, svc.db, &result, "SELECT id, name from events")
+	return svc.store.getEvents(ctx)
+}
+
+func (svc Service) GetReservationEntries(ctx context.Context, reservationID uuid.UUID) (*ReservationMeta, error) {
+	result, err := svc.store.getReservation(ctx, reservationID)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil
-		}
+		return nil, err
+	}
 
+	if err := svc.populateReservationItems(ctx, result); err != nil {
 		return nil, err
 	}
 
 	return result, nil
 }
 
-func (svc Service) GetReservationEntries(ctx context.Context, reservationID uuid.UUID) (*ReservationMeta, error) {
-	result := &ReservationMeta{}
-	err := pgxscan.Get(
-		ctx, svc.db, result,
-		`SELECT r.id, r.expires_at, r.is_paid, e.event_id, e.name as event_name 
-		FROM reservations r
-		LEFT JOIN events e ON r.event_id = e.id
-		WHERE r.id = $1
-		LIMIT 1`,
-		reservationID,
-	)
+func (svc Service) GetReservations(ctx context.Context, userID uuid.UUID) ([]*ReservationMeta, error) {
+	result, err := svc.store.getUserReservations(ctx, userID)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrNotFound
-		}
+		return nil, err
+	}
 
-		return nil, fmt.Errorf("failed to query reservation: %w", err)
+	if err := svc.populateReservationItems(ctx, result...); err != nil {
+		return nil, err
 	}
 
 	return result, nil
 }
 
-func (svc Service) GetReservations(ctx context.Context, userID uuid.UUID) ([]*ReservationMeta, error) {
-	var result []*ReservationMeta
-	err := pgxscan.Select(
-		ctx, svc.db, &result,
-		`SELECT r.id, r.expires_at, r.is_paid, e.id as event_id, e.name as event_name 
-		FROM reservations r
-		INNER JOIN events e ON r.event_id = e.id
-		WHERE r.actor_id = $1`,
-		userID,
-	)
+// populateReservationItems fetches and attaches line items to passed reservations.
+func (svc Service) populateReservationItems(ctx context.Context, reservations ...*ReservationMeta) error {
+	if len(reservations) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(reservations))
+	byID := make(map[uuid.UUID]*ReservationMeta, len(reservations))
+	for _, r := range reservations {
+		ids = append(ids, r.ID)
+		byID[r.ID] = r
+		r.Items = []*ReservationItem{}
+	}
+
+	items, err := svc.store.getReservationItems(ctx, ids...)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil
+		return err
+	}
+
+	for _, item := range items {
+		r, ok := byID[item.ReservationID]
+		if !ok {
+			continue
 		}
 
-		return nil, fmt.Errorf("failed to query reservations: %w", err)
+		r.Items = append(r.Items, item)
 	}
 
-	return result, nil
+	return nil
 }
 
 type reservationHeader struct {
-	ExpiresAt time.Time `db:"expires_at"`
-	IsPaid    bool      `db:"is_paid"`
+	reservationRef
+	Status     ReservationStatus `db:"status"`
+	TotalCents uint              `db:"total_cents"`
+	Currency   Currency          `db:"currency"`
+	CreatedAt  time.Time         `db:"created_at"`
+
+	// Extensions is number of times reservation hold was extended.
+	Extensions uint `db:"extensions_count"`
+
+	// ResaleListingID is set for reservations which hold a resale listing instead of inventory tickets.
+	ResaleListingID *uuid.UUID `db:"resale_listing_id"`
 }
 
 func (svc Service) PayReservation(ctx context.Context, params PaymentParams) (*PaymentResult, error) {
 	rID := params.ReservationID
 	cardNumber := params.CardNumber
 
-	tx, err := svc.db.BeginTx(ctx, pgx.TxOptions{
-		IsoLevel:   pgx.ReadCommitted,
-		AccessMode: pgx.ReadWrite,
-	})
+	var presentmentCurrency Currency
+	if params.Currency != "" {
+		c, err := ParseCurrency(string(params.Currency))
+		if err != nil {
+			return nil, err
+		}
+
+		presentmentCurrency = c
+	}
+
+	tx, err := svc.store.begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open TX: %w", err)
 	}
 
 	defer tx.Rollback(ctx)
 
-	h := &reservationHeader{}
-	err = pgxscan.Get(ctx, tx, h, `SELECT expires_at, is_paid FROM reservations WHERE id = $1`, rID)
+	// Reservation row is locked to serialize payment with concurrent cancellation or expiry.
+	h, err := tx.lockReservation(ctx, rID)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrNotFound
-		}
-
-		return nil, fmt.Errorf("failed to get reservation: %w", err)
+		return nil, err
 	}
 
-	if h.IsPaid {
-		return nil, errors.New("reservation already paid")
+	switch h.Status {
+	case ReservationPending:
+	case ReservationExpired:
+		return nil, ErrReservationExpired
+	case ReservationPaid:
+		return nil, fmt.Errorf("%w: reservation already paid", ErrInvalidStatus)
+	default:
+		return nil, fmt.Errorf("%w: reservation is %s", ErrInvalidStatus, h.Status)
 	}
 
 	now := time.Now()
@@ -182,137 +299,237 @@ func (svc Service) PayReservation(ctx context.Context, params PaymentParams) (*P
 		return nil, ErrReservationExpired
 	}
 
-	// Get tickets by reservation ID and compute total price from ticket_tiers
-	type ticketPrice struct {
-		PriceCents int `db:"price_cents"`
+	// Total is computed from prices locked at reservation time (see reservation_items),
+	// so any tier price change after tickets were held doesn't affect the charged amount.
+	// Resale reservations hold a listing, which is checked on settlement.
+	if h.ResaleListingID == nil {
+		heldCount, err := tx.countHeldTickets(ctx, rID)
+		if err != nil {
+			return nil, err
+		}
+
+		if heldCount == 0 {
+			return nil, errors.New("no tickets found for reservation")
+		}
 	}
 
-	var ticketPrices []ticketPrice
-	err = pgxscan.Select(ctx, tx, &ticketPrices, `
-		SELECT tt.price_cents
-		FROM tickets t
-		JOIN ticket_tiers tt ON t.tier_id = tt.id
-		WHERE t.hold_token = $1
-	`, rID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get ticket prices: %w", err)
+	totalCents := h.TotalCents
+	settlement := NewMoney(int64(totalCents), h.Currency)
+	if presentmentCurrency == "" {
+		presentmentCurrency = h.Currency
 	}
 
-	if len(ticketPrices) == 0 {
-		return nil, errors.New("no tickets found for reservation")
+	// Settlement amount is authoritative, presentment amount is derived from it.
+	rate, err := svc.rates.GetRate(ctx, h.Currency, presentmentCurrency)
+	if err != nil {
+		return nil, err
 	}
 
-	// Compute total price
-	var totalCents uint = 0
-	for _, tp := range ticketPrices {
-		totalCents += uint(tp.PriceCents)
+	// Converted by stored rate precision, so presentment amount can be reproduced from payment record.
+	rate = rate.Rounded()
+	presentment, err := rate.Convert(settlement)
+	if err != nil {
+		return nil, err
 	}
 
 	// Call payer to process payment
 	payResult, err := svc.payer.Pay(PayParams{
 		ReservID:    rID,
 		Card:        cardNumber,
-		AmountCents: totalCents,
+		Settlement:  settlement,
+		Presentment: presentment,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("payment failed: %w", err)
 	}
 
-	_, err = tx.Exec(ctx, `
-		UPDATE tickets 
-		SET is_sold = true, hold_token = NULL, hold_expires_at = NULL
-		WHERE hold_token = $1
-	`, rID)
-	if err != nil {
+	// rollback reverts charge and seller payout if payment can't be completed.
+	var payoutTxID uuid.UUID
+	rollback := func() {
 		_ = svc.payer.Rollback(payResult.TXID)
-		return nil, fmt.Errorf("failed to mark tickets as sold: %w", err)
+		if payoutTxID != uuid.Nil {
+			_ = svc.payer.Rollback(payoutTxID)
+		}
+	}
+
+	if err := tx.sellHeldTickets(ctx, rID); err != nil {
+		rollback()
+		return nil, err
+	}
+
+	if err := tx.setReservationStatus(ctx, rID, ReservationPaid); err != nil {
+		rollback()
+		return nil, err
+	}
+
+	if err := tx.resolveWaitlistOffers(ctx, WaitlistFulfilled, rID); err != nil {
+		rollback()
+		return nil, err
+	}
+
+	paidEvent := h.event(PaymentSucceededEvent)
+	paidEvent.Amount = &settlement
+	events := []DomainEvent{paidEvent}
+	if h.ResaleListingID != nil {
+		var soldEvent DomainEvent
+		payoutTxID, soldEvent, err = svc.settleResale(ctx, tx, h, *h.ResaleListingID)
+		if err != nil {
+			rollback()
+			return nil, err
+		}
+
+		events = append(events, soldEvent)
+	} else {
+		if err := svc.issueTicketCodes(ctx, tx, rID, h.ActorID); err != nil {
+			rollback()
+			return nil, err
+		}
+
+		// Sold tickets won't return to a queue, so entries which can't be satisfied anymore
+		// are skipped and free tickets are offered to next actors.
+		offers, err := svc.offerPaidTiers(ctx, tx, rID)
+		if err != nil {
+			rollback()
+			return nil, err
+		}
+
+		events = append(events, waitlistOfferEvents(offers)...)
 	}
 
-	_, err = tx.Exec(ctx, `
-		UPDATE reservations 
-		SET is_paid = true
-		WHERE id = $1
-	`, rID)
+	err = tx.insertPayment(ctx, rID, &PaymentRecord{
+		TxID:         payResult.TXID,
+		PaidAt:       now,
+		Settlement:   settlement,
+		Presentment:  presentment,
+		ExchangeRate: rate.String(),
+	})
 	if err != nil {
-		_ = svc.payer.Rollback(payResult.TXID)
-		return nil, fmt.Errorf("failed to mark reservation as paid: %w", err)
+		rollback()
+		return nil, err
+	}
+
+	if err := tx.writeOutbox(ctx, events...); err != nil {
+		rollback()
+		return nil, err
 	}
 
 	if err := tx.Commit(ctx); err != nil {
-		_ = svc.payer.Rollback(payResult.TXID)
+		rollback()
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return &PaymentResult{
-		TxID:        payResult.TXID,
-		AmountCents: totalCents,
+		TxID:         payResult.TXID,
+		AmountCents:  totalCents,
+		Settlement:   settlement,
+		Presentment:  presentment,
+		ExchangeRate: rate.String(),
 	}, nil
 }
 
 func (svc Service) ReserveTickets(ctx context.Context, params ReservationParams) (*ReservationResult, error) {
 	reservationID := uuid.New()
-	expireAt := time.Now().Add(reservationTTL)
 
-	tx, err := svc.db.BeginTx(ctx, pgx.TxOptions{
-		IsoLevel: pgx.ReadCommitted,
-	})
+	email, err := parseContactEmail(params.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := svc.store.begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open TX: %w", err)
 	}
 
-	// TODO: store and update reservation total price
 	defer tx.Rollback(ctx)
 
-	_, err = tx.Exec(
-		ctx, `
-		INSERT INTO reservations (id, event_id, actor_id, expires_at, idempotency_key)
-		VALUES ($1, $2, $3, $4, $5)
-		ON CONFLICT (idempotency_key) DO NOTHING
-		`,
-		reservationID, params.EventID, params.ActorID, expireAt, params.IdempotencyKey,
-	)
+	// Reservation is settled in event currency.
+	event, err := tx.shareEvent(ctx, params.EventID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create reservation: %w", err)
+		return nil, err
+	}
+
+	if event.CancelledAt != nil {
+		return nil, fmt.Errorf("%w: event is cancelled", ErrInvalidStatus)
 	}
 
+	expireAt := time.Now().Add(time.Duration(event.HoldTTLSeconds) * time.Second)
+	created, err := tx.insertReservation(ctx, &newReservation{
+		reservationRef: reservationRef{
+			ID:        reservationID,
+			EventID:   params.EventID,
+			ActorID:   params.ActorID,
+			Email:     email,
+			ExpiresAt: expireAt,
+		},
+		Currency:       event.Currency,
+		IdempotencyKey: &params.IdempotencyKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !created {
+		// Request with the same idempotency key was already processed.
+		_ = tx.Rollback(ctx)
+		return svc.store.getReservationByIdempotencyKey(ctx, params.IdempotencyKey)
+	}
+
+	tierIDs := make([]uuid.UUID, 0, len(params.TicketsCount))
+	for tierID, qty := range params.TicketsCount {
+		if qty > 0 {
+			tierIDs = append(tierIDs, tierID)
+		}
+	}
+
+	tiers, err := tx.getTierPricing(ctx, tierIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tiers with non-empty waitlist are sold-out for public until all waiting actors get their offers.
+	waitlisted, err := tx.getWaitlistedTiers(ctx, tierIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var totalCents uint
 	for tierID, qty := range params.TicketsCount {
 		if qty == 0 {
 			continue
 		}
 
-		rows, err := tx.Query(ctx, `
-			WITH picked AS (
-				SELECT id
-				FROM tickets
-				WHERE event_id = $1
-					AND tier_id  = $2
-					AND is_sold  = false
-					AND (hold_expires_at IS NULL OR hold_expires_at < now())
-				ORDER BY id
-				FOR UPDATE SKIP LOCKED
-				LIMIT $3
-			)
-			UPDATE tickets t
-			SET hold_token = $4, hold_expires_at = $5
-			WHERE t.id IN (SELECT id FROM picked)
-			RETURNING t.id
-		`,
-			params.EventID, tierID, qty, reservationID, expireAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to lock tickets of tier %q: %w", tierID, err)
+		tier, ok := tiers[tierID]
+		if !ok || tier.EventID != params.EventID || waitlisted[tierID] {
+			return nil, NewInsufficientTicketsError(tierID)
 		}
 
-		// cmp locked and expected count
-		got := 0
-		for rows.Next() {
-			got++
+		unitPrice, ok, err := holdTierTickets(ctx, tx, tier, reservationID, qty, expireAt, now)
+		if err != nil {
+			return nil, err
 		}
-		rows.Close()
 
-		if got != int(qty) {
+		if !ok {
 			return nil, NewInsufficientTicketsError(tierID)
 		}
+
+		totalCents += qty * unitPrice
+	}
+
+	if err := tx.setReservationTotal(ctx, reservationID, totalCents); err != nil {
+		return nil, err
+	}
+
+	createdEvent := reservationRef{
+		ID:        reservationID,
+		EventID:   params.EventID,
+		ActorID:   params.ActorID,
+		Email:     email,
+		ExpiresAt: expireAt,
+	}.event(ReservationCreatedEvent)
+	createdEvent.ExpiresAt = &expireAt
+	if err := tx.writeOutbox(ctx, createdEvent); err != nil {
+		return nil, err
 	}
 
 	if err := tx.Commit(ctx); err != nil {
@@ -325,33 +542,89 @@ func (svc Service) ReserveTickets(ctx context.Context, params ReservationParams)
 	}, nil
 }
 
-func (svc Service) GetTicketTiers(ctx context.Context, eventID uuid.UUID) ([]*TicketTier, error) {
-	var result []*TicketTier
-
-	// TODO: use different approach to check counters as query is quite expensive.
-	query := `
-	SELECT 
-		tt.id AS tier_id,
-		tt.name AS tier_name,
-		tt.price_cents,
-		COUNT(*) FILTER (
-			WHERE t.is_sold = FALSE 
-			AND (t.hold_expires_at IS NULL OR t.hold_expires_at < now())
-		) AS available_count
-	FROM ticket_tiers tt
-	LEFT JOIN tickets t ON t.tier_id = tt.id
-	WHERE tt.event_id = $1
-	GROUP BY tt.id, tt.name, tt.price_cents, tt.event_id
-	ORDER BY tt.price_cents
-`
-	err := pgxscan.Select(ctx, svc.db, &result, query, eventID)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrNotFound
+// holdTierTickets holds requested amount of tier tickets for a reservation
+// and stores reservation line item with locked-in unit price.
+//
+// Held tickets are priced at the current tier price. If reservation already has a line item of the tier
+// with the same unit price, tickets are added to it, otherwise a new line is created,
+// so tickets held earlier keep their locked price.
+//
+// Tickets are held all-or-nothing: if there is not enough free tickets, nothing is held
+// and false is returned.
+func holdTierTickets(
+	ctx context.Context, tx storeTx, tier *tierPricing, reservationID uuid.UUID, qty uint, expireAt, now time.Time,
+) (unitPrice uint, ok bool, err error) {
+	// Price is locked-in at the moment of reservation and stored in reservation items.
+	unitPrice = uint(pricing.PriceOf(tier.state(), tier.Rules, now))
+
+	ok, err = tx.holdTickets(ctx, tier.EventID, tier.TierID, reservationID, qty, expireAt)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+
+	if err := tx.addReservationItem(ctx, reservationID, tier.TierID, qty, unitPrice); err != nil {
+		return 0, false, err
+	}
+
+	// Taken tickets percentage changed, so price for next buyers might change as well.
+	tier.Taken += int(qty)
+	newPrice := pricing.PriceOf(tier.state(), tier.Rules, now)
+	if newPrice != tier.PriceCents {
+		if err := tx.updateTierPrice(ctx, tier.TierID, newPrice, priceReasonReservation); err != nil {
+			return 0, false, err
 		}
 
+		tier.PriceCents = newPrice
+	}
+
+	return unitPrice, true, nil
+}
+
+// GetTicketTiers returns event ticket tiers with availability and effective prices.
+//
+// If presentment currency is specified, prices are also converted to that currency for display.
+func (svc Service) GetTicketTiers(ctx context.Context, eventID uuid.UUID, presentment Currency) ([]*TicketTier, error) {
+	tiers, err := svc.store.getEventTiers(ctx, eventID)
+	if err != nil {
 		return nil, err
 	}
 
-	return result, err
+	// Stored price might be outdated if time window opened or closed since the last reprice.
+	now := time.Now()
+	result := make([]*TicketTier, 0, len(tiers))
+	for _, t := range tiers {
+		tier := &TicketTier{
+			TierID:         t.TierID,
+			Name:           t.Name,
+			PriceCents:     t.PriceCents,
+			Currency:       t.Currency,
+			AvailableCount: t.AvailableCount,
+		}
+
+		if len(t.Rules) > 0 {
+			quote := pricing.Evaluate(t.state(), t.Rules, now)
+			tier.PriceCents = quote.PriceCents
+			tier.NextThreshold = quote.Next
+		}
+
+		if presentment != "" {
+			// Each unit price is converted individually, so sum of displayed prices
+			// may differ from converted reservation total by 1 minor unit per item.
+			rate, err := svc.rates.GetRate(ctx, tier.Currency, presentment)
+			if err != nil {
+				return nil, err
+			}
+
+			price, err := rate.Convert(NewMoney(int64(tier.PriceCents), tier.Currency))
+			if err != nil {
+				return nil, err
+			}
+
+			tier.PresentmentPrice = &price
+		}
+
+		result = append(result, tier)
+	}
+
+	return result, nil
 }
@@ -0,0 +1,323 @@
+package booking
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Store is persistence layer of Service.
+//
+// Service owns booking rules: state transitions, pricing, waitlist order, payments and domain events.
+// Store only reads and writes state, so the same rules run on top of PostgresStore and MemoryStore.
+//
+// Rows returned by store are copies and can be modified by caller.
+type Store interface {
+	storeQueries
+
+	// begin starts a read-write transaction. Transaction should be rolled back if it's not committed.
+	begin(ctx context.Context) (storeTx, error)
+}
+
+// storeQueries are read queries available both within and outside a transaction.
+//
+// Missing rows are reported as ErrNotFound, unless method documents otherwise.
+type storeQueries interface {
+	getEvents(ctx context.Context) ([]*Event, error)
+	getEvent(ctx context.Context, eventID uuid.UUID) (*Event, error)
+
+	// getEventTiers returns event tiers with ticket counters ordered by stored price.
+	// Unknown event has no tiers.
+	getEventTiers(ctx context.Context, eventID uuid.UUID) ([]*eventTier, error)
+
+	// getTierPricing returns pricing snapshot of tiers by ID. Unknown tiers are omitted.
+	getTierPricing(ctx context.Context, tierIDs []uuid.UUID) (map[uuid.UUID]*tierPricing, error)
+
+	// getPricedTierIDs returns tiers which have pricing rules.
+	getPricedTierIDs(ctx context.Context) ([]uuid.UUID, error)
+	getPriceHistory(ctx context.Context, tierID uuid.UUID) ([]*PriceChange, error)
+
+	// getReservation returns reservation without line items.
+	getReservation(ctx context.Context, reservationID uuid.UUID) (*ReservationMeta, error)
+	getUserReservations(ctx context.Context, actorID uuid.UUID) ([]*ReservationMeta, error)
+	getReservationHeader(ctx context.Context, reservationID uuid.UUID) (*reservationHeader, error)
+	getReservationByIdempotencyKey(ctx context.Context, key uuid.UUID) (*ReservationResult, error)
+
+	// getReservationItems returns line items of reservations, the most expensive first.
+	getReservationItems(ctx context.Context, reservationIDs ...uuid.UUID) ([]*ReservationItem, error)
+
+	countHeldTickets(ctx context.Context, reservationID uuid.UUID) (int, error)
+	getSoldTickets(ctx context.Context, reservationID uuid.UUID) ([]soldTicket, error)
+
+	// getIssuedTickets returns valid codes of reservation tickets owned by an actor.
+	getIssuedTickets(ctx context.Context, reservationID, ownerID uuid.UUID) ([]*IssuedTicket, error)
+	getUserTickets(ctx context.Context, actorID uuid.UUID) ([]*IssuedTicket, error)
+	getTicketOwnership(ctx context.Context, ticketID uuid.UUID) ([]*OwnershipRecord, error)
+
+	// hasForeignCodes reports whether valid codes of reservation tickets are owned by someone else than owner.
+	hasForeignCodes(ctx context.Context, reservationID, ownerID uuid.UUID) (bool, error)
+	getTicketCodeState(ctx context.Context, codeID uuid.UUID) (*ticketCodeState, error)
+
+	// isRefundedSince reports whether ticket code was revoked without reissue (e.g. by refund) after specified time.
+	isRefundedSince(ctx context.Context, ticketID uuid.UUID, t time.Time) (bool, error)
+	getFaceValues(ctx context.Context, ticketIDs []uuid.UUID) ([]listedTicket, error)
+
+	// getPayments returns reservation payments in chronological order.
+	getPayments(ctx context.Context, reservationID uuid.UUID) ([]*PaymentRecord, error)
+
+	// getDueRefunds returns pending refunds which should be retried now.
+	getDueRefunds(ctx context.Context, limit int) ([]uuid.UUID, error)
+
+	getWaitlistEntry(ctx context.Context, entryID uuid.UUID) (*WaitlistEntry, error)
+	getUserWaitlistEntries(ctx context.Context, actorID uuid.UUID) ([]*WaitlistEntry, error)
+
+	// getWaitlistedTiers returns set of tiers which have actors waiting in a queue.
+	//
+	// Entries which request more tickets than there are unsold in a tier are ignored,
+	// as they can't be satisfied until some tickets are refunded.
+	getWaitlistedTiers(ctx context.Context, tierIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+
+	// getWaitingTiers returns reservation tiers which have waiting waitlist entries.
+	getWaitingTiers(ctx context.Context, reservationID uuid.UUID) ([]uuid.UUID, error)
+	isWaitlistOffer(ctx context.Context, reservationID uuid.UUID) (bool, error)
+
+	getResaleListing(ctx context.Context, listingID uuid.UUID) (*ResaleListing, error)
+
+	// getEventResaleListings returns active listings which are not held and have no checked-in tickets,
+	// cheapest first.
+	getEventResaleListings(ctx context.Context, eventID uuid.UUID) ([]*ResaleListing, error)
+	getUserResaleListings(ctx context.Context, actorID uuid.UUID) ([]*ResaleListing, error)
+	hasActiveListings(ctx context.Context, ticketIDs []uuid.UUID) (bool, error)
+
+	getTransfer(ctx context.Context, transferID uuid.UUID) (*Transfer, error)
+
+	// getTransferClaimToken returns claim token of a transfer, which is nil for transfers addressed by actor ID.
+	getTransferClaimToken(ctx context.Context, transferID uuid.UUID) (*string, error)
+	getUserTransfers(ctx context.Context, actorID uuid.UUID) ([]*Transfer, error)
+	hasPendingTransfers(ctx context.Context, ticketIDs []uuid.UUID) (bool, error)
+
+	// getScan returns uploaded offline scan or nil if scan is new.
+	getScan(ctx context.Context, scanID uuid.UUID) (*checkInScan, error)
+
+	// getCheckInCounts returns number of valid event tickets by check-in gate.
+	getCheckInCounts(ctx context.Context, eventID uuid.UUID) ([]gateCount, error)
+}
+
+// storeTx is a store transaction.
+//
+// Lock methods lock returned rows until end of transaction, the same as row locks in Postgres.
+type storeTx interface {
+	storeQueries
+
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context) error
+
+	// savepoint starts a nested transaction, which can be rolled back without affecting this one.
+	savepoint(ctx context.Context) (storeTx, error)
+
+	// writeOutbox stores domain events, which are published only if transaction is committed.
+	writeOutbox(ctx context.Context, events ...DomainEvent) error
+
+	insertEvent(ctx context.Context, event *Event) error
+
+	// shareEvent returns event and prevents its concurrent update.
+	shareEvent(ctx context.Context, eventID uuid.UUID) (*Event, error)
+	lockEvent(ctx context.Context, eventID uuid.UUID) (*Event, error)
+	cancelEvent(ctx context.Context, eventID uuid.UUID) error
+	setTransfersBlocked(ctx context.Context, eventID uuid.UUID, blocked bool) error
+
+	// insertTier creates a tier with its pricing rules and tickets.
+	insertTier(ctx context.Context, tier *eventTier, ticketsCount int) error
+
+	// setTierPricing replaces tier base price, price limits and pricing rules.
+	setTierPricing(ctx context.Context, tierID uuid.UUID, params TierPricingParams) error
+
+	// updateTierPrice stores tier effective price and records the change in price history.
+	//
+	// Price is updated only if it differs from the stored one,
+	// so concurrent transactions won't record the same change twice.
+	updateTierPrice(ctx context.Context, tierID uuid.UUID, newPrice int, reason string) error
+
+	// insertReservation creates a pending reservation.
+	//
+	// Returns false if reservation with the same idempotency key already exists.
+	insertReservation(ctx context.Context, r *newReservation) (bool, error)
+	lockReservation(ctx context.Context, reservationID uuid.UUID) (*reservationHeader, error)
+	setReservationStatus(ctx context.Context, reservationID uuid.UUID, status ReservationStatus) error
+	setReservationTotal(ctx context.Context, reservationID uuid.UUID, totalCents uint) error
+	linkResaleListing(ctx context.Context, reservationID, listingID uuid.UUID) error
+
+	// setHoldExpiration moves expiration time of reservation and everything it holds, counting an extension.
+	//
+	// Hold reminder is sent again before the new expiration time.
+	setHoldExpiration(ctx context.Context, reservationID uuid.UUID, expireAt time.Time) error
+
+	// expireReservations expires up to limit pending reservations which hold has lapsed.
+	//
+	// Reservations locked by concurrent transactions are skipped.
+	expireReservations(ctx context.Context, limit int) ([]reservationRef, error)
+
+	// markExpiringReservations marks up to limit pending reservations with contact email
+	// which expire within lead time as reminded. Already reminded reservations are skipped.
+	markExpiringReservations(ctx context.Context, lead time.Duration, limit int) ([]reservationRef, error)
+
+	// cancelEventReservations cancels pending reservations of an event.
+	//
+	// Returns cancelled and paid reservations.
+	cancelEventReservations(ctx context.Context, eventID uuid.UUID) ([]reservationRef, error)
+
+	// addReservationItem adds tickets to reservation line item with the same tier and unit price.
+	addReservationItem(ctx context.Context, reservationID, tierID uuid.UUID, quantity, unitPriceCents uint) error
+
+	// setReservationItem updates line item quantity, item with zero quantity is deleted.
+	setReservationItem(ctx context.Context, item *ReservationItem) error
+
+	// holdTickets holds free tier tickets for reservation.
+	//
+	// Tickets are held all-or-nothing: if there is not enough free tickets, nothing is held and false is returned.
+	// Tickets held by concurrent transactions are skipped, so two transactions never pick the same ticket.
+	holdTickets(
+		ctx context.Context, eventID, tierID, reservationID uuid.UUID, quantity uint, expireAt time.Time,
+	) (bool, error)
+
+	// releaseHeldTickets releases tickets held by reservations and returns affected tier IDs.
+	releaseHeldTickets(ctx context.Context, reservationIDs ...uuid.UUID) ([]uuid.UUID, error)
+
+	// releaseTierTickets releases count of tier tickets held by reservation.
+	releaseTierTickets(ctx context.Context, reservationID, tierID uuid.UUID, count uint) error
+	releaseEventTickets(ctx context.Context, eventID uuid.UUID) error
+	sellHeldTickets(ctx context.Context, reservationID uuid.UUID) error
+
+	// returnSoldTickets returns tickets sold to reservation back to inventory and returns affected tier IDs.
+	returnSoldTickets(ctx context.Context, reservationID uuid.UUID) ([]uuid.UUID, error)
+	moveTickets(ctx context.Context, ticketIDs []uuid.UUID, reservationID uuid.UUID) error
+	checkInTicket(ctx context.Context, ticketID uuid.UUID, at time.Time, gateID string) error
+
+	insertTicketCodes(ctx context.Context, codes []*ticketCode) error
+
+	// lockTicketCodes returns valid codes of tickets ordered by ticket ID.
+	lockTicketCodes(ctx context.Context, ticketIDs []uuid.UUID) ([]transferTicket, error)
+	lockTicketCode(ctx context.Context, codeID uuid.UUID) (*ticketCodeState, error)
+	revokeReservationCodes(ctx context.Context, reservationID uuid.UUID) error
+
+	// reissueCodes revokes codes which are replaced by codes of a new owner.
+	reissueCodes(ctx context.Context, codeIDs []uuid.UUID) error
+
+	insertPayment(ctx context.Context, reservationID uuid.UUID, p *PaymentRecord) error
+
+	// lockRefundablePayment returns the latest not refunded payment of reservation.
+	lockRefundablePayment(ctx context.Context, reservationID uuid.UUID) (*PaymentRecord, error)
+	setPaymentRefunded(ctx context.Context, txID uuid.UUID) error
+
+	insertPendingRefund(ctx context.Context, txID, reservationID uuid.UUID) error
+
+	// lockPendingRefund returns number of failed refund attempts.
+	//
+	// Returns false if refund is already issued or is locked by another transaction.
+	lockPendingRefund(ctx context.Context, txID uuid.UUID) (attempts int, ok bool, err error)
+	deferPendingRefund(ctx context.Context, txID uuid.UUID, lastErr string, backoff time.Duration) error
+	deletePendingRefund(ctx context.Context, txID uuid.UUID) error
+
+	// insertWaitlistEntry creates waiting entry. Returns ErrAlreadyWaitlisted if actor already has active entry.
+	insertWaitlistEntry(ctx context.Context, entry *WaitlistEntry, email *string) error
+	lockWaitlistEntry(ctx context.Context, entryID uuid.UUID) (*WaitlistEntry, error)
+	setWaitlistStatus(ctx context.Context, entryID uuid.UUID, status WaitlistStatus) error
+
+	// lockWaitlistHead returns the first waiting entry of a tier queue which requests at most maxQuantity tickets.
+	//
+	// Returns nil if there is no such entry.
+	lockWaitlistHead(ctx context.Context, tierID uuid.UUID, maxQuantity int) (*waitlistHead, error)
+	setWaitlistOffer(ctx context.Context, entryID, reservationID uuid.UUID) error
+
+	// resolveWaitlistOffers sets status of waitlist entries offered by reservations.
+	resolveWaitlistOffers(ctx context.Context, status WaitlistStatus, reservationIDs ...uuid.UUID) error
+	cancelEventWaitlist(ctx context.Context, eventID uuid.UUID) error
+
+	insertResaleListing(ctx context.Context, listing *ResaleListing) error
+	lockResaleListing(ctx context.Context, listingID uuid.UUID) (*ResaleListing, error)
+
+	// setListingStatus resolves listing with passed status.
+	setListingStatus(ctx context.Context, listingID uuid.UUID, status ResaleStatus) error
+	holdListing(ctx context.Context, listingID, reservationID uuid.UUID, expireAt time.Time) error
+
+	// sellListing marks active listing held by reservation as sold.
+	//
+	// Returns false if listing is no longer held by reservation.
+	sellListing(ctx context.Context, listingID, reservationID uuid.UUID) (bool, error)
+	setListingPayout(ctx context.Context, listingID uuid.UUID, feeCents, payoutCents int64, txID uuid.UUID) error
+
+	// releaseHeldListings releases resale listings held by pending reservations.
+	releaseHeldListings(ctx context.Context, reservationIDs ...uuid.UUID) error
+
+	// cancelReservationListings cancels active listings of reservation tickets.
+	cancelReservationListings(ctx context.Context, reservationID uuid.UUID) error
+	cancelEventListings(ctx context.Context, eventID uuid.UUID) error
+
+	insertTransfer(ctx context.Context, transfer *Transfer, claimToken *string) error
+	lockTransfer(ctx context.Context, transferID uuid.UUID) (*Transfer, error)
+
+	// resolveTransfer sets final status of transfer. Accepting actor is set for accepted transfers.
+	resolveTransfer(ctx context.Context, transferID uuid.UUID, status TransferStatus, acceptedBy *uuid.UUID) error
+
+	// cancelReservationTransfers cancels pending transfers of reservation tickets.
+	cancelReservationTransfers(ctx context.Context, reservationID uuid.UUID) error
+	cancelEventTransfers(ctx context.Context, eventID uuid.UUID) error
+
+	// lockScan serializes processing of offline scan with the same ID.
+	lockScan(ctx context.Context, scanID uuid.UUID) error
+	insertScan(ctx context.Context, scan *checkInScan) error
+
+	// supersedeScans marks accepted scans of a ticket made at specified time as superseded.
+	supersedeScans(ctx context.Context, ticketID uuid.UUID, scannedAt time.Time) error
+}
+
+// eventTier is a ticket tier with pricing snapshot and availability.
+type eventTier struct {
+	tierPricing
+	Name           string   `db:"tier_name"`
+	Currency       Currency `db:"currency"`
+	AvailableCount int      `db:"available_count"`
+}
+
+// newReservation is a pending reservation to create.
+type newReservation struct {
+	reservationRef
+	Currency Currency
+
+	// IdempotencyKey is nil for reservations created by the service itself, e.g. waitlist offers.
+	IdempotencyKey *uuid.UUID
+}
+
+// ticketCode is a signed code issued to ticket owner.
+type ticketCode struct {
+	ID            uuid.UUID
+	TicketID      uuid.UUID
+	ReservationID uuid.UUID
+	EventID       uuid.UUID
+	Code          string
+	OwnerID       uuid.UUID
+
+	// TransferID is set if owner received ticket by transfer.
+	TransferID *uuid.UUID
+}
+
+// checkInScan is a stored ticket scan.
+type checkInScan struct {
+	// ScanID is set only for offline scans.
+	ScanID *uuid.UUID `db:"scan_id"`
+
+	// CodeID is empty for codes which are not issued.
+	CodeID    *uuid.UUID    `db:"code_id"`
+	GateID    string        `db:"gate_id"`
+	Status    CheckInStatus `db:"status"`
+	IsOffline bool          `db:"is_offline"`
+	ScannedAt time.Time     `db:"scanned_at"`
+}
+
+// gateCount is number of valid tickets checked-in by a gate.
+type gateCount struct {
+	// GateID is nil for tickets which are not checked-in.
+	GateID *string `db:"checked_in_gate"`
+	Count  int     `db:"count"`
+}
@@ -0,0 +1,1893 @@
+package booking
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/pricing"
+)
+
+// MemoryStore is Store implementation which keeps state in process memory.
+//
+// Write transactions are serialized by a single lock and work on a private copy of the state,
+// which replaces committed state on commit. Reads outside a transaction see the last committed state.
+// Published domain events are kept in memory and are never delivered,
+// so memory store is intended for unit tests and local experiments.
+type MemoryStore struct {
+	memoryQueries
+
+	mu    sync.Mutex
+	state atomic.Pointer[memoryState]
+}
+
+// NewMemoryStore returns a new empty memory store.
+func NewMemoryStore() *MemoryStore {
+	s := &MemoryStore{}
+	s.memoryQueries = memoryQueries{state: s.state.Load}
+	s.state.Store(newMemoryState())
+	return s
+}
+
+func (s *MemoryStore) begin(_ context.Context) (storeTx, error) {
+	s.mu.Lock()
+	tx := &memoryTx{
+		store: s,
+		state: s.state.Load().clone(),
+	}
+	tx.memoryQueries = memoryQueries{state: tx.current}
+	return tx, nil
+}
+
+// memoryState is a snapshot of all stored rows.
+//
+// Committed snapshot is never modified, transactions modify its copy.
+type memoryState struct {
+	// seq orders rows by insertion, it stands in for creation time and serial IDs.
+	seq int64
+
+	events       map[uuid.UUID]*memoryEvent
+	tiers        map[uuid.UUID]*memoryTier
+	tickets      map[uuid.UUID]*memoryTicket
+	reservations map[uuid.UUID]*memoryReservation
+	idempotency  map[uuid.UUID]uuid.UUID
+	items        map[memoryItemKey]*memoryItem
+	priceHistory map[uuid.UUID][]PriceChange
+	codes        map[uuid.UUID]*memoryCode
+	payments     map[uuid.UUID]*memoryPayment
+	refunds      map[uuid.UUID]*memoryRefund
+	waitlist     map[uuid.UUID]*memoryWaitlistEntry
+	listings     map[uuid.UUID]*memoryListing
+	transfers    map[uuid.UUID]*memoryTransfer
+	scans        map[int64]*memoryScan
+	outbox       []DomainEvent
+}
+
+type memoryEvent struct {
+	Event
+	seq int64
+}
+
+type memoryTier struct {
+	// tierPricing keeps static pricing configuration, counters are computed from tickets.
+	tierPricing
+
+	name     string
+	currency Currency
+	seq      int64
+
+	// tickets are IDs of tier tickets in the order they are picked.
+	tickets []uuid.UUID
+}
+
+type memoryTicket struct {
+	id      uuid.UUID
+	eventID uuid.UUID
+	tierID  uuid.UUID
+	isSold  bool
+
+	// reservationID is ID of reservation which bought a ticket.
+	reservationID *uuid.UUID
+
+	// holdToken is ID of reservation which holds a ticket until holdExpiresAt.
+	holdToken     *uuid.UUID
+	holdExpiresAt *time.Time
+
+	checkedInAt   *time.Time
+	checkedInGate *string
+}
+
+// isTaken reports whether ticket is sold or actively held.
+func (t *memoryTicket) isTaken(now time.Time) bool {
+	return t.isSold || (t.holdExpiresAt != nil && !t.holdExpiresAt.Before(now))
+}
+
+// isAvailable reports whether ticket can be held.
+func (t *memoryTicket) isAvailable(now time.Time) bool {
+	return !t.isSold && (t.holdExpiresAt == nil || t.holdExpiresAt.Before(now))
+}
+
+func (t *memoryTicket) releaseHold() {
+	t.holdToken = nil
+	t.holdExpiresAt = nil
+}
+
+type memoryReservation struct {
+	reservationHeader
+	isPaid         bool
+	idempotencyKey *uuid.UUID
+	reminderSentAt *time.Time
+	seq            int64
+}
+
+type memoryItemKey struct {
+	reservationID  uuid.UUID
+	tierID         uuid.UUID
+	unitPriceCents uint
+}
+
+type memoryItem struct {
+	key      memoryItemKey
+	quantity uint
+}
+
+type memoryCode struct {
+	ticketCode
+	issuedAt  time.Time
+	revokedAt *time.Time
+	reissued  bool
+	seq       int64
+}
+
+type memoryPayment struct {
+	PaymentRecord
+	reservationID uuid.UUID
+	seq           int64
+}
+
+type memoryRefund struct {
+	attempts      int
+	lastError     string
+	nextAttemptAt time.Time
+	seq           int64
+}
+
+type memoryWaitlistEntry struct {
+	WaitlistEntry
+	email *string
+	seq   int64
+}
+
+type memoryListing struct {
+	// ResaleListing keeps stored columns, tier name and hold state are filled on read.
+	ResaleListing
+
+	holdToken          *uuid.UUID
+	holdExpiresAt      *time.Time
+	buyerReservationID *uuid.UUID
+	payoutTxID         *uuid.UUID
+	seq                int64
+}
+
+type memoryTransfer struct {
+	Transfer
+	claimToken *string
+	seq        int64
+}
+
+type memoryScan struct {
+	checkInScan
+	seq int64
+}
+
+func newMemoryState() *memoryState {
+	return &memoryState{
+		events:       map[uuid.UUID]*memoryEvent{},
+		tiers:        map[uuid.UUID]*memoryTier{},
+		tickets:      map[uuid.UUID]*memoryTicket{},
+		reservations: map[uuid.UUID]*memoryReservation{},
+		idempotency:  map[uuid.UUID]uuid.UUID{},
+		items:        map[memoryItemKey]*memoryItem{},
+		priceHistory: map[uuid.UUID][]PriceChange{},
+		codes:        map[uuid.UUID]*memoryCode{},
+		payments:     map[uuid.UUID]*memoryPayment{},
+		refunds:      map[uuid.UUID]*memoryRefund{},
+		waitlist:     map[uuid.UUID]*memoryWaitlistEntry{},
+		listings:     map[uuid.UUID]*memoryListing{},
+		transfers:    map[uuid.UUID]*memoryTransfer{},
+		scans:        map[int64]*memoryScan{},
+	}
+}
+
+// cloneRows returns a copy of rows map. Rows are copied, slices referenced by rows are shared
+// and are always replaced instead of being modified in place.
+func cloneRows[K comparable, V any](rows map[K]*V) map[K]*V {
+	result := make(map[K]*V, len(rows))
+	for k, v := range rows {
+		row := *v
+		result[k] = &row
+	}
+
+	return result
+}
+
+func (s *memoryState) clone() *memoryState {
+	history := make(map[uuid.UUID][]PriceChange, len(s.priceHistory))
+	for k, v := range s.priceHistory {
+		history[k] = slices.Clip(v)
+	}
+
+	idempotency := make(map[uuid.UUID]uuid.UUID, len(s.idempotency))
+	for k, v := range s.idempotency {
+		idempotency[k] = v
+	}
+
+	return &memoryState{
+		seq:          s.seq,
+		events:       cloneRows(s.events),
+		tiers:        cloneRows(s.tiers),
+		tickets:      cloneRows(s.tickets),
+		reservations: cloneRows(s.reservations),
+		idempotency:  idempotency,
+		items:        cloneRows(s.items),
+		priceHistory: history,
+		codes:        cloneRows(s.codes),
+		payments:     cloneRows(s.payments),
+		refunds:      cloneRows(s.refunds),
+		waitlist:     cloneRows(s.waitlist),
+		listings:     cloneRows(s.listings),
+		transfers:    cloneRows(s.transfers),
+		scans:        cloneRows(s.scans),
+		outbox:       slices.Clip(s.outbox),
+	}
+}
+
+func (s *memoryState) nextSeq() int64 {
+	s.seq++
+	return s.seq
+}
+
+func compareIDs(a, b uuid.UUID) int {
+	return slices.Compare(a[:], b[:])
+}
+
+// sortedIDs returns a sorted copy of IDs.
+func sortedIDs(ids []uuid.UUID) []uuid.UUID {
+	ids = slices.Clone(ids)
+	slices.SortFunc(ids, compareIDs)
+	return ids
+}
+
+// collectRows returns copies of rows which match filter ordered by cmp.
+func collectRows[K comparable, V any](rows map[K]*V, match func(*V) bool, order func(a, b *V) int) []*V {
+	var result []*V
+	for _, row := range rows {
+		if match(row) {
+			c := *row
+			result = append(result, &c)
+		}
+	}
+
+	slices.SortFunc(result, order)
+	return result
+}
+
+// tierPricing returns tier pricing snapshot with actual ticket counters.
+func (s *memoryState) tierPricing(tier *memoryTier, now time.Time) *tierPricing {
+	tp := tier.tierPricing
+	tp.Rules = slices.Clone(tier.Rules)
+	tp.Capacity = len(tier.tickets)
+	for _, id := range tier.tickets {
+		t := s.tickets[id]
+		if t.isTaken(now) {
+			tp.Taken++
+		}
+
+		if t.isSold {
+			tp.Sold++
+		}
+	}
+
+	return &tp
+}
+
+func (s *memoryState) reservationRef(r *memoryReservation) reservationRef {
+	return r.reservationRef
+}
+
+func (s *memoryState) listing(l *memoryListing, now time.Time) *ResaleListing {
+	listing := l.ResaleListing
+	listing.TierName = s.tiers[l.TierID].name
+	listing.TicketIDs = slices.Clone(l.TicketIDs)
+	if l.Status == ResaleActive && l.holdExpiresAt != nil && !l.holdExpiresAt.Before(now) {
+		heldUntil := *l.holdExpiresAt
+		listing.HeldUntil = &heldUntil
+	}
+
+	return &listing
+}
+
+func (s *memoryState) transfer(t *memoryTransfer) *Transfer {
+	transfer := t.Transfer
+	transfer.TicketIDs = slices.Clone(t.TicketIDs)
+	return &transfer
+}
+
+// validCode returns valid code of a ticket.
+func (s *memoryState) validCode(ticketID uuid.UUID) *memoryCode {
+	for _, c := range s.codes {
+		if c.TicketID == ticketID && c.revokedAt == nil {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// memoryQueries reads either committed state or state of a transaction.
+type memoryQueries struct {
+	state func() *memoryState
+}
+
+func (q memoryQueries) getEvents(_ context.Context) ([]*Event, error) {
+	s := q.state()
+	events := collectRows(s.events, func(*memoryEvent) bool { return true }, func(a, b *memoryEvent) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+
+	result := make([]*Event, 0, len(events))
+	for _, e := range events {
+		result = append(result, &e.Event)
+	}
+
+	return result, nil
+}
+
+func (q memoryQueries) getEvent(_ context.Context, eventID uuid.UUID) (*Event, error) {
+	e, ok := q.state().events[eventID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	event := e.Event
+	return &event, nil
+}
+
+func (q memoryQueries) getEventTiers(_ context.Context, eventID uuid.UUID) ([]*eventTier, error) {
+	s := q.state()
+	now := time.Now()
+	result := []*eventTier{}
+	for _, tier := range s.tiers {
+		if tier.EventID != eventID {
+			continue
+		}
+
+		available := 0
+		for _, id := range tier.tickets {
+			if s.tickets[id].isAvailable(now) {
+				available++
+			}
+		}
+
+		result = append(result, &eventTier{
+			tierPricing:    *s.tierPricing(tier, now),
+			Name:           tier.name,
+			Currency:       tier.currency,
+			AvailableCount: available,
+		})
+	}
+
+	slices.SortFunc(result, func(a, b *eventTier) int {
+		return cmp.Or(cmp.Compare(a.PriceCents, b.PriceCents), cmp.Compare(a.Name, b.Name))
+	})
+
+	return result, nil
+}
+
+func (q memoryQueries) getTierPricing(_ context.Context, tierIDs []uuid.UUID) (map[uuid.UUID]*tierPricing, error) {
+	s := q.state()
+	now := time.Now()
+	result := make(map[uuid.UUID]*tierPricing, len(tierIDs))
+	for _, id := range tierIDs {
+		if tier, ok := s.tiers[id]; ok {
+			result[id] = s.tierPricing(tier, now)
+		}
+	}
+
+	return result, nil
+}
+
+func (q memoryQueries) getPricedTierIDs(_ context.Context) ([]uuid.UUID, error) {
+	var result []uuid.UUID
+	for id, tier := range q.state().tiers {
+		if len(tier.Rules) > 0 {
+			result = append(result, id)
+		}
+	}
+
+	return result, nil
+}
+
+func (q memoryQueries) getPriceHistory(_ context.Context, tierID uuid.UUID) ([]*PriceChange, error) {
+	history := q.state().priceHistory[tierID]
+	result := make([]*PriceChange, 0, len(history))
+	for _, c := range history {
+		result = append(result, &c)
+	}
+
+	return result, nil
+}
+
+func (q memoryQueries) reservationMeta(s *memoryState, r *memoryReservation) *ReservationMeta {
+	meta := &ReservationMeta{
+		ID:         r.ID,
+		EventID:    r.EventID,
+		ExpiresAt:  r.ExpiresAt,
+		IsPaid:     r.isPaid,
+		Status:     r.Status,
+		TotalCents: r.TotalCents,
+		Currency:   r.Currency,
+		Extensions: r.Extensions,
+	}
+	if e, ok := s.events[r.EventID]; ok {
+		meta.EventName = e.Name
+	}
+
+	return meta
+}
+
+func (q memoryQueries) getReservation(_ context.Context, reservationID uuid.UUID) (*ReservationMeta, error) {
+	s := q.state()
+	r, ok := s.reservations[reservationID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return q.reservationMeta(s, r), nil
+}
+
+func (q memoryQueries) getUserReservations(_ context.Context, actorID uuid.UUID) ([]*ReservationMeta, error) {
+	s := q.state()
+	reservations := collectRows(s.reservations, func(r *memoryReservation) bool {
+		return r.ActorID == actorID
+	}, func(a, b *memoryReservation) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+
+	var result []*ReservationMeta
+	for _, r := range reservations {
+		result = append(result, q.reservationMeta(s, r))
+	}
+
+	return result, nil
+}
+
+func (q memoryQueries) getReservationHeader(_ context.Context, reservationID uuid.UUID) (*reservationHeader, error) {
+	r, ok := q.state().reservations[reservationID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	h := r.reservationHeader
+	return &h, nil
+}
+
+func (q memoryQueries) getReservationByIdempotencyKey(_ context.Context, key uuid.UUID) (*ReservationResult, error) {
+	s := q.state()
+	id, ok := s.idempotency[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return &ReservationResult{
+		ReservationID: id,
+		ExpiresAt:     s.reservations[id].ExpiresAt,
+	}, nil
+}
+
+func (q memoryQueries) getReservationItems(_ context.Context, reservationIDs ...uuid.UUID) ([]*ReservationItem, error) {
+	s := q.state()
+	var result []*ReservationItem
+	for _, item := range s.items {
+		if !slices.Contains(reservationIDs, item.key.reservationID) {
+			continue
+		}
+
+		result = append(result, &ReservationItem{
+			ReservationID:  item.key.reservationID,
+			TierID:         item.key.tierID,
+			TierName:       s.tiers[item.key.tierID].name,
+			Quantity:       item.quantity,
+			UnitPriceCents: item.key.unitPriceCents,
+		})
+	}
+
+	slices.SortFunc(result, func(a, b *ReservationItem) int {
+		return cmp.Or(cmp.Compare(b.UnitPriceCents, a.UnitPriceCents), cmp.Compare(a.TierName, b.TierName))
+	})
+
+	return result, nil
+}
+
+func (q memoryQueries) countHeldTickets(_ context.Context, reservationID uuid.UUID) (int, error) {
+	count := 0
+	for _, t := range q.state().tickets {
+		if t.holdToken != nil && *t.holdToken == reservationID {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (q memoryQueries) getSoldTickets(_ context.Context, reservationID uuid.UUID) ([]soldTicket, error) {
+	var result []soldTicket
+	for _, t := range q.state().tickets {
+		if t.isSold && t.reservationID != nil && *t.reservationID == reservationID {
+			result = append(result, soldTicket{
+				ID:            t.id,
+				EventID:       t.eventID,
+				ReservationID: reservationID,
+			})
+		}
+	}
+
+	slices.SortFunc(result, func(a, b soldTicket) int {
+		return compareIDs(a.ID, b.ID)
+	})
+
+	return result, nil
+}
+
+func (q memoryQueries) issuedTickets(match func(c *memoryCode) bool) []*IssuedTicket {
+	s := q.state()
+	result := []*IssuedTicket{}
+	for _, c := range s.codes {
+		if c.revokedAt != nil || !match(c) {
+			continue
+		}
+
+		tier := s.tiers[s.tickets[c.TicketID].tierID]
+		result = append(result, &IssuedTicket{
+			TicketID: c.TicketID,
+			EventID:  c.EventID,
+			TierID:   tier.TierID,
+			TierName: tier.name,
+			Code:     c.Code,
+			IssuedAt: c.issuedAt,
+		})
+	}
+
+	return result
+}
+
+func (q memoryQueries) getIssuedTickets(_ context.Context, reservationID, ownerID uuid.UUID) ([]*IssuedTicket, error) {
+	result := q.issuedTickets(func(c *memoryCode) bool {
+		return c.ReservationID == reservationID && c.OwnerID == ownerID
+	})
+
+	slices.SortFunc(result, func(a, b *IssuedTicket) int {
+		return cmp.Or(cmp.Compare(a.TierName, b.TierName), compareIDs(a.TicketID, b.TicketID))
+	})
+
+	return result, nil
+}
+
+func (q memoryQueries) getUserTickets(_ context.Context, actorID uuid.UUID) ([]*IssuedTicket, error) {
+	result := q.issuedTickets(func(c *memoryCode) bool {
+		return c.OwnerID == actorID
+	})
+
+	slices.SortFunc(result, func(a, b *IssuedTicket) int {
+		return cmp.Or(b.IssuedAt.Compare(a.IssuedAt), compareIDs(a.TicketID, b.TicketID))
+	})
+
+	return result, nil
+}
+
+func (q memoryQueries) getTicketOwnership(_ context.Context, ticketID uuid.UUID) ([]*OwnershipRecord, error) {
+	codes := collectRows(q.state().codes, func(c *memoryCode) bool {
+		return c.TicketID == ticketID
+	}, func(a, b *memoryCode) int {
+		return cmp.Or(a.issuedAt.Compare(b.issuedAt), cmp.Compare(a.seq, b.seq))
+	})
+
+	result := make([]*OwnershipRecord, 0, len(codes))
+	for _, c := range codes {
+		result = append(result, &OwnershipRecord{
+			OwnerID:       c.OwnerID,
+			TransferID:    c.TransferID,
+			ReservationID: c.ReservationID,
+			AcquiredAt:    c.issuedAt,
+			ReleasedAt:    c.revokedAt,
+		})
+	}
+
+	return result, nil
+}
+
+func (q memoryQueries) hasForeignCodes(_ context.Context, reservationID, ownerID uuid.UUID) (bool, error) {
+	for _, c := range q.state().codes {
+		if c.ReservationID == reservationID && c.revokedAt == nil && c.OwnerID != ownerID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (q memoryQueries) getTicketCodeState(_ context.Context, codeID uuid.UUID) (*ticketCodeState, error) {
+	s := q.state()
+	c, ok := s.codes[codeID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	t := s.tickets[c.TicketID]
+	return &ticketCodeState{
+		ID:            c.ID,
+		TicketID:      c.TicketID,
+		EventID:       c.EventID,
+		TierName:      s.tiers[t.tierID].name,
+		RevokedAt:     c.revokedAt,
+		Reissued:      c.reissued,
+		CheckedInAt:   t.checkedInAt,
+		CheckedInGate: t.checkedInGate,
+	}, nil
+}
+
+func (q memoryQueries) isRefundedSince(_ context.Context, ticketID uuid.UUID, t time.Time) (bool, error) {
+	for _, c := range q.state().codes {
+		if c.TicketID == ticketID && c.revokedAt != nil && c.revokedAt.After(t) && !c.reissued {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (q memoryQueries) getFaceValues(_ context.Context, ticketIDs []uuid.UUID) ([]listedTicket, error) {
+	s := q.state()
+	var result []listedTicket
+	for _, id := range ticketIDs {
+		c := s.validCode(id)
+		if c == nil {
+			continue
+		}
+
+		tierID := s.tickets[id].tierID
+		var faceValue uint
+		if r := s.reservations[c.ReservationID]; r.ResaleListingID != nil {
+			faceValue = s.listings[*r.ResaleListingID].FaceValueCents
+		} else {
+			found := false
+			for _, item := range s.items {
+				if item.key.reservationID != c.ReservationID || item.key.tierID != tierID {
+					continue
+				}
+
+				if !found || item.key.unitPriceCents < faceValue {
+					faceValue = item.key.unitPriceCents
+					found = true
+				}
+			}
+		}
+
+		result = append(result, listedTicket{
+			TicketID:       id,
+			TierID:         tierID,
+			FaceValueCents: faceValue,
+		})
+	}
+
+	return result, nil
+}
+
+func (q memoryQueries) getPayments(_ context.Context, reservationID uuid.UUID) ([]*PaymentRecord, error) {
+	payments := collectRows(q.state().payments, func(p *memoryPayment) bool {
+		return p.reservationID == reservationID
+	}, func(a, b *memoryPayment) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+
+	result := make([]*PaymentRecord, 0, len(payments))
+	for _, p := range payments {
+		result = append(result, &p.PaymentRecord)
+	}
+
+	return result, nil
+}
+
+func (q memoryQueries) getDueRefunds(_ context.Context, limit int) ([]uuid.UUID, error) {
+	type dueRefund struct {
+		txID uuid.UUID
+		*memoryRefund
+	}
+
+	now := time.Now()
+	var due []dueRefund
+	for txID, r := range q.state().refunds {
+		if !r.nextAttemptAt.After(now) {
+			due = append(due, dueRefund{txID: txID, memoryRefund: r})
+		}
+	}
+
+	slices.SortFunc(due, func(a, b dueRefund) int {
+		return cmp.Or(a.nextAttemptAt.Compare(b.nextAttemptAt), cmp.Compare(a.seq, b.seq))
+	})
+
+	result := make([]uuid.UUID, 0, min(limit, len(due)))
+	for _, r := range due[:min(limit, len(due))] {
+		result = append(result, r.txID)
+	}
+
+	return result, nil
+}
+
+func (q memoryQueries) getWaitlistEntry(_ context.Context, entryID uuid.UUID) (*WaitlistEntry, error) {
+	e, ok := q.state().waitlist[entryID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	entry := e.WaitlistEntry
+	return &entry, nil
+}
+
+func (q memoryQueries) getUserWaitlistEntries(_ context.Context, actorID uuid.UUID) ([]*WaitlistEntry, error) {
+	entries := collectRows(q.state().waitlist, func(e *memoryWaitlistEntry) bool {
+		return e.ActorID == actorID
+	}, func(a, b *memoryWaitlistEntry) int {
+		return cmp.Compare(b.seq, a.seq)
+	})
+
+	result := make([]*WaitlistEntry, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, &e.WaitlistEntry)
+	}
+
+	return result, nil
+}
+
+func (q memoryQueries) getWaitlistedTiers(_ context.Context, tierIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	s := q.state()
+	result := map[uuid.UUID]bool{}
+	for _, e := range s.waitlist {
+		if e.Status != WaitlistWaiting || !slices.Contains(tierIDs, e.TierID) {
+			continue
+		}
+
+		unsold := 0
+		for _, id := range s.tiers[e.TierID].tickets {
+			if !s.tickets[id].isSold {
+				unsold++
+			}
+		}
+
+		if e.Quantity <= uint(unsold) {
+			result[e.TierID] = true
+		}
+	}
+
+	return result, nil
+}
+
+func (q memoryQueries) getWaitingTiers(_ context.Context, reservationID uuid.UUID) ([]uuid.UUID, error) {
+	s := q.state()
+	var result []uuid.UUID
+	for _, item := range s.items {
+		if item.key.reservationID != reservationID || slices.Contains(result, item.key.tierID) {
+			continue
+		}
+
+		for _, e := range s.waitlist {
+			if e.TierID == item.key.tierID && e.Status == WaitlistWaiting {
+				result = append(result, item.key.tierID)
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (q memoryQueries) isWaitlistOffer(_ context.Context, reservationID uuid.UUID) (bool, error) {
+	for _, e := range q.state().waitlist {
+		if e.OfferReservationID != nil && *e.OfferReservationID == reservationID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (q memoryQueries) getResaleListing(_ context.Context, listingID uuid.UUID) (*ResaleListing, error) {
+	s := q.state()
+	l, ok := s.listings[listingID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return s.listing(l, time.Now()), nil
+}
+
+func (q memoryQueries) getEventResaleListings(_ context.Context, eventID uuid.UUID) ([]*ResaleListing, error) {
+	s := q.state()
+	now := time.Now()
+	listings := collectRows(s.listings, func(l *memoryListing) bool {
+		if l.EventID != eventID || l.Status != ResaleActive {
+			return false
+		}
+
+		if l.holdExpiresAt != nil && !l.holdExpiresAt.Before(now) {
+			return false
+		}
+
+		return !slices.ContainsFunc(l.TicketIDs, func(id uuid.UUID) bool {
+			return s.tickets[id].checkedInAt != nil
+		})
+	}, func(a, b *memoryListing) int {
+		return cmp.Or(cmp.Compare(a.PriceCents, b.PriceCents), cmp.Compare(a.seq, b.seq))
+	})
+
+	result := make([]*ResaleListing, 0, len(listings))
+	for _, l := range listings {
+		result = append(result, s.listing(l, now))
+	}
+
+	return result, nil
+}
+
+func (q memoryQueries) getUserResaleListings(_ context.Context, actorID uuid.UUID) ([]*ResaleListing, error) {
+	s := q.state()
+	now := time.Now()
+	listings := collectRows(s.listings, func(l *memoryListing) bool {
+		return l.SellerID == actorID
+	}, func(a, b *memoryListing) int {
+		return cmp.Compare(b.seq, a.seq)
+	})
+
+	result := make([]*ResaleListing, 0, len(listings))
+	for _, l := range listings {
+		result = append(result, s.listing(l, now))
+	}
+
+	return result, nil
+}
+
+func (q memoryQueries) hasActiveListings(_ context.Context, ticketIDs []uuid.UUID) (bool, error) {
+	for _, l := range q.state().listings {
+		if l.Status == ResaleActive && slices.ContainsFunc(l.TicketIDs, func(id uuid.UUID) bool {
+			return slices.Contains(ticketIDs, id)
+		}) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (q memoryQueries) getTransfer(_ context.Context, transferID uuid.UUID) (*Transfer, error) {
+	s := q.state()
+	t, ok := s.transfers[transferID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return s.transfer(t), nil
+}
+
+func (q memoryQueries) getTransferClaimToken(_ context.Context, transferID uuid.UUID) (*string, error) {
+	t, ok := q.state().transfers[transferID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return t.claimToken, nil
+}
+
+func (q memoryQueries) getUserTransfers(_ context.Context, actorID uuid.UUID) ([]*Transfer, error) {
+	s := q.state()
+	isActor := func(id *uuid.UUID) bool {
+		return id != nil && *id == actorID
+	}
+
+	transfers := collectRows(s.transfers, func(t *memoryTransfer) bool {
+		return t.FromActorID == actorID || isActor(t.ToActorID) || isActor(t.AcceptedBy)
+	}, func(a, b *memoryTransfer) int {
+		return cmp.Compare(b.seq, a.seq)
+	})
+
+	result := make([]*Transfer, 0, len(transfers))
+	for _, t := range transfers {
+		result = append(result, s.transfer(t))
+	}
+
+	return result, nil
+}
+
+func (q memoryQueries) hasPendingTransfers(_ context.Context, ticketIDs []uuid.UUID) (bool, error) {
+	for _, t := range q.state().transfers {
+		if t.Status == TransferPending && slices.ContainsFunc(t.TicketIDs, func(id uuid.UUID) bool {
+			return slices.Contains(ticketIDs, id)
+		}) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (q memoryQueries) getScan(_ context.Context, scanID uuid.UUID) (*checkInScan, error) {
+	for _, scan := range q.state().scans {
+		if scan.ScanID != nil && *scan.ScanID == scanID {
+			result := scan.checkInScan
+			return &result, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (q memoryQueries) getCheckInCounts(_ context.Context, eventID uuid.UUID) ([]gateCount, error) {
+	s := q.state()
+	counts := map[string]int{}
+	notCheckedIn := 0
+	for _, c := range s.codes {
+		if c.EventID != eventID || c.revokedAt != nil {
+			continue
+		}
+
+		if gate := s.tickets[c.TicketID].checkedInGate; gate != nil {
+			counts[*gate]++
+		} else {
+			notCheckedIn++
+		}
+	}
+
+	var result []gateCount
+	if notCheckedIn > 0 {
+		result = append(result, gateCount{Count: notCheckedIn})
+	}
+
+	for gate, count := range counts {
+		result = append(result, gateCount{GateID: &gate, Count: count})
+	}
+
+	return result, nil
+}
+
+// memoryTx is a memory store transaction.
+//
+// Transaction holds store lock until it's committed or rolled back, so lock methods only read rows.
+type memoryTx struct {
+	memoryQueries
+
+	store *MemoryStore
+
+	// parent is set for savepoints, which are committed into parent transaction state.
+	parent *memoryTx
+	state  *memoryState
+	done   bool
+}
+
+func (t *memoryTx) current() *memoryState {
+	return t.state
+}
+
+func (t *memoryTx) Commit(_ context.Context) error {
+	if t.done {
+		return errors.New("transaction is already closed")
+	}
+
+	t.done = true
+	if t.parent != nil {
+		t.parent.state = t.state
+		return nil
+	}
+
+	t.store.state.Store(t.state)
+	t.store.mu.Unlock()
+	return nil
+}
+
+func (t *memoryTx) Rollback(_ context.Context) error {
+	if t.done {
+		return nil
+	}
+
+	t.done = true
+	if t.parent == nil {
+		t.store.mu.Unlock()
+	}
+
+	return nil
+}
+
+func (t *memoryTx) savepoint(_ context.Context) (storeTx, error) {
+	sp := &memoryTx{
+		store:  t.store,
+		parent: t,
+		state:  t.state.clone(),
+	}
+	sp.memoryQueries = memoryQueries{state: sp.current}
+	return sp, nil
+}
+
+func (t *memoryTx) writeOutbox(_ context.Context, events ...DomainEvent) error {
+	t.state.outbox = append(t.state.outbox, events...)
+	return nil
+}
+
+func (t *memoryTx) insertEvent(_ context.Context, event *Event) error {
+	if _, ok := t.state.events[event.ID]; ok {
+		return fmt.Errorf("event %s already exists", event.ID)
+	}
+
+	t.state.events[event.ID] = &memoryEvent{
+		Event: *event,
+		seq:   t.state.nextSeq(),
+	}
+	return nil
+}
+
+func (t *memoryTx) shareEvent(ctx context.Context, eventID uuid.UUID) (*Event, error) {
+	return t.getEvent(ctx, eventID)
+}
+
+func (t *memoryTx) lockEvent(ctx context.Context, eventID uuid.UUID) (*Event, error) {
+	return t.getEvent(ctx, eventID)
+}
+
+func (t *memoryTx) cancelEvent(_ context.Context, eventID uuid.UUID) error {
+	if e, ok := t.state.events[eventID]; ok {
+		now := time.Now()
+		e.CancelledAt = &now
+	}
+
+	return nil
+}
+
+func (t *memoryTx) setTransfersBlocked(_ context.Context, eventID uuid.UUID, blocked bool) error {
+	e, ok := t.state.events[eventID]
+	if !ok {
+		return ErrNotFound
+	}
+
+	e.TransfersBlocked = blocked
+	return nil
+}
+
+// withRuleIDs returns copy of pricing rules with generated IDs.
+func withRuleIDs(rules []pricing.Rule) []pricing.Rule {
+	result := slices.Clone(rules)
+	for i := range result {
+		if result[i].ID == uuid.Nil {
+			result[i].ID = uuid.New()
+		}
+	}
+
+	return result
+}
+
+func (t *memoryTx) insertTier(_ context.Context, tier *eventTier, ticketsCount int) error {
+	if _, ok := t.state.events[tier.EventID]; !ok {
+		return fmt.Errorf("event %s doesn't exist", tier.EventID)
+	}
+
+	for _, other := range t.state.tiers {
+		if other.EventID == tier.EventID && other.name == tier.Name {
+			return fmt.Errorf("tier %q already exists", tier.Name)
+		}
+	}
+
+	stored := &memoryTier{
+		tierPricing: tier.tierPricing,
+		name:        tier.Name,
+		currency:    tier.Currency,
+		seq:         t.state.nextSeq(),
+		tickets:     make([]uuid.UUID, 0, ticketsCount),
+	}
+	stored.Capacity, stored.Taken, stored.Sold = 0, 0, 0
+	stored.Rules = withRuleIDs(tier.Rules)
+
+	for range ticketsCount {
+		ticket := &memoryTicket{
+			id:      uuid.New(),
+			eventID: tier.EventID,
+			tierID:  tier.TierID,
+		}
+
+		t.state.tickets[ticket.id] = ticket
+		stored.tickets = append(stored.tickets, ticket.id)
+	}
+
+	stored.tickets = sortedIDs(stored.tickets)
+	t.state.tiers[tier.TierID] = stored
+	return nil
+}
+
+func (t *memoryTx) setTierPricing(_ context.Context, tierID uuid.UUID, params TierPricingParams) error {
+	tier, ok := t.state.tiers[tierID]
+	if !ok {
+		return ErrNotFound
+	}
+
+	tier.BasePriceCents = params.BasePriceCents
+	tier.MinPriceCents = params.MinPriceCents
+	tier.MaxPriceCents = params.MaxPriceCents
+	tier.Rules = withRuleIDs(params.Rules)
+	return nil
+}
+
+func (t *memoryTx) updateTierPrice(_ context.Context, tierID uuid.UUID, newPrice int, reason string) error {
+	tier, ok := t.state.tiers[tierID]
+	if !ok || tier.PriceCents == newPrice {
+		return nil
+	}
+
+	t.state.priceHistory[tierID] = append(t.state.priceHistory[tierID], PriceChange{
+		OldPriceCents: tier.PriceCents,
+		NewPriceCents: newPrice,
+		Reason:        reason,
+		ChangedAt:     time.Now(),
+	})
+	tier.PriceCents = newPrice
+	return nil
+}
+
+func (t *memoryTx) insertReservation(_ context.Context, r *newReservation) (bool, error) {
+	if r.IdempotencyKey != nil {
+		if _, ok := t.state.idempotency[*r.IdempotencyKey]; ok {
+			return false, nil
+		}
+
+		t.state.idempotency[*r.IdempotencyKey] = r.ID
+	}
+
+	t.state.reservations[r.ID] = &memoryReservation{
+		reservationHeader: reservationHeader{
+			reservationRef: r.reservationRef,
+			Status:         ReservationPending,
+			Currency:       r.Currency,
+			CreatedAt:      time.Now(),
+		},
+		idempotencyKey: r.IdempotencyKey,
+		seq:            t.state.nextSeq(),
+	}
+	return true, nil
+}
+
+func (t *memoryTx) lockReservation(ctx context.Context, reservationID uuid.UUID) (*reservationHeader, error) {
+	return t.getReservationHeader(ctx, reservationID)
+}
+
+func (t *memoryTx) setReservationStatus(_ context.Context, reservationID uuid.UUID, status ReservationStatus) error {
+	if r, ok := t.state.reservations[reservationID]; ok {
+		r.Status = status
+		r.isPaid = status == ReservationPaid
+	}
+
+	return nil
+}
+
+func (t *memoryTx) setReservationTotal(_ context.Context, reservationID uuid.UUID, totalCents uint) error {
+	if r, ok := t.state.reservations[reservationID]; ok {
+		r.TotalCents = totalCents
+	}
+
+	return nil
+}
+
+func (t *memoryTx) linkResaleListing(_ context.Context, reservationID, listingID uuid.UUID) error {
+	if r, ok := t.state.reservations[reservationID]; ok {
+		r.ResaleListingID = &listingID
+	}
+
+	return nil
+}
+
+func (t *memoryTx) setHoldExpiration(_ context.Context, reservationID uuid.UUID, expireAt time.Time) error {
+	for _, ticket := range t.state.tickets {
+		if !ticket.isSold && ticket.holdToken != nil && *ticket.holdToken == reservationID {
+			ticket.holdExpiresAt = &expireAt
+		}
+	}
+
+	for _, l := range t.state.listings {
+		if l.Status == ResaleActive && l.holdToken != nil && *l.holdToken == reservationID {
+			l.holdExpiresAt = &expireAt
+		}
+	}
+
+	if r, ok := t.state.reservations[reservationID]; ok {
+		r.ExpiresAt = expireAt
+		r.Extensions++
+		r.reminderSentAt = nil
+	}
+
+	return nil
+}
+
+// updateReservations applies update to up to limit matching reservations in passed order and returns them.
+func (t *memoryTx) updateReservations(
+	match func(r *memoryReservation) bool, order func(a, b *memoryReservation) int, limit int,
+	update func(r *memoryReservation),
+) []reservationRef {
+	var matched []*memoryReservation
+	for _, r := range t.state.reservations {
+		if match(r) {
+			matched = append(matched, r)
+		}
+	}
+
+	slices.SortFunc(matched, order)
+	if limit > 0 && len(matched) > limit {
+		matched = matched[:limit]
+	}
+
+	refs := make([]reservationRef, 0, len(matched))
+	for _, r := range matched {
+		update(r)
+		refs = append(refs, r.reservationRef)
+	}
+
+	return refs
+}
+
+func byExpiration(a, b *memoryReservation) int {
+	return cmp.Or(a.ExpiresAt.Compare(b.ExpiresAt), cmp.Compare(a.seq, b.seq))
+}
+
+func (t *memoryTx) expireReservations(_ context.Context, limit int) ([]reservationRef, error) {
+	now := time.Now()
+	return t.updateReservations(func(r *memoryReservation) bool {
+		return r.Status == ReservationPending && r.ExpiresAt.Before(now)
+	}, byExpiration, limit, func(r *memoryReservation) {
+		r.Status = ReservationExpired
+	}), nil
+}
+
+func (t *memoryTx) markExpiringReservations(_ context.Context, lead time.Duration, limit int) ([]reservationRef, error) {
+	now := time.Now()
+	return t.updateReservations(func(r *memoryReservation) bool {
+		return r.Status == ReservationPending && r.reminderSentAt == nil && r.Email != nil &&
+			r.ExpiresAt.After(now) && r.ExpiresAt.Before(now.Add(lead))
+	}, byExpiration, limit, func(r *memoryReservation) {
+		r.reminderSentAt = &now
+	}), nil
+}
+
+func (t *memoryTx) cancelEventReservations(_ context.Context, eventID uuid.UUID) ([]reservationRef, error) {
+	return t.updateReservations(func(r *memoryReservation) bool {
+		return r.EventID == eventID && (r.Status == ReservationPending || r.Status == ReservationPaid)
+	}, func(a, b *memoryReservation) int {
+		return cmp.Compare(a.seq, b.seq)
+	}, 0, func(r *memoryReservation) {
+		if r.Status == ReservationPending {
+			r.Status = ReservationCancelled
+		}
+	}), nil
+}
+
+func (t *memoryTx) addReservationItem(
+	_ context.Context, reservationID, tierID uuid.UUID, quantity, unitPriceCents uint,
+) error {
+	key := memoryItemKey{
+		reservationID:  reservationID,
+		tierID:         tierID,
+		unitPriceCents: unitPriceCents,
+	}
+	if item, ok := t.state.items[key]; ok {
+		item.quantity += quantity
+		return nil
+	}
+
+	t.state.items[key] = &memoryItem{key: key, quantity: quantity}
+	return nil
+}
+
+func (t *memoryTx) setReservationItem(_ context.Context, item *ReservationItem) error {
+	key := memoryItemKey{
+		reservationID:  item.ReservationID,
+		tierID:         item.TierID,
+		unitPriceCents: item.UnitPriceCents,
+	}
+	if item.Quantity == 0 {
+		delete(t.state.items, key)
+		return nil
+	}
+
+	if stored, ok := t.state.items[key]; ok {
+		stored.quantity = item.Quantity
+	}
+
+	return nil
+}
+
+func (t *memoryTx) holdTickets(
+	_ context.Context, eventID, tierID, reservationID uuid.UUID, quantity uint, expireAt time.Time,
+) (bool, error) {
+	tier, ok := t.state.tiers[tierID]
+	if !ok || tier.EventID != eventID {
+		return quantity == 0, nil
+	}
+
+	now := time.Now()
+	picked := make([]*memoryTicket, 0, quantity)
+	for _, id := range tier.tickets {
+		if uint(len(picked)) == quantity {
+			break
+		}
+
+		if ticket := t.state.tickets[id]; ticket.isAvailable(now) {
+			picked = append(picked, ticket)
+		}
+	}
+
+	if uint(len(picked)) != quantity {
+		return false, nil
+	}
+
+	for _, ticket := range picked {
+		ticket.holdToken = &reservationID
+		ticket.holdExpiresAt = &expireAt
+	}
+
+	return true, nil
+}
+
+// appendTier adds tier ID to a list if it's not there yet.
+func appendTier(tierIDs []uuid.UUID, tierID uuid.UUID) []uuid.UUID {
+	if slices.Contains(tierIDs, tierID) {
+		return tierIDs
+	}
+
+	return append(tierIDs, tierID)
+}
+
+func (t *memoryTx) releaseHeldTickets(_ context.Context, reservationIDs ...uuid.UUID) ([]uuid.UUID, error) {
+	var tierIDs []uuid.UUID
+	for _, ticket := range t.state.tickets {
+		if ticket.isSold || ticket.holdToken == nil || !slices.Contains(reservationIDs, *ticket.holdToken) {
+			continue
+		}
+
+		ticket.releaseHold()
+		tierIDs = appendTier(tierIDs, ticket.tierID)
+	}
+
+	return tierIDs, nil
+}
+
+func (t *memoryTx) releaseTierTickets(_ context.Context, reservationID, tierID uuid.UUID, count uint) error {
+	tier, ok := t.state.tiers[tierID]
+	if !ok {
+		return ErrNotFound
+	}
+
+	held := make([]*memoryTicket, 0, count)
+	for _, id := range tier.tickets {
+		if uint(len(held)) == count {
+			break
+		}
+
+		ticket := t.state.tickets[id]
+		if !ticket.isSold && ticket.holdToken != nil && *ticket.holdToken == reservationID {
+			held = append(held, ticket)
+		}
+	}
+
+	if uint(len(held)) != count {
+		return errors.New("reservation holds less tickets than reserved")
+	}
+
+	for _, ticket := range held {
+		ticket.releaseHold()
+	}
+
+	return nil
+}
+
+func (t *memoryTx) releaseEventTickets(_ context.Context, eventID uuid.UUID) error {
+	for _, ticket := range t.state.tickets {
+		if ticket.eventID == eventID && !ticket.isSold {
+			ticket.releaseHold()
+		}
+	}
+
+	return nil
+}
+
+func (t *memoryTx) sellHeldTickets(_ context.Context, reservationID uuid.UUID) error {
+	for _, ticket := range t.state.tickets {
+		if ticket.holdToken != nil && *ticket.holdToken == reservationID {
+			ticket.isSold = true
+			ticket.reservationID = &reservationID
+			ticket.releaseHold()
+		}
+	}
+
+	return nil
+}
+
+func (t *memoryTx) returnSoldTickets(_ context.Context, reservationID uuid.UUID) ([]uuid.UUID, error) {
+	var tierIDs []uuid.UUID
+	for _, ticket := range t.state.tickets {
+		if ticket.reservationID == nil || *ticket.reservationID != reservationID {
+			continue
+		}
+
+		ticket.isSold = false
+		ticket.reservationID = nil
+		ticket.checkedInAt = nil
+		ticket.checkedInGate = nil
+		tierIDs = appendTier(tierIDs, ticket.tierID)
+	}
+
+	return tierIDs, nil
+}
+
+func (t *memoryTx) moveTickets(_ context.Context, ticketIDs []uuid.UUID, reservationID uuid.UUID) error {
+	for _, id := range ticketIDs {
+		if ticket, ok := t.state.tickets[id]; ok {
+			ticket.reservationID = &reservationID
+		}
+	}
+
+	return nil
+}
+
+func (t *memoryTx) checkInTicket(_ context.Context, ticketID uuid.UUID, at time.Time, gateID string) error {
+	if ticket, ok := t.state.tickets[ticketID]; ok {
+		ticket.checkedInAt = &at
+		ticket.checkedInGate = &gateID
+	}
+
+	return nil
+}
+
+func (t *memoryTx) insertTicketCodes(_ context.Context, codes []*ticketCode) error {
+	now := time.Now()
+	for _, c := range codes {
+		if t.state.validCode(c.TicketID) != nil {
+			return fmt.Errorf("failed to store ticket codes: ticket %s already has a valid code", c.TicketID)
+		}
+
+		t.state.codes[c.ID] = &memoryCode{
+			ticketCode: *c,
+			issuedAt:   now,
+			seq:        t.state.nextSeq(),
+		}
+	}
+
+	return nil
+}
+
+func (t *memoryTx) lockTicketCodes(_ context.Context, ticketIDs []uuid.UUID) ([]transferTicket, error) {
+	var result []transferTicket
+	for _, id := range sortedIDs(ticketIDs) {
+		c := t.state.validCode(id)
+		if c == nil {
+			continue
+		}
+
+		event := t.state.events[c.EventID]
+		result = append(result, transferTicket{
+			CodeID:           c.ID,
+			TicketID:         c.TicketID,
+			EventID:          c.EventID,
+			ReservationID:    c.ReservationID,
+			OwnerID:          c.OwnerID,
+			CheckedInAt:      t.state.tickets[id].checkedInAt,
+			TransfersBlocked: event.TransfersBlocked,
+			EventCancelled:   event.CancelledAt != nil,
+		})
+	}
+
+	return result, nil
+}
+
+func (t *memoryTx) lockTicketCode(ctx context.Context, codeID uuid.UUID) (*ticketCodeState, error) {
+	return t.getTicketCodeState(ctx, codeID)
+}
+
+func (t *memoryTx) revokeReservationCodes(_ context.Context, reservationID uuid.UUID) error {
+	now := time.Now()
+	for _, c := range t.state.codes {
+		if c.ReservationID == reservationID && c.revokedAt == nil {
+			c.revokedAt = &now
+		}
+	}
+
+	return nil
+}
+
+func (t *memoryTx) reissueCodes(_ context.Context, codeIDs []uuid.UUID) error {
+	now := time.Now()
+	for _, id := range codeIDs {
+		if c, ok := t.state.codes[id]; ok {
+			c.revokedAt = &now
+			c.reissued = true
+		}
+	}
+
+	return nil
+}
+
+func (t *memoryTx) insertPayment(_ context.Context, reservationID uuid.UUID, p *PaymentRecord) error {
+	payment := &memoryPayment{
+		PaymentRecord: *p,
+		reservationID: reservationID,
+		seq:           t.state.nextSeq(),
+	}
+	payment.PaidAt = time.Now()
+	payment.RefundedAt = nil
+	t.state.payments[p.TxID] = payment
+	return nil
+}
+
+func (t *memoryTx) lockRefundablePayment(_ context.Context, reservationID uuid.UUID) (*PaymentRecord, error) {
+	var latest *memoryPayment
+	for _, p := range t.state.payments {
+		if p.reservationID != reservationID || p.RefundedAt != nil {
+			continue
+		}
+
+		if latest == nil || p.seq > latest.seq {
+			latest = p
+		}
+	}
+
+	if latest == nil {
+		return nil, ErrNotFound
+	}
+
+	payment := latest.PaymentRecord
+	return &payment, nil
+}
+
+func (t *memoryTx) setPaymentRefunded(_ context.Context, txID uuid.UUID) error {
+	if p, ok := t.state.payments[txID]; ok {
+		now := time.Now()
+		p.RefundedAt = &now
+	}
+
+	return nil
+}
+
+func (t *memoryTx) insertPendingRefund(_ context.Context, txID, _ uuid.UUID) error {
+	t.state.refunds[txID] = &memoryRefund{
+		nextAttemptAt: time.Now(),
+		seq:           t.state.nextSeq(),
+	}
+	return nil
+}
+
+func (t *memoryTx) lockPendingRefund(_ context.Context, txID uuid.UUID) (int, bool, error) {
+	r, ok := t.state.refunds[txID]
+	if !ok {
+		return 0, false, nil
+	}
+
+	return r.attempts, true, nil
+}
+
+func (t *memoryTx) deferPendingRefund(_ context.Context, txID uuid.UUID, lastErr string, backoff time.Duration) error {
+	if r, ok := t.state.refunds[txID]; ok {
+		r.attempts++
+		r.lastError = lastErr
+		r.nextAttemptAt = time.Now().Add(backoff)
+	}
+
+	return nil
+}
+
+func (t *memoryTx) deletePendingRefund(_ context.Context, txID uuid.UUID) error {
+	delete(t.state.refunds, txID)
+	return nil
+}
+
+func (t *memoryTx) insertWaitlistEntry(_ context.Context, entry *WaitlistEntry, email *string) error {
+	for _, e := range t.state.waitlist {
+		isActive := e.Status == WaitlistWaiting || e.Status == WaitlistOffered
+		if isActive && e.TierID == entry.TierID && e.ActorID == entry.ActorID {
+			return ErrAlreadyWaitlisted
+		}
+	}
+
+	stored := &memoryWaitlistEntry{
+		WaitlistEntry: *entry,
+		email:         email,
+		seq:           t.state.nextSeq(),
+	}
+	stored.Status = WaitlistWaiting
+	stored.CreatedAt = time.Now()
+	t.state.waitlist[entry.ID] = stored
+	return nil
+}
+
+func (t *memoryTx) lockWaitlistEntry(ctx context.Context, entryID uuid.UUID) (*WaitlistEntry, error) {
+	return t.getWaitlistEntry(ctx, entryID)
+}
+
+func (t *memoryTx) setWaitlistStatus(_ context.Context, entryID uuid.UUID, status WaitlistStatus) error {
+	if e, ok := t.state.waitlist[entryID]; ok {
+		e.Status = status
+	}
+
+	return nil
+}
+
+func (t *memoryTx) lockWaitlistHead(_ context.Context, tierID uuid.UUID, maxQuantity int) (*waitlistHead, error) {
+	var head *memoryWaitlistEntry
+	for _, e := range t.state.waitlist {
+		if e.TierID != tierID || e.Status != WaitlistWaiting || e.Quantity > uint(max(maxQuantity, 0)) {
+			continue
+		}
+
+		if head == nil || e.seq < head.seq {
+			head = e
+		}
+	}
+
+	if head == nil {
+		return nil, nil
+	}
+
+	event := t.state.events[head.EventID]
+	return &waitlistHead{
+		ID:             head.ID,
+		EventID:        head.EventID,
+		ActorID:        head.ActorID,
+		Email:          head.email,
+		Quantity:       head.Quantity,
+		Currency:       event.Currency,
+		HoldTTLSeconds: int(event.HoldTTLSeconds),
+	}, nil
+}
+
+func (t *memoryTx) setWaitlistOffer(_ context.Context, entryID, reservationID uuid.UUID) error {
+	if e, ok := t.state.waitlist[entryID]; ok {
+		e.Status = WaitlistOffered
+		e.OfferReservationID = &reservationID
+	}
+
+	return nil
+}
+
+func (t *memoryTx) resolveWaitlistOffers(_ context.Context, status WaitlistStatus, reservationIDs ...uuid.UUID) error {
+	for _, e := range t.state.waitlist {
+		if e.OfferReservationID != nil && slices.Contains(reservationIDs, *e.OfferReservationID) {
+			e.Status = status
+		}
+	}
+
+	return nil
+}
+
+func (t *memoryTx) cancelEventWaitlist(_ context.Context, eventID uuid.UUID) error {
+	for _, e := range t.state.waitlist {
+		if e.EventID == eventID && (e.Status == WaitlistWaiting || e.Status == WaitlistOffered) {
+			e.Status = WaitlistCancelled
+		}
+	}
+
+	return nil
+}
+
+func (t *memoryTx) insertResaleListing(_ context.Context, listing *ResaleListing) error {
+	stored := &memoryListing{
+		ResaleListing: *listing,
+		seq:           t.state.nextSeq(),
+	}
+	stored.Status = ResaleActive
+	stored.TicketIDs = sortedIDs(listing.TicketIDs)
+	stored.CreatedAt = time.Now()
+	t.state.listings[listing.ID] = stored
+	return nil
+}
+
+func (t *memoryTx) lockResaleListing(ctx context.Context, listingID uuid.UUID) (*ResaleListing, error) {
+	return t.getResaleListing(ctx, listingID)
+}
+
+// resolveListing sets final listing status and releases its hold.
+func resolveListing(l *memoryListing, status ResaleStatus, releaseHold bool) {
+	now := time.Now()
+	l.Status = status
+	l.ResolvedAt = &now
+	if releaseHold {
+		l.holdToken = nil
+		l.holdExpiresAt = nil
+	}
+}
+
+func (t *memoryTx) setListingStatus(_ context.Context, listingID uuid.UUID, status ResaleStatus) error {
+	if l, ok := t.state.listings[listingID]; ok {
+		resolveListing(l, status, false)
+	}
+
+	return nil
+}
+
+func (t *memoryTx) holdListing(_ context.Context, listingID, reservationID uuid.UUID, expireAt time.Time) error {
+	if l, ok := t.state.listings[listingID]; ok {
+		l.holdToken = &reservationID
+		l.holdExpiresAt = &expireAt
+	}
+
+	return nil
+}
+
+func (t *memoryTx) sellListing(_ context.Context, listingID, reservationID uuid.UUID) (bool, error) {
+	l, ok := t.state.listings[listingID]
+	if !ok || l.Status != ResaleActive || l.holdToken == nil || *l.holdToken != reservationID {
+		return false, nil
+	}
+
+	resolveListing(l, ResaleSold, true)
+	l.buyerReservationID = &reservationID
+	return true, nil
+}
+
+func (t *memoryTx) setListingPayout(
+	_ context.Context, listingID uuid.UUID, feeCents, payoutCents int64, txID uuid.UUID,
+) error {
+	if l, ok := t.state.listings[listingID]; ok {
+		fee, payout := uint(feeCents), uint(payoutCents)
+		l.FeeCents = &fee
+		l.PayoutCents = &payout
+		l.payoutTxID = &txID
+	}
+
+	return nil
+}
+
+func (t *memoryTx) releaseHeldListings(_ context.Context, reservationIDs ...uuid.UUID) error {
+	for _, l := range t.state.listings {
+		if l.Status == ResaleActive && l.holdToken != nil && slices.Contains(reservationIDs, *l.holdToken) {
+			l.holdToken = nil
+			l.holdExpiresAt = nil
+		}
+	}
+
+	return nil
+}
+
+func (t *memoryTx) cancelReservationListings(_ context.Context, reservationID uuid.UUID) error {
+	for _, l := range t.state.listings {
+		if l.Status != ResaleActive {
+			continue
+		}
+
+		if slices.ContainsFunc(l.TicketIDs, func(id uuid.UUID) bool {
+			r := t.state.tickets[id].reservationID
+			return r != nil && *r == reservationID
+		}) {
+			resolveListing(l, ResaleCancelled, true)
+		}
+	}
+
+	return nil
+}
+
+func (t *memoryTx) cancelEventListings(_ context.Context, eventID uuid.UUID) error {
+	for _, l := range t.state.listings {
+		if l.EventID == eventID && l.Status == ResaleActive {
+			resolveListing(l, ResaleCancelled, true)
+		}
+	}
+
+	return nil
+}
+
+func (t *memoryTx) insertTransfer(_ context.Context, transfer *Transfer, claimToken *string) error {
+	stored := &memoryTransfer{
+		Transfer:   *transfer,
+		claimToken: claimToken,
+		seq:        t.state.nextSeq(),
+	}
+	stored.Status = TransferPending
+	stored.TicketIDs = sortedIDs(transfer.TicketIDs)
+	stored.CreatedAt = time.Now()
+	t.state.transfers[transfer.ID] = stored
+	return nil
+}
+
+func (t *memoryTx) lockTransfer(ctx context.Context, transferID uuid.UUID) (*Transfer, error) {
+	return t.getTransfer(ctx, transferID)
+}
+
+func (t *memoryTx) resolveTransfer(
+	_ context.Context, transferID uuid.UUID, status TransferStatus, acceptedBy *uuid.UUID,
+) error {
+	if tr, ok := t.state.transfers[transferID]; ok {
+		now := time.Now()
+		tr.Status = status
+		tr.AcceptedBy = acceptedBy
+		tr.ResolvedAt = &now
+	}
+
+	return nil
+}
+
+func (t *memoryTx) cancelTransfers(match func(tr *memoryTransfer) bool) {
+	now := time.Now()
+	for _, tr := range t.state.transfers {
+		if tr.Status == TransferPending && match(tr) {
+			tr.Status = TransferCancelled
+			tr.ResolvedAt = &now
+		}
+	}
+}
+
+func (t *memoryTx) cancelReservationTransfers(_ context.Context, reservationID uuid.UUID) error {
+	t.cancelTransfers(func(tr *memoryTransfer) bool {
+		return slices.ContainsFunc(tr.TicketIDs, func(id uuid.UUID) bool {
+			r := t.state.tickets[id].reservationID
+			return r != nil && *r == reservationID
+		})
+	})
+
+	return nil
+}
+
+func (t *memoryTx) cancelEventTransfers(_ context.Context, eventID uuid.UUID) error {
+	t.cancelTransfers(func(tr *memoryTransfer) bool {
+		return tr.EventID == eventID
+	})
+
+	return nil
+}
+
+func (t *memoryTx) lockScan(_ context.Context, _ uuid.UUID) error {
+	// Transactions are already serialized by store lock.
+	return nil
+}
+
+func (t *memoryTx) insertScan(ctx context.Context, scan *checkInScan) error {
+	if scan.ScanID != nil {
+		existing, err := t.getScan(ctx, *scan.ScanID)
+		if err != nil {
+			return err
+		}
+
+		if existing != nil {
+			return fmt.Errorf("failed to store scan: scan %s already exists", *scan.ScanID)
+		}
+	}
+
+	seq := t.state.nextSeq()
+	t.state.scans[seq] = &memoryScan{
+		checkInScan: *scan,
+		seq:         seq,
+	}
+	return nil
+}
+
+func (t *memoryTx) supersedeScans(_ context.Context, ticketID uuid.UUID, scannedAt time.Time) error {
+	for _, scan := range t.state.scans {
+		if scan.CodeID == nil || scan.Status != CheckInAccepted || !scan.ScannedAt.Equal(scannedAt) {
+			continue
+		}
+
+		if c, ok := t.state.codes[*scan.CodeID]; ok && c.TicketID == ticketID {
+			scan.Status = CheckInSuperseded
+		}
+	}
+
+	return nil
+}
+
+var (
+	_ Store   = (*MemoryStore)(nil)
+	_ storeTx = (*memoryTx)(nil)
+)
@@ -0,0 +1,27 @@
+package booking_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking/bookingtest"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/ticketcode"
+)
+
+func TestMemoryStore(t *testing.T) {
+	bookingtest.RunRepositoryTests(t, newMemoryService)
+}
+
+func TestMemoryStoreModel(t *testing.T) {
+	bookingtest.RunLifecycleModel(t, newMemoryService)
+}
+
+func newMemoryService(t *testing.T) booking.Repository {
+	codes, err := ticketcode.NewSigner([]byte("memory-repository-test-signing-key"))
+	require.NoError(t, err)
+
+	rates := booking.NewStaticRates(booking.DefaultCurrency, nil)
+	return booking.NewService(booking.NewMemoryStore(), nil, rates, codes, booking.DefaultResalePolicy)
+}
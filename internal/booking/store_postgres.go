@@ -0,0 +1,1664 @@
+package booking
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/pricing"
+)
+
+const pgUniqueViolation = "23505"
+
+const resaleListingColumns = `
+	l.id, l.event_id, l.tier_id, tt.name AS tier_name, l.seller_id, l.status, l.currency,
+	l.price_cents, l.face_value_cents, l.fee_cents, l.payout_cents, l.created_at, l.resolved_at,
+	CASE WHEN l.status = 'active' AND l.hold_expires_at >= now() THEN l.hold_expires_at END AS held_until,
+	ARRAY(SELECT i.ticket_id FROM resale_listing_items i WHERE i.listing_id = l.id ORDER BY i.ticket_id) AS ticket_ids
+`
+
+const transferColumns = `
+	t.id, t.event_id, t.from_actor_id, t.to_actor_id, t.to_email, t.status, t.accepted_by, t.created_at, t.resolved_at,
+	ARRAY(SELECT i.ticket_id FROM ticket_transfer_items i WHERE i.transfer_id = t.id ORDER BY i.ticket_id) AS ticket_ids
+`
+
+const eventColumns = `id, name, currency, tax_rate_bps, organizer_id, transfers_blocked, hold_ttl_seconds, cancelled_at`
+
+const reservationHeaderColumns = `
+	id, event_id, actor_id, contact_email, expires_at, status, total_cents, currency,
+	resale_listing_id, created_at, extensions_count
+`
+
+const reservationRefColumns = `id, event_id, actor_id, contact_email, expires_at`
+
+const waitlistEntryColumns = `id, event_id, tier_id, actor_id, quantity, status, offer_reservation_id, created_at`
+
+const paymentColumns = `
+	tx_id, created_at, settlement_amount, settlement_currency,
+	presentment_amount, presentment_currency, exchange_rate::TEXT, refunded_at
+`
+
+// PostgresStore is Store implementation backed by Postgres.
+//
+// Concurrency guarantees rely on row locks, so transactions run with read committed isolation.
+type PostgresStore struct {
+	pgQueries
+	db *pgxpool.Pool
+}
+
+// NewPostgresStore returns a new Postgres store.
+func NewPostgresStore(db *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{
+		pgQueries: pgQueries{q: db},
+		db:        db,
+	}
+}
+
+func (s *PostgresStore) begin(ctx context.Context) (storeTx, error) {
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.ReadCommitted,
+		AccessMode: pgx.ReadWrite,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newPgTx(tx), nil
+}
+
+// pgQuerier is implemented both by connection pool and transaction.
+type pgQuerier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type pgQueries struct {
+	q pgQuerier
+}
+
+type pgTx struct {
+	pgQueries
+	tx pgx.Tx
+}
+
+func newPgTx(tx pgx.Tx) *pgTx {
+	return &pgTx{
+		pgQueries: pgQueries{q: tx},
+		tx:        tx,
+	}
+}
+
+func (t *pgTx) Commit(ctx context.Context) error {
+	return t.tx.Commit(ctx)
+}
+
+func (t *pgTx) Rollback(ctx context.Context) error {
+	return t.tx.Rollback(ctx)
+}
+
+func (t *pgTx) savepoint(ctx context.Context) (storeTx, error) {
+	sp, err := t.tx.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	return newPgTx(sp), nil
+}
+
+// getOne scans a single row into dst. Missing row is reported as ErrNotFound.
+func (q pgQueries) getOne(ctx context.Context, dst any, what string, query string, args ...any) error {
+	if err := pgxscan.Get(ctx, q.q, dst, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+
+		return fmt.Errorf("failed to get %s: %w", what, err)
+	}
+
+	return nil
+}
+
+func (q pgQueries) exists(ctx context.Context, what string, query string, args ...any) (bool, error) {
+	var ok bool
+	if err := q.q.QueryRow(ctx, query, args...).Scan(&ok); err != nil {
+		return false, fmt.Errorf("failed to query %s: %w", what, err)
+	}
+
+	return ok, nil
+}
+
+func (q pgQueries) getEvents(ctx context.Context) ([]*Event, error) {
+	var result []*Event
+	if err := pgxscan.Select(ctx, q.q, &result, `SELECT `+eventColumns+` FROM events`); err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+
+	return result, nil
+}
+
+func (q pgQueries) getEvent(ctx context.Context, eventID uuid.UUID) (*Event, error) {
+	event := &Event{}
+	err := q.getOne(ctx, event, "event", `SELECT `+eventColumns+` FROM events WHERE id = $1`, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func (q pgQueries) getEventTiers(ctx context.Context, eventID uuid.UUID) ([]*eventTier, error) {
+	var tiers []*eventTier
+
+	// TODO: use different approach to check counters as query is quite expensive.
+	err := pgxscan.Select(ctx, q.q, &tiers, `
+		SELECT
+			tt.id AS tier_id,
+			tt.event_id,
+			tt.name AS tier_name,
+			tt.currency,
+			tt.price_cents,
+			tt.base_price_cents,
+			tt.min_price_cents,
+			tt.max_price_cents,
+			COUNT(t.id) AS capacity,
+			COUNT(t.id) FILTER (
+				WHERE t.is_sold OR t.hold_expires_at >= now()
+			) AS taken,
+			COUNT(t.id) FILTER (WHERE t.is_sold) AS sold,
+			COUNT(*) FILTER (
+				WHERE t.is_sold = FALSE
+				AND (t.hold_expires_at IS NULL OR t.hold_expires_at < now())
+			) AS available_count
+		FROM ticket_tiers tt
+		LEFT JOIN tickets t ON t.tier_id = tt.id
+		WHERE tt.event_id = $1
+		GROUP BY tt.id
+		ORDER BY tt.price_cents, tt.name
+	`, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tiers: %w", err)
+	}
+
+	tierIDs := make([]uuid.UUID, 0, len(tiers))
+	byID := make(map[uuid.UUID]*tierPricing, len(tiers))
+	for _, t := range tiers {
+		tierIDs = append(tierIDs, t.TierID)
+		byID[t.TierID] = &t.tierPricing
+	}
+
+	if err := q.loadPricingRules(ctx, byID, tierIDs); err != nil {
+		return nil, err
+	}
+
+	return tiers, nil
+}
+
+func (q pgQueries) getTierPricing(ctx context.Context, tierIDs []uuid.UUID) (map[uuid.UUID]*tierPricing, error) {
+	var tiers []*tierPricing
+	err := pgxscan.Select(ctx, q.q, &tiers, `
+		SELECT
+			tt.id AS tier_id,
+			tt.event_id,
+			tt.price_cents,
+			tt.base_price_cents,
+			tt.min_price_cents,
+			tt.max_price_cents,
+			COUNT(t.id) AS capacity,
+			COUNT(t.id) FILTER (
+				WHERE t.is_sold OR t.hold_expires_at >= now()
+			) AS taken,
+			COUNT(t.id) FILTER (WHERE t.is_sold) AS sold
+		FROM ticket_tiers tt
+		LEFT JOIN tickets t ON t.tier_id = tt.id
+		WHERE tt.id = ANY($1)
+		GROUP BY tt.id
+	`, tierIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tier pricing: %w", err)
+	}
+
+	result := make(map[uuid.UUID]*tierPricing, len(tiers))
+	for _, t := range tiers {
+		result[t.TierID] = t
+	}
+
+	if err := q.loadPricingRules(ctx, result, tierIDs); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+type tierRule struct {
+	TierID uuid.UUID `db:"tier_id"`
+	pricing.Rule
+}
+
+// loadPricingRules attaches pricing rules to passed tiers.
+func (q pgQueries) loadPricingRules(ctx context.Context, tiers map[uuid.UUID]*tierPricing, tierIDs []uuid.UUID) error {
+	var rules []*tierRule
+	err := pgxscan.Select(ctx, q.q, &rules, `
+		SELECT tier_id, id, kind, adjustment_bps, sold_percent, active_from, active_until
+		FROM pricing_rules
+		WHERE tier_id = ANY($1)
+		ORDER BY created_at, id
+	`, tierIDs)
+	if err != nil {
+		return fmt.Errorf("failed to query pricing rules: %w", err)
+	}
+
+	for _, r := range rules {
+		if t, ok := tiers[r.TierID]; ok {
+			t.Rules = append(t.Rules, r.Rule)
+		}
+	}
+
+	return nil
+}
+
+func (q pgQueries) getPricedTierIDs(ctx context.Context) ([]uuid.UUID, error) {
+	var tierIDs []uuid.UUID
+	if err := pgxscan.Select(ctx, q.q, &tierIDs, `SELECT DISTINCT tier_id FROM pricing_rules`); err != nil {
+		return nil, fmt.Errorf("failed to query tiers: %w", err)
+	}
+
+	return tierIDs, nil
+}
+
+func (q pgQueries) getPriceHistory(ctx context.Context, tierID uuid.UUID) ([]*PriceChange, error) {
+	result := []*PriceChange{}
+	err := pgxscan.Select(ctx, q.q, &result, `
+		SELECT old_price_cents, new_price_cents, reason, changed_at
+		FROM tier_price_history
+		WHERE tier_id = $1
+		ORDER BY changed_at, id
+	`, tierID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query price history: %w", err)
+	}
+
+	return result, nil
+}
+
+func (q pgQueries) getReservation(ctx context.Context, reservationID uuid.UUID) (*ReservationMeta, error) {
+	result := &ReservationMeta{}
+	err := q.getOne(ctx, result, "reservation", `
+		SELECT r.id, r.expires_at, r.is_paid, r.status, r.total_cents, r.currency, r.extensions_count,
+			r.event_id, e.name as event_name
+		FROM reservations r
+		LEFT JOIN events e ON r.event_id = e.id
+		WHERE r.id = $1
+	`, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (q pgQueries) getUserReservations(ctx context.Context, actorID uuid.UUID) ([]*ReservationMeta, error) {
+	var result []*ReservationMeta
+	err := pgxscan.Select(ctx, q.q, &result, `
+		SELECT r.id, r.expires_at, r.is_paid, r.status, r.total_cents, r.currency, r.extensions_count,
+			e.id as event_id, e.name as event_name
+		FROM reservations r
+		INNER JOIN events e ON r.event_id = e.id
+		WHERE r.actor_id = $1
+	`, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reservations: %w", err)
+	}
+
+	return result, nil
+}
+
+func (q pgQueries) getReservationHeader(ctx context.Context, reservationID uuid.UUID) (*reservationHeader, error) {
+	h := &reservationHeader{}
+	err := q.getOne(
+		ctx, h, "reservation", `SELECT `+reservationHeaderColumns+` FROM reservations WHERE id = $1`, reservationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+func (q pgQueries) getReservationByIdempotencyKey(ctx context.Context, key uuid.UUID) (*ReservationResult, error) {
+	result := &ReservationResult{}
+	err := q.getOne(
+		ctx, result, "reservation",
+		`SELECT id AS reservation_id, expires_at FROM reservations WHERE idempotency_key = $1`, key,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (q pgQueries) getReservationItems(ctx context.Context, reservationIDs ...uuid.UUID) ([]*ReservationItem, error) {
+	var items []*ReservationItem
+	err := pgxscan.Select(ctx, q.q, &items, `
+		SELECT ri.reservation_id, ri.tier_id, tt.name AS tier_name, ri.quantity, ri.unit_price_cents
+		FROM reservation_items ri
+		INNER JOIN ticket_tiers tt ON tt.id = ri.tier_id
+		WHERE ri.reservation_id = ANY($1)
+		ORDER BY ri.unit_price_cents DESC, tt.name
+	`, reservationIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reservation items: %w", err)
+	}
+
+	return items, nil
+}
+
+func (q pgQueries) countHeldTickets(ctx context.Context, reservationID uuid.UUID) (int, error) {
+	var count int
+	err := q.q.QueryRow(ctx, `SELECT COUNT(*) FROM tickets WHERE hold_token = $1`, reservationID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get reserved tickets: %w", err)
+	}
+
+	return count, nil
+}
+
+func (q pgQueries) getSoldTickets(ctx context.Context, reservationID uuid.UUID) ([]soldTicket, error) {
+	var tickets []soldTicket
+	err := pgxscan.Select(
+		ctx, q.q, &tickets,
+		`SELECT id, event_id, reservation_id FROM tickets WHERE reservation_id = $1 AND is_sold = true`,
+		reservationID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sold tickets: %w", err)
+	}
+
+	return tickets, nil
+}
+
+func (q pgQueries) getIssuedTickets(ctx context.Context, reservationID, ownerID uuid.UUID) ([]*IssuedTicket, error) {
+	result := []*IssuedTicket{}
+	err := pgxscan.Select(ctx, q.q, &result, `
+		SELECT c.ticket_id, c.event_id, t.tier_id, tt.name AS tier_name, c.code, c.issued_at
+		FROM ticket_codes c
+		INNER JOIN tickets t ON t.id = c.ticket_id
+		INNER JOIN ticket_tiers tt ON tt.id = t.tier_id
+		WHERE c.reservation_id = $1 AND c.owner_id = $2 AND c.revoked_at IS NULL
+		ORDER BY tt.name, c.ticket_id
+	`, reservationID, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tickets: %w", err)
+	}
+
+	return result, nil
+}
+
+func (q pgQueries) getUserTickets(ctx context.Context, actorID uuid.UUID) ([]*IssuedTicket, error) {
+	result := []*IssuedTicket{}
+	err := pgxscan.Select(ctx, q.q, &result, `
+		SELECT c.ticket_id, c.event_id, t.tier_id, tt.name AS tier_name, c.code, c.issued_at
+		FROM ticket_codes c
+		INNER JOIN tickets t ON t.id = c.ticket_id
+		INNER JOIN ticket_tiers tt ON tt.id = t.tier_id
+		WHERE c.owner_id = $1 AND c.revoked_at IS NULL
+		ORDER BY c.issued_at DESC, c.ticket_id
+	`, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tickets: %w", err)
+	}
+
+	return result, nil
+}
+
+func (q pgQueries) getTicketOwnership(ctx context.Context, ticketID uuid.UUID) ([]*OwnershipRecord, error) {
+	result := []*OwnershipRecord{}
+	err := pgxscan.Select(ctx, q.q, &result, `
+		SELECT owner_id, transfer_id, reservation_id, issued_at, revoked_at
+		FROM ticket_codes
+		WHERE ticket_id = $1
+		ORDER BY issued_at, id
+	`, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ticket codes: %w", err)
+	}
+
+	return result, nil
+}
+
+func (q pgQueries) hasForeignCodes(ctx context.Context, reservationID, ownerID uuid.UUID) (bool, error) {
+	return q.exists(ctx, "ticket owners", `
+		SELECT EXISTS (
+			SELECT 1 FROM ticket_codes WHERE reservation_id = $1 AND revoked_at IS NULL AND owner_id <> $2
+		)
+	`, reservationID, ownerID)
+}
+
+func (q pgQueries) getTicketCodeState(ctx context.Context, codeID uuid.UUID) (*ticketCodeState, error) {
+	return q.ticketCodeState(ctx, codeID, false)
+}
+
+func (q pgQueries) ticketCodeState(ctx context.Context, codeID uuid.UUID, forUpdate bool) (*ticketCodeState, error) {
+	query := `
+		SELECT
+			c.id, c.ticket_id, c.event_id, tt.name AS tier_name, c.revoked_at, c.reissued,
+			t.checked_in_at, t.checked_in_gate
+		FROM ticket_codes c
+		INNER JOIN tickets t ON t.id = c.ticket_id
+		INNER JOIN ticket_tiers tt ON tt.id = t.tier_id
+		WHERE c.id = $1
+	`
+	if forUpdate {
+		query += " FOR UPDATE OF c, t"
+	}
+
+	state := &ticketCodeState{}
+	if err := q.getOne(ctx, state, "ticket code", query, codeID); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+func (q pgQueries) isRefundedSince(ctx context.Context, ticketID uuid.UUID, t time.Time) (bool, error) {
+	return q.exists(ctx, "ticket codes", `
+		SELECT EXISTS (
+			SELECT 1 FROM ticket_codes WHERE ticket_id = $1 AND revoked_at > $2 AND NOT reissued
+		)
+	`, ticketID, t)
+}
+
+// getFaceValues returns face values of tickets with valid codes.
+//
+// Face value of a resold ticket is taken from the listing it was bought from,
+// so price cap doesn't grow with each resale. Tickets aren't linked to a particular line item,
+// so if tier was reserved at several prices, the lowest one is used.
+func (q pgQueries) getFaceValues(ctx context.Context, ticketIDs []uuid.UUID) ([]listedTicket, error) {
+	var result []listedTicket
+	err := pgxscan.Select(ctx, q.q, &result, `
+		SELECT
+			c.ticket_id, t.tier_id,
+			COALESCE(l.face_value_cents, (
+				SELECT MIN(ri.unit_price_cents)
+				FROM reservation_items ri
+				WHERE ri.reservation_id = c.reservation_id AND ri.tier_id = t.tier_id
+			), 0) AS face_value_cents
+		FROM ticket_codes c
+		INNER JOIN tickets t ON t.id = c.ticket_id
+		INNER JOIN reservations r ON r.id = c.reservation_id
+		LEFT JOIN resale_listings l ON l.id = r.resale_listing_id
+		WHERE c.ticket_id = ANY($1) AND c.revoked_at IS NULL
+	`, ticketIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query face values: %w", err)
+	}
+
+	return result, nil
+}
+
+func scanPayment(row pgx.Row) (*PaymentRecord, error) {
+	p := &PaymentRecord{}
+	err := row.Scan(
+		&p.TxID, &p.PaidAt, &p.Settlement.Amount, &p.Settlement.Currency,
+		&p.Presentment.Amount, &p.Presentment.Currency, &p.ExchangeRate, &p.RefundedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (q pgQueries) getPayments(ctx context.Context, reservationID uuid.UUID) ([]*PaymentRecord, error) {
+	rows, err := q.q.Query(ctx, `
+		SELECT `+paymentColumns+`
+		FROM payments
+		WHERE reservation_id = $1
+		ORDER BY created_at
+	`, reservationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query payments: %w", err)
+	}
+
+	defer rows.Close()
+
+	payments := []*PaymentRecord{}
+	for rows.Next() {
+		p, err := scanPayment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read payment: %w", err)
+		}
+
+		payments = append(payments, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query payments: %w", err)
+	}
+
+	return payments, nil
+}
+
+func (q pgQueries) getDueRefunds(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	var txIDs []uuid.UUID
+	err := pgxscan.Select(ctx, q.q, &txIDs, `
+		SELECT tx_id
+		FROM pending_refunds
+		WHERE next_attempt_at <= now()
+		ORDER BY next_attempt_at
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending refunds: %w", err)
+	}
+
+	return txIDs, nil
+}
+
+func (q pgQueries) getWaitlistEntry(ctx context.Context, entryID uuid.UUID) (*WaitlistEntry, error) {
+	return q.waitlistEntry(ctx, entryID, false)
+}
+
+func (q pgQueries) waitlistEntry(ctx context.Context, entryID uuid.UUID, forUpdate bool) (*WaitlistEntry, error) {
+	query := `SELECT ` + waitlistEntryColumns + ` FROM waitlist_entries WHERE id = $1`
+	if forUpdate {
+		query += " FOR UPDATE"
+	}
+
+	entry := &WaitlistEntry{}
+	if err := q.getOne(ctx, entry, "waitlist entry", query, entryID); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (q pgQueries) getUserWaitlistEntries(ctx context.Context, actorID uuid.UUID) ([]*WaitlistEntry, error) {
+	result := []*WaitlistEntry{}
+	err := pgxscan.Select(ctx, q.q, &result, `
+		SELECT `+waitlistEntryColumns+`
+		FROM waitlist_entries
+		WHERE actor_id = $1
+		ORDER BY created_at DESC
+	`, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query waitlist entries: %w", err)
+	}
+
+	return result, nil
+}
+
+func (q pgQueries) getWaitlistedTiers(ctx context.Context, tierIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	var waitlisted []uuid.UUID
+	err := pgxscan.Select(ctx, q.q, &waitlisted, `
+		SELECT DISTINCT w.tier_id
+		FROM waitlist_entries w
+		WHERE w.tier_id = ANY($1) AND w.status = $2 AND w.quantity <= (
+			SELECT COUNT(*) FROM tickets t WHERE t.tier_id = w.tier_id AND NOT t.is_sold
+		)
+	`, tierIDs, WaitlistWaiting)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query waitlist: %w", err)
+	}
+
+	result := make(map[uuid.UUID]bool, len(waitlisted))
+	for _, id := range waitlisted {
+		result[id] = true
+	}
+
+	return result, nil
+}
+
+func (q pgQueries) getWaitingTiers(ctx context.Context, reservationID uuid.UUID) ([]uuid.UUID, error) {
+	var tierIDs []uuid.UUID
+	err := pgxscan.Select(ctx, q.q, &tierIDs, `
+		SELECT DISTINCT w.tier_id
+		FROM waitlist_entries w
+		INNER JOIN reservation_items ri ON ri.tier_id = w.tier_id
+		WHERE ri.reservation_id = $1 AND w.status = $2
+	`, reservationID, WaitlistWaiting)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query waitlist: %w", err)
+	}
+
+	return tierIDs, nil
+}
+
+func (q pgQueries) isWaitlistOffer(ctx context.Context, reservationID uuid.UUID) (bool, error) {
+	return q.exists(
+		ctx, "waitlist offer",
+		`SELECT EXISTS (SELECT 1 FROM waitlist_entries WHERE offer_reservation_id = $1)`, reservationID,
+	)
+}
+
+func (q pgQueries) getResaleListing(ctx context.Context, listingID uuid.UUID) (*ResaleListing, error) {
+	return q.resaleListing(ctx, listingID, false)
+}
+
+func (q pgQueries) resaleListing(ctx context.Context, listingID uuid.UUID, forUpdate bool) (*ResaleListing, error) {
+	query := `
+		SELECT ` + resaleListingColumns + `
+		FROM resale_listings l
+		INNER JOIN ticket_tiers tt ON tt.id = l.tier_id
+		WHERE l.id = $1
+	`
+	if forUpdate {
+		query += " FOR UPDATE OF l"
+	}
+
+	listing := &ResaleListing{}
+	if err := q.getOne(ctx, listing, "listing", query, listingID); err != nil {
+		return nil, err
+	}
+
+	return listing, nil
+}
+
+func (q pgQueries) getEventResaleListings(ctx context.Context, eventID uuid.UUID) ([]*ResaleListing, error) {
+	result := []*ResaleListing{}
+	err := pgxscan.Select(ctx, q.q, &result, `
+		SELECT `+resaleListingColumns+`
+		FROM resale_listings l
+		INNER JOIN ticket_tiers tt ON tt.id = l.tier_id
+		WHERE l.event_id = $1
+			AND l.status = $2
+			AND (l.hold_expires_at IS NULL OR l.hold_expires_at < now())
+			AND NOT EXISTS (
+				SELECT 1
+				FROM resale_listing_items i
+				INNER JOIN tickets t ON t.id = i.ticket_id
+				WHERE i.listing_id = l.id AND t.checked_in_at IS NOT NULL
+			)
+		ORDER BY l.price_cents, l.created_at
+	`, eventID, ResaleActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query listings: %w", err)
+	}
+
+	return result, nil
+}
+
+func (q pgQueries) getUserResaleListings(ctx context.Context, actorID uuid.UUID) ([]*ResaleListing, error) {
+	result := []*ResaleListing{}
+	err := pgxscan.Select(ctx, q.q, &result, `
+		SELECT `+resaleListingColumns+`
+		FROM resale_listings l
+		INNER JOIN ticket_tiers tt ON tt.id = l.tier_id
+		WHERE l.seller_id = $1
+		ORDER BY l.created_at DESC
+	`, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query listings: %w", err)
+	}
+
+	return result, nil
+}
+
+func (q pgQueries) hasActiveListings(ctx context.Context, ticketIDs []uuid.UUID) (bool, error) {
+	return q.exists(ctx, "resale listings", `
+		SELECT EXISTS (
+			SELECT 1
+			FROM resale_listing_items i
+			INNER JOIN resale_listings l ON l.id = i.listing_id
+			WHERE i.ticket_id = ANY($1) AND l.status = $2
+		)
+	`, ticketIDs, ResaleActive)
+}
+
+func (q pgQueries) getTransfer(ctx context.Context, transferID uuid.UUID) (*Transfer, error) {
+	return q.transfer(ctx, transferID, false)
+}
+
+func (q pgQueries) transfer(ctx context.Context, transferID uuid.UUID, forUpdate bool) (*Transfer, error) {
+	query := `SELECT ` + transferColumns + ` FROM ticket_transfers t WHERE t.id = $1`
+	if forUpdate {
+		query += " FOR UPDATE OF t"
+	}
+
+	transfer := &Transfer{}
+	if err := q.getOne(ctx, transfer, "transfer", query, transferID); err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+func (q pgQueries) getTransferClaimToken(ctx context.Context, transferID uuid.UUID) (*string, error) {
+	var claimToken *string
+	err := q.q.QueryRow(
+		ctx, `SELECT claim_token FROM ticket_transfers WHERE id = $1`, transferID,
+	).Scan(&claimToken)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get transfer: %w", err)
+	}
+
+	return claimToken, nil
+}
+
+func (q pgQueries) getUserTransfers(ctx context.Context, actorID uuid.UUID) ([]*Transfer, error) {
+	result := []*Transfer{}
+	err := pgxscan.Select(ctx, q.q, &result, `
+		SELECT `+transferColumns+`
+		FROM ticket_transfers t
+		WHERE t.from_actor_id = $1 OR t.to_actor_id = $1 OR t.accepted_by = $1
+		ORDER BY t.created_at DESC
+	`, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transfers: %w", err)
+	}
+
+	return result, nil
+}
+
+func (q pgQueries) hasPendingTransfers(ctx context.Context, ticketIDs []uuid.UUID) (bool, error) {
+	return q.exists(ctx, "pending transfers", `
+		SELECT EXISTS (
+			SELECT 1
+			FROM ticket_transfer_items i
+			INNER JOIN ticket_transfers t ON t.id = i.transfer_id
+			WHERE i.ticket_id = ANY($1) AND t.status = $2
+		)
+	`, ticketIDs, TransferPending)
+}
+
+func (q pgQueries) getScan(ctx context.Context, scanID uuid.UUID) (*checkInScan, error) {
+	scan := &checkInScan{}
+	err := pgxscan.Get(ctx, q.q, scan, `
+		SELECT scan_id, code_id, gate_id, status, is_offline, scanned_at
+		FROM checkin_scans
+		WHERE scan_id = $1
+	`, scanID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to get scan: %w", err)
+	}
+
+	return scan, nil
+}
+
+func (q pgQueries) getCheckInCounts(ctx context.Context, eventID uuid.UUID) ([]gateCount, error) {
+	var rows []gateCount
+	err := pgxscan.Select(ctx, q.q, &rows, `
+		SELECT t.checked_in_gate, COUNT(*) AS count
+		FROM ticket_codes c
+		INNER JOIN tickets t ON t.id = c.ticket_id
+		WHERE c.event_id = $1 AND c.revoked_at IS NULL
+		GROUP BY t.checked_in_gate
+	`, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query check-in stats: %w", err)
+	}
+
+	return rows, nil
+}
+
+// exec runs a statement and wraps its error with a description of what failed.
+func (t *pgTx) exec(ctx context.Context, what string, query string, args ...any) (pgconn.CommandTag, error) {
+	tag, err := t.tx.Exec(ctx, query, args...)
+	if err != nil {
+		return tag, fmt.Errorf("failed to %s: %w", what, err)
+	}
+
+	return tag, nil
+}
+
+// selectIDs runs a statement which returns a list of IDs.
+func (t *pgTx) selectIDs(ctx context.Context, what string, query string, args ...any) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := pgxscan.Select(ctx, t.tx, &ids, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to %s: %w", what, err)
+	}
+
+	return ids, nil
+}
+
+// selectRefs runs a statement which returns a list of reservation refs.
+func (t *pgTx) selectRefs(ctx context.Context, what string, query string, args ...any) ([]reservationRef, error) {
+	var refs []reservationRef
+	if err := pgxscan.Select(ctx, t.tx, &refs, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to %s: %w", what, err)
+	}
+
+	return refs, nil
+}
+
+// writeOutbox stores domain events in the outbox table, from where they are published by the outbox relay.
+func (t *pgTx) writeOutbox(ctx context.Context, events ...DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	rows := make([][]any, 0, len(events))
+	for _, e := range events {
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %q event: %w", e.Type, err)
+		}
+
+		rows = append(rows, []any{e.ID, string(e.Type), e.ReservationID, payload})
+	}
+
+	_, err := t.tx.CopyFrom(
+		ctx, pgx.Identifier{"outbox"},
+		[]string{"event_id", "event_type", "aggregate_id", "payload"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write outbox: %w", err)
+	}
+
+	return nil
+}
+
+func (t *pgTx) insertEvent(ctx context.Context, e *Event) error {
+	_, err := t.exec(ctx, "insert event", `
+		INSERT INTO events (id, name, currency, tax_rate_bps, organizer_id, transfers_blocked, hold_ttl_seconds)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, e.ID, e.Name, e.Currency, e.TaxRateBps, e.OrganizerID, e.TransfersBlocked, e.HoldTTLSeconds)
+	return err
+}
+
+func (t *pgTx) shareEvent(ctx context.Context, eventID uuid.UUID) (*Event, error) {
+	event := &Event{}
+	err := t.getOne(ctx, event, "event", `SELECT `+eventColumns+` FROM events WHERE id = $1 FOR SHARE`, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func (t *pgTx) lockEvent(ctx context.Context, eventID uuid.UUID) (*Event, error) {
+	event := &Event{}
+	err := t.getOne(ctx, event, "event", `SELECT `+eventColumns+` FROM events WHERE id = $1 FOR UPDATE`, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func (t *pgTx) cancelEvent(ctx context.Context, eventID uuid.UUID) error {
+	_, err := t.exec(ctx, "cancel event", `UPDATE events SET cancelled_at = now() WHERE id = $1`, eventID)
+	return err
+}
+
+func (t *pgTx) setTransfersBlocked(ctx context.Context, eventID uuid.UUID, blocked bool) error {
+	tag, err := t.exec(
+		ctx, "update event", `UPDATE events SET transfers_blocked = $2 WHERE id = $1`, eventID, blocked,
+	)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (t *pgTx) insertTier(ctx context.Context, tier *eventTier, ticketsCount int) error {
+	_, err := t.exec(ctx, "insert tier", `
+		INSERT INTO ticket_tiers (
+			id, event_id, name, currency, price_cents, base_price_cents, min_price_cents, max_price_cents
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`,
+		tier.TierID, tier.EventID, tier.Name, tier.Currency, tier.PriceCents,
+		tier.BasePriceCents, tier.MinPriceCents, tier.MaxPriceCents,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := t.insertPricingRules(ctx, tier.TierID, tier.Rules); err != nil {
+		return err
+	}
+
+	_, err = t.exec(
+		ctx, "create tickets",
+		`INSERT INTO tickets (event_id, tier_id) SELECT $1::UUID, $2::UUID FROM generate_series(1, $3)`,
+		tier.EventID, tier.TierID, ticketsCount,
+	)
+	return err
+}
+
+func (t *pgTx) insertPricingRules(ctx context.Context, tierID uuid.UUID, rules []pricing.Rule) error {
+	for _, r := range rules {
+		_, err := t.exec(ctx, "insert pricing rule", `
+			INSERT INTO pricing_rules (tier_id, kind, adjustment_bps, sold_percent, active_from, active_until)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, tierID, r.Kind, r.AdjustmentBps, r.SoldPercent, r.ActiveFrom, r.ActiveUntil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *pgTx) setTierPricing(ctx context.Context, tierID uuid.UUID, params TierPricingParams) error {
+	tag, err := t.exec(ctx, "update tier", `
+		UPDATE ticket_tiers
+		SET base_price_cents = $2, min_price_cents = $3, max_price_cents = $4
+		WHERE id = $1
+	`, tierID, params.BasePriceCents, params.MinPriceCents, params.MaxPriceCents)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	if _, err := t.exec(ctx, "delete pricing rules", `DELETE FROM pricing_rules WHERE tier_id = $1`, tierID); err != nil {
+		return err
+	}
+
+	return t.insertPricingRules(ctx, tierID, params.Rules)
+}
+
+func (t *pgTx) updateTierPrice(ctx context.Context, tierID uuid.UUID, newPrice int, reason string) error {
+	_, err := t.exec(ctx, fmt.Sprintf("update price of tier %q", tierID), `
+		WITH prev AS (
+			SELECT id, price_cents FROM ticket_tiers WHERE id = $1 FOR UPDATE
+		), updated AS (
+			UPDATE ticket_tiers tt
+			SET price_cents = $2
+			FROM prev
+			WHERE tt.id = prev.id AND prev.price_cents <> $2
+			RETURNING tt.id, prev.price_cents AS old_price_cents
+		)
+		INSERT INTO tier_price_history (tier_id, old_price_cents, new_price_cents, reason)
+		SELECT id, old_price_cents, $2, $3 FROM updated
+	`, tierID, newPrice, reason)
+	return err
+}
+
+func (t *pgTx) insertReservation(ctx context.Context, r *newReservation) (bool, error) {
+	err := t.tx.QueryRow(ctx, `
+		INSERT INTO reservations (id, event_id, actor_id, expires_at, idempotency_key, currency, contact_email)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (idempotency_key) DO NOTHING
+		RETURNING id
+	`, r.ID, r.EventID, r.ActorID, r.ExpiresAt, r.IdempotencyKey, r.Currency, r.Email).Scan(&r.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to create reservation: %w", err)
+	}
+
+	return true, nil
+}
+
+func (t *pgTx) lockReservation(ctx context.Context, reservationID uuid.UUID) (*reservationHeader, error) {
+	h := &reservationHeader{}
+	err := t.getOne(
+		ctx, h, "reservation",
+		`SELECT `+reservationHeaderColumns+` FROM reservations WHERE id = $1 FOR UPDATE`, reservationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+func (t *pgTx) setReservationStatus(ctx context.Context, reservationID uuid.UUID, status ReservationStatus) error {
+	_, err := t.exec(
+		ctx, "update reservation", `UPDATE reservations SET status = $2, is_paid = $3 WHERE id = $1`,
+		reservationID, status, status == ReservationPaid,
+	)
+	return err
+}
+
+func (t *pgTx) setReservationTotal(ctx context.Context, reservationID uuid.UUID, totalCents uint) error {
+	_, err := t.exec(
+		ctx, "update reservation total", `UPDATE reservations SET total_cents = $2 WHERE id = $1`,
+		reservationID, totalCents,
+	)
+	return err
+}
+
+func (t *pgTx) linkResaleListing(ctx context.Context, reservationID, listingID uuid.UUID) error {
+	_, err := t.exec(
+		ctx, "link resale listing", `UPDATE reservations SET resale_listing_id = $2 WHERE id = $1`,
+		reservationID, listingID,
+	)
+	return err
+}
+
+func (t *pgTx) setHoldExpiration(ctx context.Context, reservationID uuid.UUID, expireAt time.Time) error {
+	_, err := t.exec(ctx, "extend tickets hold", `
+		UPDATE tickets SET hold_expires_at = $2 WHERE hold_token = $1 AND is_sold = false
+	`, reservationID, expireAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.exec(ctx, "extend listing hold", `
+		UPDATE resale_listings SET hold_expires_at = $2 WHERE hold_token = $1 AND status = $3
+	`, reservationID, expireAt, ResaleActive)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.exec(ctx, "update reservation", `
+		UPDATE reservations
+		SET expires_at = $2, extensions_count = extensions_count + 1, reminder_sent_at = NULL
+		WHERE id = $1
+	`, reservationID, expireAt)
+	return err
+}
+
+func (t *pgTx) expireReservations(ctx context.Context, limit int) ([]reservationRef, error) {
+	return t.selectRefs(ctx, "expire reservations", `
+		UPDATE reservations
+		SET status = $1
+		WHERE id IN (
+			SELECT id
+			FROM reservations
+			WHERE status = $2 AND expires_at < now()
+			ORDER BY expires_at
+			FOR UPDATE SKIP LOCKED
+			LIMIT $3
+		)
+		RETURNING `+reservationRefColumns, ReservationExpired, ReservationPending, limit)
+}
+
+func (t *pgTx) markExpiringReservations(ctx context.Context, lead time.Duration, limit int) ([]reservationRef, error) {
+	return t.selectRefs(ctx, "query expiring reservations", `
+		UPDATE reservations
+		SET reminder_sent_at = now()
+		WHERE id IN (
+			SELECT id
+			FROM reservations
+			WHERE status = $1
+				AND reminder_sent_at IS NULL
+				AND contact_email IS NOT NULL
+				AND expires_at > now()
+				AND expires_at < now() + $2 * INTERVAL '1 second'
+			ORDER BY expires_at
+			FOR UPDATE SKIP LOCKED
+			LIMIT $3
+		)
+		RETURNING `+reservationRefColumns, ReservationPending, int(lead.Seconds()), limit)
+}
+
+func (t *pgTx) cancelEventReservations(ctx context.Context, eventID uuid.UUID) ([]reservationRef, error) {
+	return t.selectRefs(ctx, "cancel reservations", `
+		UPDATE reservations
+		SET status = CASE WHEN status = $2 THEN $3 ELSE status END
+		WHERE event_id = $1 AND status IN ($2, $4)
+		RETURNING `+reservationRefColumns, eventID, ReservationPending, ReservationCancelled, ReservationPaid)
+}
+
+func (t *pgTx) addReservationItem(ctx context.Context, reservationID, tierID uuid.UUID, quantity, unitPriceCents uint) error {
+	_, err := t.exec(ctx, fmt.Sprintf("store reservation item of tier %q", tierID), `
+		INSERT INTO reservation_items (reservation_id, tier_id, quantity, unit_price_cents)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (reservation_id, tier_id, unit_price_cents) DO UPDATE
+		SET quantity = reservation_items.quantity + EXCLUDED.quantity
+	`, reservationID, tierID, quantity, unitPriceCents)
+	return err
+}
+
+func (t *pgTx) setReservationItem(ctx context.Context, item *ReservationItem) error {
+	what := fmt.Sprintf("update reservation item of tier %q", item.TierID)
+	if item.Quantity == 0 {
+		_, err := t.exec(ctx, what, `
+			DELETE FROM reservation_items WHERE reservation_id = $1 AND tier_id = $2 AND unit_price_cents = $3
+		`, item.ReservationID, item.TierID, item.UnitPriceCents)
+		return err
+	}
+
+	_, err := t.exec(ctx, what, `
+		UPDATE reservation_items SET quantity = $4
+		WHERE reservation_id = $1 AND tier_id = $2 AND unit_price_cents = $3
+	`, item.ReservationID, item.TierID, item.UnitPriceCents, item.Quantity)
+	return err
+}
+
+func (t *pgTx) holdTickets(
+	ctx context.Context, eventID, tierID, reservationID uuid.UUID, quantity uint, expireAt time.Time,
+) (bool, error) {
+	rows, err := t.tx.Query(ctx, `
+		WITH picked AS (
+			SELECT id
+			FROM tickets
+			WHERE event_id = $1
+				AND tier_id  = $2
+				AND is_sold  = false
+				AND (hold_expires_at IS NULL OR hold_expires_at < now())
+			ORDER BY id
+			FOR UPDATE SKIP LOCKED
+			LIMIT $3
+		)
+		UPDATE tickets t
+		SET hold_token = $4, hold_expires_at = $5
+		WHERE t.id IN (SELECT id FROM picked)
+			AND (SELECT COUNT(*) FROM picked) = $3
+		RETURNING t.id
+	`, eventID, tierID, quantity, reservationID, expireAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to lock tickets of tier %q: %w", tierID, err)
+	}
+
+	// cmp locked and expected count
+	got := 0
+	for rows.Next() {
+		got++
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("failed to lock tickets of tier %q: %w", tierID, err)
+	}
+
+	return got == int(quantity), nil
+}
+
+func (t *pgTx) releaseHeldTickets(ctx context.Context, reservationIDs ...uuid.UUID) ([]uuid.UUID, error) {
+	return t.selectIDs(ctx, "release tickets", `
+		WITH released AS (
+			UPDATE tickets
+			SET hold_token = NULL, hold_expires_at = NULL
+			WHERE hold_token = ANY($1) AND is_sold = false
+			RETURNING tier_id
+		)
+		SELECT DISTINCT tier_id FROM released
+	`, reservationIDs)
+}
+
+func (t *pgTx) releaseTierTickets(ctx context.Context, reservationID, tierID uuid.UUID, count uint) error {
+	tag, err := t.exec(ctx, fmt.Sprintf("release tickets of tier %q", tierID), `
+		UPDATE tickets
+		SET hold_token = NULL, hold_expires_at = NULL
+		WHERE id IN (
+			SELECT id
+			FROM tickets
+			WHERE hold_token = $1 AND tier_id = $2 AND is_sold = false
+			ORDER BY id
+			LIMIT $3
+		)
+	`, reservationID, tierID, count)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() != int64(count) {
+		return errors.New("reservation holds less tickets than reserved")
+	}
+
+	return nil
+}
+
+func (t *pgTx) releaseEventTickets(ctx context.Context, eventID uuid.UUID) error {
+	_, err := t.exec(ctx, "release tickets", `
+		UPDATE tickets
+		SET hold_token = NULL, hold_expires_at = NULL
+		WHERE event_id = $1 AND is_sold = false AND hold_token IS NOT NULL
+	`, eventID)
+	return err
+}
+
+func (t *pgTx) sellHeldTickets(ctx context.Context, reservationID uuid.UUID) error {
+	_, err := t.exec(ctx, "mark tickets as sold", `
+		UPDATE tickets
+		SET is_sold = true, reservation_id = $1, hold_token = NULL, hold_expires_at = NULL
+		WHERE hold_token = $1
+	`, reservationID)
+	return err
+}
+
+func (t *pgTx) returnSoldTickets(ctx context.Context, reservationID uuid.UUID) ([]uuid.UUID, error) {
+	return t.selectIDs(ctx, "return tickets", `
+		WITH returned AS (
+			UPDATE tickets
+			SET is_sold = false, reservation_id = NULL, checked_in_at = NULL, checked_in_gate = NULL
+			WHERE reservation_id = $1
+			RETURNING tier_id
+		)
+		SELECT DISTINCT tier_id FROM returned
+	`, reservationID)
+}
+
+func (t *pgTx) moveTickets(ctx context.Context, ticketIDs []uuid.UUID, reservationID uuid.UUID) error {
+	_, err := t.exec(
+		ctx, "update tickets", `UPDATE tickets SET reservation_id = $2 WHERE id = ANY($1)`, ticketIDs, reservationID,
+	)
+	return err
+}
+
+func (t *pgTx) checkInTicket(ctx context.Context, ticketID uuid.UUID, at time.Time, gateID string) error {
+	_, err := t.exec(
+		ctx, "check-in ticket", `UPDATE tickets SET checked_in_at = $2, checked_in_gate = $3 WHERE id = $1`,
+		ticketID, at, gateID,
+	)
+	return err
+}
+
+func (t *pgTx) insertTicketCodes(ctx context.Context, codes []*ticketCode) error {
+	rows := make([][]any, 0, len(codes))
+	for _, c := range codes {
+		rows = append(rows, []any{c.ID, c.TicketID, c.ReservationID, c.EventID, c.Code, c.OwnerID, c.TransferID})
+	}
+
+	_, err := t.tx.CopyFrom(
+		ctx, pgx.Identifier{"ticket_codes"},
+		[]string{"id", "ticket_id", "reservation_id", "event_id", "code", "owner_id", "transfer_id"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store ticket codes: %w", err)
+	}
+
+	return nil
+}
+
+// lockTicketCodes also locks tickets, so transfer is serialized with check-in
+// and concurrent transfers of the same ticket.
+func (t *pgTx) lockTicketCodes(ctx context.Context, ticketIDs []uuid.UUID) ([]transferTicket, error) {
+	var tickets []transferTicket
+	err := pgxscan.Select(ctx, t.tx, &tickets, `
+		SELECT
+			c.id AS code_id, c.ticket_id, c.event_id, c.reservation_id, c.owner_id, t.checked_in_at,
+			e.transfers_blocked, e.cancelled_at IS NOT NULL AS event_cancelled
+		FROM ticket_codes c
+		INNER JOIN tickets t ON t.id = c.ticket_id
+		INNER JOIN events e ON e.id = c.event_id
+		WHERE c.ticket_id = ANY($1) AND c.revoked_at IS NULL
+		ORDER BY c.ticket_id
+		FOR UPDATE OF c, t
+	`, ticketIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ticket codes: %w", err)
+	}
+
+	return tickets, nil
+}
+
+func (t *pgTx) lockTicketCode(ctx context.Context, codeID uuid.UUID) (*ticketCodeState, error) {
+	return t.ticketCodeState(ctx, codeID, true)
+}
+
+func (t *pgTx) revokeReservationCodes(ctx context.Context, reservationID uuid.UUID) error {
+	_, err := t.exec(
+		ctx, "revoke ticket codes",
+		`UPDATE ticket_codes SET revoked_at = now() WHERE reservation_id = $1 AND revoked_at IS NULL`,
+		reservationID,
+	)
+	return err
+}
+
+func (t *pgTx) reissueCodes(ctx context.Context, codeIDs []uuid.UUID) error {
+	_, err := t.exec(
+		ctx, "revoke ticket codes",
+		`UPDATE ticket_codes SET revoked_at = now(), reissued = true WHERE id = ANY($1)`, codeIDs,
+	)
+	return err
+}
+
+func (t *pgTx) insertPayment(ctx context.Context, reservationID uuid.UUID, p *PaymentRecord) error {
+	_, err := t.exec(ctx, "store payment", `
+		INSERT INTO payments (
+			tx_id, reservation_id, settlement_amount, settlement_currency,
+			presentment_amount, presentment_currency, exchange_rate
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7::NUMERIC)
+	`,
+		p.TxID, reservationID, p.Settlement.Amount, p.Settlement.Currency,
+		p.Presentment.Amount, p.Presentment.Currency, p.ExchangeRate,
+	)
+	return err
+}
+
+func (t *pgTx) lockRefundablePayment(ctx context.Context, reservationID uuid.UUID) (*PaymentRecord, error) {
+	p, err := scanPayment(t.tx.QueryRow(ctx, `
+		SELECT `+paymentColumns+`
+		FROM payments
+		WHERE reservation_id = $1 AND refunded_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT 1
+		FOR UPDATE
+	`, reservationID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+
+	return p, nil
+}
+
+func (t *pgTx) setPaymentRefunded(ctx context.Context, txID uuid.UUID) error {
+	_, err := t.exec(ctx, "update payment", `UPDATE payments SET refunded_at = now() WHERE tx_id = $1`, txID)
+	return err
+}
+
+func (t *pgTx) insertPendingRefund(ctx context.Context, txID, reservationID uuid.UUID) error {
+	_, err := t.exec(
+		ctx, "store pending refund",
+		`INSERT INTO pending_refunds (tx_id, reservation_id) VALUES ($1, $2)`, txID, reservationID,
+	)
+	return err
+}
+
+func (t *pgTx) lockPendingRefund(ctx context.Context, txID uuid.UUID) (int, bool, error) {
+	var attempts int
+	err := t.tx.QueryRow(
+		ctx, `SELECT attempts FROM pending_refunds WHERE tx_id = $1 FOR UPDATE SKIP LOCKED`, txID,
+	).Scan(&attempts)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, nil
+		}
+
+		return 0, false, fmt.Errorf("failed to get pending refund: %w", err)
+	}
+
+	return attempts, true, nil
+}
+
+func (t *pgTx) deferPendingRefund(ctx context.Context, txID uuid.UUID, lastErr string, backoff time.Duration) error {
+	_, err := t.exec(ctx, "update pending refund", `
+		UPDATE pending_refunds
+		SET attempts = attempts + 1, last_error = $2, next_attempt_at = now() + $3 * INTERVAL '1 second'
+		WHERE tx_id = $1
+	`, txID, lastErr, int(backoff.Seconds()))
+	return err
+}
+
+func (t *pgTx) deletePendingRefund(ctx context.Context, txID uuid.UUID) error {
+	_, err := t.exec(ctx, "delete pending refund", `DELETE FROM pending_refunds WHERE tx_id = $1`, txID)
+	return err
+}
+
+func (t *pgTx) insertWaitlistEntry(ctx context.Context, entry *WaitlistEntry, email *string) error {
+	_, err := t.tx.Exec(ctx, `
+		INSERT INTO waitlist_entries (id, event_id, tier_id, actor_id, quantity, contact_email)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, entry.ID, entry.EventID, entry.TierID, entry.ActorID, entry.Quantity, email)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return ErrAlreadyWaitlisted
+		}
+
+		return fmt.Errorf("failed to create waitlist entry: %w", err)
+	}
+
+	return nil
+}
+
+func (t *pgTx) lockWaitlistEntry(ctx context.Context, entryID uuid.UUID) (*WaitlistEntry, error) {
+	return t.waitlistEntry(ctx, entryID, true)
+}
+
+func (t *pgTx) setWaitlistStatus(ctx context.Context, entryID uuid.UUID, status WaitlistStatus) error {
+	_, err := t.exec(
+		ctx, "update waitlist entry",
+		`UPDATE waitlist_entries SET status = $2, updated_at = now() WHERE id = $1`, entryID, status,
+	)
+	return err
+}
+
+// lockWaitlistHead doesn't skip locked entries, so concurrent offers wait for each other and preserve FIFO order.
+func (t *pgTx) lockWaitlistHead(ctx context.Context, tierID uuid.UUID, maxQuantity int) (*waitlistHead, error) {
+	head := &waitlistHead{}
+	err := pgxscan.Get(ctx, t.tx, head, `
+		SELECT w.id, w.event_id, w.actor_id, w.contact_email, w.quantity, e.currency, e.hold_ttl_seconds
+		FROM waitlist_entries w
+		INNER JOIN events e ON e.id = w.event_id
+		WHERE w.tier_id = $1 AND w.status = $2 AND w.quantity <= $3
+		ORDER BY w.created_at, w.id
+		LIMIT 1
+		FOR UPDATE OF w
+	`, tierID, WaitlistWaiting, maxQuantity)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to query waitlist: %w", err)
+	}
+
+	return head, nil
+}
+
+func (t *pgTx) setWaitlistOffer(ctx context.Context, entryID, reservationID uuid.UUID) error {
+	_, err := t.exec(ctx, "update waitlist entry", `
+		UPDATE waitlist_entries
+		SET status = $2, offer_reservation_id = $3, updated_at = now()
+		WHERE id = $1
+	`, entryID, WaitlistOffered, reservationID)
+	return err
+}
+
+func (t *pgTx) resolveWaitlistOffers(ctx context.Context, status WaitlistStatus, reservationIDs ...uuid.UUID) error {
+	_, err := t.exec(ctx, "update waitlist offers", `
+		UPDATE waitlist_entries
+		SET status = $2, updated_at = now()
+		WHERE offer_reservation_id = ANY($1)
+	`, reservationIDs, status)
+	return err
+}
+
+func (t *pgTx) cancelEventWaitlist(ctx context.Context, eventID uuid.UUID) error {
+	_, err := t.exec(ctx, "cancel waitlist", `
+		UPDATE waitlist_entries
+		SET status = $2, updated_at = now()
+		WHERE event_id = $1 AND status IN ($3, $4)
+	`, eventID, WaitlistCancelled, WaitlistWaiting, WaitlistOffered)
+	return err
+}
+
+func (t *pgTx) insertResaleListing(ctx context.Context, listing *ResaleListing) error {
+	_, err := t.exec(ctx, "create listing", `
+		INSERT INTO resale_listings (id, event_id, tier_id, seller_id, currency, price_cents, face_value_cents)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`,
+		listing.ID, listing.EventID, listing.TierID, listing.SellerID,
+		listing.Currency, listing.PriceCents, listing.FaceValueCents,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]any, 0, len(listing.TicketIDs))
+	for _, id := range listing.TicketIDs {
+		rows = append(rows, []any{listing.ID, id})
+	}
+
+	_, err = t.tx.CopyFrom(
+		ctx, pgx.Identifier{"resale_listing_items"}, []string{"listing_id", "ticket_id"}, pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store listing items: %w", err)
+	}
+
+	return nil
+}
+
+func (t *pgTx) lockResaleListing(ctx context.Context, listingID uuid.UUID) (*ResaleListing, error) {
+	return t.resaleListing(ctx, listingID, true)
+}
+
+func (t *pgTx) setListingStatus(ctx context.Context, listingID uuid.UUID, status ResaleStatus) error {
+	_, err := t.exec(
+		ctx, "update listing", `UPDATE resale_listings SET status = $2, resolved_at = now() WHERE id = $1`,
+		listingID, status,
+	)
+	return err
+}
+
+func (t *pgTx) holdListing(ctx context.Context, listingID, reservationID uuid.UUID, expireAt time.Time) error {
+	_, err := t.exec(
+		ctx, "hold listing", `UPDATE resale_listings SET hold_token = $2, hold_expires_at = $3 WHERE id = $1`,
+		listingID, reservationID, expireAt,
+	)
+	return err
+}
+
+func (t *pgTx) sellListing(ctx context.Context, listingID, reservationID uuid.UUID) (bool, error) {
+	tag, err := t.exec(ctx, "update listing", `
+		UPDATE resale_listings
+		SET status = $3, buyer_reservation_id = $2, hold_token = NULL, hold_expires_at = NULL, resolved_at = now()
+		WHERE id = $1 AND status = $4 AND hold_token = $2
+	`, listingID, reservationID, ResaleSold, ResaleActive)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (t *pgTx) setListingPayout(ctx context.Context, listingID uuid.UUID, feeCents, payoutCents int64, txID uuid.UUID) error {
+	_, err := t.exec(ctx, "store payout", `
+		UPDATE resale_listings
+		SET fee_cents = $2, payout_cents = $3, payout_tx_id = $4
+		WHERE id = $1
+	`, listingID, feeCents, payoutCents, txID)
+	return err
+}
+
+func (t *pgTx) releaseHeldListings(ctx context.Context, reservationIDs ...uuid.UUID) error {
+	_, err := t.exec(ctx, "release listings", `
+		UPDATE resale_listings
+		SET hold_token = NULL, hold_expires_at = NULL
+		WHERE hold_token = ANY($1) AND status = $2
+	`, reservationIDs, ResaleActive)
+	return err
+}
+
+func (t *pgTx) cancelReservationListings(ctx context.Context, reservationID uuid.UUID) error {
+	_, err := t.exec(ctx, "cancel resale listings", `
+		UPDATE resale_listings
+		SET status = $2, hold_token = NULL, hold_expires_at = NULL, resolved_at = now()
+		WHERE status = $3 AND id IN (
+			SELECT i.listing_id
+			FROM resale_listing_items i
+			INNER JOIN tickets t ON t.id = i.ticket_id
+			WHERE t.reservation_id = $1
+		)
+	`, reservationID, ResaleCancelled, ResaleActive)
+	return err
+}
+
+func (t *pgTx) cancelEventListings(ctx context.Context, eventID uuid.UUID) error {
+	_, err := t.exec(ctx, "cancel resale listings", `
+		UPDATE resale_listings
+		SET status = $2, hold_token = NULL, hold_expires_at = NULL, resolved_at = now()
+		WHERE event_id = $1 AND status = $3
+	`, eventID, ResaleCancelled, ResaleActive)
+	return err
+}
+
+func (t *pgTx) insertTransfer(ctx context.Context, transfer *Transfer, claimToken *string) error {
+	_, err := t.exec(ctx, "create transfer", `
+		INSERT INTO ticket_transfers (id, event_id, from_actor_id, to_actor_id, to_email, claim_token)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, transfer.ID, transfer.EventID, transfer.FromActorID, transfer.ToActorID, transfer.ToEmail, claimToken)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]any, 0, len(transfer.TicketIDs))
+	for _, id := range transfer.TicketIDs {
+		rows = append(rows, []any{transfer.ID, id})
+	}
+
+	_, err = t.tx.CopyFrom(
+		ctx, pgx.Identifier{"ticket_transfer_items"}, []string{"transfer_id", "ticket_id"}, pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store transfer items: %w", err)
+	}
+
+	return nil
+}
+
+func (t *pgTx) lockTransfer(ctx context.Context, transferID uuid.UUID) (*Transfer, error) {
+	return t.transfer(ctx, transferID, true)
+}
+
+func (t *pgTx) resolveTransfer(
+	ctx context.Context, transferID uuid.UUID, status TransferStatus, acceptedBy *uuid.UUID,
+) error {
+	_, err := t.exec(ctx, "update transfer", `
+		UPDATE ticket_transfers
+		SET status = $2, accepted_by = $3, resolved_at = now()
+		WHERE id = $1
+	`, transferID, status, acceptedBy)
+	return err
+}
+
+func (t *pgTx) cancelReservationTransfers(ctx context.Context, reservationID uuid.UUID) error {
+	_, err := t.exec(ctx, "cancel pending transfers", `
+		UPDATE ticket_transfers
+		SET status = $2, resolved_at = now()
+		WHERE status = $3 AND id IN (
+			SELECT i.transfer_id
+			FROM ticket_transfer_items i
+			INNER JOIN tickets t ON t.id = i.ticket_id
+			WHERE t.reservation_id = $1
+		)
+	`, reservationID, TransferCancelled, TransferPending)
+	return err
+}
+
+func (t *pgTx) cancelEventTransfers(ctx context.Context, eventID uuid.UUID) error {
+	_, err := t.exec(ctx, "cancel transfers", `
+		UPDATE ticket_transfers
+		SET status = $2, resolved_at = now()
+		WHERE event_id = $1 AND status = $3
+	`, eventID, TransferCancelled, TransferPending)
+	return err
+}
+
+func (t *pgTx) lockScan(ctx context.Context, scanID uuid.UUID) error {
+	_, err := t.exec(ctx, "lock scan", `SELECT pg_advisory_xact_lock(hashtextextended($1::text, 0))`, scanID)
+	return err
+}
+
+func (t *pgTx) insertScan(ctx context.Context, scan *checkInScan) error {
+	_, err := t.exec(ctx, "store scan", `
+		INSERT INTO checkin_scans (scan_id, code_id, gate_id, status, is_offline, scanned_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, scan.ScanID, scan.CodeID, scan.GateID, scan.Status, scan.IsOffline, scan.ScannedAt)
+	return err
+}
+
+func (t *pgTx) supersedeScans(ctx context.Context, ticketID uuid.UUID, scannedAt time.Time) error {
+	_, err := t.exec(ctx, "supersede scan", `
+		UPDATE checkin_scans
+		SET status = $3
+		WHERE code_id IN (SELECT id FROM ticket_codes WHERE ticket_id = $1) AND status = $4 AND scanned_at = $2
+	`, ticketID, scannedAt, CheckInSuperseded, CheckInAccepted)
+	return err
+}
+
+var (
+	_ Store   = (*PostgresStore)(nil)
+	_ storeTx = (*pgTx)(nil)
+)
@@ -0,0 +1,90 @@
+package booking
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/ticketcode"
+)
+
+type soldTicket struct {
+	ID            uuid.UUID `db:"id"`
+	EventID       uuid.UUID `db:"event_id"`
+	ReservationID uuid.UUID `db:"reservation_id"`
+}
+
+// issueTicketCodes issues signed codes for all tickets sold within a reservation to reservation owner.
+func (svc Service) issueTicketCodes(ctx context.Context, tx storeTx, reservationID, ownerID uuid.UUID) error {
+	tickets, err := tx.getSoldTickets(ctx, reservationID)
+	if err != nil {
+		return err
+	}
+
+	return svc.storeTicketCodes(ctx, tx, tickets, ownerID, nil)
+}
+
+// storeTicketCodes issues new codes for tickets to an owner.
+//
+// Transfer ID is set if owner received tickets by transfer.
+func (svc Service) storeTicketCodes(
+	ctx context.Context, tx storeTx, tickets []soldTicket, ownerID uuid.UUID, transferID *uuid.UUID,
+) error {
+	codes := make([]*ticketCode, 0, len(tickets))
+	for _, t := range tickets {
+		claims := ticketcode.Claims{
+			ID:      uuid.New(),
+			EventID: t.EventID,
+		}
+
+		codes = append(codes, &ticketCode{
+			ID:            claims.ID,
+			TicketID:      t.ID,
+			ReservationID: t.ReservationID,
+			EventID:       t.EventID,
+			Code:          svc.codes.Sign(claims),
+			OwnerID:       ownerID,
+			TransferID:    transferID,
+		})
+	}
+
+	return tx.insertTicketCodes(ctx, codes)
+}
+
+// GetReservationTickets returns valid tickets with codes issued for a paid reservation.
+//
+// Tickets transferred to other users are not included.
+func (svc Service) GetReservationTickets(ctx context.Context, reservationID uuid.UUID) ([]*IssuedTicket, error) {
+	h, err := svc.store.getReservationHeader(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.Status != ReservationPaid {
+		return nil, fmt.Errorf(
+			"%w: tickets are issued only for paid reservations, reservation is %s", ErrInvalidStatus, h.Status,
+		)
+	}
+
+	return svc.store.getIssuedTickets(ctx, reservationID, h.ActorID)
+}
+
+// GetUserTickets returns valid tickets owned by an actor, including received by transfer.
+func (svc Service) GetUserTickets(ctx context.Context, actorID uuid.UUID) ([]*IssuedTicket, error) {
+	return svc.store.getUserTickets(ctx, actorID)
+}
+
+// GetTicketOwnership returns ownership history of a ticket in chronological order.
+func (svc Service) GetTicketOwnership(ctx context.Context, ticketID uuid.UUID) ([]*OwnershipRecord, error) {
+	result, err := svc.store.getTicketOwnership(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return result, nil
+}
@@ -0,0 +1,372 @@
+package booking
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxTransferTickets is max number of tickets in a single transfer.
+const maxTransferTickets = 50
+
+// transferTicket is a transferred ticket with its current valid code.
+type transferTicket struct {
+	CodeID           uuid.UUID  `db:"code_id"`
+	TicketID         uuid.UUID  `db:"ticket_id"`
+	EventID          uuid.UUID  `db:"event_id"`
+	ReservationID    uuid.UUID  `db:"reservation_id"`
+	OwnerID          uuid.UUID  `db:"owner_id"`
+	CheckedInAt      *time.Time `db:"checked_in_at"`
+	TransfersBlocked bool       `db:"transfers_blocked"`
+	EventCancelled   bool       `db:"event_cancelled"`
+}
+
+// checkTransferable checks that all tickets are valid, owned by an actor and can be transferred.
+func checkTransferable(ticketIDs []uuid.UUID, tickets []transferTicket, ownerID uuid.UUID) error {
+	found := make(map[uuid.UUID]struct{}, len(tickets))
+	for _, t := range tickets {
+		found[t.TicketID] = struct{}{}
+	}
+
+	// Tickets without valid code were never sold or are refunded.
+	for _, id := range ticketIDs {
+		if _, ok := found[id]; !ok {
+			return fmt.Errorf("%w: ticket %s is not issued or refunded", ErrInvalidStatus, id)
+		}
+	}
+
+	for _, t := range tickets {
+		switch {
+		case t.OwnerID != ownerID:
+			return fmt.Errorf("%w: ticket %s is not owned by sender", ErrInvalidParams, t.TicketID)
+		case t.EventID != tickets[0].EventID:
+			return fmt.Errorf("%w: all transferred tickets should belong to the same event", ErrInvalidParams)
+		case t.CheckedInAt != nil:
+			return fmt.Errorf("%w: ticket %s is already checked-in", ErrInvalidStatus, t.TicketID)
+		case t.EventCancelled:
+			return fmt.Errorf("%w: event is cancelled", ErrInvalidStatus)
+		case t.TransfersBlocked:
+			return fmt.Errorf("%w: transfers are disabled for the event", ErrInvalidStatus)
+		}
+	}
+
+	return nil
+}
+
+// checkTicketsAvailable checks that tickets are not a part of pending transfer or active resale listing.
+//
+// Should be called after ticket codes are locked.
+func checkTicketsAvailable(ctx context.Context, tx storeTx, ticketIDs []uuid.UUID) error {
+	isPending, err := tx.hasPendingTransfers(ctx, ticketIDs)
+	if err != nil {
+		return err
+	}
+
+	if isPending {
+		return fmt.Errorf("%w: ticket is already being transferred", ErrInvalidStatus)
+	}
+
+	isListed, err := tx.hasActiveListings(ctx, ticketIDs)
+	if err != nil {
+		return err
+	}
+
+	if isListed {
+		return fmt.Errorf("%w: ticket is listed for resale", ErrInvalidStatus)
+	}
+
+	return nil
+}
+
+func validateTransfer(params TransferParams) (*string, error) {
+	if len(params.TicketIDs) == 0 || len(params.TicketIDs) > maxTransferTickets {
+		return nil, fmt.Errorf("%w: transfer should contain from 1 to %d tickets", ErrInvalidParams, maxTransferTickets)
+	}
+
+	seen := make(map[uuid.UUID]struct{}, len(params.TicketIDs))
+	for _, id := range params.TicketIDs {
+		if _, ok := seen[id]; ok {
+			return nil, fmt.Errorf("%w: duplicate ticket %s", ErrInvalidParams, id)
+		}
+
+		seen[id] = struct{}{}
+	}
+
+	email, err := parseContactEmail(params.ToEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	if (params.ToActorID == nil) == (email == nil) {
+		return nil, fmt.Errorf("%w: either recipient actor ID or email should be set", ErrInvalidParams)
+	}
+
+	if params.ToActorID != nil && *params.ToActorID == params.ActorID {
+		return nil, fmt.Errorf("%w: can't transfer tickets to yourself", ErrInvalidParams)
+	}
+
+	return email, nil
+}
+
+// CreateTransfer initiates transfer of owned tickets to another user.
+//
+// Tickets stay valid for the sender until recipient accepts the transfer.
+// Transfer addressed by email gets a claim token, which is sent only to recipient's email.
+func (svc Service) CreateTransfer(ctx context.Context, params TransferParams) (*Transfer, error) {
+	toEmail, err := validateTransfer(params)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := svc.store.begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	tickets, err := tx.lockTicketCodes(ctx, params.TicketIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTransferable(params.TicketIDs, tickets, params.ActorID); err != nil {
+		return nil, err
+	}
+
+	if err := checkTicketsAvailable(ctx, tx, params.TicketIDs); err != nil {
+		return nil, err
+	}
+
+	var claimToken *string
+	if toEmail != nil {
+		token := rand.Text()
+		claimToken = &token
+	}
+
+	transferID := uuid.New()
+	eventID := tickets[0].EventID
+	err = tx.insertTransfer(ctx, &Transfer{
+		ID:          transferID,
+		EventID:     eventID,
+		FromActorID: params.ActorID,
+		ToActorID:   params.ToActorID,
+		ToEmail:     toEmail,
+		Status:      TransferPending,
+		TicketIDs:   params.TicketIDs,
+	}, claimToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Recipient addressed by email is notified.
+	createdEvent := reservationRef{
+		ID:      tickets[0].ReservationID,
+		EventID: eventID,
+		ActorID: params.ActorID,
+		Email:   toEmail,
+	}.event(TransferCreatedEvent)
+	createdEvent.TransferID = &transferID
+	if err := tx.writeOutbox(ctx, createdEvent); err != nil {
+		return nil, err
+	}
+
+	transfer, err := tx.getTransfer(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return transfer, nil
+}
+
+// AcceptTransfer accepts pending transfer.
+//
+// Sender's ticket codes are revoked and new codes are issued to the recipient.
+func (svc Service) AcceptTransfer(ctx context.Context, params TransferAcceptParams) (*Transfer, error) {
+	tx, err := svc.store.begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	transfer, err := tx.lockTransfer(ctx, params.TransferID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.Status != TransferPending {
+		return nil, fmt.Errorf("%w: transfer is %s", ErrInvalidStatus, transfer.Status)
+	}
+
+	claimToken, err := tx.getTransferClaimToken(ctx, transfer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkRecipient(transfer, claimToken, params); err != nil {
+		return nil, err
+	}
+
+	// Tickets could be checked-in, refunded or blocked for transfer since transfer was created.
+	tickets, err := tx.lockTicketCodes(ctx, transfer.TicketIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTransferable(transfer.TicketIDs, tickets, transfer.FromActorID); err != nil {
+		return nil, err
+	}
+
+	codeIDs := make([]uuid.UUID, 0, len(tickets))
+	reissued := make([]soldTicket, 0, len(tickets))
+	for _, t := range tickets {
+		codeIDs = append(codeIDs, t.CodeID)
+		reissued = append(reissued, soldTicket{
+			ID:            t.TicketID,
+			EventID:       t.EventID,
+			ReservationID: t.ReservationID,
+		})
+	}
+
+	if err := tx.reissueCodes(ctx, codeIDs); err != nil {
+		return nil, err
+	}
+
+	if err := svc.storeTicketCodes(ctx, tx, reissued, params.ActorID, &transfer.ID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.resolveTransfer(ctx, transfer.ID, TransferAccepted, &params.ActorID); err != nil {
+		return nil, err
+	}
+
+	acceptedEvent := reservationRef{
+		ID:      tickets[0].ReservationID,
+		EventID: transfer.EventID,
+		ActorID: params.ActorID,
+	}.event(TransferAcceptedEvent)
+	acceptedEvent.TransferID = &transfer.ID
+	if err := tx.writeOutbox(ctx, acceptedEvent); err != nil {
+		return nil, err
+	}
+
+	transfer, err = tx.getTransfer(ctx, transfer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return transfer, nil
+}
+
+func checkRecipient(transfer *Transfer, claimToken *string, params TransferAcceptParams) error {
+	if params.ActorID == transfer.FromActorID {
+		return fmt.Errorf("%w: can't accept own transfer", ErrInvalidParams)
+	}
+
+	if transfer.ToActorID != nil && *transfer.ToActorID != params.ActorID {
+		return fmt.Errorf("%w: transfer is addressed to another user", ErrInvalidParams)
+	}
+
+	// Recipient email is not a secret, so transfer addressed by email is claimed by a token sent to it.
+	if transfer.ToEmail != nil &&
+		(claimToken == nil || subtle.ConstantTimeCompare([]byte(params.ClaimToken), []byte(*claimToken)) != 1) {
+		return fmt.Errorf("%w: invalid transfer claim token", ErrInvalidParams)
+	}
+
+	return nil
+}
+
+// CancelTransfer cancels pending transfer. Transfer can be cancelled by sender or declined by recipient.
+func (svc Service) CancelTransfer(ctx context.Context, params TransferCancelParams) error {
+	tx, err := svc.store.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	transfer, err := tx.lockTransfer(ctx, params.TransferID)
+	if err != nil {
+		return err
+	}
+
+	isRecipient := transfer.ToActorID != nil && *transfer.ToActorID == params.ActorID
+	if params.ActorID != transfer.FromActorID && !isRecipient {
+		return fmt.Errorf("%w: transfer can be cancelled only by sender or recipient", ErrInvalidParams)
+	}
+
+	if transfer.Status != TransferPending {
+		return fmt.Errorf("%w: transfer is %s", ErrInvalidStatus, transfer.Status)
+	}
+
+	if err := tx.resolveTransfer(ctx, transfer.ID, TransferCancelled, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetTransfer returns transfer by ID.
+func (svc Service) GetTransfer(ctx context.Context, transferID uuid.UUID) (*Transfer, error) {
+	return svc.store.getTransfer(ctx, transferID)
+}
+
+// GetTransferClaimToken returns claim token of a transfer addressed by email.
+//
+// Token is sent only to recipient's email and is never returned by the API.
+func (svc Service) GetTransferClaimToken(ctx context.Context, transferID uuid.UUID) (string, error) {
+	claimToken, err := svc.store.getTransferClaimToken(ctx, transferID)
+	if err != nil {
+		return "", err
+	}
+
+	if claimToken == nil {
+		return "", ErrNotFound
+	}
+
+	return *claimToken, nil
+}
+
+// GetUserTransfers returns transfers sent or received by an actor.
+func (svc Service) GetUserTransfers(ctx context.Context, actorID uuid.UUID) ([]*Transfer, error) {
+	return svc.store.getUserTransfers(ctx, actorID)
+}
+
+// SetTransfersBlocked allows or forbids ticket transfers for an event.
+//
+// Pending transfers can't be accepted while transfers are blocked.
+func (svc Service) SetTransfersBlocked(ctx context.Context, eventID uuid.UUID, blocked bool) error {
+	tx, err := svc.store.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err := tx.setTransfersBlocked(ctx, eventID, blocked); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -4,28 +4,106 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/pricing"
 )
 
 type Event struct {
-	ID   uuid.UUID `json:"id" db:"id"`
-	Name string    `json:"name" db:"name"`
+	ID       uuid.UUID `json:"id" db:"id"`
+	Name     string    `json:"name" db:"name"`
+	Currency Currency  `json:"currency" db:"currency"`
+
+	// TaxRateBps is sales tax rate in basis points included in tier prices.
+	TaxRateBps uint `json:"taxRateBps" db:"tax_rate_bps"`
+
+	// OrganizerID is optional organizer which owns the event.
+	OrganizerID *uuid.UUID `json:"organizerID,omitempty" db:"organizer_id"`
+
+	// TransfersBlocked is true if tickets of the event can't be transferred.
+	TransfersBlocked bool `json:"transfersBlocked" db:"transfers_blocked"`
+
+	// HoldTTLSeconds is how long reserved tickets are held until payment.
+	HoldTTLSeconds uint `json:"holdTTLSeconds" db:"hold_ttl_seconds"`
+
+	CancelledAt *time.Time `json:"cancelledAt,omitempty" db:"cancelled_at"`
 }
 
 type TicketTier struct {
 	TierID         uuid.UUID `json:"tier_id" db:"tier_id"`
 	Name           string    `json:"name" db:"tier_name"`
 	PriceCents     int       `json:"priceCents" db:"price_cents"`
+	Currency       Currency  `json:"currency" db:"currency"`
 	AvailableCount int       `json:"availableCount" db:"available_count"`
+
+	// PresentmentPrice is a tier price converted to requested display currency.
+	PresentmentPrice *Money `json:"presentmentPrice,omitempty" db:"-"`
+
+	// NextThreshold is the next price change point, if tier has pricing rules.
+	NextThreshold *pricing.Threshold `json:"nextThreshold,omitempty" db:"-"`
 }
 
 type CreateTierParams struct {
+	// PriceCents is a base tier price.
 	PriceCents   int `json:"priceCents"`
 	TicketsCount int `json:"ticketsCount"`
+
+	// Currency is optional tier currency. Should match event currency.
+	Currency Currency `json:"currency,omitempty"`
+
+	// Limits are optional price floor and ceiling for dynamic pricing.
+	pricing.Limits
+
+	// PricingRules are optional dynamic pricing rules.
+	PricingRules []pricing.Rule `json:"pricingRules,omitempty"`
+}
+
+// TierPricingParams is tier pricing configuration.
+type TierPricingParams struct {
+	BasePriceCents int `json:"basePriceCents"`
+	pricing.Limits
+
+	Rules []pricing.Rule `json:"rules"`
+}
+
+// TierPricing is tier pricing configuration with current effective price.
+type TierPricing struct {
+	TierID         uuid.UUID `json:"tierID"`
+	BasePriceCents int       `json:"basePriceCents"`
+	PriceCents     int       `json:"priceCents"`
+	pricing.Limits
+
+	Rules         []pricing.Rule     `json:"rules"`
+	NextThreshold *pricing.Threshold `json:"nextThreshold,omitempty"`
+}
+
+// PriceChange is tier price history entry.
+type PriceChange struct {
+	OldPriceCents int       `json:"oldPriceCents" db:"old_price_cents"`
+	NewPriceCents int       `json:"newPriceCents" db:"new_price_cents"`
+	Reason        string    `json:"reason" db:"reason"`
+	ChangedAt     time.Time `json:"changedAt" db:"changed_at"`
 }
 
 type EventCreateParams struct {
 	EventName string `json:"name"`
 
+	// Currency is event settlement currency. USD is used by default.
+	Currency Currency `json:"currency,omitempty"`
+
+	// TaxRateBps is sales tax rate in basis points (e.g. 2000 is 20%).
+	//
+	// Tier prices are tax-inclusive, tax is only itemized on receipts.
+	TaxRateBps uint `json:"taxRateBps,omitempty"`
+
+	// OrganizerID is optional organizer (promoter) which owns the event.
+	OrganizerID *uuid.UUID `json:"organizerID,omitempty"`
+
+	// TransfersBlocked forbids ticket transfers between users.
+	TransfersBlocked bool `json:"transfersBlocked,omitempty"`
+
+	// HoldTTLSeconds is optional reservation hold duration. 15 minutes are used by default.
+	HoldTTLSeconds uint `json:"holdTTLSeconds,omitempty"`
+
 	Tiers map[string]CreateTierParams `json:"tiers"`
 }
 
@@ -39,27 +117,407 @@ type ReservationParams struct {
 	ActorID        uuid.UUID          `json:"actorID"`
 	EventID        uuid.UUID          `json:"eventID"`
 	TicketsCount   map[uuid.UUID]uint `json:"ticketsCount"`
+
+	// Email is optional customer email used for notifications.
+	Email string `json:"email,omitempty"`
 }
 
 type ReservationResult struct {
+	ReservationID uuid.UUID `json:"reservationID" db:"reservation_id"`
+	ExpiresAt     time.Time `json:"expiresAt" db:"expires_at"`
+}
+
+// ReservationModifyParams is a change of pending reservation quantities.
+type ReservationModifyParams struct {
+	ReservationID uuid.UUID `json:"-"`
+
+	// TicketsCount is new number of tickets per tier.
+	// Tiers which are not specified are kept as is, zero quantity removes a tier.
+	TicketsCount map[uuid.UUID]uint `json:"ticketsCount"`
+}
+
+// ExtensionResult is reservation hold state after extension.
+type ExtensionResult struct {
 	ReservationID uuid.UUID `json:"reservationID"`
 	ExpiresAt     time.Time `json:"expiresAt"`
+
+	// ExtensionsLeft is number of remaining hold extensions.
+	ExtensionsLeft uint `json:"extensionsLeft"`
 }
 
+// ReservationStatus is reservation lifecycle status.
+type ReservationStatus string
+
+const (
+	ReservationPending   ReservationStatus = "pending"
+	ReservationPaid      ReservationStatus = "paid"
+	ReservationCancelled ReservationStatus = "cancelled"
+	ReservationExpired   ReservationStatus = "expired"
+	ReservationRefunded  ReservationStatus = "refunded"
+)
+
 type ReservationMeta struct {
-	ID        uuid.UUID `json:"id" db:"id"`
-	EventID   uuid.UUID `json:"eventID" db:"event_id"`
-	EventName string    `json:"eventName" db:"event_name"`
-	ExpiresAt time.Time `json:"expiresAt" db:"expires_at"`
-	IsPaid    bool      `json:"isPaid" db:"is_paid"`
+	ID         uuid.UUID         `json:"id" db:"id"`
+	EventID    uuid.UUID         `json:"eventID" db:"event_id"`
+	EventName  string            `json:"eventName" db:"event_name"`
+	ExpiresAt  time.Time         `json:"expiresAt" db:"expires_at"`
+	IsPaid     bool              `json:"isPaid" db:"is_paid"`
+	Status     ReservationStatus `json:"status" db:"status"`
+	TotalCents uint              `json:"totalCents" db:"total_cents"`
+	Currency   Currency          `json:"currency" db:"currency"`
+
+	// Extensions is number of times reservation hold was extended.
+	Extensions uint `json:"extensions" db:"extensions_count"`
+
+	Items []*ReservationItem `json:"items" db:"-"`
+}
+
+// ReservationItem is a reservation line item.
+//
+// Unit price is locked-in at reservation time.
+type ReservationItem struct {
+	ReservationID  uuid.UUID `json:"-" db:"reservation_id"`
+	TierID         uuid.UUID `json:"tierID" db:"tier_id"`
+	TierName       string    `json:"tierName" db:"tier_name"`
+	Quantity       uint      `json:"quantity" db:"quantity"`
+	UnitPriceCents uint      `json:"unitPriceCents" db:"unit_price_cents"`
 }
 
 type PaymentResult struct {
-	TxID        uuid.UUID `json:"txId"`
-	AmountCents uint      `json:"amountCents"`
+	TxID uuid.UUID `json:"txId"`
+
+	// AmountCents is charged amount in settlement currency.
+	AmountCents uint `json:"amountCents"`
+
+	// Settlement is charged amount in event currency.
+	Settlement Money `json:"settlement"`
+
+	// Presentment is charged amount in currency displayed to a customer.
+	Presentment Money `json:"presentment"`
+
+	// ExchangeRate is settlement to presentment currency rate used for conversion.
+	ExchangeRate string `json:"exchangeRate"`
+}
+
+// IssuedTicket is a sold ticket with a code to present at the venue entrance.
+type IssuedTicket struct {
+	TicketID uuid.UUID `json:"ticketID" db:"ticket_id"`
+	EventID  uuid.UUID `json:"eventID" db:"event_id"`
+	TierID   uuid.UUID `json:"tierID" db:"tier_id"`
+	TierName string    `json:"tierName" db:"tier_name"`
+
+	// Code is HMAC-signed ticket code encoded in QR code.
+	Code     string    `json:"code" db:"code"`
+	IssuedAt time.Time `json:"issuedAt" db:"issued_at"`
+}
+
+// OwnershipRecord is a period of ticket ownership.
+type OwnershipRecord struct {
+	OwnerID uuid.UUID `json:"ownerID" db:"owner_id"`
+
+	// TransferID is a transfer by which owner received the ticket. Empty for the buyer.
+	TransferID    *uuid.UUID `json:"transferID,omitempty" db:"transfer_id"`
+	ReservationID uuid.UUID  `json:"reservationID" db:"reservation_id"`
+
+	// AcquiredAt is when owner got the ticket code.
+	AcquiredAt time.Time `json:"acquiredAt" db:"issued_at"`
+
+	// ReleasedAt is when owner's code was revoked by transfer or refund.
+	ReleasedAt *time.Time `json:"releasedAt,omitempty" db:"revoked_at"`
+}
+
+// TransferStatus is ticket transfer status.
+type TransferStatus string
+
+const (
+	// TransferPending means that transfer waits for recipient to accept it.
+	TransferPending TransferStatus = "pending"
+
+	// TransferAccepted means that tickets were reissued to recipient.
+	TransferAccepted TransferStatus = "accepted"
+
+	// TransferCancelled means that transfer was cancelled by sender or declined by recipient.
+	TransferCancelled TransferStatus = "cancelled"
+)
+
+type TransferParams struct {
+	// ActorID is current tickets owner.
+	ActorID   uuid.UUID   `json:"actorID"`
+	TicketIDs []uuid.UUID `json:"ticketIDs"`
+
+	// Recipient is addressed either by actor ID or by email.
+	ToActorID *uuid.UUID `json:"toActorID,omitempty"`
+	ToEmail   string     `json:"toEmail,omitempty"`
+}
+
+type TransferAcceptParams struct {
+	TransferID uuid.UUID `json:"-"`
+
+	// ActorID is a recipient which becomes new tickets owner.
+	ActorID uuid.UUID `json:"actorID"`
+
+	// ClaimToken is required if transfer is addressed by email.
+	//
+	// Token is sent to recipient's email on transfer creation.
+	ClaimToken string `json:"claimToken,omitempty"`
+}
+
+type TransferCancelParams struct {
+	TransferID uuid.UUID `json:"-"`
+
+	// ActorID is either sender or recipient actor.
+	ActorID uuid.UUID `json:"actorID"`
+}
+
+// Transfer is a transfer of tickets between users.
+type Transfer struct {
+	ID          uuid.UUID      `json:"id" db:"id"`
+	EventID     uuid.UUID      `json:"eventID" db:"event_id"`
+	FromActorID uuid.UUID      `json:"fromActorID" db:"from_actor_id"`
+	ToActorID   *uuid.UUID     `json:"toActorID,omitempty" db:"to_actor_id"`
+	ToEmail     *string        `json:"toEmail,omitempty" db:"to_email"`
+	Status      TransferStatus `json:"status" db:"status"`
+	TicketIDs   []uuid.UUID    `json:"ticketIDs" db:"ticket_ids"`
+
+	// AcceptedBy is recipient actor which accepted the transfer.
+	AcceptedBy *uuid.UUID `json:"acceptedBy,omitempty" db:"accepted_by"`
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty" db:"resolved_at"`
+}
+
+// PaymentRecord is a stored payment of a reservation.
+type PaymentRecord struct {
+	TxID         uuid.UUID  `json:"txId"`
+	PaidAt       time.Time  `json:"paidAt"`
+	Settlement   Money      `json:"settlement"`
+	Presentment  Money      `json:"presentment"`
+	ExchangeRate string     `json:"exchangeRate"`
+	RefundedAt   *time.Time `json:"refundedAt,omitempty"`
+}
+
+// Receipt is a payment receipt of a reservation.
+type Receipt struct {
+	Reservation *ReservationMeta `json:"reservation"`
+	Payment     PaymentRecord    `json:"payment"`
+
+	// TaxRateBps is event sales tax rate in basis points.
+	TaxRateBps uint `json:"taxRateBps"`
+
+	// Tax is tax amount included in reservation total.
+	Tax Money `json:"tax"`
+}
+
+type RefundResult struct {
+	TxID uuid.UUID `json:"txId"`
+
+	// Settlement is refunded amount in event currency.
+	Settlement Money `json:"settlement"`
+
+	// IsPending is true if payment provider failed to process refund and it will be retried.
+	IsPending bool `json:"isPending,omitempty"`
 }
 
 type PaymentParams struct {
 	ReservationID uuid.UUID `json:"reservationID"`
 	CardNumber    string    `json:"cardNumber"`
+
+	// Currency is optional presentment currency. Settlement currency is used by default.
+	Currency Currency `json:"currency,omitempty"`
+}
+
+// WaitlistStatus is waitlist entry status.
+type WaitlistStatus string
+
+const (
+	// WaitlistWaiting means that actor waits for tickets in a queue.
+	WaitlistWaiting WaitlistStatus = "waiting"
+
+	// WaitlistOffered means that tickets are held for actor in an offer reservation.
+	WaitlistOffered WaitlistStatus = "offered"
+
+	// WaitlistFulfilled means that offer reservation was paid.
+	WaitlistFulfilled WaitlistStatus = "fulfilled"
+
+	// WaitlistExpired means that offer reservation wasn't paid in time.
+	WaitlistExpired WaitlistStatus = "expired"
+
+	// WaitlistCancelled means that actor left the waitlist or cancelled the offer.
+	WaitlistCancelled WaitlistStatus = "cancelled"
+)
+
+type WaitlistJoinParams struct {
+	EventID  uuid.UUID `json:"-"`
+	TierID   uuid.UUID `json:"-"`
+	ActorID  uuid.UUID `json:"actorID"`
+	Quantity uint      `json:"quantity"`
+
+	// Email is optional actor email used to notify about an offer.
+	Email string `json:"email,omitempty"`
+}
+
+type WaitlistEntry struct {
+	ID       uuid.UUID      `json:"id" db:"id"`
+	EventID  uuid.UUID      `json:"eventID" db:"event_id"`
+	TierID   uuid.UUID      `json:"tierID" db:"tier_id"`
+	ActorID  uuid.UUID      `json:"actorID" db:"actor_id"`
+	Quantity uint           `json:"quantity" db:"quantity"`
+	Status   WaitlistStatus `json:"status" db:"status"`
+
+	// OfferReservationID is a reservation which holds offered tickets.
+	OfferReservationID *uuid.UUID `json:"offerReservationID,omitempty" db:"offer_reservation_id"`
+
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// WaitlistOffer is exclusive ticket hold offered to a waitlisted actor.
+type WaitlistOffer struct {
+	EntryID       uuid.UUID
+	ActorID       uuid.UUID
+	Email         *string
+	EventID       uuid.UUID
+	TierID        uuid.UUID
+	ReservationID uuid.UUID
+	Quantity      uint
+	ExpiresAt     time.Time
+}
+
+// CheckInStatus is ticket scan result.
+type CheckInStatus string
+
+const (
+	// CheckInAccepted means that ticket is valid and checked-in by this scan.
+	CheckInAccepted CheckInStatus = "accepted"
+
+	// CheckInDuplicate means that ticket was already checked-in by an earlier scan.
+	CheckInDuplicate CheckInStatus = "duplicate"
+
+	// CheckInRevoked means that ticket was refunded or transferred.
+	CheckInRevoked CheckInStatus = "revoked"
+
+	// CheckInInvalid means that code is malformed, forged or unknown.
+	CheckInInvalid CheckInStatus = "invalid"
+
+	// CheckInWrongEvent means that ticket is valid but belongs to another event.
+	CheckInWrongEvent CheckInStatus = "wrong_event"
+
+	// CheckInSuperseded means that scan admitted the ticket, but an earlier offline scan was uploaded later.
+	//
+	// Only stored in scans audit log.
+	CheckInSuperseded CheckInStatus = "superseded"
+)
+
+type CheckInParams struct {
+	Code   string `json:"code"`
+	GateID string `json:"gateID"`
+
+	// EventID is optional event served by a gate. Tickets of other events are rejected.
+	EventID uuid.UUID `json:"eventID"`
+}
+
+// OfflineScan is a scan made by a gate device while it was disconnected.
+type OfflineScan struct {
+	// ScanID is unique scan ID generated by device. Used to deduplicate repeated uploads.
+	ScanID uuid.UUID `json:"scanID"`
+
+	Code      string    `json:"code"`
+	ScannedAt time.Time `json:"scannedAt"`
+}
+
+type CheckInBatchParams struct {
+	GateID  string        `json:"gateID"`
+	EventID uuid.UUID     `json:"eventID"`
+	Scans   []OfflineScan `json:"scans"`
+}
+
+type CheckInResult struct {
+	ScanID *uuid.UUID    `json:"scanID,omitempty"`
+	Status CheckInStatus `json:"status"`
+
+	TicketID *uuid.UUID `json:"ticketID,omitempty"`
+	EventID  *uuid.UUID `json:"eventID,omitempty"`
+	TierName string     `json:"tierName,omitempty"`
+
+	// CheckedInAt and GateID describe the check-in which admitted the ticket.
+	//
+	// For duplicate scans it's the earlier scan.
+	CheckedInAt *time.Time `json:"checkedInAt,omitempty"`
+	GateID      string     `json:"gateID,omitempty"`
+}
+
+// CheckInStats is event check-in summary for ops dashboard.
+type CheckInStats struct {
+	EventID uuid.UUID `json:"eventID"`
+
+	// Issued is number of valid issued tickets.
+	Issued int `json:"issued"`
+
+	// CheckedIn is number of checked-in tickets.
+	CheckedIn int `json:"checkedIn"`
+
+	// Gates is number of checked-in tickets per gate.
+	Gates map[string]int `json:"gates"`
+}
+
+// ResaleStatus is resale listing status.
+type ResaleStatus string
+
+const (
+	// ResaleActive means that listing is on sale. Active listing might be temporarily held by a buyer.
+	ResaleActive ResaleStatus = "active"
+
+	// ResaleSold means that tickets were paid by a buyer and reissued to them.
+	ResaleSold ResaleStatus = "sold"
+
+	// ResaleCancelled means that listing was withdrawn by seller or tickets became invalid.
+	ResaleCancelled ResaleStatus = "cancelled"
+)
+
+type ResaleListingParams struct {
+	// ActorID is current tickets owner.
+	ActorID   uuid.UUID   `json:"actorID"`
+	TicketIDs []uuid.UUID `json:"ticketIDs"`
+
+	// PriceCents is price per ticket in event currency. Capped at percentage of face value.
+	PriceCents uint `json:"priceCents"`
+}
+
+type ResaleCancelParams struct {
+	ListingID uuid.UUID `json:"-"`
+	ActorID   uuid.UUID `json:"actorID"`
+}
+
+type ResaleReserveParams struct {
+	ListingID      uuid.UUID `json:"-"`
+	IdempotencyKey uuid.UUID `json:"idempotencyKey"`
+	ActorID        uuid.UUID `json:"actorID"`
+
+	// Email is optional customer email used for notifications.
+	Email string `json:"email,omitempty"`
+}
+
+// ResaleListing is a group of tickets of a single tier put on resale by an owner.
+type ResaleListing struct {
+	ID       uuid.UUID    `json:"id" db:"id"`
+	EventID  uuid.UUID    `json:"eventID" db:"event_id"`
+	TierID   uuid.UUID    `json:"tierID" db:"tier_id"`
+	TierName string       `json:"tierName" db:"tier_name"`
+	SellerID uuid.UUID    `json:"sellerID" db:"seller_id"`
+	Status   ResaleStatus `json:"status" db:"status"`
+	Currency Currency     `json:"currency" db:"currency"`
+
+	// PriceCents is price per ticket.
+	PriceCents uint `json:"priceCents" db:"price_cents"`
+
+	// FaceValueCents is the lowest original price paid for listed tickets.
+	FaceValueCents uint        `json:"faceValueCents" db:"face_value_cents"`
+	TicketIDs      []uuid.UUID `json:"ticketIDs" db:"ticket_ids"`
+
+	// FeeCents and PayoutCents are marketplace fee and amount paid out to seller for sold listing.
+	// HeldUntil is set while active listing is held by a buyer reservation.
+	HeldUntil *time.Time `json:"heldUntil,omitempty" db:"held_until"`
+
+	FeeCents    *uint      `json:"feeCents,omitempty" db:"fee_cents"`
+	PayoutCents *uint      `json:"payoutCents,omitempty" db:"payout_cents"`
+	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
+	ResolvedAt  *time.Time `json:"resolvedAt,omitempty" db:"resolved_at"`
 }
@@ -0,0 +1,271 @@
+package booking
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// waitlistOfferEvents returns domain events of created waitlist offers.
+func waitlistOfferEvents(offers []WaitlistOffer) []DomainEvent {
+	events := make([]DomainEvent, 0, len(offers))
+	for _, offer := range offers {
+		e := reservationRef{
+			ID:      offer.ReservationID,
+			EventID: offer.EventID,
+			ActorID: offer.ActorID,
+			Email:   offer.Email,
+		}.event(WaitlistOfferedEvent)
+		e.ExpiresAt = &offer.ExpiresAt
+		events = append(events, e)
+	}
+
+	return events
+}
+
+// JoinWaitlist adds actor to tier waitlist.
+//
+// Only sold-out tiers can be joined: tiers without free tickets or reserved for actors already in a queue.
+// Requested quantity can't exceed tier capacity.
+func (svc Service) JoinWaitlist(ctx context.Context, params WaitlistJoinParams) (*WaitlistEntry, error) {
+	if params.Quantity == 0 {
+		return nil, fmt.Errorf("%w: quantity should be positive", ErrInvalidParams)
+	}
+
+	email, err := parseContactEmail(params.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := svc.store.begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	tiers, err := tx.getTierPricing(ctx, []uuid.UUID{params.TierID})
+	if err != nil {
+		return nil, err
+	}
+
+	tier, ok := tiers[params.TierID]
+	if !ok || tier.EventID != params.EventID {
+		return nil, ErrNotFound
+	}
+
+	event, err := tx.getEvent(ctx, tier.EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.CancelledAt != nil {
+		return nil, fmt.Errorf("%w: event is cancelled", ErrInvalidStatus)
+	}
+
+	if params.Quantity > uint(tier.Capacity) {
+		return nil, fmt.Errorf("%w: tier has only %d tickets", ErrInvalidParams, tier.Capacity)
+	}
+
+	waitlisted, err := tx.getWaitlistedTiers(ctx, []uuid.UUID{params.TierID})
+	if err != nil {
+		return nil, err
+	}
+
+	if tier.Capacity > tier.Taken && !waitlisted[params.TierID] {
+		return nil, fmt.Errorf("%w: tier is not sold out", ErrInvalidStatus)
+	}
+
+	entryID := uuid.New()
+	err = tx.insertWaitlistEntry(ctx, &WaitlistEntry{
+		ID:       entryID,
+		EventID:  params.EventID,
+		TierID:   params.TierID,
+		ActorID:  params.ActorID,
+		Quantity: params.Quantity,
+		Status:   WaitlistWaiting,
+	}, email)
+	if err != nil {
+		return nil, err
+	}
+
+	offers, err := svc.offerWaitlist(ctx, tx, []uuid.UUID{params.TierID})
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := tx.getWaitlistEntry(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.writeOutbox(ctx, waitlistOfferEvents(offers)...); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return entry, nil
+}
+
+// LeaveWaitlist removes actor from a waitlist.
+//
+// Only waiting entries can be cancelled. Offers should be cancelled by cancelling offer reservation.
+func (svc Service) LeaveWaitlist(ctx context.Context, entryID uuid.UUID) error {
+	tx, err := svc.store.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	entry, err := tx.lockWaitlistEntry(ctx, entryID)
+	if err != nil {
+		return err
+	}
+
+	if entry.Status != WaitlistWaiting {
+		return fmt.Errorf("%w: waitlist entry is %s", ErrInvalidStatus, entry.Status)
+	}
+
+	if err := tx.setWaitlistStatus(ctx, entryID, WaitlistCancelled); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetWaitlistEntries returns all waitlist entries of an actor.
+func (svc Service) GetWaitlistEntries(ctx context.Context, actorID uuid.UUID) ([]*WaitlistEntry, error) {
+	return svc.store.getUserWaitlistEntries(ctx, actorID)
+}
+
+type waitlistHead struct {
+	ID             uuid.UUID `db:"id"`
+	EventID        uuid.UUID `db:"event_id"`
+	ActorID        uuid.UUID `db:"actor_id"`
+	Email          *string   `db:"contact_email"`
+	Quantity       uint      `db:"quantity"`
+	Currency       Currency  `db:"currency"`
+	HoldTTLSeconds int       `db:"hold_ttl_seconds"`
+}
+
+// offerPaidTiers offers free tickets of waitlisted tiers of a paid reservation.
+func (svc Service) offerPaidTiers(ctx context.Context, tx storeTx, reservationID uuid.UUID) ([]WaitlistOffer, error) {
+	tierIDs, err := tx.getWaitingTiers(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.offerWaitlist(ctx, tx, tierIDs)
+}
+
+// offerWaitlist offers free tickets of passed tiers to waiting actors in FIFO order.
+//
+// Each offer is a pending reservation which exclusively holds tickets for actor for event hold TTL.
+// Queue head is not skipped if there is not enough free tickets for it, to keep FIFO order fair.
+// Entries which request more tickets than there are unsold are skipped but keep their place in a queue.
+func (svc Service) offerWaitlist(ctx context.Context, tx storeTx, tierIDs []uuid.UUID) ([]WaitlistOffer, error) {
+	if len(tierIDs) == 0 {
+		return nil, nil
+	}
+
+	tiers, err := tx.getTierPricing(ctx, tierIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var offers []WaitlistOffer
+	now := time.Now()
+	for tierID, tier := range tiers {
+		for {
+			offer, err := offerWaitlistHead(ctx, tx, tier, now)
+			if err != nil {
+				return nil, fmt.Errorf("failed to offer tickets of tier %q: %w", tierID, err)
+			}
+
+			if offer == nil {
+				break
+			}
+
+			offers = append(offers, *offer)
+		}
+	}
+
+	return offers, nil
+}
+
+// offerWaitlistHead creates offer for the first actor in a tier queue which can be satisfied by unsold tickets.
+//
+// Returns nil if queue is empty or there is not enough free tickets.
+func offerWaitlistHead(ctx context.Context, tx storeTx, tier *tierPricing, now time.Time) (*WaitlistOffer, error) {
+	// Queue head is locked to serialize concurrent offers and preserve FIFO order.
+	head, err := tx.lockWaitlistHead(ctx, tier.TierID, tier.Capacity-tier.Sold)
+	if err != nil || head == nil {
+		return nil, err
+	}
+
+	// Savepoint to discard offer reservation if there is not enough tickets.
+	sp, err := tx.savepoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer sp.Rollback(ctx)
+
+	reservationID := uuid.New()
+	expireAt := now.Add(time.Duration(head.HoldTTLSeconds) * time.Second)
+	_, err = sp.insertReservation(ctx, &newReservation{
+		reservationRef: reservationRef{
+			ID:        reservationID,
+			EventID:   head.EventID,
+			ActorID:   head.ActorID,
+			Email:     head.Email,
+			ExpiresAt: expireAt,
+		},
+		Currency: head.Currency,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	unitPrice, ok, err := holdTierTickets(ctx, sp, tier, reservationID, head.Quantity, expireAt, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, nil
+	}
+
+	if err := sp.setReservationTotal(ctx, reservationID, head.Quantity*unitPrice); err != nil {
+		return nil, err
+	}
+
+	if err := sp.setWaitlistOffer(ctx, head.ID, reservationID); err != nil {
+		return nil, err
+	}
+
+	if err := sp.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to release savepoint: %w", err)
+	}
+
+	return &WaitlistOffer{
+		EntryID:       head.ID,
+		ActorID:       head.ActorID,
+		Email:         head.Email,
+		EventID:       head.EventID,
+		TierID:        tier.TierID,
+		ReservationID: reservationID,
+		Quantity:      head.Quantity,
+		ExpiresAt:     expireAt,
+	}, nil
+}
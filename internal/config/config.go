@@ -4,6 +4,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
@@ -13,11 +14,106 @@ type HTTPConfig struct {
 	ListenAddress string `envconfig:"ADDR" default:":8000"`
 }
 
+// FXConfig is currency exchange config.
+type FXConfig struct {
+	// RatesFile is optional path to JSON exchange rates file.
+	//
+	// If empty, rates are read from "exchange_rates" table.
+	RatesFile string `envconfig:"RATES_FILE"`
+}
+
+// TicketsConfig is ticket issuance config.
+type TicketsConfig struct {
+	// SigningKey is secret key used to sign ticket codes. Should be at least 32 bytes long.
+	//
+	// The same key should be provisioned to all instances and door scanners to verify codes offline.
+	// Server refuses to start without it.
+	SigningKey string `envconfig:"SIGNING_KEY"`
+}
+
+// SMTPConfig is mail server config used for customer notifications.
+type SMTPConfig struct {
+	// Addr is SMTP server address in host:port format.
+	//
+	// If empty, notifications are written to log.
+	Addr     string `envconfig:"ADDR"`
+	Username string `envconfig:"USERNAME"`
+	Password string `envconfig:"PASSWORD"`
+	From     string `envconfig:"FROM" default:"Tickets <tickets@localhost>"`
+
+	// MaxAttempts is number of failed attempts after which email is dead-lettered.
+	MaxAttempts int `envconfig:"MAX_ATTEMPTS" default:"8"`
+
+	// RetryBase is delay before the first retry, doubled on each next retry up to RetryMax.
+	RetryBase time.Duration `envconfig:"RETRY_BASE" default:"10s"`
+	RetryMax  time.Duration `envconfig:"RETRY_MAX" default:"1h"`
+}
+
+// OutboxConfig is domain events relay config.
+type OutboxConfig struct {
+	// Sink is where events are published: "log" or "redis".
+	Sink string `envconfig:"SINK" default:"log"`
+
+	// Stream is Redis stream name used by "redis" sink.
+	Stream string `envconfig:"STREAM" default:"booking:events"`
+
+	// StreamMaxLen is approximate max length of Redis stream. Zero disables trimming.
+	StreamMaxLen int64 `envconfig:"STREAM_MAX_LEN" default:"100000"`
+}
+
+// WebhooksConfig is partner webhooks delivery config.
+type WebhooksConfig struct {
+	// MaxAttempts is number of failed attempts after which delivery is dead-lettered.
+	MaxAttempts int `envconfig:"MAX_ATTEMPTS" default:"8"`
+
+	// RetryBase is delay before the first retry, doubled on each next retry up to RetryMax.
+	RetryBase time.Duration `envconfig:"RETRY_BASE" default:"10s"`
+	RetryMax  time.Duration `envconfig:"RETRY_MAX" default:"1h"`
+
+	// Timeout is partner endpoint request timeout.
+	Timeout time.Duration `envconfig:"TIMEOUT" default:"10s"`
+
+	// AllowPrivateTargets allows endpoints on loopback, private and link-local addresses.
+	// Should be enabled only for development.
+	AllowPrivateTargets bool `envconfig:"ALLOW_PRIVATE_TARGETS"`
+}
+
+// ResaleConfig is fan-to-fan resale marketplace config.
+type ResaleConfig struct {
+	// PriceCapBps is max resale price in basis points of ticket face value.
+	PriceCapBps uint `envconfig:"PRICE_CAP_BPS" default:"11000"`
+
+	// FeeBps is marketplace fee in basis points withheld from seller payout.
+	FeeBps uint `envconfig:"FEE_BPS" default:"1000"`
+}
+
+// AdminConfig is admin API config.
+type AdminConfig struct {
+	// Token is a bearer token required by admin API.
+	//
+	// If empty, admin API is disabled.
+	Token string `envconfig:"TOKEN"`
+}
+
+// ReportingConfig is organizer reporting config.
+type ReportingConfig struct {
+	// FeeBps is platform fee in basis points withheld from organizer sales.
+	FeeBps uint `envconfig:"FEE_BPS" default:"0"`
+}
+
 type Config struct {
-	DB    DBConfig    `envconfig:"DB"`
-	Redis RedisConfig `envconfig:"REDIS"`
-	Log   LogConfig   `envconfig:"LOG"`
-	HTTP  HTTPConfig  `envconfig:"ADDR"`
+	DB        DBConfig        `envconfig:"DB"`
+	Redis     RedisConfig     `envconfig:"REDIS"`
+	Log       LogConfig       `envconfig:"LOG"`
+	HTTP      HTTPConfig      `envconfig:"ADDR"`
+	FX        FXConfig        `envconfig:"FX"`
+	Tickets   TicketsConfig   `envconfig:"TICKETS"`
+	SMTP      SMTPConfig      `envconfig:"SMTP"`
+	Outbox    OutboxConfig    `envconfig:"OUTBOX"`
+	Webhooks  WebhooksConfig  `envconfig:"WEBHOOKS"`
+	Resale    ResaleConfig    `envconfig:"RESALE"`
+	Reporting ReportingConfig `envconfig:"REPORTING"`
+	Admin     AdminConfig     `envconfig:"ADMIN"`
 }
 
 // LoadEnvFile populates environment variables from env file (if specified in a flag).
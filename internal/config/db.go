@@ -13,6 +13,11 @@ import (
 
 type DBConfig struct {
 	URL string `required:"true"`
+
+	// Migrate applies pending migrations on server start.
+	//
+	// Otherwise, server refuses to start if database schema version doesn't match migrations.
+	Migrate bool `envconfig:"MIGRATE" default:"false"`
 }
 
 func (cfg DBConfig) NewPgxPool(ctx context.Context) (*pgxpool.Pool, error) {
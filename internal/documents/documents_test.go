@@ -0,0 +1,67 @@
+package documents
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+func testReservation() *booking.ReservationMeta {
+	return &booking.ReservationMeta{
+		ID:         uuid.New(),
+		EventName:  "Café Concert – Ünïcode",
+		Status:     booking.ReservationPaid,
+		TotalCents: 120_00,
+		Currency:   "EUR",
+		Items: []*booking.ReservationItem{
+			{TierID: uuid.New(), TierName: "VIP", Quantity: 1, UnitPriceCents: 100_00},
+			{TierID: uuid.New(), TierName: "GA", Quantity: 2, UnitPriceCents: 10_00},
+		},
+	}
+}
+
+func TestRenderTickets(t *testing.T) {
+	meta := testReservation()
+	tickets := []*booking.IssuedTicket{
+		{TicketID: uuid.New(), TierID: meta.Items[0].TierID, TierName: "VIP", Code: "AEQWERTY234567", IssuedAt: time.Now()},
+		{TicketID: uuid.New(), TierID: meta.Items[1].TierID, TierName: "GA", Code: "AEZXCVBN234567", IssuedAt: time.Now()},
+	}
+
+	pdf, err := RenderTickets(meta, tickets)
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(pdf, []byte("%PDF-")))
+
+	_, err = RenderTickets(meta, nil)
+	require.Error(t, err)
+}
+
+func TestRenderReceipt(t *testing.T) {
+	settlement := booking.NewMoney(120_00, "EUR")
+	receipt := &booking.Receipt{
+		Reservation: testReservation(),
+		Payment: booking.PaymentRecord{
+			TxID:         uuid.New(),
+			PaidAt:       time.Date(2025, 10, 30, 12, 0, 0, 0, time.UTC),
+			Settlement:   settlement,
+			Presentment:  booking.NewMoney(19500, "JPY"),
+			ExchangeRate: "162.5",
+		},
+		TaxRateBps: 2000,
+		Tax:        settlement.IncludedTax(2000),
+	}
+
+	pdf, err := RenderReceipt(receipt)
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(pdf, []byte("%PDF-")))
+}
+
+func TestFormatBps(t *testing.T) {
+	require.Equal(t, "20", formatBps(2000))
+	require.Equal(t, "20.5", formatBps(2050))
+	require.Equal(t, "0", formatBps(0))
+}
@@ -0,0 +1,79 @@
+// Package documents renders printable customer documents - e-tickets and receipts.
+//
+// Documents are rendered as PDF using pure Go implementation, without any external tools.
+package documents
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	pageFormat = "A4"
+	fontFamily = "Helvetica"
+
+	// dateFormat is a date format used in documents.
+	dateFormat = "2006-01-02 15:04 MST"
+)
+
+// document is a thin wrapper around PDF builder with shared page style.
+type document struct {
+	pdf *gofpdf.Fpdf
+
+	// tr converts UTF-8 text to core font encoding (cp1252).
+	//
+	// Characters which are not supported by the encoding are replaced.
+	tr func(string) string
+}
+
+func newDocument(title string, createdAt time.Time) *document {
+	pdf := gofpdf.New("P", "mm", pageFormat, "")
+	pdf.SetTitle(title, true)
+	pdf.SetCreator("thoughtly-ticket-booking", true)
+
+	// Document date is used instead of render time, as documents are re-rendered on each download.
+	pdf.SetCreationDate(createdAt)
+	pdf.SetModificationDate(createdAt)
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(true, 20)
+
+	return &document{
+		pdf: pdf,
+		tr:  pdf.UnicodeTranslatorFromDescriptor(""),
+	}
+}
+
+func (d *document) heading(text string) {
+	d.pdf.SetFont(fontFamily, "B", 18)
+	d.pdf.CellFormat(0, 10, d.tr(text), "", 1, "L", false, 0, "")
+	d.pdf.Ln(2)
+}
+
+// field prints a label and a value on the same line.
+func (d *document) field(label, value string) {
+	d.pdf.SetFont(fontFamily, "B", 10)
+	d.pdf.CellFormat(40, 6, d.tr(label), "", 0, "L", false, 0, "")
+	d.pdf.SetFont(fontFamily, "", 10)
+	d.pdf.CellFormat(0, 6, d.tr(value), "", 1, "L", false, 0, "")
+}
+
+func (d *document) write(w io.Writer) error {
+	if err := d.pdf.Output(w); err != nil {
+		return fmt.Errorf("failed to render PDF: %w", err)
+	}
+
+	return nil
+}
+
+func (d *document) bytes() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := d.write(buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
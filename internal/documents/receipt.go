@@ -0,0 +1,90 @@
+package documents
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+// receiptColumns are widths of line items table columns in millimeters.
+var receiptColumns = [...]float64{80, 20, 35, 35}
+
+// RenderReceipt renders PDF receipt of a paid reservation with line items and included taxes.
+func RenderReceipt(r *booking.Receipt) ([]byte, error) {
+	meta := r.Reservation
+	settlement := r.Payment.Settlement
+
+	doc := newDocument(fmt.Sprintf("Receipt %s", meta.ID), r.Payment.PaidAt)
+	doc.pdf.AddPage()
+	doc.heading("Receipt")
+	if r.Payment.RefundedAt != nil {
+		doc.pdf.SetTextColor(200, 0, 0)
+		doc.field("REFUNDED", r.Payment.RefundedAt.UTC().Format(dateFormat))
+		doc.pdf.SetTextColor(0, 0, 0)
+	}
+
+	doc.field("Event", meta.EventName)
+	doc.field("Reservation", meta.ID.String())
+	doc.field("Transaction", r.Payment.TxID.String())
+	doc.field("Paid at", r.Payment.PaidAt.UTC().Format(dateFormat))
+	doc.pdf.Ln(6)
+
+	doc.tableRow(true, "Item", "Qty", "Unit price", "Amount")
+	for _, item := range meta.Items {
+		unitPrice := booking.NewMoney(int64(item.UnitPriceCents), settlement.Currency)
+		amount := booking.NewMoney(int64(item.UnitPriceCents*item.Quantity), settlement.Currency)
+		doc.tableRow(false, item.TierName, strconv.FormatUint(uint64(item.Quantity), 10), unitPrice.String(), amount.String())
+	}
+
+	doc.pdf.Ln(4)
+	net := booking.NewMoney(settlement.Amount-r.Tax.Amount, settlement.Currency)
+	doc.totalRow("Subtotal (excl. tax)", net.String())
+	doc.totalRow(fmt.Sprintf("Tax %s%% (included)", formatBps(r.TaxRateBps)), r.Tax.String())
+	doc.totalRow("Total", settlement.String())
+
+	if r.Payment.Presentment.Currency != settlement.Currency {
+		doc.totalRow("Charged", r.Payment.Presentment.String())
+		doc.pdf.SetFont(fontFamily, "I", 8)
+		doc.pdf.CellFormat(
+			0, 5, fmt.Sprintf("Exchange rate: 1 %s = %s %s", settlement.Currency, r.Payment.ExchangeRate, r.Payment.Presentment.Currency),
+			"", 1, "R", false, 0, "",
+		)
+	}
+
+	return doc.bytes()
+}
+
+func (d *document) tableRow(header bool, cols ...string) {
+	style := ""
+	border := ""
+	if header {
+		style = "B"
+		border = "B"
+	}
+
+	d.pdf.SetFont(fontFamily, style, 10)
+	for i, col := range cols {
+		align := "R"
+		if i == 0 {
+			align = "L"
+		}
+
+		d.pdf.CellFormat(receiptColumns[i], 7, d.tr(col), border, 0, align, false, 0, "")
+	}
+
+	d.pdf.Ln(-1)
+}
+
+func (d *document) totalRow(label, value string) {
+	labelWidth := receiptColumns[0] + receiptColumns[1] + receiptColumns[2]
+	d.pdf.SetFont(fontFamily, "B", 10)
+	d.pdf.CellFormat(labelWidth, 6, d.tr(label), "", 0, "R", false, 0, "")
+	d.pdf.SetFont(fontFamily, "", 10)
+	d.pdf.CellFormat(receiptColumns[3], 6, d.tr(value), "", 1, "R", false, 0, "")
+}
+
+// formatBps formats basis points as percent, e.g. 2050 -> "20.5".
+func formatBps(bps uint) string {
+	return strconv.FormatFloat(float64(bps)/100, 'f', -1, 64)
+}
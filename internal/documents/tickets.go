@@ -0,0 +1,80 @@
+package documents
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/ticketcode"
+)
+
+const (
+	// qrSize is QR code size on a page in millimeters.
+	qrSize = 70
+
+	// qrResolution is QR code image size in pixels, enough for 300 DPI print.
+	qrResolution = 840
+)
+
+// RenderTickets renders PDF with e-tickets of a reservation, one ticket per page.
+func RenderTickets(meta *booking.ReservationMeta, tickets []*booking.IssuedTicket) ([]byte, error) {
+	if len(tickets) == 0 {
+		return nil, errors.New("no tickets to render")
+	}
+
+	unitPrices := make(map[uuid.UUID]booking.Money, len(meta.Items))
+	for _, item := range meta.Items {
+		unitPrices[item.TierID] = booking.NewMoney(int64(item.UnitPriceCents), meta.Currency)
+	}
+
+	doc := newDocument(fmt.Sprintf("Tickets - %s", meta.EventName), tickets[0].IssuedAt)
+	for i, ticket := range tickets {
+		doc.pdf.AddPage()
+		doc.heading(meta.EventName)
+		doc.field("Ticket", fmt.Sprintf("%d of %d", i+1, len(tickets)))
+		doc.field("Tier", ticket.TierName)
+		if price, ok := unitPrices[ticket.TierID]; ok {
+			doc.field("Price paid", price.String())
+		}
+
+		doc.field("Ticket ID", ticket.TicketID.String())
+		doc.field("Reservation", meta.ID.String())
+		doc.field("Issued at", ticket.IssuedAt.UTC().Format(dateFormat))
+
+		if err := doc.qrCode(fmt.Sprintf("qr-%d", i), ticket.Code); err != nil {
+			return nil, err
+		}
+
+		doc.pdf.SetFont("Courier", "", 9)
+		doc.pdf.MultiCell(0, 4, ticket.Code, "", "C", false)
+		doc.pdf.Ln(4)
+		doc.pdf.SetFont(fontFamily, "I", 9)
+		doc.pdf.MultiCell(0, 5, "Present this QR code at the venue entrance. The ticket is valid for a single entry.", "", "C", false)
+	}
+
+	return doc.bytes()
+}
+
+// qrCode draws centered QR code of a ticket code.
+func (d *document) qrCode(name, code string) error {
+	png, err := ticketcode.RenderPNG(code, qrResolution)
+	if err != nil {
+		return err
+	}
+
+	d.pdf.RegisterImageOptionsReader(name, gofpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(png))
+	if err := d.pdf.Error(); err != nil {
+		return fmt.Errorf("failed to embed QR code: %w", err)
+	}
+
+	pageWidth, _ := d.pdf.GetPageSize()
+	d.pdf.Ln(8)
+	y := d.pdf.GetY()
+	d.pdf.ImageOptions(name, (pageWidth-qrSize)/2, y, qrSize, qrSize, false, gofpdf.ImageOptions{}, 0, "")
+	d.pdf.SetY(y + qrSize + 4)
+	return nil
+}
@@ -0,0 +1,274 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/pricing"
+)
+
+// MaxTierTickets is max number of tickets in an imported tier.
+const MaxTierTickets = 100_000
+
+// EventService is a subset of booking service used to import events.
+type EventService interface {
+	GetEvents(ctx context.Context) ([]*booking.Event, error)
+
+	// CreateEvent creates an event with tiers in a single transaction.
+	CreateEvent(ctx context.Context, opts booking.EventCreateParams) (*booking.EventCreateResult, error)
+}
+
+// Result is import summary.
+type Result struct {
+	DryRun bool `json:"dryRun"`
+
+	// Created is number of created events. Zero in dry-run mode.
+	Created int `json:"created"`
+
+	// Failed is number of events which failed to be created.
+	Failed int `json:"failed"`
+
+	// Existing is number of events which already exist and are left as is.
+	Existing int `json:"existing"`
+
+	Events []*EventResult `json:"events"`
+}
+
+// EventResult is an imported event.
+type EventResult struct {
+	Row     int        `json:"row"`
+	Name    string     `json:"name"`
+	EventID *uuid.UUID `json:"eventID,omitempty"`
+
+	// Exists is true if event with the same name already exists. Existing events are not updated.
+	Exists bool `json:"exists,omitempty"`
+
+	Tickets int           `json:"tickets"`
+	Tiers   []*TierResult `json:"tiers"`
+	Error   string        `json:"error,omitempty"`
+	params  booking.EventCreateParams
+}
+
+// TierResult is an imported tier.
+type TierResult struct {
+	Row          int        `json:"row"`
+	Name         string     `json:"name"`
+	PriceCents   int        `json:"priceCents"`
+	TicketsCount int        `json:"ticketsCount"`
+	PricingRules int        `json:"pricingRules"`
+	TierID       *uuid.UUID `json:"tierID,omitempty"`
+}
+
+// Importer creates events from manifests.
+type Importer struct {
+	events EventService
+}
+
+func New(events EventService) *Importer {
+	return &Importer{
+		events: events,
+	}
+}
+
+// Import validates the whole manifest and creates its events.
+//
+// Nothing is created if manifest has invalid rows, in that case ValidationError with all invalid rows is returned.
+// Each event is created in its own transaction, so failure of one event doesn't affect others
+// and is reported in event result.
+//
+// Import is idempotent: events are matched by name and existing events are reported and left as is,
+// so re-running the same manifest doesn't create duplicates.
+//
+// In dry-run mode manifest is only validated and result shows what would be created.
+func (imp *Importer) Import(ctx context.Context, m *Manifest, dryRun bool) (*Result, error) {
+	events, err := Validate(m)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := imp.events.GetEvents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	eventIDs := make(map[string]uuid.UUID, len(existing))
+	for _, e := range existing {
+		eventIDs[e.Name] = e.ID
+	}
+
+	result := &Result{
+		DryRun: dryRun,
+		Events: events,
+	}
+	for _, e := range events {
+		if eventID, ok := eventIDs[e.Name]; ok {
+			e.EventID = &eventID
+			e.Exists = true
+			result.Existing++
+		}
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	for _, e := range events {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		if e.Exists {
+			continue
+		}
+
+		created, err := imp.events.CreateEvent(ctx, e.params)
+		if err != nil {
+			e.Error = err.Error()
+			result.Failed++
+			continue
+		}
+
+		e.EventID = &created.EventID
+		for _, t := range e.Tiers {
+			tierID := created.Tiers[t.Name]
+			t.TierID = &tierID
+		}
+
+		result.Created++
+	}
+
+	return result, nil
+}
+
+// Validate checks all manifest rows and returns events to create.
+//
+// Returns ValidationError with all invalid rows.
+func Validate(m *Manifest) ([]*EventResult, error) {
+	var errs []*RowError
+	fail := func(row int, event, tier, format string, args ...any) {
+		errs = append(errs, &RowError{
+			Row:     row,
+			Event:   event,
+			Tier:    tier,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	if len(m.Events) == 0 {
+		return nil, &ValidationError{
+			Rows: []*RowError{{Message: "manifest has no events"}},
+		}
+	}
+
+	results := make([]*EventResult, 0, len(m.Events))
+	eventRows := make(map[string]int, len(m.Events))
+	for _, e := range m.Events {
+		name := strings.TrimSpace(e.Name)
+		if name == "" {
+			fail(e.Row, "", "", "event name is required")
+		} else if row, ok := eventRows[name]; ok {
+			fail(e.Row, name, "", "duplicate event, first defined in row %d", row)
+		} else {
+			eventRows[name] = e.Row
+		}
+
+		params := booking.EventCreateParams{
+			EventName:        name,
+			Currency:         booking.Currency(e.Currency),
+			TaxRateBps:       e.TaxRateBps,
+			TransfersBlocked: e.TransfersBlocked,
+			Tiers:            make(map[string]booking.CreateTierParams, len(e.Tiers)),
+		}
+
+		if e.OrganizerID != "" {
+			organizerID, err := uuid.Parse(e.OrganizerID)
+			if err != nil {
+				fail(e.Row, name, "", "invalid organizer ID %q", e.OrganizerID)
+			} else {
+				params.OrganizerID = &organizerID
+			}
+		}
+
+		if len(e.Tiers) == 0 {
+			fail(e.Row, name, "", "event has no tiers")
+		}
+
+		result := &EventResult{
+			Row:   e.Row,
+			Name:  name,
+			Tiers: make([]*TierResult, 0, len(e.Tiers)),
+		}
+
+		tierRows := make(map[string]int, len(e.Tiers))
+		for _, t := range e.Tiers {
+			tierName := strings.TrimSpace(t.Name)
+			switch {
+			case tierName == "":
+				fail(t.Row, name, "", "tier name is required")
+			case tierRows[tierName] != 0:
+				fail(t.Row, name, tierName, "duplicate tier, first defined in row %d", tierRows[tierName])
+			default:
+				tierRows[tierName] = t.Row
+			}
+
+			if t.PriceCents < 0 {
+				fail(t.Row, name, tierName, "price should not be negative")
+			}
+
+			if t.TicketsCount <= 0 || t.TicketsCount > MaxTierTickets {
+				fail(t.Row, name, tierName, "tickets count should be between 1 and %d", MaxTierTickets)
+			}
+
+			limits := pricing.Limits{MinPriceCents: t.MinPriceCents, MaxPriceCents: t.MaxPriceCents}
+			if err := limits.Validate(); err != nil {
+				fail(t.Row, name, tierName, "%s", err)
+			}
+
+			rules := make([]pricing.Rule, 0, len(t.PricingRules))
+			for i, r := range t.PricingRules {
+				rule := r.Rule()
+				if err := rule.Validate(); err != nil {
+					fail(t.Row, name, tierName, "pricing rule %d: %s", i+1, err)
+				}
+
+				rules = append(rules, rule)
+			}
+
+			params.Tiers[tierName] = booking.CreateTierParams{
+				PriceCents:   t.PriceCents,
+				TicketsCount: t.TicketsCount,
+				Limits:       limits,
+				PricingRules: rules,
+			}
+
+			result.Tickets += t.TicketsCount
+			result.Tiers = append(result.Tiers, &TierResult{
+				Row:          t.Row,
+				Name:         tierName,
+				PriceCents:   t.PriceCents,
+				TicketsCount: t.TicketsCount,
+				PricingRules: len(rules),
+			})
+		}
+
+		// Tiers are already checked above, so only event params like currency and tax rate are left.
+		eventParams := params
+		eventParams.Tiers = nil
+		if err := eventParams.Validate(); err != nil {
+			fail(e.Row, name, "", "%s", err)
+		}
+
+		result.params = params
+		results = append(results, result)
+	}
+
+	if len(errs) > 0 {
+		return nil, &ValidationError{Rows: errs}
+	}
+
+	return results, nil
+}
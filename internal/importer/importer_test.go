@@ -0,0 +1,240 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/pricing"
+)
+
+type fakeCreator struct {
+	existing []*booking.Event
+	created  []booking.EventCreateParams
+	fail     string
+}
+
+func (f *fakeCreator) GetEvents(context.Context) ([]*booking.Event, error) {
+	return f.existing, nil
+}
+
+func (f *fakeCreator) CreateEvent(_ context.Context, opts booking.EventCreateParams) (*booking.EventCreateResult, error) {
+	if opts.EventName == f.fail {
+		return nil, errors.New("connection reset")
+	}
+
+	f.created = append(f.created, opts)
+	result := &booking.EventCreateResult{EventID: uuid.New(), Tiers: map[string]uuid.UUID{}}
+	for name := range opts.Tiers {
+		result.Tiers[name] = uuid.New()
+	}
+
+	return result, nil
+}
+
+func TestParseExamples(t *testing.T) {
+	var manifests []*Manifest
+	for _, path := range []string{"../../docs/examples/import.yaml", "../../docs/examples/import.csv"} {
+		format, err := FormatFromPath(path)
+		require.NoError(t, err)
+
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = f.Close() })
+
+		m, err := Parse(f, format)
+		require.NoError(t, err, path)
+		manifests = append(manifests, m)
+	}
+
+	for _, m := range manifests {
+		events, err := Validate(m)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		require.Equal(t, "Summer Festival 2026", events[0].Name)
+		require.Equal(t, 5400, events[0].Tickets)
+		require.Equal(t, booking.Currency("EUR"), events[0].params.Currency)
+		require.Equal(t, uint(2000), events[0].params.TaxRateBps)
+		require.Equal(t, 9000, *events[0].params.Tiers["GA"].MaxPriceCents)
+		require.Len(t, events[0].params.Tiers["GA"].PricingRules, 2)
+		require.Equal(t, pricing.RuleTimeWindow, events[0].params.Tiers["GA"].PricingRules[0].Kind)
+		require.Nil(t, events[0].params.Tiers["GA"].PricingRules[0].ActiveFrom)
+		require.Equal(t, 80, *events[0].params.Tiers["GA"].PricingRules[1].SoldPercent)
+		require.NotNil(t, events[1].params.OrganizerID)
+	}
+
+	// Both formats describe the same events, only row numbers differ.
+	yamlEvents, _ := Validate(manifests[0])
+	csvEvents, _ := Validate(manifests[1])
+	for i := range yamlEvents {
+		require.Equal(t, yamlEvents[i].params, csvEvents[i].params)
+	}
+}
+
+func TestValidateReportsAllRows(t *testing.T) {
+	manifest := `event,currency,tier,price_cents,tickets_count,min_price_cents,max_price_cents
+Show,USD,VIP,10000,10,,
+Show,,GA,5000,10,,
+Show,,GA,5000,0,,
+Other,XXXX,GA,-1,10,200,100
+,,GA,100,1,,
+`
+	m, err := Parse(strings.NewReader(manifest), FormatCSV)
+	require.NoError(t, err)
+
+	_, err = New(&fakeCreator{}).Import(context.Background(), m, false)
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	require.ErrorIs(t, err, booking.ErrInvalidParams)
+
+	rows := make([]int, 0, len(verr.Rows))
+	for _, r := range verr.Rows {
+		rows = append(rows, r.Row)
+	}
+
+	require.Equal(t, []int{4, 4, 5, 5, 5, 6}, rows, verr.Rows)
+	require.Contains(t, verr.Rows[0].Message, "duplicate tier")
+	require.Equal(t, "GA", verr.Rows[0].Tier)
+	require.Contains(t, verr.Rows[5].Message, "event name is required")
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	_, err := Parse(strings.NewReader("event,tier,price_cents\n"), FormatCSV)
+	require.ErrorIs(t, err, booking.ErrInvalidParams)
+	require.Contains(t, err.Error(), `missing CSV column "tickets_count"`)
+
+	manifest := `event,currency,transfers_blocked,tier,price_cents,tickets_count
+Show,USD,no,VIP,ten,10
+Show,EUR,,GA,100,10
+`
+	_, err = Parse(strings.NewReader(manifest), FormatCSV)
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	require.Len(t, verr.Rows, 3)
+	require.Equal(t, 2, verr.Rows[0].Row)
+	require.Equal(t, 3, verr.Rows[2].Row)
+	require.Contains(t, verr.Rows[2].Message, "doesn't match")
+}
+
+func TestParseCSVPricingRules(t *testing.T) {
+	manifest := `event,tier,price_cents,tickets_count,pricing_rules
+Show,VIP,10000,10,sold_percent 50 +2000;time_window 2026-01-01T00:00:00Z.. -1000
+Show,GA,5000,10,sold_percent half 1000
+Show,Balcony,5000,10,discount 10 1000; time_window 2026-01-01 -1000
+Other,GA,5000,10,sold_percent 150 1000
+`
+	_, err := Parse(strings.NewReader(manifest), FormatCSV)
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	require.Len(t, verr.Rows, 3)
+	require.Contains(t, verr.Rows[0].Message, `invalid sold percent "half"`)
+	require.Contains(t, verr.Rows[1].Message, `rule 1: unknown rule kind "discount"`)
+	require.Contains(t, verr.Rows[2].Message, `rule 2: invalid time window`)
+
+	m, err := Parse(strings.NewReader(strings.Join(strings.Split(manifest, "\n")[:2], "\n")), FormatCSV)
+	require.NoError(t, err)
+	rules := m.Events[0].Tiers[0].PricingRules
+	require.Len(t, rules, 2)
+	require.Equal(t, 50, *rules[0].SoldPercent)
+	require.Equal(t, 2000, rules[0].AdjustmentBps)
+	require.Equal(t, "2026-01-01T00:00:00Z", rules[1].ActiveFrom.Format(time.RFC3339))
+	require.Nil(t, rules[1].ActiveUntil)
+
+	// Rule parameters are validated with the rest of manifest
+	m, err = Parse(strings.NewReader("event,tier,price_cents,tickets_count,pricing_rules\n"+
+		"Other,GA,5000,10,sold_percent 150 1000\n"), FormatCSV)
+	require.NoError(t, err)
+	_, err = Validate(m)
+	require.ErrorAs(t, err, &verr)
+	require.Len(t, verr.Rows, 1)
+	require.Contains(t, verr.Rows[0].Message, "pricing rule 1")
+}
+
+func TestImport(t *testing.T) {
+	manifest := `
+events:
+  - name: First
+    tiers:
+      - name: GA
+        priceCents: 1000
+        ticketsCount: 10
+  - name: Broken
+    tiers:
+      - name: GA
+        priceCents: 1000
+        ticketsCount: 10
+  - name: Last
+    tiers:
+      - name: GA
+        priceCents: 1000
+        ticketsCount: 10
+`
+	m, err := Parse(strings.NewReader(manifest), FormatYAML)
+	require.NoError(t, err)
+	require.Equal(t, 3, m.Events[0].Row)
+	require.Equal(t, 5, m.Events[0].Tiers[0].Row)
+
+	creator := &fakeCreator{fail: "Broken"}
+	result, err := New(creator).Import(context.Background(), m, true)
+	require.NoError(t, err)
+	require.True(t, result.DryRun)
+	require.Zero(t, result.Created)
+	require.Empty(t, creator.created)
+	require.Nil(t, result.Events[0].EventID)
+
+	result, err = New(creator).Import(context.Background(), m, false)
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Created)
+	require.Equal(t, 1, result.Failed)
+	require.Len(t, creator.created, 2)
+	require.NotNil(t, result.Events[0].EventID)
+	require.NotNil(t, result.Events[0].Tiers[0].TierID)
+	require.Equal(t, "connection reset", result.Events[1].Error)
+	require.NotNil(t, result.Events[2].EventID)
+}
+
+func TestImportExistingEvents(t *testing.T) {
+	manifest := `
+events:
+  - name: First
+    tiers:
+      - name: GA
+        priceCents: 1000
+        ticketsCount: 10
+  - name: Second
+    tiers:
+      - name: GA
+        priceCents: 1000
+        ticketsCount: 10
+`
+	m, err := Parse(strings.NewReader(manifest), FormatYAML)
+	require.NoError(t, err)
+
+	existingID := uuid.New()
+	creator := &fakeCreator{existing: []*booking.Event{{ID: existingID, Name: "First"}}}
+
+	// Dry run reports existing events too
+	result, err := New(creator).Import(context.Background(), m, true)
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Existing)
+	require.True(t, result.Events[0].Exists)
+	require.Equal(t, existingID, *result.Events[0].EventID)
+	require.False(t, result.Events[1].Exists)
+	require.Nil(t, result.Events[1].EventID)
+
+	result, err = New(creator).Import(context.Background(), m, false)
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Created)
+	require.Equal(t, 1, result.Existing)
+	require.Len(t, creator.created, 1)
+	require.Equal(t, "Second", creator.created[0].EventName)
+	require.Equal(t, existingID, *result.Events[0].EventID)
+	require.Nil(t, result.Events[0].Tiers[0].TierID)
+}
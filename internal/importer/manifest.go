@@ -0,0 +1,471 @@
+// Package importer creates events and ticket inventory in bulk from CSV or YAML manifests.
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/pricing"
+)
+
+// Format is manifest file format.
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatYAML Format = "yaml"
+)
+
+// ParseFormat parses format name or MIME type.
+func ParseFormat(s string) (Format, error) {
+	mediaType, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(s)), ";")
+	switch strings.TrimSpace(mediaType) {
+	case "csv", "text/csv":
+		return FormatCSV, nil
+	case "yaml", "yml", "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return FormatYAML, nil
+	default:
+		return "", fmt.Errorf("%w: unsupported manifest format %q", booking.ErrInvalidParams, s)
+	}
+}
+
+// FormatFromPath detects manifest format by file extension.
+func FormatFromPath(path string) (Format, error) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+// Manifest is a list of events to import.
+type Manifest struct {
+	Events []*EventRow `yaml:"events"`
+}
+
+// EventRow is an event in manifest.
+type EventRow struct {
+	// Row is line number of event in manifest. For CSV it's a line of the first event tier.
+	Row int `yaml:"-"`
+
+	Name             string `yaml:"name"`
+	Currency         string `yaml:"currency"`
+	TaxRateBps       uint   `yaml:"taxRateBps"`
+	OrganizerID      string `yaml:"organizerID"`
+	TransfersBlocked bool   `yaml:"transfersBlocked"`
+
+	Tiers []*TierRow `yaml:"tiers"`
+}
+
+func (e *EventRow) UnmarshalYAML(value *yaml.Node) error {
+	type plain EventRow
+	if err := value.Decode((*plain)(e)); err != nil {
+		return err
+	}
+
+	e.Row = value.Line
+	return nil
+}
+
+// TierRow is a ticket tier in manifest.
+//
+// Seats are not modelled, so tier is a general admission pool of tickets.
+type TierRow struct {
+	// Row is line number of tier in manifest.
+	Row int `yaml:"-"`
+
+	Name          string `yaml:"name"`
+	PriceCents    int    `yaml:"priceCents"`
+	TicketsCount  int    `yaml:"ticketsCount"`
+	MinPriceCents *int   `yaml:"minPriceCents"`
+	MaxPriceCents *int   `yaml:"maxPriceCents"`
+
+	PricingRules []*RuleRow `yaml:"pricingRules"`
+}
+
+func (t *TierRow) UnmarshalYAML(value *yaml.Node) error {
+	type plain TierRow
+	if err := value.Decode((*plain)(t)); err != nil {
+		return err
+	}
+
+	t.Row = value.Line
+	return nil
+}
+
+// RuleRow is a tier dynamic pricing rule in manifest.
+type RuleRow struct {
+	// Kind is rule kind: sold_percent or time_window.
+	Kind string `yaml:"kind"`
+
+	AdjustmentBps int        `yaml:"adjustmentBps"`
+	SoldPercent   *int       `yaml:"soldPercent"`
+	ActiveFrom    *time.Time `yaml:"activeFrom"`
+	ActiveUntil   *time.Time `yaml:"activeUntil"`
+}
+
+// Rule returns pricing rule.
+func (r *RuleRow) Rule() pricing.Rule {
+	return pricing.Rule{
+		Kind:          pricing.RuleKind(r.Kind),
+		AdjustmentBps: r.AdjustmentBps,
+		SoldPercent:   r.SoldPercent,
+		ActiveFrom:    r.ActiveFrom,
+		ActiveUntil:   r.ActiveUntil,
+	}
+}
+
+// RowError is a validation error of a manifest row.
+type RowError struct {
+	// Row is line number in manifest.
+	Row     int    `json:"row"`
+	Event   string `json:"event,omitempty"`
+	Tier    string `json:"tier,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e *RowError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "row %d", e.Row)
+	if e.Event != "" {
+		fmt.Fprintf(&sb, ", event %q", e.Event)
+	}
+
+	if e.Tier != "" {
+		fmt.Fprintf(&sb, ", tier %q", e.Tier)
+	}
+
+	sb.WriteString(": ")
+	sb.WriteString(e.Message)
+	return sb.String()
+}
+
+// ValidationError is returned when manifest has invalid rows.
+type ValidationError struct {
+	Rows []*RowError
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("manifest has %d errors, first: %s", len(e.Rows), e.Rows[0])
+}
+
+func (e *ValidationError) Unwrap() error {
+	return booking.ErrInvalidParams
+}
+
+// Parse reads manifest in a specified format.
+//
+// Malformed CSV values are reported as ValidationError with all invalid rows.
+func Parse(r io.Reader, format Format) (*Manifest, error) {
+	switch format {
+	case FormatYAML:
+		return parseYAML(r)
+	case FormatCSV:
+		return parseCSV(r)
+	default:
+		return nil, fmt.Errorf("%w: unsupported manifest format %q", booking.ErrInvalidParams, format)
+	}
+}
+
+func parseYAML(r io.Reader) (*Manifest, error) {
+	m := &Manifest{}
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(m); err != nil {
+		if errors.Is(err, io.EOF) {
+			return m, nil
+		}
+
+		return nil, fmt.Errorf("%w: invalid YAML manifest: %s", booking.ErrInvalidParams, err)
+	}
+
+	return m, nil
+}
+
+// CSV manifest columns. Each row is a tier, rows with the same event name form an event.
+//
+// Event columns can be omitted in all but the first event row.
+//
+// Pricing rules column is a list of rules separated by semicolon, each rule is one of:
+//
+//	sold_percent <percent> <adjustment bps>
+//	time_window <from>..<until> <adjustment bps>
+//
+// Window bounds are RFC 3339 timestamps, either of them can be empty for open window.
+const (
+	colEvent            = "event"
+	colCurrency         = "currency"
+	colTaxRateBps       = "tax_rate_bps"
+	colOrganizerID      = "organizer_id"
+	colTransfersBlocked = "transfers_blocked"
+	colTier             = "tier"
+	colPriceCents       = "price_cents"
+	colTicketsCount     = "tickets_count"
+	colMinPriceCents    = "min_price_cents"
+	colMaxPriceCents    = "max_price_cents"
+	colPricingRules     = "pricing_rules"
+)
+
+var (
+	requiredColumns = []string{colEvent, colTier, colPriceCents, colTicketsCount}
+	knownColumns    = []string{
+		colEvent, colCurrency, colTaxRateBps, colOrganizerID, colTransfersBlocked,
+		colTier, colPriceCents, colTicketsCount, colMinPriceCents, colMaxPriceCents, colPricingRules,
+	}
+)
+
+// csvRow is a CSV record with column lookup by name.
+type csvRow struct {
+	line    int
+	columns map[string]int
+	record  []string
+	errs    []*RowError
+}
+
+func (r *csvRow) get(col string) string {
+	i, ok := r.columns[col]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+
+	return strings.TrimSpace(r.record[i])
+}
+
+func (r *csvRow) fail(format string, args ...any) {
+	r.errs = append(r.errs, &RowError{
+		Row:     r.line,
+		Event:   r.get(colEvent),
+		Tier:    r.get(colTier),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (r *csvRow) int(col string) int {
+	v := r.get(col)
+	if v == "" {
+		return 0
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		r.fail("%s: invalid integer %q", col, v)
+	}
+
+	return n
+}
+
+func (r *csvRow) uint(col string) uint {
+	v := r.get(col)
+	if v == "" {
+		return 0
+	}
+
+	n, err := strconv.ParseUint(v, 10, 32)
+	if err != nil {
+		r.fail("%s: invalid non-negative integer %q", col, v)
+	}
+
+	return uint(n)
+}
+
+func (r *csvRow) optionalInt(col string) *int {
+	if r.get(col) == "" {
+		return nil
+	}
+
+	n := r.int(col)
+	return &n
+}
+
+func (r *csvRow) bool(col string) bool {
+	v := r.get(col)
+	if v == "" {
+		return false
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		r.fail("%s: invalid boolean %q", col, v)
+	}
+
+	return b
+}
+
+func (r *csvRow) rules(col string) []*RuleRow {
+	v := r.get(col)
+	if v == "" {
+		return nil
+	}
+
+	var rules []*RuleRow
+	for i, spec := range strings.Split(v, ";") {
+		rule, err := parseRule(spec)
+		if err != nil {
+			r.fail("%s: rule %d: %s", col, i+1, err)
+			continue
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// parseRule parses pricing rule in CSV column format.
+func parseRule(spec string) (*RuleRow, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("expected \"<kind> <condition> <adjustment bps>\", got %q", strings.TrimSpace(spec))
+	}
+
+	bps, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid adjustment %q", fields[2])
+	}
+
+	rule := &RuleRow{Kind: fields[0], AdjustmentBps: bps}
+	switch pricing.RuleKind(rule.Kind) {
+	case pricing.RuleSoldPercent:
+		percent, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid sold percent %q", fields[1])
+		}
+
+		rule.SoldPercent = &percent
+	case pricing.RuleTimeWindow:
+		from, until, ok := strings.Cut(fields[1], "..")
+		if !ok {
+			return nil, fmt.Errorf("invalid time window %q, expected \"<from>..<until>\"", fields[1])
+		}
+
+		if rule.ActiveFrom, err = parseOptionalTime(from); err != nil {
+			return nil, err
+		}
+
+		if rule.ActiveUntil, err = parseOptionalTime(until); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown rule kind %q", rule.Kind)
+	}
+
+	return rule, nil
+}
+
+func parseOptionalTime(v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time %q, expected RFC 3339 format", v)
+	}
+
+	return &t, nil
+}
+
+func parseCSV(r io.Reader) (*Manifest, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return &Manifest{}, nil
+		}
+
+		return nil, fmt.Errorf("%w: invalid CSV manifest: %s", booking.ErrInvalidParams, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, col := range header {
+		col = strings.ToLower(strings.TrimSpace(col))
+		if !slices.Contains(knownColumns, col) {
+			return nil, fmt.Errorf("%w: unknown CSV column %q", booking.ErrInvalidParams, col)
+		}
+
+		columns[col] = i
+	}
+
+	for _, col := range requiredColumns {
+		if _, ok := columns[col]; !ok {
+			return nil, fmt.Errorf("%w: missing CSV column %q", booking.ErrInvalidParams, col)
+		}
+	}
+
+	m := &Manifest{}
+	events := make(map[string]*EventRow)
+	var errs []*RowError
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid CSV manifest: %s", booking.ErrInvalidParams, err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := &csvRow{line: line, columns: columns, record: record}
+		tier := &TierRow{
+			Row:           line,
+			Name:          row.get(colTier),
+			PriceCents:    row.int(colPriceCents),
+			TicketsCount:  row.int(colTicketsCount),
+			MinPriceCents: row.optionalInt(colMinPriceCents),
+			MaxPriceCents: row.optionalInt(colMaxPriceCents),
+			PricingRules:  row.rules(colPricingRules),
+		}
+
+		name := row.get(colEvent)
+		event, ok := events[name]
+		if !ok {
+			event = &EventRow{
+				Row:              line,
+				Name:             name,
+				Currency:         row.get(colCurrency),
+				TaxRateBps:       row.uint(colTaxRateBps),
+				OrganizerID:      row.get(colOrganizerID),
+				TransfersBlocked: row.bool(colTransfersBlocked),
+			}
+			events[name] = event
+			m.Events = append(m.Events, event)
+		} else {
+			checkEventColumns(row, event)
+		}
+
+		event.Tiers = append(event.Tiers, tier)
+		errs = append(errs, row.errs...)
+	}
+
+	if len(errs) > 0 {
+		return nil, &ValidationError{Rows: errs}
+	}
+
+	return m, nil
+}
+
+// checkEventColumns checks that event columns in subsequent rows of an event are empty or match the first row.
+func checkEventColumns(row *csvRow, event *EventRow) {
+	check := func(col, value string) {
+		if v := row.get(col); v != "" && !strings.EqualFold(v, value) {
+			row.fail("%s: value %q doesn't match %q in row %d", col, v, value, event.Row)
+		}
+	}
+
+	check(colCurrency, event.Currency)
+	check(colTaxRateBps, strconv.FormatUint(uint64(event.TaxRateBps), 10))
+	check(colOrganizerID, event.OrganizerID)
+	if row.get(colTransfersBlocked) != "" && row.bool(colTransfersBlocked) != event.TransfersBlocked {
+		row.fail("%s: value doesn't match row %d", colTransfersBlocked, event.Row)
+	}
+}
@@ -0,0 +1,142 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/documents"
+)
+
+const dateFormat = "Jan 2, 2006 15:04 MST"
+
+// ReservationSource provides reservation and transfer details for messages.
+type ReservationSource interface {
+	GetReservationEntries(ctx context.Context, reservationID uuid.UUID) (*booking.ReservationMeta, error)
+	GetReservationTickets(ctx context.Context, reservationID uuid.UUID) ([]*booking.IssuedTicket, error)
+	GetTransferClaimToken(ctx context.Context, transferID uuid.UUID) (string, error)
+}
+
+type templateData struct {
+	Event       booking.DomainEvent
+	Reservation *booking.ReservationMeta
+	Total       booking.Money
+	Amount      string
+	ExpiresAt   string
+
+	// ClaimToken is a secret token of a transfer addressed by email.
+	// It's not a part of domain event, as events are also published to partners.
+	ClaimToken string
+}
+
+// Mailer sends customer emails on booking domain events.
+//
+// Events without customer email and events without message template are ignored.
+type Mailer struct {
+	notifier     Notifier
+	reservations ReservationSource
+	templates    messageTemplates
+}
+
+func NewMailer(notifier Notifier, reservations ReservationSource) (*Mailer, error) {
+	templates, err := loadTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Mailer{
+		notifier:     notifier,
+		reservations: reservations,
+		templates:    templates,
+	}, nil
+}
+
+// Accepts reports whether event produces an email.
+func (m *Mailer) Accepts(e booking.DomainEvent) bool {
+	_, ok := m.templates[e.Type]
+	return ok && e.Email != ""
+}
+
+// HandleEvent renders and sends email for an event.
+func (m *Mailer) HandleEvent(ctx context.Context, e booking.DomainEvent) error {
+	if !m.Accepts(e) {
+		return nil
+	}
+
+	meta, err := m.reservations.GetReservationEntries(ctx, e.ReservationID)
+	if err != nil {
+		return fmt.Errorf("failed to get reservation %s: %w", e.ReservationID, err)
+	}
+
+	data := templateData{
+		Event:       e,
+		Reservation: meta,
+		Total:       booking.NewMoney(int64(meta.TotalCents), meta.Currency),
+		ExpiresAt:   formatTime(e.ExpiresAt),
+	}
+	if e.Amount != nil {
+		data.Amount = e.Amount.String()
+	}
+
+	if e.Type == booking.TransferCreatedEvent && e.TransferID != nil {
+		data.ClaimToken, err = m.reservations.GetTransferClaimToken(ctx, *e.TransferID)
+		if err != nil {
+			return fmt.Errorf("failed to get transfer %s: %w", *e.TransferID, err)
+		}
+	}
+
+	subject, body, err := m.templates.render(e.Type, data)
+	if err != nil {
+		return err
+	}
+
+	msg := Message{
+		To:      []string{e.Email},
+		Subject: subject,
+		Body:    body,
+	}
+
+	if e.Type == booking.PaymentSucceededEvent {
+		attachment, err := m.renderTickets(ctx, meta)
+		if err != nil {
+			return err
+		}
+
+		msg.Attachments = append(msg.Attachments, *attachment)
+	}
+
+	if err := m.notifier.Send(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send %q notification: %w", e.Type, err)
+	}
+
+	return nil
+}
+
+func (m *Mailer) renderTickets(ctx context.Context, meta *booking.ReservationMeta) (*Attachment, error) {
+	tickets, err := m.reservations.GetReservationTickets(ctx, meta.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tickets: %w", err)
+	}
+
+	pdf, err := documents.RenderTickets(meta, tickets)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Attachment{
+		FileName:    "tickets.pdf",
+		ContentType: "application/pdf",
+		Data:        pdf,
+	}, nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.UTC().Format(dateFormat)
+}
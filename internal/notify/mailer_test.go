@@ -0,0 +1,118 @@
+package notify
+
+import (
+	"context"
+	"mime"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+type fakeNotifier struct {
+	sent []Message
+}
+
+func (n *fakeNotifier) Send(_ context.Context, msg Message) error {
+	n.sent = append(n.sent, msg)
+	return nil
+}
+
+type fakeReservations struct {
+	meta *booking.ReservationMeta
+}
+
+func (r fakeReservations) GetReservationEntries(context.Context, uuid.UUID) (*booking.ReservationMeta, error) {
+	return r.meta, nil
+}
+
+func (r fakeReservations) GetReservationTickets(context.Context, uuid.UUID) ([]*booking.IssuedTicket, error) {
+	return nil, nil
+}
+
+func (r fakeReservations) GetTransferClaimToken(context.Context, uuid.UUID) (string, error) {
+	return "CLAIMTOKEN", nil
+}
+
+func TestMailer(t *testing.T) {
+	meta := &booking.ReservationMeta{
+		ID:         uuid.New(),
+		EventID:    uuid.New(),
+		EventName:  "Jazz Night",
+		Status:     booking.ReservationPending,
+		TotalCents: 50_00,
+		Currency:   booking.DefaultCurrency,
+		Items: []*booking.ReservationItem{
+			{TierName: "GA", Quantity: 2, UnitPriceCents: 25_00},
+		},
+	}
+
+	notifier := &fakeNotifier{}
+	mailer, err := NewMailer(notifier, fakeReservations{meta: meta})
+	require.NoError(t, err)
+
+	// Events without email are ignored
+	require.NoError(t, mailer.HandleEvent(context.Background(), booking.DomainEvent{
+		Type:          booking.ReservationCreatedEvent,
+		ReservationID: meta.ID,
+	}))
+	require.Empty(t, notifier.sent)
+
+	expiresAt := time.Date(2025, 10, 31, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, mailer.HandleEvent(context.Background(), booking.DomainEvent{
+		Type:          booking.ReservationCreatedEvent,
+		ReservationID: meta.ID,
+		Email:         "buyer@example.com",
+		ExpiresAt:     &expiresAt,
+	}))
+	require.Len(t, notifier.sent, 1)
+
+	msg := notifier.sent[0]
+	require.Equal(t, []string{"buyer@example.com"}, msg.To)
+	require.Equal(t, "Your reservation for Jazz Night", msg.Subject)
+	require.Contains(t, msg.Body, "GA")
+	require.Contains(t, msg.Body, expiresAt.Format(dateFormat))
+
+	// Claim token is sent only by email
+	transferID := uuid.New()
+	require.NoError(t, mailer.HandleEvent(context.Background(), booking.DomainEvent{
+		Type:          booking.TransferCreatedEvent,
+		ReservationID: meta.ID,
+		Email:         "friend@example.com",
+		TransferID:    &transferID,
+	}))
+	require.Len(t, notifier.sent, 2)
+	require.Contains(t, notifier.sent[1].Body, "Claim token: CLAIMTOKEN")
+
+	for eventType := range mailer.templates {
+		_, _, err := mailer.templates.render(eventType, templateData{Reservation: meta})
+		require.NoError(t, err, eventType)
+	}
+}
+
+func TestEncodeMessage(t *testing.T) {
+	from := &mail.Address{Name: "Tickets", Address: "tickets@example.com"}
+	data, err := encodeMessage(from, Message{
+		To:      []string{"buyer@example.com"},
+		Subject: "Билеты",
+		Body:    "Hello",
+		Attachments: []Attachment{
+			{FileName: "tickets.pdf", ContentType: "application/pdf", Data: []byte("%PDF")},
+		},
+	}, time.Now())
+	require.NoError(t, err)
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(data)))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	require.Equal(t, "Билеты", subject)
+	require.Contains(t, msg.Header.Get("Content-Type"), "multipart/mixed")
+	require.Contains(t, string(data), `filename=tickets.pdf`)
+}
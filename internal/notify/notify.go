@@ -0,0 +1,49 @@
+// Package notify sends customer notifications about booking state changes.
+package notify
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// Message is a notification message.
+type Message struct {
+	To          []string
+	Subject     string
+	Body        string
+	Attachments []Attachment
+}
+
+// Attachment is a file attached to a message.
+type Attachment struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+// Notifier delivers messages to recipients.
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogNotifier writes messages to a log instead of delivering them.
+//
+// Used in development when no mail server is configured.
+type LogNotifier struct {
+	logger *zap.SugaredLogger
+}
+
+func NewLogNotifier(logger *zap.SugaredLogger) LogNotifier {
+	return LogNotifier{logger: logger}
+}
+
+func (n LogNotifier) Send(_ context.Context, msg Message) error {
+	n.logger.Infow(
+		"notification",
+		"to", msg.To,
+		"subject", msg.Subject,
+		"attachments", len(msg.Attachments),
+	)
+	return nil
+}
@@ -0,0 +1,206 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+const (
+	// deliveryBatchSize is max number of emails sent concurrently by a single run.
+	deliveryBatchSize = 20
+
+	// sendTimeout is max time given to render and send a single email.
+	sendTimeout = 30 * time.Second
+
+	maxErrorLength = 512
+)
+
+// DeliveryStatus is email delivery status.
+type DeliveryStatus string
+
+const (
+	// DeliveryPending means that email is waiting for the first or next attempt.
+	DeliveryPending DeliveryStatus = "pending"
+
+	// DeliveryDelivered means that mail server accepted the message.
+	DeliveryDelivered DeliveryStatus = "delivered"
+
+	// DeliveryDead means that all attempts failed.
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+// QueueConfig is email delivery queue config.
+type QueueConfig struct {
+	// MaxAttempts is number of failed attempts after which delivery is dead-lettered.
+	MaxAttempts int
+
+	// RetryBase is delay before the first retry. Delay is doubled on each next retry.
+	RetryBase time.Duration
+
+	// RetryMax is max delay between retries.
+	RetryMax time.Duration
+}
+
+// Backoff returns delay before the next attempt after specified number of failed attempts.
+func (cfg QueueConfig) Backoff(attempts int) time.Duration {
+	delay := cfg.RetryBase
+	for i := 1; i < attempts && delay < cfg.RetryMax; i++ {
+		delay *= 2
+	}
+
+	return min(delay, cfg.RetryMax)
+}
+
+// Queue stores relayed domain events as pending emails and sends them with retries.
+//
+// Queued emails survive restarts and failed sends are retried with exponential backoff.
+type Queue struct {
+	db     *pgxpool.Pool
+	mailer *Mailer
+	cfg    QueueConfig
+}
+
+func NewQueue(db *pgxpool.Pool, mailer *Mailer, cfg QueueConfig) *Queue {
+	return &Queue{
+		db:     db,
+		mailer: mailer,
+		cfg:    cfg,
+	}
+}
+
+// Enqueue schedules email for an event. Events which don't produce emails are skipped.
+//
+// Enqueue is idempotent, so repeated calls for the same event don't send duplicate emails.
+func (q *Queue) Enqueue(ctx context.Context, e booking.DomainEvent) error {
+	if !q.mailer.Accepts(e) {
+		return nil
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	_, err = q.db.Exec(ctx, `
+		INSERT INTO mail_deliveries (event_id, event_type, payload)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (event_id) DO NOTHING
+	`, e.ID, string(e.Type), payload)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue email: %w", err)
+	}
+
+	return nil
+}
+
+type dueDelivery struct {
+	EventID  uuid.UUID       `db:"event_id"`
+	Payload  json.RawMessage `db:"payload"`
+	Attempts int             `db:"attempts"`
+}
+
+// DeliverDue sends due emails.
+//
+// Method is called periodically by a background worker. Returns number of processed deliveries.
+func (q *Queue) DeliverDue(ctx context.Context) (int, error) {
+	// Deliveries are leased by moving next attempt time, so concurrent workers don't pick them
+	// and lease expires if worker dies during delivery.
+	lease := 2 * sendTimeout
+	var due []dueDelivery
+	err := pgxscan.Select(ctx, q.db, &due, `
+		UPDATE mail_deliveries
+		SET next_attempt_at = now() + $3 * INTERVAL '1 second'
+		WHERE event_id IN (
+			SELECT event_id
+			FROM mail_deliveries
+			WHERE status = $1 AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			FOR UPDATE SKIP LOCKED
+			LIMIT $2
+		)
+		RETURNING event_id, payload, attempts
+	`, DeliveryPending, deliveryBatchSize, int(lease.Seconds()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to query due emails: %w", err)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, d := range due {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := q.deliver(ctx, d); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	return len(due), errors.Join(errs...)
+}
+
+func (q *Queue) deliver(ctx context.Context, d dueDelivery) error {
+	sendErr := q.send(ctx, d)
+	if sendErr == nil {
+		_, err := q.db.Exec(ctx, `
+			UPDATE mail_deliveries
+			SET status = $2, attempts = attempts + 1, delivered_at = now(), last_error = NULL
+			WHERE event_id = $1
+		`, d.EventID, DeliveryDelivered)
+		if err != nil {
+			return fmt.Errorf("failed to update email %s: %w", d.EventID, err)
+		}
+
+		return nil
+	}
+
+	attempts := d.Attempts + 1
+	status := DeliveryPending
+	if attempts >= q.cfg.MaxAttempts {
+		status = DeliveryDead
+	}
+
+	errMsg := sendErr.Error()
+	if len(errMsg) > maxErrorLength {
+		errMsg = errMsg[:maxErrorLength]
+	}
+
+	_, err := q.db.Exec(ctx, `
+		UPDATE mail_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = now() + $4 * INTERVAL '1 millisecond', last_error = $5
+		WHERE event_id = $1
+	`, d.EventID, status, attempts, q.cfg.Backoff(attempts).Milliseconds(), errMsg)
+	if err != nil {
+		return fmt.Errorf("failed to update email %s: %w", d.EventID, err)
+	}
+
+	return nil
+}
+
+func (q *Queue) send(ctx context.Context, d dueDelivery) error {
+	var e booking.DomainEvent
+	if err := json.Unmarshal(d.Payload, &e); err != nil {
+		return fmt.Errorf("failed to decode event payload: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	return q.mailer.HandleEvent(ctx, e)
+}
@@ -0,0 +1,213 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"time"
+)
+
+// SMTPConfig is SMTP server connection config.
+type SMTPConfig struct {
+	// Addr is SMTP server address in host:port format.
+	Addr string
+
+	// Username and Password are optional credentials for PLAIN auth.
+	Username string
+	Password string
+
+	// From is sender address, e.g. "Tickets <tickets@example.com>".
+	From string
+}
+
+// SMTPNotifier sends messages as emails using SMTP server.
+//
+// STARTTLS is used if supported by server.
+type SMTPNotifier struct {
+	cfg  SMTPConfig
+	host string
+	from *mail.Address
+}
+
+func NewSMTPNotifier(cfg SMTPConfig) (*SMTPNotifier, error) {
+	host, _, err := net.SplitHostPort(cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP server address %q: %w", cfg.Addr, err)
+	}
+
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", cfg.From, err)
+	}
+
+	return &SMTPNotifier{
+		cfg:  cfg,
+		host: host,
+		from: from,
+	}, nil
+}
+
+func (n *SMTPNotifier) Send(ctx context.Context, msg Message) error {
+	data, err := encodeMessage(n.from, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", n.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		return fmt.Errorf("SMTP handshake failed: %w", err)
+	}
+
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: n.host}); err != nil {
+			return fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	}
+
+	if n.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.host)); err != nil {
+			return fmt.Errorf("SMTP auth failed: %w", err)
+		}
+	}
+
+	if err := c.Mail(n.from.Address); err != nil {
+		return fmt.Errorf("MAIL command failed: %w", err)
+	}
+
+	for _, rcpt := range msg.To {
+		if err := c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("RCPT command failed for %q: %w", rcpt, err)
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("DATA command failed: %w", err)
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+
+	return c.Quit()
+}
+
+// encodeMessage builds MIME message with plain-text body and optional attachments.
+func encodeMessage(from *mail.Address, msg Message, date time.Time) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(buf, "To: %s\r\n", joinAddresses(msg.To))
+	fmt.Fprintf(buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	if len(msg.Attachments) == 0 {
+		buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		if err := writeQuotedPrintable(buf, msg.Body); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	mw := multipart.NewWriter(buf)
+	fmt.Fprintf(buf, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", mw.Boundary())
+
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeQuotedPrintable(part, msg.Body); err != nil {
+		return nil, err
+	}
+
+	for _, a := range msg.Attachments {
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {a.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.FileName})},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := writeBase64(part, a.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qw := quotedprintable.NewWriter(w)
+	if _, err := qw.Write([]byte(body)); err != nil {
+		return err
+	}
+
+	return qw.Close()
+}
+
+// writeBase64 writes base64-encoded data split into 76 characters lines as required by RFC 2045.
+func writeBase64(w io.Writer, data []byte) error {
+	const lineLen = 76
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 0 {
+		n := min(lineLen, len(encoded))
+		if _, err := fmt.Fprintf(w, "%s\r\n", encoded[:n]); err != nil {
+			return err
+		}
+
+		encoded = encoded[n:]
+	}
+
+	return nil
+}
+
+func joinAddresses(addrs []string) string {
+	buf := &bytes.Buffer{}
+	for i, addr := range addrs {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+
+		buf.WriteString((&mail.Address{Address: addr}).String())
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+const (
+	layoutTemplate = "layout.tmpl"
+	templateExt    = ".tmpl"
+)
+
+// templatesFS contains message templates named after domain event type.
+//
+// Each template defines "subject" and "body" blocks. Layout template contains shared blocks.
+//
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+// messageTemplates is a set of message templates by event type.
+type messageTemplates map[booking.DomainEventType]*template.Template
+
+func loadTemplates() (messageTemplates, error) {
+	entries, err := templatesFS.ReadDir("templates")
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(messageTemplates, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if name == layoutTemplate {
+			continue
+		}
+
+		t, err := template.ParseFS(templatesFS, "templates/"+layoutTemplate, "templates/"+name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template %q: %w", name, err)
+		}
+
+		result[booking.DomainEventType(strings.TrimSuffix(name, templateExt))] = t
+	}
+
+	return result, nil
+}
+
+// render renders message subject and body.
+func (t messageTemplates) render(eventType booking.DomainEventType, data any) (subject, body string, err error) {
+	tpl, ok := t[eventType]
+	if !ok {
+		return "", "", fmt.Errorf("no template for %q", eventType)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tpl.ExecuteTemplate(buf, "subject", data); err != nil {
+		return "", "", fmt.Errorf("failed to render %q subject: %w", eventType, err)
+	}
+
+	subject = strings.TrimSpace(buf.String())
+	buf.Reset()
+	if err := tpl.ExecuteTemplate(buf, "body", data); err != nil {
+		return "", "", fmt.Errorf("failed to render %q body: %w", eventType, err)
+	}
+
+	return subject, strings.TrimSpace(buf.String()) + "\n", nil
+}
@@ -0,0 +1,62 @@
+// Package outbox relays domain events stored by booking service in the outbox table to external sinks.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+// Record is an outbox record.
+type Record struct {
+	// Seq is record sequence number. Records are published in sequence order.
+	Seq int64 `db:"id"`
+
+	EventID   uuid.UUID               `db:"event_id"`
+	EventType booking.DomainEventType `db:"event_type"`
+
+	// AggregateID is reservation ID. Records with the same aggregate ID are delivered in order.
+	AggregateID uuid.UUID `db:"aggregate_id"`
+
+	// Payload is JSON-encoded booking.DomainEvent.
+	Payload   json.RawMessage `db:"payload"`
+	CreatedAt time.Time       `db:"created_at"`
+}
+
+// Event decodes record payload.
+func (r Record) Event() (booking.DomainEvent, error) {
+	var e booking.DomainEvent
+	err := json.Unmarshal(r.Payload, &e)
+	return e, err
+}
+
+// Sink publishes outbox records.
+//
+// Relay retries failed records, so sink may receive the same record more than once.
+type Sink interface {
+	Publish(ctx context.Context, r Record) error
+}
+
+// SinkFunc is a function adapter for Sink.
+type SinkFunc func(ctx context.Context, r Record) error
+
+func (fn SinkFunc) Publish(ctx context.Context, r Record) error {
+	return fn(ctx, r)
+}
+
+// MultiSink publishes records to all sinks in order.
+type MultiSink []Sink
+
+func (m MultiSink) Publish(ctx context.Context, r Record) error {
+	for _, s := range m {
+		if err := s.Publish(ctx, r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// DefaultBatchSize is max number of records published by a single relay run.
+	DefaultBatchSize = 100
+
+	// relayLockKey is advisory lock key which guarantees a single active relay across instances.
+	relayLockKey = 0x6f7574626f78 // "outbox"
+)
+
+// Relay publishes unpublished outbox records to a sink.
+//
+// Records are published strictly in sequence order, publishing stops at the first failed record
+// and the record is retried on the next run. That gives at-least-once delivery preserving per-reservation order:
+// state changes of a reservation are serialized by reservation row lock, so their records get increasing sequence numbers.
+type Relay struct {
+	db        *pgxpool.Pool
+	sink      Sink
+	batchSize int
+}
+
+func NewRelay(db *pgxpool.Pool, sink Sink, batchSize int) *Relay {
+	return &Relay{
+		db:        db,
+		sink:      sink,
+		batchSize: batchSize,
+	}
+}
+
+// Run publishes pending records until outbox is drained or publish fails.
+//
+// Returns number of published records.
+func (r *Relay) Run(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := r.publishBatch(ctx)
+		total += n
+		if err != nil || n < r.batchSize {
+			return total, err
+		}
+	}
+}
+
+func (r *Relay) publishBatch(ctx context.Context) (int, error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.ReadCommitted,
+		AccessMode: pgx.ReadWrite,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	// Concurrent relays would break publish order, so only one instance publishes at a time.
+	var acquired bool
+	if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1)`, relayLockKey).Scan(&acquired); err != nil {
+		return 0, fmt.Errorf("failed to acquire relay lock: %w", err)
+	}
+
+	if !acquired {
+		return 0, nil
+	}
+
+	var records []Record
+	err = pgxscan.Select(ctx, tx, &records, `
+		SELECT id, event_id, event_type, aggregate_id, payload, created_at
+		FROM outbox
+		WHERE published_at IS NULL
+		ORDER BY id
+		LIMIT $1
+	`, r.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query outbox: %w", err)
+	}
+
+	var (
+		published  = make([]int64, 0, len(records))
+		publishErr error
+	)
+	for _, rec := range records {
+		if err := r.sink.Publish(ctx, rec); err != nil {
+			publishErr = fmt.Errorf("failed to publish record %d (%s): %w", rec.Seq, rec.EventType, err)
+			break
+		}
+
+		published = append(published, rec.Seq)
+	}
+
+	if len(published) == 0 {
+		return 0, publishErr
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE outbox SET published_at = now() WHERE id = ANY($1)`, published)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark records as published: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(published), publishErr
+}
+
+// Prune removes records published before the retention period.
+//
+// Returns number of removed records.
+func Prune(ctx context.Context, db *pgxpool.Pool, retention time.Duration) (int64, error) {
+	tag, err := db.Exec(
+		ctx, `DELETE FROM outbox WHERE published_at < now() - $1 * INTERVAL '1 second'`,
+		int(retention.Seconds()),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune outbox: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,78 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// DefaultStream is default Redis stream name for published events.
+const DefaultStream = "booking:events"
+
+// RedisStreamSink appends records to a Redis stream.
+//
+// Stream entry contains record fields, consumers should deduplicate entries by "event_id".
+type RedisStreamSink struct {
+	rdb    redis.UniversalClient
+	stream string
+	maxLen int64
+}
+
+// NewRedisStreamSink returns a new sink.
+//
+// If maxLen is positive, stream is approximately trimmed to the specified length.
+func NewRedisStreamSink(rdb redis.UniversalClient, stream string, maxLen int64) *RedisStreamSink {
+	return &RedisStreamSink{
+		rdb:    rdb,
+		stream: stream,
+		maxLen: maxLen,
+	}
+}
+
+func (s *RedisStreamSink) Publish(ctx context.Context, r Record) error {
+	args := &redis.XAddArgs{
+		Stream: s.stream,
+		Values: map[string]any{
+			"seq":          strconv.FormatInt(r.Seq, 10),
+			"event_id":     r.EventID.String(),
+			"type":         string(r.EventType),
+			"aggregate_id": r.AggregateID.String(),
+			"payload":      string(r.Payload),
+		},
+	}
+
+	if s.maxLen > 0 {
+		args.MaxLen = s.maxLen
+		args.Approx = true
+	}
+
+	if err := s.rdb.XAdd(ctx, args).Err(); err != nil {
+		return fmt.Errorf("XADD %s: %w", s.stream, err)
+	}
+
+	return nil
+}
+
+// LogSink writes records to log. Used for development.
+type LogSink struct {
+	logger *zap.SugaredLogger
+}
+
+func NewLogSink(logger *zap.SugaredLogger) LogSink {
+	return LogSink{logger: logger}
+}
+
+func (s LogSink) Publish(_ context.Context, r Record) error {
+	s.logger.Infow(
+		"outbox event",
+		"seq", r.Seq,
+		"event_id", r.EventID,
+		"type", r.EventType,
+		"aggregate_id", r.AggregateID,
+		"payload", string(r.Payload),
+	)
+	return nil
+}
@@ -0,0 +1,185 @@
+package pricing
+
+import (
+	"slices"
+	"time"
+)
+
+// TierState is a ticket tier snapshot used to evaluate price.
+type TierState struct {
+	// BasePriceCents is tier price before adjustments.
+	BasePriceCents int
+
+	// Limits are price floor and ceiling.
+	Limits Limits
+
+	// Capacity is total number of tickets in the tier.
+	Capacity int
+
+	// Taken is number of sold or actively held tickets.
+	Taken int
+}
+
+// Threshold describes the next price change point.
+type Threshold struct {
+	// PriceCents is a price after the threshold is reached.
+	PriceCents int `json:"priceCents"`
+
+	// SoldPercent is set when threshold is a percentage of taken tickets.
+	SoldPercent *int `json:"soldPercent,omitempty"`
+
+	// TicketsLeft is number of tickets to be taken before SoldPercent threshold is reached.
+	TicketsLeft *int `json:"ticketsLeft,omitempty"`
+
+	// At is set when threshold is a time boundary.
+	At *time.Time `json:"at,omitempty"`
+}
+
+// Quote is a result of price evaluation.
+type Quote struct {
+	// PriceCents is current effective price.
+	PriceCents int
+
+	// Next is next price change point. Nil if price won't change anymore.
+	//
+	// Sold percent thresholds take precedence over time boundaries.
+	Next *Threshold
+}
+
+// Evaluate computes effective tier price at specified moment and finds the next price threshold.
+func Evaluate(state TierState, rules []Rule, now time.Time) Quote {
+	price := PriceOf(state, rules, now)
+	quote := Quote{
+		PriceCents: price,
+	}
+
+	if next := nextSoldThreshold(state, rules, now, price); next != nil {
+		quote.Next = next
+		return quote
+	}
+
+	quote.Next = nextTimeThreshold(state, rules, now, price)
+	return quote
+}
+
+// PriceOf returns effective tier price at specified moment.
+func PriceOf(state TierState, rules []Rule, now time.Time) int {
+	adjustment := 0
+
+	// Only the highest reached sold percent step is applied.
+	var step *Rule
+	for i, r := range rules {
+		switch r.Kind {
+		case RuleSoldPercent:
+			if r.SoldPercent == nil || !isReached(state, *r.SoldPercent) {
+				continue
+			}
+
+			if step == nil || *r.SoldPercent > *step.SoldPercent ||
+				(*r.SoldPercent == *step.SoldPercent && r.AdjustmentBps > step.AdjustmentBps) {
+				step = &rules[i]
+			}
+		case RuleTimeWindow:
+			if r.isActiveAt(now) {
+				adjustment += r.AdjustmentBps
+			}
+		}
+	}
+
+	if step != nil {
+		adjustment += step.AdjustmentBps
+	}
+
+	return state.Limits.clamp(applyBps(state.BasePriceCents, adjustment))
+}
+
+// applyBps applies adjustment in basis points to a price.
+//
+// Result is rounded half-up to a whole cent and never goes below zero.
+func applyBps(price, adjustmentBps int) int {
+	factor := bpsDenominator + adjustmentBps
+	if factor <= 0 {
+		return 0
+	}
+
+	return (price*factor + bpsDenominator/2) / bpsDenominator
+}
+
+// isReached reports whether percentage of taken tickets reached a threshold.
+func isReached(state TierState, percent int) bool {
+	if state.Capacity <= 0 {
+		return false
+	}
+
+	return state.Taken*100 >= percent*state.Capacity
+}
+
+// takenAt returns minimal number of taken tickets required to reach a threshold.
+func takenAt(capacity, percent int) int {
+	return (percent*capacity + 99) / 100
+}
+
+func nextSoldThreshold(state TierState, rules []Rule, now time.Time, current int) *Threshold {
+	if state.Capacity <= 0 {
+		return nil
+	}
+
+	var thresholds []int
+	for _, r := range rules {
+		if r.Kind != RuleSoldPercent || r.SoldPercent == nil || isReached(state, *r.SoldPercent) {
+			continue
+		}
+
+		thresholds = append(thresholds, *r.SoldPercent)
+	}
+
+	slices.Sort(thresholds)
+	thresholds = slices.Compact(thresholds)
+	for _, percent := range thresholds {
+		next := state
+		next.Taken = takenAt(state.Capacity, percent)
+		price := PriceOf(next, rules, now)
+		if price == current {
+			continue
+		}
+
+		left := next.Taken - state.Taken
+		return &Threshold{
+			PriceCents:  price,
+			SoldPercent: &percent,
+			TicketsLeft: &left,
+		}
+	}
+
+	return nil
+}
+
+func nextTimeThreshold(state TierState, rules []Rule, now time.Time, current int) *Threshold {
+	var boundaries []time.Time
+	for _, r := range rules {
+		if r.Kind != RuleTimeWindow {
+			continue
+		}
+
+		for _, t := range []*time.Time{r.ActiveFrom, r.ActiveUntil} {
+			if t != nil && t.After(now) {
+				boundaries = append(boundaries, *t)
+			}
+		}
+	}
+
+	slices.SortFunc(boundaries, time.Time.Compare)
+	for _, at := range boundaries {
+		price := PriceOf(state, rules, at)
+		if price == current {
+			continue
+		}
+
+		return &Threshold{
+			PriceCents: price,
+			At:         &at,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,255 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC)
+
+func soldStep(percent, adjustmentBps int) Rule {
+	return Rule{Kind: RuleSoldPercent, SoldPercent: &percent, AdjustmentBps: adjustmentBps}
+}
+
+func window(from, until time.Duration, adjustmentBps int) Rule {
+	r := Rule{Kind: RuleTimeWindow, AdjustmentBps: adjustmentBps}
+	if from != 0 {
+		t := testNow.Add(from)
+		r.ActiveFrom = &t
+	}
+
+	if until != 0 {
+		t := testNow.Add(until)
+		r.ActiveUntil = &t
+	}
+
+	return r
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestApplyBps(t *testing.T) {
+	cases := []struct {
+		price, bps, want int
+	}{
+		{price: 1000, bps: 0, want: 1000},
+		{price: 1000, bps: 1500, want: 1150},
+		{price: 1000, bps: -2000, want: 800},
+		{price: 999, bps: 500, want: 1049},
+		{price: 1001, bps: -5000, want: 501},
+		{price: 1, bps: 4999, want: 1},
+		{price: 1, bps: 5000, want: 2},
+		{price: 1000, bps: -10000, want: 0},
+		{price: 1000, bps: -15000, want: 0},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.want, applyBps(c.price, c.bps), "%d %+d bps", c.price, c.bps)
+	}
+}
+
+func TestPriceOf(t *testing.T) {
+	cases := map[string]struct {
+		state TierState
+		rules []Rule
+		at    time.Duration
+		want  int
+	}{
+		"no rules": {
+			state: TierState{BasePriceCents: 1000, Capacity: 10},
+			want:  1000,
+		},
+		"below sold threshold": {
+			state: TierState{BasePriceCents: 1000, Capacity: 10, Taken: 4},
+			rules: []Rule{soldStep(50, 2000)},
+			want:  1000,
+		},
+		"at sold threshold": {
+			state: TierState{BasePriceCents: 1000, Capacity: 10, Taken: 5},
+			rules: []Rule{soldStep(50, 2000)},
+			want:  1200,
+		},
+		"threshold rounds up taken tickets": {
+			state: TierState{BasePriceCents: 1000, Capacity: 3, Taken: 1},
+			rules: []Rule{soldStep(50, 2000)},
+			want:  1000,
+		},
+		"only highest reached step": {
+			state: TierState{BasePriceCents: 1000, Capacity: 10, Taken: 9},
+			rules: []Rule{soldStep(80, 5000), soldStep(50, 2000), soldStep(95, 9000)},
+			want:  1500,
+		},
+		"same threshold picks larger adjustment": {
+			state: TierState{BasePriceCents: 1000, Capacity: 10, Taken: 5},
+			rules: []Rule{soldStep(50, 1000), soldStep(50, 3000)},
+			want:  1300,
+		},
+		"empty tier never reaches threshold": {
+			state: TierState{BasePriceCents: 1000},
+			rules: []Rule{soldStep(50, 2000)},
+			want:  1000,
+		},
+		"overlapping windows are summed": {
+			state: TierState{BasePriceCents: 1000, Capacity: 10},
+			rules: []Rule{window(0, time.Hour, -2000), window(-time.Hour, 2*time.Hour, -1000)},
+			want:  700,
+		},
+		"window end is exclusive": {
+			state: TierState{BasePriceCents: 1000, Capacity: 10},
+			rules: []Rule{window(0, time.Hour, -2000), window(-time.Hour, 2*time.Hour, -1000)},
+			at:    time.Hour,
+			want:  900,
+		},
+		"window start is inclusive": {
+			state: TierState{BasePriceCents: 1000, Capacity: 10},
+			rules: []Rule{window(time.Hour, 0, 1000)},
+			at:    time.Hour,
+			want:  1100,
+		},
+		"window and step": {
+			state: TierState{BasePriceCents: 1000, Capacity: 10, Taken: 5},
+			rules: []Rule{window(-time.Hour, time.Hour, -2000), soldStep(50, 5000)},
+			want:  1300,
+		},
+		"clamped to floor": {
+			state: TierState{BasePriceCents: 1000, Capacity: 10, Limits: Limits{MinPriceCents: ptr(800)}},
+			rules: []Rule{window(-time.Hour, time.Hour, -5000)},
+			want:  800,
+		},
+		"clamped to ceiling": {
+			state: TierState{BasePriceCents: 1000, Capacity: 10, Taken: 10, Limits: Limits{MaxPriceCents: ptr(1400)}},
+			rules: []Rule{soldStep(100, 5000)},
+			want:  1400,
+		},
+		"base price outside limits": {
+			state: TierState{BasePriceCents: 1000, Limits: Limits{MinPriceCents: ptr(1200), MaxPriceCents: ptr(1500)}},
+			want:  1200,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.want, PriceOf(c.state, c.rules, testNow.Add(c.at)))
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	cases := map[string]struct {
+		state TierState
+		rules []Rule
+		want  Quote
+	}{
+		"no rules": {
+			state: TierState{BasePriceCents: 1000, Capacity: 10},
+			want:  Quote{PriceCents: 1000},
+		},
+		"next sold threshold": {
+			state: TierState{BasePriceCents: 1000, Capacity: 10, Taken: 2},
+			rules: []Rule{soldStep(50, 5000)},
+			want: Quote{
+				PriceCents: 1000,
+				Next:       &Threshold{PriceCents: 1500, SoldPercent: ptr(50), TicketsLeft: ptr(3)},
+			},
+		},
+		"reached threshold": {
+			state: TierState{BasePriceCents: 1000, Capacity: 10, Taken: 5},
+			rules: []Rule{soldStep(50, 5000)},
+			want:  Quote{PriceCents: 1500},
+		},
+		"threshold price is clamped": {
+			state: TierState{BasePriceCents: 1000, Capacity: 10, Limits: Limits{MaxPriceCents: ptr(1400)}},
+			rules: []Rule{soldStep(50, 5000)},
+			want: Quote{
+				PriceCents: 1000,
+				Next:       &Threshold{PriceCents: 1400, SoldPercent: ptr(50), TicketsLeft: ptr(5)},
+			},
+		},
+		"threshold without price change is skipped": {
+			state: TierState{BasePriceCents: 1000, Capacity: 10},
+			rules: []Rule{soldStep(30, 0), soldStep(60, 2000)},
+			want: Quote{
+				PriceCents: 1000,
+				Next:       &Threshold{PriceCents: 1200, SoldPercent: ptr(60), TicketsLeft: ptr(6)},
+			},
+		},
+		"fully clamped thresholds": {
+			state: TierState{BasePriceCents: 1000, Capacity: 10, Limits: Limits{MaxPriceCents: ptr(1000)}},
+			rules: []Rule{soldStep(50, 5000)},
+			want:  Quote{PriceCents: 1000},
+		},
+		"sold threshold takes precedence over time": {
+			state: TierState{BasePriceCents: 1000, Capacity: 10},
+			rules: []Rule{window(time.Hour, 0, -2000), soldStep(50, 5000)},
+			want: Quote{
+				PriceCents: 1000,
+				Next:       &Threshold{PriceCents: 1500, SoldPercent: ptr(50), TicketsLeft: ptr(5)},
+			},
+		},
+		"early bird end": {
+			state: TierState{BasePriceCents: 1000, Capacity: 10},
+			rules: []Rule{window(0, time.Hour, -2000), window(2*time.Hour, 0, 1000)},
+			want: Quote{
+				PriceCents: 800,
+				Next:       &Threshold{PriceCents: 1000, At: ptr(testNow.Add(time.Hour))},
+			},
+		},
+		"boundary without price change is skipped": {
+			state: TierState{BasePriceCents: 1000, Capacity: 10},
+			rules: []Rule{window(0, time.Hour, -1000), window(time.Hour, 2*time.Hour, -1000)},
+			want: Quote{
+				PriceCents: 900,
+				Next:       &Threshold{PriceCents: 1000, At: ptr(testNow.Add(2 * time.Hour))},
+			},
+		},
+		"past boundaries are ignored": {
+			state: TierState{BasePriceCents: 1000, Capacity: 10},
+			rules: []Rule{window(-2*time.Hour, -time.Hour, -1000)},
+			want:  Quote{PriceCents: 1000},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.want, Evaluate(c.state, c.rules, testNow))
+		})
+	}
+}
+
+func TestRuleValidate(t *testing.T) {
+	cases := map[string]struct {
+		rule    Rule
+		wantErr bool
+	}{
+		"sold step":               {rule: soldStep(50, 1000)},
+		"early bird":              {rule: window(0, time.Hour, -2000)},
+		"open window":             {rule: Rule{Kind: RuleTimeWindow}, wantErr: true},
+		"reversed window":         {rule: window(time.Hour, -time.Hour, 1000), wantErr: true},
+		"zero sold percent":       {rule: soldStep(0, 1000), wantErr: true},
+		"sold percent above 100":  {rule: soldStep(101, 1000), wantErr: true},
+		"missing sold percent":    {rule: Rule{Kind: RuleSoldPercent, AdjustmentBps: 1000}, wantErr: true},
+		"free tickets":            {rule: soldStep(50, -10000), wantErr: true},
+		"unknown kind":            {rule: Rule{Kind: "unknown"}, wantErr: true},
+		"window with sold amount": {rule: Rule{Kind: RuleTimeWindow, ActiveUntil: &testNow, SoldPercent: ptr(5)}, wantErr: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := c.rule.Validate()
+			if c.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+
+	require.Error(t, Limits{MinPriceCents: ptr(1500), MaxPriceCents: ptr(1000)}.Validate())
+	require.Error(t, Limits{MinPriceCents: ptr(-1)}.Validate())
+	require.NoError(t, Limits{MinPriceCents: ptr(1000), MaxPriceCents: ptr(1000)}.Validate())
+}
@@ -0,0 +1,137 @@
+// Package pricing provides dynamic ticket tier pricing rules engine.
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RuleKind is pricing rule type.
+type RuleKind string
+
+const (
+	// RuleSoldPercent adjusts price once percentage of taken (sold or held) tickets reaches a threshold.
+	//
+	// Only the highest reached step is applied.
+	RuleSoldPercent RuleKind = "sold_percent"
+
+	// RuleTimeWindow adjusts price during a time window.
+	//
+	// Used for early-bird (negative adjustment) or last-minute pricing.
+	// Adjustments of all active windows are summed.
+	RuleTimeWindow RuleKind = "time_window"
+)
+
+// bpsDenominator is basis points denominator (100% = 10000 bps).
+const bpsDenominator = 10_000
+
+// Rule is a single price adjustment rule of a ticket tier.
+type Rule struct {
+	ID   uuid.UUID `json:"id" db:"id"`
+	Kind RuleKind  `json:"kind" db:"kind"`
+
+	// AdjustmentBps is price adjustment in basis points relative to tier base price.
+	//
+	// For example, 1500 is +15% and -2000 is -20%.
+	AdjustmentBps int `json:"adjustmentBps" db:"adjustment_bps"`
+
+	// SoldPercent is a threshold of taken tickets percentage for RuleSoldPercent.
+	SoldPercent *int `json:"soldPercent,omitempty" db:"sold_percent"`
+
+	// ActiveFrom is window start for RuleTimeWindow. Nil means open start.
+	ActiveFrom *time.Time `json:"activeFrom,omitempty" db:"active_from"`
+
+	// ActiveUntil is window end (exclusive) for RuleTimeWindow. Nil means open end.
+	ActiveUntil *time.Time `json:"activeUntil,omitempty" db:"active_until"`
+}
+
+// Validate checks whether rule parameters are consistent with rule kind.
+func (r Rule) Validate() error {
+	if r.AdjustmentBps <= -bpsDenominator {
+		return errors.New("adjustment can't be -100% or lower")
+	}
+
+	switch r.Kind {
+	case RuleSoldPercent:
+		if r.SoldPercent == nil {
+			return errors.New("missing sold percent threshold")
+		}
+
+		if *r.SoldPercent <= 0 || *r.SoldPercent > 100 {
+			return fmt.Errorf("sold percent threshold should be in (0, 100] range, got %d", *r.SoldPercent)
+		}
+
+		if r.ActiveFrom != nil || r.ActiveUntil != nil {
+			return errors.New("time window is not supported by sold percent rule")
+		}
+	case RuleTimeWindow:
+		if r.ActiveFrom == nil && r.ActiveUntil == nil {
+			return errors.New("time window requires at least start or end time")
+		}
+
+		if r.ActiveFrom != nil && r.ActiveUntil != nil && !r.ActiveUntil.After(*r.ActiveFrom) {
+			return errors.New("time window end should be after start")
+		}
+
+		if r.SoldPercent != nil {
+			return errors.New("sold percent is not supported by time window rule")
+		}
+	default:
+		return fmt.Errorf("unknown rule kind %q", r.Kind)
+	}
+
+	return nil
+}
+
+// isActiveAt reports whether time window rule is active at specified time.
+func (r Rule) isActiveAt(t time.Time) bool {
+	if r.ActiveFrom != nil && t.Before(*r.ActiveFrom) {
+		return false
+	}
+
+	if r.ActiveUntil != nil && !t.Before(*r.ActiveUntil) {
+		return false
+	}
+
+	return true
+}
+
+// Limits are price floor and ceiling caps.
+//
+// Nil value means no limit.
+type Limits struct {
+	MinPriceCents *int `json:"minPriceCents,omitempty"`
+	MaxPriceCents *int `json:"maxPriceCents,omitempty"`
+}
+
+// Validate checks whether floor and ceiling are consistent.
+func (l Limits) Validate() error {
+	if l.MinPriceCents != nil && *l.MinPriceCents < 0 {
+		return errors.New("price floor can't be negative")
+	}
+
+	if l.MaxPriceCents != nil && *l.MaxPriceCents < 0 {
+		return errors.New("price ceiling can't be negative")
+	}
+
+	if l.MinPriceCents != nil && l.MaxPriceCents != nil && *l.MinPriceCents > *l.MaxPriceCents {
+		return errors.New("price floor is greater than ceiling")
+	}
+
+	return nil
+}
+
+func (l Limits) clamp(price int) int {
+	if l.MinPriceCents != nil && price < *l.MinPriceCents {
+		price = *l.MinPriceCents
+	}
+
+	if l.MaxPriceCents != nil && price > *l.MaxPriceCents {
+		price = *l.MaxPriceCents
+	}
+
+	return price
+}
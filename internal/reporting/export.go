@@ -0,0 +1,455 @@
+package reporting
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+// exportBatchSize is number of rows fetched from export cursor at once.
+const exportBatchSize = 1000
+
+// ExportDataset is a kind of exported records.
+type ExportDataset string
+
+const (
+	ExportReservations ExportDataset = "reservations"
+	ExportPayments     ExportDataset = "payments"
+	ExportTickets      ExportDataset = "tickets"
+)
+
+// ExportFormat is export file format.
+type ExportFormat string
+
+const (
+	FormatCSV    ExportFormat = "csv"
+	FormatNDJSON ExportFormat = "ndjson"
+)
+
+// ContentType returns MIME type of a format.
+func (f ExportFormat) ContentType() string {
+	if f == FormatNDJSON {
+		return "application/x-ndjson"
+	}
+
+	return "text/csv; charset=utf-8"
+}
+
+// Payment and ticket statuses used by export filter.
+const (
+	PaymentPaid     = "paid"
+	PaymentRefunded = "refunded"
+
+	TicketValid     = "valid"
+	TicketCheckedIn = "checked_in"
+	TicketRevoked   = "revoked"
+)
+
+// ExportParams is event export query. Zero fields are ignored.
+type ExportParams struct {
+	EventID uuid.UUID
+	Dataset ExportDataset
+
+	// Format is output format. CSV is used by default.
+	Format ExportFormat
+
+	// Status filters records by reservation, payment or ticket status depending on dataset.
+	Status string
+
+	// From and To filter records by creation time. To is exclusive.
+	//
+	// Reservations are filtered by creation time, payments by payment time and tickets by code issue time.
+	From *time.Time
+	To   *time.Time
+}
+
+// ReservationRecord is an exported reservation.
+type ReservationRecord struct {
+	ID         uuid.UUID                 `json:"id" db:"id"`
+	ActorID    uuid.UUID                 `json:"actorID" db:"actor_id"`
+	Email      *string                   `json:"email,omitempty" db:"contact_email"`
+	Status     booking.ReservationStatus `json:"status" db:"status"`
+	Tickets    int                       `json:"tickets" db:"tickets"`
+	TotalCents int64                     `json:"totalCents" db:"total_cents"`
+	Currency   booking.Currency          `json:"currency" db:"currency"`
+	CreatedAt  time.Time                 `json:"createdAt" db:"created_at"`
+	ExpiresAt  time.Time                 `json:"expiresAt" db:"expires_at"`
+}
+
+// PaymentRecord is an exported payment.
+type PaymentRecord struct {
+	TXID                uuid.UUID        `json:"txID" db:"tx_id"`
+	ReservationID       uuid.UUID        `json:"reservationID" db:"reservation_id"`
+	ActorID             uuid.UUID        `json:"actorID" db:"actor_id"`
+	Status              string           `json:"status" db:"status"`
+	SettlementAmount    int64            `json:"settlementAmount" db:"settlement_amount"`
+	SettlementCurrency  booking.Currency `json:"settlementCurrency" db:"settlement_currency"`
+	PresentmentAmount   int64            `json:"presentmentAmount" db:"presentment_amount"`
+	PresentmentCurrency booking.Currency `json:"presentmentCurrency" db:"presentment_currency"`
+	ExchangeRate        string           `json:"exchangeRate" db:"exchange_rate"`
+	CreatedAt           time.Time        `json:"createdAt" db:"created_at"`
+	RefundedAt          *time.Time       `json:"refundedAt,omitempty" db:"refunded_at"`
+}
+
+// TicketRecord is an exported issued ticket code, i.e. an attendee.
+//
+// Code value is not exported as it grants venue entry.
+type TicketRecord struct {
+	TicketID      uuid.UUID  `json:"ticketID" db:"ticket_id"`
+	CodeID        uuid.UUID  `json:"codeID" db:"code_id"`
+	TierName      string     `json:"tierName" db:"tier_name"`
+	ReservationID uuid.UUID  `json:"reservationID" db:"reservation_id"`
+	OwnerID       uuid.UUID  `json:"ownerID" db:"owner_id"`
+	Email         *string    `json:"email,omitempty" db:"contact_email"`
+	Status        string     `json:"status" db:"status"`
+	IssuedAt      time.Time  `json:"issuedAt" db:"issued_at"`
+	RevokedAt     *time.Time `json:"revokedAt,omitempty" db:"revoked_at"`
+	CheckedInAt   *time.Time `json:"checkedInAt,omitempty" db:"checked_in_at"`
+	CheckedInGate *string    `json:"checkedInGate,omitempty" db:"checked_in_gate"`
+}
+
+type exportRecord interface {
+	csvRecord() []string
+}
+
+func (r *ReservationRecord) csvRecord() []string {
+	return []string{
+		r.ID.String(), r.ActorID.String(), formatOptional(r.Email), string(r.Status), strconv.Itoa(r.Tickets),
+		strconv.FormatInt(r.TotalCents, 10), string(r.Currency), formatTime(&r.CreatedAt), formatTime(&r.ExpiresAt),
+	}
+}
+
+func (r *PaymentRecord) csvRecord() []string {
+	return []string{
+		r.TXID.String(), r.ReservationID.String(), r.ActorID.String(), r.Status,
+		strconv.FormatInt(r.SettlementAmount, 10), string(r.SettlementCurrency),
+		strconv.FormatInt(r.PresentmentAmount, 10), string(r.PresentmentCurrency),
+		r.ExchangeRate, formatTime(&r.CreatedAt), formatTime(r.RefundedAt),
+	}
+}
+
+func (r *TicketRecord) csvRecord() []string {
+	return []string{
+		r.TicketID.String(), r.CodeID.String(), r.TierName, r.ReservationID.String(), r.OwnerID.String(),
+		formatOptional(r.Email), r.Status, formatTime(&r.IssuedAt), formatTime(r.RevokedAt),
+		formatTime(r.CheckedInAt), formatOptional(r.CheckedInGate),
+	}
+}
+
+func formatOptional(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+// exportQuery describes a dataset.
+//
+// Query receives event ID, optional status and time range as $1-$4 parameters.
+type exportQuery struct {
+	statuses []string
+	header   []string
+	query    string
+	stream   func(ctx context.Context, tx pgx.Tx, enc recordEncoder) error
+}
+
+var exportQueries = map[ExportDataset]exportQuery{
+	ExportReservations: {
+		statuses: []string{
+			string(booking.ReservationPending), string(booking.ReservationPaid), string(booking.ReservationCancelled),
+			string(booking.ReservationExpired), string(booking.ReservationRefunded),
+		},
+		header: []string{
+			"id", "actor_id", "email", "status", "tickets", "total_cents", "currency", "created_at", "expires_at",
+		},
+		query: `
+			SELECT
+				r.id, r.actor_id, r.contact_email, r.status, r.total_cents, r.currency, r.created_at, r.expires_at,
+				COALESCE((SELECT SUM(ri.quantity) FROM reservation_items ri WHERE ri.reservation_id = r.id), 0) AS tickets
+			FROM reservations r
+			WHERE r.event_id = $1
+				AND ($2::TEXT IS NULL OR r.status = $2)
+				AND ($3::TIMESTAMPTZ IS NULL OR r.created_at >= $3)
+				AND ($4::TIMESTAMPTZ IS NULL OR r.created_at < $4)
+			ORDER BY r.created_at, r.id
+		`,
+		stream: streamRecords[*ReservationRecord],
+	},
+	ExportPayments: {
+		statuses: []string{PaymentPaid, PaymentRefunded},
+		header: []string{
+			"tx_id", "reservation_id", "actor_id", "status", "settlement_amount", "settlement_currency",
+			"presentment_amount", "presentment_currency", "exchange_rate", "created_at", "refunded_at",
+		},
+		query: `
+			SELECT * FROM (
+				SELECT
+					p.tx_id, p.reservation_id, r.actor_id,
+					CASE WHEN p.refunded_at IS NULL THEN 'paid' ELSE 'refunded' END AS status,
+					p.settlement_amount, p.settlement_currency, p.presentment_amount, p.presentment_currency,
+					p.exchange_rate::TEXT AS exchange_rate, p.created_at, p.refunded_at
+				FROM payments p
+				INNER JOIN reservations r ON r.id = p.reservation_id
+				WHERE r.event_id = $1
+					AND ($3::TIMESTAMPTZ IS NULL OR p.created_at >= $3)
+					AND ($4::TIMESTAMPTZ IS NULL OR p.created_at < $4)
+			) payments
+			WHERE $2::TEXT IS NULL OR status = $2
+			ORDER BY created_at, tx_id
+		`,
+		stream: streamRecords[*PaymentRecord],
+	},
+	ExportTickets: {
+		statuses: []string{TicketValid, TicketCheckedIn, TicketRevoked},
+		header: []string{
+			"ticket_id", "code_id", "tier_name", "reservation_id", "owner_id", "email", "status",
+			"issued_at", "revoked_at", "checked_in_at", "checked_in_gate",
+		},
+		// Reservation email belongs to a buyer, so it's not exported for transferred tickets.
+		query: `
+			SELECT * FROM (
+				SELECT
+					c.ticket_id, c.id AS code_id, tt.name AS tier_name, c.reservation_id, c.owner_id,
+					CASE WHEN c.owner_id = r.actor_id THEN r.contact_email END AS contact_email,
+					CASE
+						WHEN c.revoked_at IS NOT NULL THEN 'revoked'
+						WHEN t.checked_in_at IS NOT NULL THEN 'checked_in'
+						ELSE 'valid'
+					END AS status,
+					c.issued_at, c.revoked_at,
+					CASE WHEN c.revoked_at IS NULL THEN t.checked_in_at END AS checked_in_at,
+					CASE WHEN c.revoked_at IS NULL THEN t.checked_in_gate END AS checked_in_gate
+				FROM ticket_codes c
+				INNER JOIN tickets t ON t.id = c.ticket_id
+				INNER JOIN ticket_tiers tt ON tt.id = t.tier_id
+				INNER JOIN reservations r ON r.id = c.reservation_id
+				WHERE c.event_id = $1
+					AND ($3::TIMESTAMPTZ IS NULL OR c.issued_at >= $3)
+					AND ($4::TIMESTAMPTZ IS NULL OR c.issued_at < $4)
+			) codes
+			WHERE $2::TEXT IS NULL OR status = $2
+			ORDER BY issued_at, code_id
+		`,
+		stream: streamRecords[*TicketRecord],
+	},
+}
+
+// Export is an open export cursor.
+//
+// Export holds a database connection until closed.
+type Export struct {
+	tx     pgx.Tx
+	params ExportParams
+	query  exportQuery
+}
+
+// OpenExport validates export params and opens a cursor over exported records.
+//
+// Records are read from a single read-only snapshot without row locks.
+// Caller should stream records using Export.Stream and close the export afterwards.
+func (svc *Service) OpenExport(ctx context.Context, params ExportParams) (*Export, error) {
+	q, ok := exportQueries[params.Dataset]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown dataset %q", booking.ErrInvalidParams, params.Dataset)
+	}
+
+	switch params.Format {
+	case "":
+		params.Format = FormatCSV
+	case FormatCSV, FormatNDJSON:
+	default:
+		return nil, fmt.Errorf(
+			"%w: unknown format %q, should be %q or %q", booking.ErrInvalidParams, params.Format, FormatCSV, FormatNDJSON,
+		)
+	}
+
+	if params.Status != "" && !slices.Contains(q.statuses, params.Status) {
+		return nil, fmt.Errorf("%w: unknown %s status %q", booking.ErrInvalidParams, params.Dataset, params.Status)
+	}
+
+	if params.From != nil && params.To != nil && !params.From.Before(*params.To) {
+		return nil, fmt.Errorf("%w: export start should be before end", booking.ErrInvalidParams)
+	}
+
+	tx, err := svc.beginSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var exists bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM events WHERE id = $1)`, params.EventID).Scan(&exists)
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	if !exists {
+		_ = tx.Rollback(ctx)
+		return nil, booking.ErrNotFound
+	}
+
+	var status *string
+	if params.Status != "" {
+		status = &params.Status
+	}
+
+	_, err = tx.Exec(
+		ctx, `DECLARE export_cursor NO SCROLL CURSOR FOR `+q.query,
+		params.EventID, status, params.From, params.To,
+	)
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, fmt.Errorf("failed to declare cursor: %w", err)
+	}
+
+	return &Export{
+		tx:     tx,
+		params: params,
+		query:  q,
+	}, nil
+}
+
+// Format returns export output format.
+func (e *Export) Format() ExportFormat {
+	return e.params.Format
+}
+
+// FileName returns suggested export file name.
+func (e *Export) FileName() string {
+	return fmt.Sprintf("%s-%s.%s", e.params.Dataset, e.params.EventID, e.params.Format)
+}
+
+// Stream writes all records to a writer in batches.
+//
+// If writer implements Flush method, it's flushed after each batch.
+func (e *Export) Stream(ctx context.Context, w io.Writer) error {
+	var enc recordEncoder
+	if e.params.Format == FormatNDJSON {
+		enc = newNDJSONEncoder(w)
+	} else {
+		csvEnc, err := newCSVEncoder(w, e.query.header)
+		if err != nil {
+			return err
+		}
+
+		enc = csvEnc
+	}
+
+	return e.query.stream(ctx, e.tx, enc)
+}
+
+// Close releases export cursor and connection.
+func (e *Export) Close(ctx context.Context) error {
+	err := e.tx.Rollback(ctx)
+	if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+		return fmt.Errorf("failed to close export: %w", err)
+	}
+
+	return nil
+}
+
+func streamRecords[T exportRecord](ctx context.Context, tx pgx.Tx, enc recordEncoder) error {
+	for {
+		rows := make([]T, 0, exportBatchSize)
+		err := pgxscan.Select(ctx, tx, &rows, fmt.Sprintf("FETCH FORWARD %d FROM export_cursor", exportBatchSize))
+		if err != nil {
+			return fmt.Errorf("failed to fetch records: %w", err)
+		}
+
+		for _, row := range rows {
+			if err := enc.encode(row); err != nil {
+				return fmt.Errorf("failed to write record: %w", err)
+			}
+		}
+
+		if err := enc.flush(); err != nil {
+			return fmt.Errorf("failed to write records: %w", err)
+		}
+
+		if len(rows) < exportBatchSize {
+			return nil
+		}
+	}
+}
+
+type recordEncoder interface {
+	encode(r exportRecord) error
+	flush() error
+}
+
+type flusher interface {
+	Flush() error
+}
+
+func flushWriter(w io.Writer) error {
+	if f, ok := w.(flusher); ok {
+		return f.Flush()
+	}
+
+	return nil
+}
+
+type csvEncoder struct {
+	w   io.Writer
+	enc *csv.Writer
+}
+
+func newCSVEncoder(w io.Writer, header []string) (*csvEncoder, error) {
+	enc := csv.NewWriter(w)
+	if err := enc.Write(header); err != nil {
+		return nil, fmt.Errorf("failed to write header: %w", err)
+	}
+
+	return &csvEncoder{w: w, enc: enc}, nil
+}
+
+func (e *csvEncoder) encode(r exportRecord) error {
+	return e.enc.Write(r.csvRecord())
+}
+
+func (e *csvEncoder) flush() error {
+	e.enc.Flush()
+	if err := e.enc.Error(); err != nil {
+		return err
+	}
+
+	return flushWriter(e.w)
+}
+
+type ndjsonEncoder struct {
+	w   io.Writer
+	enc *json.Encoder
+}
+
+func newNDJSONEncoder(w io.Writer) *ndjsonEncoder {
+	return &ndjsonEncoder{w: w, enc: json.NewEncoder(w)}
+}
+
+func (e *ndjsonEncoder) encode(r exportRecord) error {
+	return e.enc.Encode(r)
+}
+
+func (e *ndjsonEncoder) flush() error {
+	return flushWriter(e.w)
+}
@@ -0,0 +1,58 @@
+package reporting
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportEncoders(t *testing.T) {
+	gate := "gate, north"
+	checkedIn := time.Date(2025, 11, 1, 19, 30, 0, 0, time.FixedZone("CET", 3600))
+	record := &TicketRecord{
+		TicketID:      uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+		CodeID:        uuid.MustParse("00000000-0000-0000-0000-000000000002"),
+		TierName:      "VIP",
+		ReservationID: uuid.MustParse("00000000-0000-0000-0000-000000000003"),
+		OwnerID:       uuid.MustParse("00000000-0000-0000-0000-000000000004"),
+		Status:        TicketCheckedIn,
+		IssuedAt:      time.Date(2025, 11, 1, 10, 0, 0, 0, time.UTC),
+		CheckedInAt:   &checkedIn,
+		CheckedInGate: &gate,
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		w := bufio.NewWriter(buf)
+		enc, err := newCSVEncoder(w, exportQueries[ExportTickets].header)
+		require.NoError(t, err)
+		require.NoError(t, enc.encode(record))
+		require.Zero(t, buf.Len(), "records should be buffered until flush")
+
+		require.NoError(t, enc.flush())
+		require.Equal(t,
+			"ticket_id,code_id,tier_name,reservation_id,owner_id,email,status,issued_at,revoked_at,checked_in_at,checked_in_gate\n"+
+				"00000000-0000-0000-0000-000000000001,00000000-0000-0000-0000-000000000002,VIP,"+
+				"00000000-0000-0000-0000-000000000003,00000000-0000-0000-0000-000000000004,,checked_in,"+
+				"2025-11-01T10:00:00Z,,2025-11-01T18:30:00Z,\"gate, north\"\n",
+			buf.String(),
+		)
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		enc := newNDJSONEncoder(buf)
+		require.NoError(t, enc.encode(record))
+		require.NoError(t, enc.encode(record))
+		require.NoError(t, enc.flush())
+
+		lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+		require.Len(t, lines, 2)
+		require.NotContains(t, string(lines[0]), "revokedAt")
+		require.Contains(t, string(lines[0]), `"checkedInGate":"gate, north"`)
+	})
+}
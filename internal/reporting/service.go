@@ -0,0 +1,352 @@
+// Package reporting provides sales and revenue reports for event organizers.
+package reporting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+// maxSeriesPoints is max number of buckets in sales series.
+const maxSeriesPoints = 5000
+
+// Config is reporting config.
+type Config struct {
+	// FeeBps is platform fee in basis points withheld from organizer sales.
+	FeeBps uint
+}
+
+// Fee returns platform fee of an amount. Result is rounded to the nearest minor unit, halves are rounded up.
+func (cfg Config) Fee(amount int64) int64 {
+	return (2*amount*int64(cfg.FeeBps) + 10_000) / 20_000
+}
+
+// Service builds organizer reports.
+//
+// Reports are built with plain reads within a read-only snapshot, so they never lock
+// ticket rows used by reservations and all report parts are consistent with each other.
+type Service struct {
+	db  *pgxpool.Pool
+	cfg Config
+}
+
+func NewService(db *pgxpool.Pool, cfg Config) *Service {
+	return &Service{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+type eventInfo struct {
+	ID          uuid.UUID        `db:"id"`
+	Name        string           `db:"name"`
+	Currency    booking.Currency `db:"currency"`
+	TaxRateBps  uint             `db:"tax_rate_bps"`
+	CancelledAt *time.Time       `db:"cancelled_at"`
+}
+
+// paymentTotals is sum of primary sale payments.
+type paymentTotals struct {
+	GrossCents   int64 `db:"gross_cents"`
+	RefundsCents int64 `db:"refunds_cents"`
+}
+
+func (svc *Service) revenue(t paymentTotals, currency booking.Currency, taxRateBps uint) Revenue {
+	sales := t.GrossCents - t.RefundsCents
+	fees := svc.cfg.Fee(sales)
+	return Revenue{
+		Gross:   booking.NewMoney(t.GrossCents, currency),
+		Refunds: booking.NewMoney(t.RefundsCents, currency),
+		Fees:    booking.NewMoney(fees, currency),
+		Net:     booking.NewMoney(sales-fees, currency),
+		Tax:     booking.NewMoney(sales, currency).IncludedTax(taxRateBps),
+	}
+}
+
+func (svc *Service) beginSnapshot(ctx context.Context) (pgx.Tx, error) {
+	tx, err := svc.db.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open TX: %w", err)
+	}
+
+	return tx, nil
+}
+
+func validateEventReport(params *EventReportParams) error {
+	switch params.Bucket {
+	case "":
+		params.Bucket = BucketDay
+	case BucketHour, BucketDay:
+	default:
+		return fmt.Errorf("%w: unknown bucket %q, should be %q or %q", booking.ErrInvalidParams, params.Bucket, BucketHour, BucketDay)
+	}
+
+	if params.From != nil && params.To != nil && !params.From.Before(*params.To) {
+		return fmt.Errorf("%w: report start should be before end", booking.ErrInvalidParams)
+	}
+
+	return nil
+}
+
+// GetEventReport returns inventory, revenue and sales series of an event.
+func (svc *Service) GetEventReport(ctx context.Context, params EventReportParams) (*EventReport, error) {
+	if err := validateEventReport(&params); err != nil {
+		return nil, err
+	}
+
+	tx, err := svc.beginSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	event := &eventInfo{}
+	err = pgxscan.Get(ctx, tx, event, `
+		SELECT id, name, currency, tax_rate_bps, cancelled_at FROM events WHERE id = $1
+	`, params.EventID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, booking.ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	tiers := []*TierReport{}
+	err = pgxscan.Select(ctx, tx, &tiers, `
+		SELECT
+			tt.id AS tier_id,
+			tt.name,
+			tt.price_cents,
+			COUNT(t.id) AS capacity,
+			COUNT(t.id) FILTER (WHERE t.is_sold) AS sold,
+			COUNT(t.id) FILTER (WHERE NOT t.is_sold AND t.hold_expires_at >= now()) AS held,
+			COUNT(t.id) FILTER (
+				WHERE NOT t.is_sold AND (t.hold_expires_at IS NULL OR t.hold_expires_at < now())
+			) AS available,
+			COALESCE((
+				SELECT SUM(ri.quantity * ri.unit_price_cents)
+				FROM reservation_items ri
+				INNER JOIN reservations r ON r.id = ri.reservation_id
+				WHERE ri.tier_id = tt.id AND r.status = $2 AND r.resale_listing_id IS NULL
+			), 0) AS sales_cents
+		FROM ticket_tiers tt
+		LEFT JOIN tickets t ON t.tier_id = tt.id
+		WHERE tt.event_id = $1
+		GROUP BY tt.id
+		ORDER BY tt.price_cents DESC, tt.name
+	`, params.EventID, booking.ReservationPaid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tiers: %w", err)
+	}
+
+	totals := paymentTotals{}
+	err = pgxscan.Get(ctx, tx, &totals, `
+		SELECT
+			COALESCE(SUM(p.settlement_amount), 0)::BIGINT AS gross_cents,
+			COALESCE(SUM(p.settlement_amount) FILTER (WHERE p.refunded_at IS NOT NULL), 0)::BIGINT AS refunds_cents
+		FROM payments p
+		INNER JOIN reservations r ON r.id = p.reservation_id
+		WHERE r.event_id = $1 AND r.resale_listing_id IS NULL
+	`, params.EventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query payments: %w", err)
+	}
+
+	var points []*SalesPoint
+	err = pgxscan.Select(ctx, tx, &points, `
+		SELECT
+			date_trunc($2, p.created_at, 'UTC') AS bucket_start,
+			COALESCE(SUM(items.quantity), 0) AS tickets_sold,
+			SUM(p.settlement_amount)::BIGINT AS gross_cents
+		FROM payments p
+		INNER JOIN reservations r ON r.id = p.reservation_id
+		CROSS JOIN LATERAL (
+			SELECT SUM(ri.quantity) AS quantity FROM reservation_items ri WHERE ri.reservation_id = r.id
+		) items
+		WHERE r.event_id = $1
+			AND r.resale_listing_id IS NULL
+			AND ($3::TIMESTAMPTZ IS NULL OR p.created_at >= $3)
+			AND ($4::TIMESTAMPTZ IS NULL OR p.created_at < $4)
+		GROUP BY bucket_start
+		ORDER BY bucket_start
+	`, params.EventID, string(params.Bucket), params.From, params.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sales: %w", err)
+	}
+
+	sales, err := fillSeries(points, params.Bucket, params.From, params.To)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventReport{
+		EventID:     event.ID,
+		Name:        event.Name,
+		Currency:    event.Currency,
+		CancelledAt: event.CancelledAt,
+		GeneratedAt: time.Now().UTC(),
+		Tiers:       tiers,
+		Revenue:     svc.revenue(totals, event.Currency, event.TaxRateBps),
+		Bucket:      params.Bucket,
+		Sales:       sales,
+	}, nil
+}
+
+// fillSeries adds empty buckets between sales points.
+//
+// Series starts and ends at report range boundaries if they are set, otherwise at the first and last sale.
+func fillSeries(points []*SalesPoint, bucket Bucket, from, to *time.Time) ([]*SalesPoint, error) {
+	step := bucket.Duration()
+	var start, end time.Time
+	switch {
+	case from != nil:
+		start = from.UTC().Truncate(step)
+	case len(points) > 0:
+		start = points[0].Start.UTC()
+	default:
+		return []*SalesPoint{}, nil
+	}
+
+	switch {
+	case to != nil:
+		// To is exclusive, so the bucket which contains it is the last one only if it's not a boundary.
+		end = to.UTC().Add(-time.Nanosecond).Truncate(step)
+	case len(points) > 0:
+		end = points[len(points)-1].Start.UTC()
+	default:
+		end = start
+	}
+
+	if end.Before(start) {
+		return []*SalesPoint{}, nil
+	}
+
+	if count := int(end.Sub(start)/step) + 1; count > maxSeriesPoints {
+		return nil, fmt.Errorf(
+			"%w: report range contains %d buckets, max is %d", booking.ErrInvalidParams, count, maxSeriesPoints,
+		)
+	}
+
+	byStart := make(map[time.Time]*SalesPoint, len(points))
+	for _, p := range points {
+		byStart[p.Start.UTC()] = p
+	}
+
+	result := make([]*SalesPoint, 0, int(end.Sub(start)/step)+1)
+	for t := start; !t.After(end); t = t.Add(step) {
+		p, ok := byStart[t]
+		if !ok {
+			p = &SalesPoint{Start: t}
+		}
+
+		p.Start = t
+		result = append(result, p)
+	}
+
+	return result, nil
+}
+
+type eventSummaryRow struct {
+	eventInfo
+	paymentTotals
+	Capacity  int `db:"capacity"`
+	Sold      int `db:"sold"`
+	Held      int `db:"held"`
+	Available int `db:"available"`
+}
+
+// GetOrganizerReport returns inventory and revenue summary of all organizer events.
+func (svc *Service) GetOrganizerReport(ctx context.Context, organizerID uuid.UUID) (*OrganizerReport, error) {
+	tx, err := svc.beginSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var rows []*eventSummaryRow
+	err = pgxscan.Select(ctx, tx, &rows, `
+		SELECT
+			e.id, e.name, e.currency, e.tax_rate_bps, e.cancelled_at,
+			inventory.capacity, inventory.sold, inventory.held, inventory.available,
+			sales.gross_cents, sales.refunds_cents
+		FROM events e
+		CROSS JOIN LATERAL (
+			SELECT
+				COUNT(*) AS capacity,
+				COUNT(*) FILTER (WHERE t.is_sold) AS sold,
+				COUNT(*) FILTER (WHERE NOT t.is_sold AND t.hold_expires_at >= now()) AS held,
+				COUNT(*) FILTER (
+					WHERE NOT t.is_sold AND (t.hold_expires_at IS NULL OR t.hold_expires_at < now())
+				) AS available
+			FROM tickets t
+			WHERE t.event_id = e.id
+		) inventory
+		CROSS JOIN LATERAL (
+			SELECT
+				COALESCE(SUM(p.settlement_amount), 0)::BIGINT AS gross_cents,
+				COALESCE(SUM(p.settlement_amount) FILTER (WHERE p.refunded_at IS NOT NULL), 0)::BIGINT AS refunds_cents
+			FROM payments p
+			INNER JOIN reservations r ON r.id = p.reservation_id
+			WHERE r.event_id = e.id AND r.resale_listing_id IS NULL
+		) sales
+		WHERE e.organizer_id = $1
+		ORDER BY e.created_at, e.id
+	`, organizerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+
+	report := &OrganizerReport{
+		OrganizerID: organizerID,
+		GeneratedAt: time.Now().UTC(),
+		Events:      make([]*EventSummary, 0, len(rows)),
+		Totals:      []*Revenue{},
+	}
+
+	// Amounts in different currencies can't be summed, so totals are per currency.
+	totals := make(map[booking.Currency]*Revenue)
+	for _, row := range rows {
+		revenue := svc.revenue(row.paymentTotals, row.Currency, row.TaxRateBps)
+		report.Events = append(report.Events, &EventSummary{
+			EventID:     row.ID,
+			Name:        row.Name,
+			Currency:    row.Currency,
+			CancelledAt: row.CancelledAt,
+			Capacity:    row.Capacity,
+			Sold:        row.Sold,
+			Held:        row.Held,
+			Available:   row.Available,
+			Revenue:     revenue,
+		})
+
+		total, ok := totals[row.Currency]
+		if !ok {
+			zero := booking.NewMoney(0, row.Currency)
+			total = &Revenue{Gross: zero, Refunds: zero, Fees: zero, Net: zero, Tax: zero}
+			totals[row.Currency] = total
+			report.Totals = append(report.Totals, total)
+		}
+
+		total.Gross.Amount += revenue.Gross.Amount
+		total.Refunds.Amount += revenue.Refunds.Amount
+		total.Fees.Amount += revenue.Fees.Amount
+		total.Net.Amount += revenue.Net.Amount
+		total.Tax.Amount += revenue.Tax.Amount
+	}
+
+	return report, nil
+}
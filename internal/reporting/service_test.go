@@ -0,0 +1,79 @@
+package reporting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+func TestConfigFee(t *testing.T) {
+	cases := map[string]struct {
+		cfg    Config
+		amount int64
+		want   int64
+	}{
+		"no fee":          {cfg: Config{}, amount: 100_00, want: 0},
+		"flat":            {cfg: Config{FeeBps: 500}, amount: 100_00, want: 5_00},
+		"half rounded up": {cfg: Config{FeeBps: 1000}, amount: 1_05, want: 11},
+		"rounded down":    {cfg: Config{FeeBps: 1000}, amount: 1_04, want: 10},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.want, c.cfg.Fee(c.amount))
+		})
+	}
+}
+
+func TestFillSeries(t *testing.T) {
+	at := func(s string) time.Time {
+		v, err := time.Parse(time.RFC3339, s)
+		require.NoError(t, err)
+		return v
+	}
+
+	ptr := func(s string) *time.Time {
+		v := at(s)
+		return &v
+	}
+
+	points := []*SalesPoint{
+		{Start: at("2025-11-01T10:00:00Z"), TicketsSold: 2, GrossCents: 200},
+		{Start: at("2025-11-01T13:00:00Z"), TicketsSold: 1, GrossCents: 100},
+	}
+
+	t.Run("between sales", func(t *testing.T) {
+		got, err := fillSeries(points, BucketHour, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, got, 4)
+		require.Equal(t, at("2025-11-01T10:00:00Z"), got[0].Start)
+		require.Equal(t, 2, got[0].TicketsSold)
+		require.Zero(t, got[1].TicketsSold)
+		require.Zero(t, got[2].GrossCents)
+		require.Equal(t, at("2025-11-01T13:00:00Z"), got[3].Start)
+		require.Equal(t, 1, got[3].TicketsSold)
+	})
+
+	t.Run("range bounds", func(t *testing.T) {
+		got, err := fillSeries(points, BucketDay, ptr("2025-10-31T15:00:00+02:00"), ptr("2025-11-03T00:00:00Z"))
+		require.NoError(t, err)
+		require.Len(t, got, 3)
+		require.Equal(t, at("2025-10-31T00:00:00Z"), got[0].Start)
+		require.Equal(t, at("2025-11-01T00:00:00Z"), got[1].Start)
+		require.Equal(t, at("2025-11-02T00:00:00Z"), got[2].Start)
+	})
+
+	t.Run("no sales", func(t *testing.T) {
+		got, err := fillSeries(nil, BucketHour, nil, nil)
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+
+	t.Run("too many buckets", func(t *testing.T) {
+		_, err := fillSeries(nil, BucketHour, ptr("2020-01-01T00:00:00Z"), ptr("2025-01-01T00:00:00Z"))
+		require.ErrorIs(t, err, booking.ErrInvalidParams)
+	})
+}
@@ -0,0 +1,114 @@
+package reporting
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+// Bucket is sales time series granularity.
+type Bucket string
+
+const (
+	BucketHour Bucket = "hour"
+	BucketDay  Bucket = "day"
+)
+
+// Duration returns bucket length.
+func (b Bucket) Duration() time.Duration {
+	if b == BucketHour {
+		return time.Hour
+	}
+
+	return 24 * time.Hour
+}
+
+// EventReportParams is event report query. Zero fields are ignored.
+type EventReportParams struct {
+	EventID uuid.UUID
+
+	// Bucket is sales series granularity. Day is used by default.
+	Bucket Bucket
+
+	// From and To limit sales series by payment time. To is exclusive.
+	From *time.Time
+	To   *time.Time
+}
+
+// TierReport is inventory and sales summary of a tier.
+type TierReport struct {
+	TierID     uuid.UUID `json:"tierID" db:"tier_id"`
+	Name       string    `json:"name" db:"name"`
+	PriceCents int       `json:"priceCents" db:"price_cents"`
+	Capacity   int       `json:"capacity" db:"capacity"`
+	Sold       int       `json:"sold" db:"sold"`
+	Held       int       `json:"held" db:"held"`
+	Available  int       `json:"available" db:"available"`
+
+	// SalesCents is sum of locked-in prices of tickets in paid reservations, refunds excluded.
+	SalesCents int64 `json:"salesCents" db:"sales_cents"`
+}
+
+// Revenue is a money summary of primary sales. Resale is not included.
+type Revenue struct {
+	// Gross is sum of all payments.
+	Gross booking.Money `json:"gross"`
+
+	// Refunds is sum of refunded payments.
+	Refunds booking.Money `json:"refunds"`
+
+	// Fees is platform fee withheld from sales net of refunds.
+	Fees booking.Money `json:"fees"`
+
+	// Net is amount due to organizer: gross minus refunds and fees.
+	Net booking.Money `json:"net"`
+
+	// Tax is sales tax included in sales net of refunds.
+	Tax booking.Money `json:"tax"`
+}
+
+// SalesPoint is a sales series bucket.
+type SalesPoint struct {
+	Start       time.Time `json:"start" db:"bucket_start"`
+	TicketsSold int       `json:"ticketsSold" db:"tickets_sold"`
+	GrossCents  int64     `json:"grossCents" db:"gross_cents"`
+}
+
+type EventReport struct {
+	EventID     uuid.UUID        `json:"eventID"`
+	Name        string           `json:"name"`
+	Currency    booking.Currency `json:"currency"`
+	CancelledAt *time.Time       `json:"cancelledAt,omitempty"`
+	GeneratedAt time.Time        `json:"generatedAt"`
+
+	Tiers   []*TierReport `json:"tiers"`
+	Revenue Revenue       `json:"revenue"`
+
+	// Bucket is sales series granularity. Empty buckets are included.
+	Bucket Bucket        `json:"bucket"`
+	Sales  []*SalesPoint `json:"sales"`
+}
+
+// EventSummary is a totals row of an event in organizer report.
+type EventSummary struct {
+	EventID     uuid.UUID        `json:"eventID"`
+	Name        string           `json:"name"`
+	Currency    booking.Currency `json:"currency"`
+	CancelledAt *time.Time       `json:"cancelledAt,omitempty"`
+	Capacity    int              `json:"capacity"`
+	Sold        int              `json:"sold"`
+	Held        int              `json:"held"`
+	Available   int              `json:"available"`
+	Revenue     Revenue          `json:"revenue"`
+}
+
+type OrganizerReport struct {
+	OrganizerID uuid.UUID       `json:"organizerID"`
+	GeneratedAt time.Time       `json:"generatedAt"`
+	Events      []*EventSummary `json:"events"`
+
+	// Totals is revenue of all events per currency.
+	Totals []*Revenue `json:"totals"`
+}
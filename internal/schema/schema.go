@@ -0,0 +1,117 @@
+// Package schema applies embedded database migrations and checks database schema version.
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+
+	"github.com/x1unix/thoughtly-ticket-booking/migrations"
+)
+
+// VersionMismatchError is returned when database schema version doesn't match the version expected by code.
+type VersionMismatchError struct {
+	Current  int64
+	Expected int64
+
+	// Pending is true if some migrations are not applied.
+	Pending bool
+}
+
+func (err *VersionMismatchError) Error() string {
+	if err.Current > err.Expected {
+		return fmt.Sprintf(
+			"database schema version %d is newer than %d expected by the application, upgrade the application",
+			err.Current, err.Expected,
+		)
+	}
+
+	if err.Current == err.Expected {
+		return fmt.Sprintf(
+			"database schema version %d has unapplied migrations, apply migrations or start with -migrate",
+			err.Current,
+		)
+	}
+
+	return fmt.Sprintf(
+		"database schema version %d is older than %d expected by the application, apply migrations or start with -migrate",
+		err.Current, err.Expected,
+	)
+}
+
+// Migrator applies embedded migrations.
+type Migrator struct {
+	db       *sql.DB
+	provider *goose.Provider
+}
+
+// NewMigrator returns a migrator which uses connections of a pool.
+//
+// Migrations are applied under Postgres advisory lock, so multiple instances can migrate at the same time.
+func NewMigrator(pool *pgxpool.Pool) (*Migrator, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration lock: %w", err)
+	}
+
+	db := stdlib.OpenDBFromPool(pool)
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, migrations.FS, goose.WithSessionLocker(locker))
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	return &Migrator{
+		db:       db,
+		provider: provider,
+	}, nil
+}
+
+// ExpectedVersion returns the latest migration version.
+func (m *Migrator) ExpectedVersion() int64 {
+	sources := m.provider.ListSources()
+	return sources[len(sources)-1].Version
+}
+
+// Migrate applies pending migrations and returns applied migrations.
+func (m *Migrator) Migrate(ctx context.Context) ([]*goose.MigrationResult, error) {
+	results, err := m.provider.Up(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply migrations: %w", err)
+	}
+
+	return results, nil
+}
+
+// Check returns VersionMismatchError if database schema doesn't match migrations embedded into the application.
+func (m *Migrator) Check(ctx context.Context) error {
+	current, expected, err := m.provider.GetVersions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database schema version: %w", err)
+	}
+
+	if current > expected {
+		return &VersionMismatchError{Current: current, Expected: expected}
+	}
+
+	pending, err := m.provider.HasPending(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check pending migrations: %w", err)
+	}
+
+	if pending {
+		return &VersionMismatchError{Current: current, Expected: expected, Pending: true}
+	}
+
+	return nil
+}
+
+// Close releases migrator resources. Pool is not closed.
+func (m *Migrator) Close() error {
+	return m.db.Close()
+}
@@ -0,0 +1,41 @@
+package schema
+
+import (
+	"context"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/migrations"
+)
+
+func TestMigratorExpectedVersion(t *testing.T) {
+	// Pool connects lazily, so database is not needed to load migrations.
+	pool, err := pgxpool.New(context.Background(), "postgres://localhost:1/none")
+	require.NoError(t, err)
+	defer pool.Close()
+
+	m, err := NewMigrator(pool)
+	require.NoError(t, err)
+	defer m.Close()
+
+	files, err := fs.Glob(migrations.FS, "*.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	latest := slices.Max(files)
+	version, err := strconv.ParseInt(strings.SplitN(latest, "_", 2)[0], 10, 64)
+	require.NoError(t, err)
+	require.Equal(t, version, m.ExpectedVersion())
+}
+
+func TestVersionMismatchError(t *testing.T) {
+	require.Contains(t, (&VersionMismatchError{Current: 2, Expected: 1}).Error(), "newer")
+	require.Contains(t, (&VersionMismatchError{Current: 1, Expected: 2}).Error(), "older")
+	require.Contains(t, (&VersionMismatchError{Current: 2, Expected: 2, Pending: true}).Error(), "unapplied")
+}
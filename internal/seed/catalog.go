@@ -0,0 +1,221 @@
+// Package seed generates a deterministic demo catalog and populates database with it.
+package seed
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+// Outcome is a final state of a seeded reservation.
+type Outcome string
+
+const (
+	OutcomePaid    Outcome = "paid"
+	OutcomeHeld    Outcome = "held"
+	OutcomeExpired Outcome = "expired"
+)
+
+// Tiers are ticket tiers of every seeded event, as defined in the task.
+var Tiers = []Tier{
+	{Name: "VIP", PriceCents: 100_00, TicketsCount: 50},
+	{Name: "Front Row", PriceCents: 50_00, TicketsCount: 100},
+	{Name: "GA", PriceCents: 10_00, TicketsCount: 1000},
+}
+
+type Tier struct {
+	Name         string
+	PriceCents   int
+	TicketsCount int
+}
+
+// Venue is a concert venue. Venues are not modelled, so venue is only a part of event name.
+type Venue struct {
+	Name string
+	City string
+}
+
+var venues = []Venue{
+	{Name: "Madison Square Garden", City: "New York"},
+	{Name: "The O2", City: "London"},
+	{Name: "Red Rocks Amphitheatre", City: "Morrison"},
+	{Name: "Hollywood Bowl", City: "Los Angeles"},
+	{Name: "Olympiastadion", City: "Berlin"},
+	{Name: "Budokan", City: "Tokyo"},
+	{Name: "Sydney Opera House", City: "Sydney"},
+}
+
+var artists = []string{
+	"The Midnight Owls", "Neon Harbor", "Velvet Static", "Paper Satellites", "Glass Canyon",
+	"The Lumen Choir", "Echo Park Radio", "Silver Tides", "Northbound Trains", "Moth & Lantern",
+	"Crimson Arcade", "The Quiet Storms", "Solar Drift", "Wild Orchid Trio", "Atlas Parade",
+	"Copper Leaves",
+}
+
+// maxEvents is max number of events in a catalog, each event has a unique artist.
+var maxEvents = len(artists)
+
+// Options are catalog parameters. The same options always produce the same catalog.
+type Options struct {
+	Seed uint64
+
+	// Start is the date of the first event. Events are scheduled every few days after it.
+	Start time.Time
+
+	Events int
+
+	// Reservations is number of reservations per event.
+	Reservations int
+
+	// Users is number of demo users which make reservations.
+	Users int
+}
+
+// Validate checks catalog options.
+func (opts Options) Validate() error {
+	switch {
+	case opts.Events <= 0 || opts.Events > maxEvents:
+		return fmt.Errorf("%w: events count should be between 1 and %d", booking.ErrInvalidParams, maxEvents)
+	case opts.Reservations < 0:
+		return fmt.Errorf("%w: reservations count should not be negative", booking.ErrInvalidParams)
+	case opts.Users <= 0:
+		return fmt.Errorf("%w: users count should be positive", booking.ErrInvalidParams)
+	case opts.Start.IsZero():
+		return fmt.Errorf("%w: start date is required", booking.ErrInvalidParams)
+	}
+
+	return nil
+}
+
+// Catalog is a generated set of events and reservations.
+type Catalog struct {
+	Users  []uuid.UUID
+	Events []*Event
+}
+
+// Event is a seeded event.
+type Event struct {
+	Name  string
+	Venue Venue
+	Date  time.Time
+
+	Reservations []*Reservation
+}
+
+// Reservation is a seeded reservation of a single tier.
+type Reservation struct {
+	// IdempotencyKey is derived from seed, so reservation is created only once.
+	IdempotencyKey uuid.UUID
+	ActorID        uuid.UUID
+	Tier           string
+	Quantity       uint
+	Outcome        Outcome
+}
+
+// namespace is UUID namespace of seeded identifiers.
+var namespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://github.com/x1unix/thoughtly-ticket-booking/seed"))
+
+// id returns UUID derived from seed and name parts.
+func id(seed uint64, parts ...any) uuid.UUID {
+	var sb strings.Builder
+	fmt.Fprint(&sb, seed)
+	for _, p := range parts {
+		fmt.Fprintf(&sb, "/%v", p)
+	}
+
+	return uuid.NewSHA1(namespace, []byte(sb.String()))
+}
+
+// Generate returns a catalog for options.
+func Generate(opts Options) (*Catalog, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	rnd := rand.New(rand.NewPCG(opts.Seed, opts.Seed^0x5eed))
+	catalog := &Catalog{
+		Users:  make([]uuid.UUID, opts.Users),
+		Events: make([]*Event, 0, opts.Events),
+	}
+
+	for i := range catalog.Users {
+		catalog.Users[i] = id(opts.Seed, "user", i)
+	}
+
+	names := make([]string, len(artists))
+	for i, j := range rnd.Perm(len(artists)) {
+		names[i] = artists[j]
+	}
+
+	date := time.Date(opts.Start.Year(), opts.Start.Month(), opts.Start.Day(), 20, 0, 0, 0, time.UTC)
+	for i := range opts.Events {
+		venue := venues[rnd.IntN(len(venues))]
+		event := &Event{
+			Venue: venue,
+			Date:  date,
+			Name:  fmt.Sprintf("%s at %s, %s (%s)", names[i], venue.Name, venue.City, date.Format("Mon, Jan 2 2006")),
+		}
+
+		for j := range opts.Reservations {
+			event.Reservations = append(event.Reservations, &Reservation{
+				IdempotencyKey: id(opts.Seed, "event", i, "reservation", j),
+				ActorID:        catalog.Users[rnd.IntN(len(catalog.Users))],
+				Tier:           pickTier(rnd),
+				Quantity:       uint(1 + rnd.IntN(4)),
+				Outcome:        pickOutcome(rnd),
+			})
+		}
+
+		catalog.Events = append(catalog.Events, event)
+		date = date.AddDate(0, 0, 2+rnd.IntN(6))
+	}
+
+	return catalog, nil
+}
+
+// pickTier picks a tier, cheaper tiers are more popular.
+func pickTier(rnd *rand.Rand) string {
+	switch n := rnd.IntN(10); {
+	case n < 2:
+		return Tiers[0].Name
+	case n < 5:
+		return Tiers[1].Name
+	default:
+		return Tiers[2].Name
+	}
+}
+
+// pickOutcome picks reservation outcome, most reservations are paid.
+func pickOutcome(rnd *rand.Rand) Outcome {
+	switch n := rnd.IntN(10); {
+	case n < 6:
+		return OutcomePaid
+	case n < 8:
+		return OutcomeHeld
+	default:
+		return OutcomeExpired
+	}
+}
+
+// EventParams returns event create params.
+func (e *Event) EventParams() booking.EventCreateParams {
+	params := booking.EventCreateParams{
+		EventName: e.Name,
+		Currency:  booking.DefaultCurrency,
+		Tiers:     make(map[string]booking.CreateTierParams, len(Tiers)),
+	}
+
+	for _, t := range Tiers {
+		params.Tiers[t.Name] = booking.CreateTierParams{
+			PriceCents:   t.PriceCents,
+			TicketsCount: t.TicketsCount,
+		}
+	}
+
+	return params
+}
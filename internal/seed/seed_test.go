@@ -0,0 +1,159 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+var testOptions = Options{
+	Seed:         42,
+	Start:        time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+	Events:       5,
+	Reservations: 10,
+	Users:        8,
+}
+
+func TestGenerate(t *testing.T) {
+	catalog, err := Generate(testOptions)
+	require.NoError(t, err)
+	require.Len(t, catalog.Events, testOptions.Events)
+	require.Len(t, catalog.Users, testOptions.Users)
+
+	again, err := Generate(testOptions)
+	require.NoError(t, err)
+	require.Equal(t, catalog, again)
+
+	other := testOptions
+	other.Seed = 43
+	different, err := Generate(other)
+	require.NoError(t, err)
+	require.NotEqual(t, catalog.Users, different.Users)
+
+	names := make(map[string]bool)
+	for i, e := range catalog.Events {
+		require.False(t, names[e.Name], "duplicate event name %q", e.Name)
+		names[e.Name] = true
+		require.Len(t, e.Reservations, testOptions.Reservations)
+		if i > 0 {
+			require.True(t, e.Date.After(catalog.Events[i-1].Date))
+		}
+	}
+
+	params := catalog.Events[0].EventParams()
+	require.Equal(t, 100_00, params.Tiers["VIP"].PriceCents)
+	require.Equal(t, 50_00, params.Tiers["Front Row"].PriceCents)
+	require.Equal(t, 10_00, params.Tiers["GA"].PriceCents)
+}
+
+func TestOptionsValidate(t *testing.T) {
+	for _, opts := range []Options{
+		{Start: testOptions.Start, Events: 0, Users: 1},
+		{Start: testOptions.Start, Events: maxEvents + 1, Users: 1},
+		{Start: testOptions.Start, Events: 1, Users: 0},
+		{Start: testOptions.Start, Events: 1, Users: 1, Reservations: -1},
+		{Events: 1, Users: 1},
+	} {
+		require.ErrorIs(t, opts.Validate(), booking.ErrInvalidParams, "%+v", opts)
+	}
+}
+
+func TestSeederIsIdempotent(t *testing.T) {
+	catalog, err := Generate(testOptions)
+	require.NoError(t, err)
+
+	svc := newFakeService()
+	seeder := NewSeeder(svc)
+	first, err := seeder.Seed(context.Background(), catalog)
+	require.NoError(t, err)
+	require.Len(t, first.Events, testOptions.Events)
+	for _, e := range first.Events {
+		require.True(t, e.Created)
+		require.Equal(t, testOptions.Reservations, e.Paid+e.Held+e.Expired+e.Skipped)
+	}
+
+	second, err := seeder.Seed(context.Background(), catalog)
+	require.NoError(t, err)
+	require.Len(t, svc.events, testOptions.Events)
+	require.Len(t, svc.reservations, testOptions.Events*testOptions.Reservations)
+	for i, e := range second.Events {
+		require.False(t, e.Created)
+		require.Equal(t, first.Events[i].EventID, e.EventID)
+		require.Equal(t, first.Events[i].Paid, e.Paid)
+		require.Equal(t, first.Events[i].Expired, e.Expired)
+	}
+}
+
+// fakeService is in-memory booking service which tracks reservation statuses.
+type fakeService struct {
+	events       []*booking.Event
+	tiers        map[uuid.UUID][]*booking.TicketTier
+	reservations map[uuid.UUID]booking.ReservationStatus
+	keys         map[uuid.UUID]uuid.UUID
+}
+
+func newFakeService() *fakeService {
+	return &fakeService{
+		tiers:        make(map[uuid.UUID][]*booking.TicketTier),
+		reservations: make(map[uuid.UUID]booking.ReservationStatus),
+		keys:         make(map[uuid.UUID]uuid.UUID),
+	}
+}
+
+func (f *fakeService) GetEvents(context.Context) ([]*booking.Event, error) {
+	return f.events, nil
+}
+
+func (f *fakeService) CreateEvent(_ context.Context, opts booking.EventCreateParams) (*booking.EventCreateResult, error) {
+	result := &booking.EventCreateResult{EventID: uuid.New(), Tiers: make(map[string]uuid.UUID)}
+	f.events = append(f.events, &booking.Event{ID: result.EventID, Name: opts.EventName})
+	for name := range opts.Tiers {
+		tierID := uuid.New()
+		result.Tiers[name] = tierID
+		f.tiers[result.EventID] = append(f.tiers[result.EventID], &booking.TicketTier{TierID: tierID, Name: name})
+	}
+
+	return result, nil
+}
+
+func (f *fakeService) GetTicketTiers(_ context.Context, eventID uuid.UUID, _ booking.Currency) ([]*booking.TicketTier, error) {
+	return f.tiers[eventID], nil
+}
+
+func (f *fakeService) ReserveTickets(_ context.Context, params booking.ReservationParams) (*booking.ReservationResult, error) {
+	if id, ok := f.keys[params.IdempotencyKey]; ok {
+		return &booking.ReservationResult{ReservationID: id}, nil
+	}
+
+	id := uuid.New()
+	f.keys[params.IdempotencyKey] = id
+	f.reservations[id] = booking.ReservationPending
+	return &booking.ReservationResult{ReservationID: id}, nil
+}
+
+func (f *fakeService) PayReservation(_ context.Context, params booking.PaymentParams) (*booking.PaymentResult, error) {
+	if err := f.transition(params.ReservationID, booking.ReservationPaid); err != nil {
+		return nil, err
+	}
+
+	return &booking.PaymentResult{}, nil
+}
+
+func (f *fakeService) ExpireReservation(_ context.Context, reservationID uuid.UUID) error {
+	return f.transition(reservationID, booking.ReservationExpired)
+}
+
+func (f *fakeService) transition(id uuid.UUID, status booking.ReservationStatus) error {
+	if current := f.reservations[id]; current != booking.ReservationPending {
+		return fmt.Errorf("%w: reservation is %s", booking.ErrInvalidStatus, current)
+	}
+
+	f.reservations[id] = status
+	return nil
+}
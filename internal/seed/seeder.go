@@ -0,0 +1,181 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+// BookingService is a subset of booking service used to populate catalog.
+type BookingService interface {
+	GetEvents(ctx context.Context) ([]*booking.Event, error)
+	CreateEvent(ctx context.Context, opts booking.EventCreateParams) (*booking.EventCreateResult, error)
+	GetTicketTiers(ctx context.Context, eventID uuid.UUID, presentment booking.Currency) ([]*booking.TicketTier, error)
+	ReserveTickets(ctx context.Context, params booking.ReservationParams) (*booking.ReservationResult, error)
+	PayReservation(ctx context.Context, params booking.PaymentParams) (*booking.PaymentResult, error)
+	ExpireReservation(ctx context.Context, reservationID uuid.UUID) error
+}
+
+// Result is a seeding summary.
+type Result struct {
+	Users  []uuid.UUID    `json:"users"`
+	Events []*EventResult `json:"events"`
+}
+
+// EventResult is a seeded event.
+type EventResult struct {
+	EventID uuid.UUID `json:"eventID"`
+	Name    string    `json:"name"`
+
+	// Created is false if event already existed.
+	Created bool `json:"created"`
+
+	Paid    int `json:"paid"`
+	Held    int `json:"held"`
+	Expired int `json:"expired"`
+
+	// Skipped is number of reservations which couldn't be created or moved to their outcome,
+	// e.g. because tickets are sold out or hold expired before payment on a previous run.
+	Skipped int `json:"skipped"`
+}
+
+// Seeder populates database with a catalog.
+type Seeder struct {
+	svc BookingService
+}
+
+func NewSeeder(svc BookingService) *Seeder {
+	return &Seeder{
+		svc: svc,
+	}
+}
+
+// Seed creates catalog events and reservations.
+//
+// Seed is idempotent: events are matched by name and reservations by idempotency key,
+// so re-running it with the same catalog doesn't create duplicates.
+// Held reservations of previous runs expire as usual and are not held again.
+func (s *Seeder) Seed(ctx context.Context, catalog *Catalog) (*Result, error) {
+	existing, err := s.svc.GetEvents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	eventIDs := make(map[string]uuid.UUID, len(existing))
+	for _, e := range existing {
+		eventIDs[e.Name] = e.ID
+	}
+
+	result := &Result{
+		Users:  catalog.Users,
+		Events: make([]*EventResult, 0, len(catalog.Events)),
+	}
+
+	for _, e := range catalog.Events {
+		r, err := s.seedEvent(ctx, e, eventIDs)
+		if err != nil {
+			return result, fmt.Errorf("failed to seed event %q: %w", e.Name, err)
+		}
+
+		result.Events = append(result.Events, r)
+	}
+
+	return result, nil
+}
+
+func (s *Seeder) seedEvent(ctx context.Context, e *Event, eventIDs map[string]uuid.UUID) (*EventResult, error) {
+	result := &EventResult{Name: e.Name}
+	tierIDs := make(map[string]uuid.UUID, len(Tiers))
+	if eventID, ok := eventIDs[e.Name]; ok {
+		tiers, err := s.svc.GetTicketTiers(ctx, eventID, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tiers: %w", err)
+		}
+
+		result.EventID = eventID
+		for _, t := range tiers {
+			tierIDs[t.Name] = t.TierID
+		}
+	} else {
+		created, err := s.svc.CreateEvent(ctx, e.EventParams())
+		if err != nil {
+			return nil, err
+		}
+
+		result.EventID = created.EventID
+		result.Created = true
+		tierIDs = created.Tiers
+	}
+
+	for _, r := range e.Reservations {
+		tierID, ok := tierIDs[r.Tier]
+		if !ok {
+			return nil, fmt.Errorf("event has no tier %q", r.Tier)
+		}
+
+		ok, err := s.seedReservation(ctx, result.EventID, tierID, r)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			result.Skipped++
+			continue
+		}
+
+		switch r.Outcome {
+		case OutcomePaid:
+			result.Paid++
+		case OutcomeHeld:
+			result.Held++
+		case OutcomeExpired:
+			result.Expired++
+		}
+	}
+
+	return result, nil
+}
+
+// seedReservation creates reservation and moves it to its outcome. Returns false if reservation was skipped.
+func (s *Seeder) seedReservation(ctx context.Context, eventID, tierID uuid.UUID, r *Reservation) (bool, error) {
+	reservation, err := s.svc.ReserveTickets(ctx, booking.ReservationParams{
+		IdempotencyKey: r.IdempotencyKey,
+		ActorID:        r.ActorID,
+		EventID:        eventID,
+		TicketsCount:   map[uuid.UUID]uint{tierID: r.Quantity},
+	})
+	if err != nil {
+		if booking.IsInsufficientTicketsError(err) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to reserve tickets: %w", err)
+	}
+
+	switch r.Outcome {
+	case OutcomePaid:
+		_, err = s.svc.PayReservation(ctx, booking.PaymentParams{
+			ReservationID: reservation.ReservationID,
+			CardNumber:    booking.KnownFakeCard,
+		})
+	case OutcomeExpired:
+		err = s.svc.ExpireReservation(ctx, reservation.ReservationID)
+	}
+
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, booking.ErrReservationExpired):
+		// Hold expired before payment, e.g. previous run was interrupted.
+		return false, nil
+	case errors.Is(err, booking.ErrInvalidStatus):
+		// Reservation was already moved to its outcome by a previous run.
+		return true, nil
+	default:
+		return false, fmt.Errorf("failed to complete %s reservation: %w", r.Outcome, err)
+	}
+}
@@ -0,0 +1,26 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// requireAdmin rejects admin API requests without a valid bearer token.
+//
+// Admin API is disabled if token is not configured.
+func (srv *Server) requireAdmin(c *fiber.Ctx) error {
+	token := srv.cfg.Admin.Token
+	if token == "" {
+		return fiber.NewError(http.StatusForbidden, "admin API is disabled")
+	}
+
+	got, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+		return fiber.NewError(http.StatusUnauthorized, "invalid admin token")
+	}
+
+	return c.Next()
+}
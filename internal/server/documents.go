@@ -0,0 +1,73 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/documents"
+)
+
+func (srv *Server) handleGetTicketsPDF(c *fiber.Ctx) error {
+	var params reservationIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	meta, err := srv.svc.GetReservationEntries(c.Context(), params.ReservationID)
+	if err != nil {
+		return mapDocumentError(err)
+	}
+
+	tickets, err := srv.svc.GetReservationTickets(c.Context(), params.ReservationID)
+	if err != nil {
+		return mapDocumentError(err)
+	}
+
+	pdf, err := documents.RenderTickets(meta, tickets)
+	if err != nil {
+		return err
+	}
+
+	return sendPDF(c, fmt.Sprintf("tickets-%s.pdf", params.ReservationID), pdf)
+}
+
+func (srv *Server) handleGetReceiptPDF(c *fiber.Ctx) error {
+	var params reservationIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	receipt, err := srv.svc.GetReceipt(c.Context(), params.ReservationID)
+	if err != nil {
+		return mapDocumentError(err)
+	}
+
+	pdf, err := documents.RenderReceipt(receipt)
+	if err != nil {
+		return err
+	}
+
+	return sendPDF(c, fmt.Sprintf("receipt-%s.pdf", params.ReservationID), pdf)
+}
+
+func mapDocumentError(err error) error {
+	if errors.Is(err, booking.ErrNotFound) {
+		return errNotFound("reservation not found")
+	}
+
+	if errors.Is(err, booking.ErrInvalidStatus) {
+		return errConflict(err)
+	}
+
+	return err
+}
+
+func sendPDF(c *fiber.Ctx, fileName string, data []byte) error {
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", fileName))
+	return c.Send(data)
+}
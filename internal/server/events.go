@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/config"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/notify"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/outbox"
+)
+
+// newOutboxSink returns external sink for domain events.
+func newOutboxSink(logger *zap.SugaredLogger, rdb redis.UniversalClient, cfg config.OutboxConfig) (outbox.Sink, error) {
+	switch cfg.Sink {
+	case "log":
+		return outbox.NewLogSink(logger), nil
+	case "redis":
+		return outbox.NewRedisStreamSink(rdb, cfg.Stream, cfg.StreamMaxLen), nil
+	default:
+		return nil, fmt.Errorf("unsupported outbox sink %q", cfg.Sink)
+	}
+}
+
+// eventSink passes relayed events to a consumer function.
+func eventSink(fn func(ctx context.Context, e booking.DomainEvent) error) outbox.Sink {
+	return outbox.SinkFunc(func(ctx context.Context, r outbox.Record) error {
+		e, err := r.Event()
+		if err != nil {
+			return fmt.Errorf("failed to decode event payload: %w", err)
+		}
+
+		return fn(ctx, e)
+	})
+}
+
+// newMailQueue returns customer email queue fed by domain events.
+func newMailQueue(
+	logger *zap.SugaredLogger, cfg config.SMTPConfig, db *pgxpool.Pool, svc *booking.Service,
+) (*notify.Queue, error) {
+	var notifier notify.Notifier = notify.NewLogNotifier(logger)
+	if cfg.Addr != "" {
+		smtpNotifier, err := notify.NewSMTPNotifier(notify.SMTPConfig{
+			Addr:     cfg.Addr,
+			Username: cfg.Username,
+			Password: cfg.Password,
+			From:     cfg.From,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		notifier = smtpNotifier
+	}
+
+	mailer, err := notify.NewMailer(notifier, svc)
+	if err != nil {
+		return nil, err
+	}
+
+	return notify.NewQueue(db, mailer, notify.QueueConfig{
+		MaxAttempts: cfg.MaxAttempts,
+		RetryBase:   cfg.RetryBase,
+		RetryMax:    cfg.RetryMax,
+	}), nil
+}
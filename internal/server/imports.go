@@ -0,0 +1,63 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/importer"
+)
+
+type importQuery struct {
+	// Format is manifest format. Detected by content type if empty.
+	Format string `query:"format"`
+	DryRun bool   `query:"dryRun"`
+}
+
+func (srv *Server) handleImport(c *fiber.Ctx) error {
+	var query importQuery
+	if err := c.QueryParser(&query); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	if query.Format == "" {
+		query.Format = c.Get(fiber.HeaderContentType)
+	}
+
+	format, err := importer.ParseFormat(query.Format)
+	if err != nil {
+		return errBadRequest(err)
+	}
+
+	manifest, err := importer.Parse(bytes.NewReader(c.Body()), format)
+	if err != nil {
+		return importError(c, err)
+	}
+
+	result, err := importer.New(srv.svc).Import(c.Context(), manifest, query.DryRun)
+	if err != nil {
+		return importError(c, err)
+	}
+
+	return c.JSON(result)
+}
+
+// importError reports invalid manifest rows.
+func importError(c *fiber.Ctx, err error) error {
+	var verr *importer.ValidationError
+	if errors.As(err, &verr) {
+		return c.Status(http.StatusBadRequest).JSON(ImportErrorResponse{
+			Error: err.Error(),
+			Rows:  verr.Rows,
+		})
+	}
+
+	if errors.Is(err, booking.ErrInvalidParams) {
+		return errBadRequest(err)
+	}
+
+	return err
+}
@@ -19,6 +19,10 @@ func (srv *Server) handleCreateEvent(c *fiber.Ctx) error {
 
 	rsp, err := srv.svc.CreateEvent(c.Context(), req)
 	if err != nil {
+		if errors.Is(err, booking.ErrInvalidParams) {
+			return errBadRequest(err)
+		}
+
 		return err
 	}
 
@@ -40,18 +44,41 @@ type eventIDRequest struct {
 	EventID uuid.UUID `params:"eventID"`
 }
 
+type listTiersQuery struct {
+	Currency string `query:"currency"`
+}
+
 func (srv *Server) handleListTiersSummary(c *fiber.Ctx) error {
 	var params eventIDRequest
 	if err := c.ParamsParser(&params); err != nil {
 		return fiber.NewError(http.StatusBadRequest, err.Error())
 	}
 
-	items, err := srv.svc.GetTicketTiers(c.Context(), params.EventID)
+	var query listTiersQuery
+	if err := c.QueryParser(&query); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	var currency booking.Currency
+	if query.Currency != "" {
+		cur, err := booking.ParseCurrency(query.Currency)
+		if err != nil {
+			return errBadRequest(err)
+		}
+
+		currency = cur
+	}
+
+	items, err := srv.svc.GetTicketTiers(c.Context(), params.EventID, currency)
 	if err != nil {
 		if errors.Is(err, booking.ErrNotFound) {
 			return errNotFound("event not found")
 		}
 
+		if errors.Is(err, booking.ErrRateNotFound) {
+			return errBadRequest(err)
+		}
+
 		return err
 	}
 
@@ -76,12 +103,25 @@ func (srv *Server) handleReserveTickets(c *fiber.Ctx) error {
 		ActorID:        body.ActorID,
 		EventID:        params.EventID,
 		TicketsCount:   body.TicketsCount,
+		Email:          body.Email,
 	})
 	if err != nil {
 		if booking.IsInsufficientTicketsError(err) {
 			return errBadRequest(err)
 		}
 
+		if errors.Is(err, booking.ErrNotFound) {
+			return errNotFound("event not found")
+		}
+
+		if errors.Is(err, booking.ErrInvalidParams) {
+			return errBadRequest(err)
+		}
+
+		if errors.Is(err, booking.ErrInvalidStatus) {
+			return errConflict(err)
+		}
+
 		return err
 	}
 
@@ -133,12 +173,394 @@ func (srv *Server) handlePayReservation(c *fiber.Ctx) error {
 			return errBadRequest("reservation expired")
 		}
 
+		if errors.Is(err, booking.ErrInvalidParams) || errors.Is(err, booking.ErrRateNotFound) {
+			return errBadRequest(err)
+		}
+
+		if errors.Is(err, booking.ErrInvalidStatus) {
+			return errConflict(err)
+		}
+
+		return err
+	}
+
+	return c.JSON(rsp)
+}
+
+func (srv *Server) handleExtendReservation(c *fiber.Ctx) error {
+	var params reservationIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	rsp, err := srv.svc.ExtendReservation(c.Context(), params.ReservationID)
+	if err != nil {
+		if errors.Is(err, booking.ErrNotFound) {
+			return errNotFound("reservation not found")
+		}
+
+		if errors.Is(err, booking.ErrReservationExpired) {
+			return errBadRequest("reservation expired")
+		}
+
+		if errors.Is(err, booking.ErrInvalidStatus) {
+			return errConflict(err)
+		}
+
 		return err
 	}
 
 	return c.JSON(rsp)
 }
 
+func (srv *Server) handleModifyReservation(c *fiber.Ctx) error {
+	var params reservationIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	var body booking.ReservationModifyParams
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	body.ReservationID = params.ReservationID
+	rsp, err := srv.svc.ModifyReservation(c.Context(), body)
+	if err != nil {
+		if errors.Is(err, booking.ErrNotFound) {
+			return errNotFound("reservation not found")
+		}
+
+		if errors.Is(err, booking.ErrReservationExpired) {
+			return errBadRequest("reservation expired")
+		}
+
+		if booking.IsInsufficientTicketsError(err) || errors.Is(err, booking.ErrInvalidParams) {
+			return errBadRequest(err)
+		}
+
+		if errors.Is(err, booking.ErrInvalidStatus) {
+			return errConflict(err)
+		}
+
+		return err
+	}
+
+	return c.JSON(rsp)
+}
+
+func (srv *Server) handleCancelReservation(c *fiber.Ctx) error {
+	var params reservationIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	err := srv.svc.CancelReservation(c.Context(), params.ReservationID)
+	if err != nil {
+		if errors.Is(err, booking.ErrNotFound) {
+			return errNotFound("reservation not found")
+		}
+
+		if errors.Is(err, booking.ErrInvalidStatus) {
+			return errConflict(err)
+		}
+
+		return err
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+func (srv *Server) handleRefundReservation(c *fiber.Ctx) error {
+	var params reservationIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	rsp, err := srv.svc.RefundReservation(c.Context(), params.ReservationID)
+	if err != nil {
+		if errors.Is(err, booking.ErrNotFound) {
+			return errNotFound("reservation not found")
+		}
+
+		if errors.Is(err, booking.ErrInvalidStatus) {
+			return errConflict(err)
+		}
+
+		return err
+	}
+
+	return c.JSON(rsp)
+}
+
+func (srv *Server) handleGetReservationTickets(c *fiber.Ctx) error {
+	var params reservationIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	items, err := srv.svc.GetReservationTickets(c.Context(), params.ReservationID)
+	if err != nil {
+		if errors.Is(err, booking.ErrNotFound) {
+			return errNotFound("reservation not found")
+		}
+
+		if errors.Is(err, booking.ErrInvalidStatus) {
+			return errConflict(err)
+		}
+
+		return err
+	}
+
+	return sendTickets(c, items)
+}
+
+func sendTickets(c *fiber.Ctx, items []*booking.IssuedTicket) error {
+	tickets := make([]Ticket, 0, len(items))
+	for _, item := range items {
+		qr, err := renderQRCode(item.Code)
+		if err != nil {
+			return err
+		}
+
+		tickets = append(tickets, Ticket{
+			IssuedTicket: item,
+			QR:           qr,
+		})
+	}
+
+	return c.JSON(&ListTicketsResponse{
+		Tickets: tickets,
+	})
+}
+
+type waitlistJoinRequest struct {
+	EventID uuid.UUID `params:"eventID"`
+	TierID  uuid.UUID `params:"tierID"`
+}
+
+func (srv *Server) handleJoinWaitlist(c *fiber.Ctx) error {
+	var params waitlistJoinRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	var body booking.WaitlistJoinParams
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	body.EventID = params.EventID
+	body.TierID = params.TierID
+	rsp, err := srv.svc.JoinWaitlist(c.Context(), body)
+	if err != nil {
+		if errors.Is(err, booking.ErrNotFound) {
+			return errNotFound("tier not found")
+		}
+
+		if errors.Is(err, booking.ErrInvalidParams) {
+			return errBadRequest(err)
+		}
+
+		if errors.Is(err, booking.ErrAlreadyWaitlisted) || errors.Is(err, booking.ErrInvalidStatus) {
+			return errConflict(err)
+		}
+
+		return err
+	}
+
+	return c.JSON(rsp)
+}
+
+func (srv *Server) handleListWaitlistEntries(c *fiber.Ctx) error {
+	var params userIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	items, err := srv.svc.GetWaitlistEntries(c.Context(), params.UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(&ListWaitlistResponse{
+		Entries: items,
+	})
+}
+
+type waitlistEntryIDRequest struct {
+	EntryID uuid.UUID `params:"entryID"`
+}
+
+func (srv *Server) handleLeaveWaitlist(c *fiber.Ctx) error {
+	var params waitlistEntryIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	err := srv.svc.LeaveWaitlist(c.Context(), params.EntryID)
+	if err != nil {
+		if errors.Is(err, booking.ErrNotFound) {
+			return errNotFound("waitlist entry not found")
+		}
+
+		if errors.Is(err, booking.ErrInvalidStatus) {
+			return errConflict(err)
+		}
+
+		return err
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+type tierIDRequest struct {
+	TierID uuid.UUID `params:"tierID"`
+}
+
+func (srv *Server) handleGetTierPricing(c *fiber.Ctx) error {
+	var params tierIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	rsp, err := srv.svc.GetTierPricing(c.Context(), params.TierID)
+	if err != nil {
+		if errors.Is(err, booking.ErrNotFound) {
+			return errNotFound("tier not found")
+		}
+
+		return err
+	}
+
+	return c.JSON(rsp)
+}
+
+func (srv *Server) handleSetTierPricing(c *fiber.Ctx) error {
+	var params tierIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	var body booking.TierPricingParams
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	rsp, err := srv.svc.SetTierPricing(c.Context(), params.TierID, body)
+	if err != nil {
+		if errors.Is(err, booking.ErrNotFound) {
+			return errNotFound("tier not found")
+		}
+
+		if errors.Is(err, booking.ErrInvalidParams) {
+			return errBadRequest(err)
+		}
+
+		return err
+	}
+
+	return c.JSON(rsp)
+}
+
+func (srv *Server) handleGetTierPriceHistory(c *fiber.Ctx) error {
+	var params tierIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	items, err := srv.svc.GetTierPriceHistory(c.Context(), params.TierID)
+	if err != nil {
+		if errors.Is(err, booking.ErrNotFound) {
+			return errNotFound("tier not found")
+		}
+
+		return err
+	}
+
+	return c.JSON(&PriceHistoryResponse{
+		Changes: items,
+	})
+}
+
+func (srv *Server) handleCheckIn(c *fiber.Ctx) error {
+	var body booking.CheckInParams
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	rsp, err := srv.svc.CheckIn(c.Context(), body)
+	if err != nil {
+		if errors.Is(err, booking.ErrInvalidParams) {
+			return errBadRequest(err)
+		}
+
+		return err
+	}
+
+	return c.JSON(rsp)
+}
+
+func (srv *Server) handleCheckInBatch(c *fiber.Ctx) error {
+	var body booking.CheckInBatchParams
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	results, err := srv.svc.CheckInBatch(c.Context(), body)
+	if err != nil {
+		if errors.Is(err, booking.ErrInvalidParams) {
+			return errBadRequest(err)
+		}
+
+		return err
+	}
+
+	return c.JSON(&CheckInBatchResponse{
+		Results: results,
+	})
+}
+
+func (srv *Server) handleGetCheckInStats(c *fiber.Ctx) error {
+	var params eventIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	rsp, err := srv.svc.GetCheckInStats(c.Context(), params.EventID)
+	if err != nil {
+		if errors.Is(err, booking.ErrNotFound) {
+			return errNotFound("event not found")
+		}
+
+		return err
+	}
+
+	return c.JSON(rsp)
+}
+
+func (srv *Server) handleCancelEvent(c *fiber.Ctx) error {
+	var params eventIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	err := srv.svc.CancelEvent(c.Context(), params.EventID)
+	if err != nil {
+		if errors.Is(err, booking.ErrNotFound) {
+			return errNotFound("event not found")
+		}
+
+		if errors.Is(err, booking.ErrInvalidStatus) {
+			return errConflict(err)
+		}
+
+		return err
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
 func errNotFound(msg string) error {
 	return fiber.NewError(http.StatusNotFound, msg)
 }
@@ -146,3 +568,7 @@ func errNotFound(msg string) error {
 func errBadRequest(args ...any) error {
 	return fiber.NewError(http.StatusBadRequest, fmt.Sprint(args...))
 }
+
+func errConflict(args ...any) error {
+	return fiber.NewError(http.StatusConflict, fmt.Sprint(args...))
+}
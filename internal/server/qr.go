@@ -0,0 +1,27 @@
+package server
+
+import (
+	"encoding/base64"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/ticketcode"
+)
+
+// qrImageSize is QR code PNG image size in pixels.
+const qrImageSize = 256
+
+func renderQRCode(code string) (QRCode, error) {
+	png, err := ticketcode.RenderPNG(code, qrImageSize)
+	if err != nil {
+		return QRCode{}, err
+	}
+
+	svg, err := ticketcode.RenderSVG(code)
+	if err != nil {
+		return QRCode{}, err
+	}
+
+	return QRCode{
+		PNG: "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+		SVG: svg,
+	}, nil
+}
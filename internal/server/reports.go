@@ -0,0 +1,174 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/reporting"
+)
+
+type eventReportQuery struct {
+	Bucket string `query:"bucket"`
+	From   string `query:"from"`
+	To     string `query:"to"`
+}
+
+type organizerIDRequest struct {
+	OrganizerID uuid.UUID `params:"organizerID"`
+}
+
+// parseTimeQuery parses optional RFC3339 timestamp query parameter.
+func parseTimeQuery(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %q parameter, RFC3339 timestamp expected: %w", name, err)
+	}
+
+	return &t, nil
+}
+
+func (srv *Server) handleGetEventReport(c *fiber.Ctx) error {
+	var params eventIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	var query eventReportQuery
+	if err := c.QueryParser(&query); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	from, err := parseTimeQuery("from", query.From)
+	if err != nil {
+		return errBadRequest(err)
+	}
+
+	to, err := parseTimeQuery("to", query.To)
+	if err != nil {
+		return errBadRequest(err)
+	}
+
+	report, err := srv.reports.GetEventReport(c.Context(), reporting.EventReportParams{
+		EventID: params.EventID,
+		Bucket:  reporting.Bucket(query.Bucket),
+		From:    from,
+		To:      to,
+	})
+	if err != nil {
+		if errors.Is(err, booking.ErrNotFound) {
+			return errNotFound("event not found")
+		}
+
+		if errors.Is(err, booking.ErrInvalidParams) {
+			return errBadRequest(err)
+		}
+
+		return err
+	}
+
+	return c.JSON(report)
+}
+
+func (srv *Server) handleGetOrganizerReport(c *fiber.Ctx) error {
+	var params organizerIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	report, err := srv.reports.GetOrganizerReport(c.Context(), params.OrganizerID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(report)
+}
+
+// exportTimeout limits export streaming duration.
+const exportTimeout = 30 * time.Minute
+
+type exportRequest struct {
+	EventID uuid.UUID `params:"eventID"`
+	Dataset string    `params:"dataset"`
+}
+
+type exportQuery struct {
+	Format string `query:"format"`
+	Status string `query:"status"`
+	From   string `query:"from"`
+	To     string `query:"to"`
+}
+
+func (srv *Server) handleExport(c *fiber.Ctx) error {
+	var params exportRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	var query exportQuery
+	if err := c.QueryParser(&query); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	from, err := parseTimeQuery("from", query.From)
+	if err != nil {
+		return errBadRequest(err)
+	}
+
+	to, err := parseTimeQuery("to", query.To)
+	if err != nil {
+		return errBadRequest(err)
+	}
+
+	export, err := srv.reports.OpenExport(c.Context(), reporting.ExportParams{
+		EventID: params.EventID,
+		Dataset: reporting.ExportDataset(params.Dataset),
+		Format:  reporting.ExportFormat(query.Format),
+		Status:  query.Status,
+		From:    from,
+		To:      to,
+	})
+	if err != nil {
+		if errors.Is(err, booking.ErrNotFound) {
+			return errNotFound("event not found")
+		}
+
+		if errors.Is(err, booking.ErrInvalidParams) {
+			return errBadRequest(err)
+		}
+
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, export.Format().ContentType())
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", export.FileName()))
+
+	// Writer is called after handler returns, so export outlives request context.
+	// Status is already sent at this point, so failed export can only be reported by truncated response.
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+		defer cancel()
+		defer func() {
+			if err := export.Close(ctx); err != nil {
+				srv.logger.Error(err)
+			}
+		}()
+
+		if err := export.Stream(ctx, w); err != nil {
+			srv.logger.Errorf("export %s failed: %s", export.FileName(), err)
+		}
+	})
+
+	return nil
+}
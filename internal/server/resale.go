@@ -0,0 +1,113 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+type listingIDRequest struct {
+	ListingID uuid.UUID `params:"listingID"`
+}
+
+func (srv *Server) handleListResaleListings(c *fiber.Ctx) error {
+	var params eventIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	listings, err := srv.svc.GetResaleListings(c.Context(), params.EventID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(&ListResaleListingsResponse{
+		Listings: listings,
+	})
+}
+
+func (srv *Server) handleListUserResaleListings(c *fiber.Ctx) error {
+	var params userIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	listings, err := srv.svc.GetUserResaleListings(c.Context(), params.UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(&ListResaleListingsResponse{
+		Listings: listings,
+	})
+}
+
+func (srv *Server) handleCreateResaleListing(c *fiber.Ctx) error {
+	var body booking.ResaleListingParams
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	listing, err := srv.svc.CreateResaleListing(c.Context(), body)
+	if err != nil {
+		return mapTransferError(err, "ticket not found")
+	}
+
+	return c.JSON(listing)
+}
+
+func (srv *Server) handleGetResaleListing(c *fiber.Ctx) error {
+	var params listingIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	listing, err := srv.svc.GetResaleListing(c.Context(), params.ListingID)
+	if err != nil {
+		return mapTransferError(err, "listing not found")
+	}
+
+	return c.JSON(listing)
+}
+
+func (srv *Server) handleReserveResaleListing(c *fiber.Ctx) error {
+	var params listingIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	var body booking.ResaleReserveParams
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	body.ListingID = params.ListingID
+	rsp, err := srv.svc.ReserveResaleListing(c.Context(), body)
+	if err != nil {
+		return mapTransferError(err, "listing not found")
+	}
+
+	return c.JSON(rsp)
+}
+
+func (srv *Server) handleCancelResaleListing(c *fiber.Ctx) error {
+	var params listingIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	var body booking.ResaleCancelParams
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	body.ListingID = params.ListingID
+	if err := srv.svc.CancelResaleListing(c.Context(), body); err != nil {
+		return mapTransferError(err, "listing not found")
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
@@ -16,6 +17,12 @@ import (
 
 	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
 	"github.com/x1unix/thoughtly-ticket-booking/internal/config"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/notify"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/outbox"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/reporting"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/schema"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/ticketcode"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/webhook"
 )
 
 const (
@@ -24,12 +31,17 @@ const (
 )
 
 type Server struct {
-	logger *zap.SugaredLogger
-	cfg    *config.Config
-	db     *pgxpool.Pool
-	rdb    redis.UniversalClient
-	svc    *booking.Service
-	app    *fiber.App
+	logger  *zap.SugaredLogger
+	cfg     *config.Config
+	db      *pgxpool.Pool
+	rdb     redis.UniversalClient
+	svc     *booking.Service
+	mail    *notify.Queue
+	relay   *outbox.Relay
+	hooks   *webhook.Service
+	reports *reporting.Service
+	app     *fiber.App
+	addr    net.Addr
 }
 
 func NewServer(ctx context.Context, logger *zap.Logger, cfg *config.Config) (*Server, error) {
@@ -42,20 +54,127 @@ func NewServer(ctx context.Context, logger *zap.Logger, cfg *config.Config) (*Se
 		return nil, fmt.Errorf("failed to conn to db: %w", err)
 	}
 
+	sugar := logger.Sugar()
+	if err := prepareSchema(ctx, sugar, db, cfg.DB.Migrate); err != nil {
+		return nil, err
+	}
+
 	rdb, err := cfg.Redis.NewRedisClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	var rates booking.RateSource = booking.NewDBRates(db)
+	if cfg.FX.RatesFile != "" {
+		rates, err = booking.LoadRatesFile(cfg.FX.RatesFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	codes, err := newCodeSigner(cfg.Tickets)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Resale.FeeBps > 10_000 {
+		return nil, fmt.Errorf("resale fee should not exceed 10000 bps, got %d", cfg.Resale.FeeBps)
+	}
+
+	if cfg.Reporting.FeeBps > 10_000 {
+		return nil, fmt.Errorf("reporting fee should not exceed 10000 bps, got %d", cfg.Reporting.FeeBps)
+	}
+
+	if cfg.Admin.Token == "" {
+		sugar.Warn("admin token is not set, admin API is disabled")
+	}
+
+	svc := booking.NewService(booking.NewPostgresStore(db), rdb, rates, codes, booking.ResalePolicy{
+		PriceCapBps: cfg.Resale.PriceCapBps,
+		FeeBps:      cfg.Resale.FeeBps,
+	})
+	mail, err := newMailQueue(sugar, cfg.SMTP, db, svc)
+	if err != nil {
+		return nil, err
+	}
+
+	sink, err := newOutboxSink(sugar, rdb, cfg.Outbox)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := webhook.NewService(db, webhook.Config{
+		MaxAttempts: cfg.Webhooks.MaxAttempts,
+		RetryBase:   cfg.Webhooks.RetryBase,
+		RetryMax:    cfg.Webhooks.RetryMax,
+		Timeout:     cfg.Webhooks.Timeout,
+
+		AllowPrivateTargets: cfg.Webhooks.AllowPrivateTargets,
+	})
+
+	reports := reporting.NewService(db, reporting.Config{
+		FeeBps: cfg.Reporting.FeeBps,
+	})
+
+	// Webhook deliveries and emails are queued after the external sink.
+	relay := outbox.NewRelay(db, outbox.MultiSink{
+		sink,
+		eventSink(webhooks.Enqueue),
+		eventSink(mail.Enqueue),
+	}, outbox.DefaultBatchSize)
+
 	return &Server{
-		logger: logger.Sugar(),
-		cfg:    cfg,
-		db:     db,
-		rdb:    rdb,
-		svc:    booking.NewService(db, rdb),
+		logger:  sugar,
+		cfg:     cfg,
+		db:      db,
+		rdb:     rdb,
+		svc:     svc,
+		mail:    mail,
+		relay:   relay,
+		hooks:   webhooks,
+		reports: reports,
 	}, nil
 }
 
+// prepareSchema applies pending migrations if enabled and checks that database schema matches the application.
+func prepareSchema(ctx context.Context, logger *zap.SugaredLogger, db *pgxpool.Pool, migrate bool) error {
+	migrator, err := schema.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	defer migrator.Close()
+	if migrate {
+		results, err := migrator.Migrate(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range results {
+			logger.Infof("applied migration %s in %s", r.Source.Path, r.Duration)
+		}
+	}
+
+	return migrator.Check(ctx)
+}
+
+// newCodeSigner returns ticket code signer.
+//
+// Signing key is required, as codes signed with a random key would be invalid after restart
+// and on other instances or door scanners.
+func newCodeSigner(cfg config.TicketsConfig) (*ticketcode.Signer, error) {
+	if cfg.SigningKey == "" {
+		return nil, errors.New("APP_TICKETS_SIGNING_KEY is required")
+	}
+
+	signer, err := ticketcode.NewSigner([]byte(cfg.SigningKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid APP_TICKETS_SIGNING_KEY: %w", err)
+	}
+
+	return signer, nil
+}
+
 func (srv *Server) mountRoutes(app *fiber.App) {
 	// Endpoints for tests
 	app.Post("/api/events", srv.handleCreateEvent)
@@ -69,9 +188,62 @@ func (srv *Server) mountRoutes(app *fiber.App) {
 	app.Post("/api/events/:eventID/reserve", srv.handleReserveTickets)
 	app.Post("/api/reservations/:reservationID/payment", srv.handlePayReservation)
 	app.Get("/api/users/:userID/reservations", srv.handleListReservations)
+	app.Patch("/api/reservations/:reservationID", srv.handleModifyReservation)
+	app.Post("/api/reservations/:reservationID/extend", srv.handleExtendReservation)
+	app.Post("/api/reservations/:reservationID/cancel", srv.handleCancelReservation)
+	app.Post("/api/reservations/:reservationID/refund", srv.handleRefundReservation)
+	app.Get("/api/reservations/:reservationID/tickets", srv.handleGetReservationTickets)
+	app.Get("/api/reservations/:reservationID/tickets.pdf", srv.handleGetTicketsPDF)
+	app.Get("/api/reservations/:reservationID/receipt.pdf", srv.handleGetReceiptPDF)
+	app.Post("/api/events/:eventID/tiers/:tierID/waitlist", srv.handleJoinWaitlist)
+	app.Get("/api/users/:userID/waitlist", srv.handleListWaitlistEntries)
+	app.Delete("/api/waitlist/:entryID", srv.handleLeaveWaitlist)
+	app.Get("/api/users/:userID/tickets", srv.handleListUserTickets)
+	app.Get("/api/tickets/:ticketID/ownership", srv.handleGetTicketOwnership)
+	app.Post("/api/transfers", srv.handleCreateTransfer)
+	app.Get("/api/transfers/:transferID", srv.handleGetTransfer)
+	app.Post("/api/transfers/:transferID/accept", srv.handleAcceptTransfer)
+	app.Post("/api/transfers/:transferID/cancel", srv.handleCancelTransfer)
+	app.Get("/api/users/:userID/transfers", srv.handleListUserTransfers)
+	app.Get("/api/events/:eventID/resale", srv.handleListResaleListings)
+	app.Post("/api/resale/listings", srv.handleCreateResaleListing)
+	app.Get("/api/resale/listings/:listingID", srv.handleGetResaleListing)
+	app.Post("/api/resale/listings/:listingID/reserve", srv.handleReserveResaleListing)
+	app.Post("/api/resale/listings/:listingID/cancel", srv.handleCancelResaleListing)
+	app.Get("/api/users/:userID/resale-listings", srv.handleListUserResaleListings)
+
+	// Venue entry API
+	app.Post("/api/checkin", srv.handleCheckIn)
+	app.Post("/api/checkin/batch", srv.handleCheckInBatch)
+	app.Get("/api/events/:eventID/checkins", srv.handleGetCheckInStats)
+
+	// Admin API
+	admin := app.Group("/api/admin", srv.requireAdmin)
+	admin.Post("/events/:eventID/cancel", srv.handleCancelEvent)
+	admin.Put("/events/:eventID/transfers", srv.handleSetTransfersBlocked)
+	admin.Get("/events/:eventID/report", srv.handleGetEventReport)
+	admin.Get("/organizers/:organizerID/report", srv.handleGetOrganizerReport)
+	admin.Get("/events/:eventID/exports/:dataset", srv.handleExport)
+	admin.Post("/imports", srv.handleImport)
+	admin.Get("/tiers/:tierID/pricing", srv.handleGetTierPricing)
+	admin.Put("/tiers/:tierID/pricing", srv.handleSetTierPricing)
+	admin.Get("/tiers/:tierID/price-history", srv.handleGetTierPriceHistory)
+	admin.Post("/webhooks", srv.handleCreateWebhook)
+	admin.Get("/webhooks", srv.handleListWebhooks)
+	admin.Delete("/webhooks/:subscriptionID", srv.handleDeleteWebhook)
+	admin.Get("/webhook-deliveries", srv.handleListWebhookDeliveries)
+	admin.Post("/webhook-deliveries/:deliveryID/replay", srv.handleReplayWebhookDelivery)
 }
 
-func (srv *Server) Listen(ctx context.Context) {
+// Listen binds HTTP listener and starts serving requests and background workers.
+//
+// Listener is bound synchronously, so the server accepts connections once Listen returns.
+func (srv *Server) Listen(ctx context.Context) error {
+	ln, err := net.Listen("tcp", srv.cfg.HTTP.ListenAddress)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %q: %w", srv.cfg.HTTP.ListenAddress, err)
+	}
+
 	app := fiber.New(fiber.Config{
 		DisableStartupMessage: true,
 		IdleTimeout:           idleTimeout,
@@ -98,10 +270,12 @@ func (srv *Server) Listen(ctx context.Context) {
 	app.Use(fiberRecover.New())
 	srv.mountRoutes(app)
 	srv.app = app
+	srv.addr = ln.Addr()
+	srv.startWorkers(ctx)
 
 	go func() {
-		srv.logger.Infof("listening on %q", srv.cfg.HTTP.ListenAddress)
-		err := app.Listen(srv.cfg.HTTP.ListenAddress)
+		srv.logger.Infof("listening on %q", srv.addr)
+		err := app.Listener(ln)
 		if err != nil {
 			if errors.Is(err, context.Canceled) {
 				return
@@ -110,6 +284,15 @@ func (srv *Server) Listen(ctx context.Context) {
 			srv.logger.Fatal("failed to start HTTP server:", err)
 		}
 	}()
+
+	return nil
+}
+
+// Addr returns address of HTTP listener, e.g. to find a port picked for ":0" address.
+//
+// Returns nil if server is not listening.
+func (srv *Server) Addr() net.Addr {
+	return srv.addr
 }
 
 func (srv *Server) Close() {
@@ -121,9 +304,14 @@ func (srv *Server) Close() {
 	}
 }
 
-func (srv *Server) ListenAndWait(ctx context.Context) {
-	srv.Listen(ctx)
+func (srv *Server) ListenAndWait(ctx context.Context) error {
+	if err := srv.Listen(ctx); err != nil {
+		srv.Close()
+		return err
+	}
+
 	<-ctx.Done()
 	srv.logger.Info("shutting down")
 	srv.Close()
+	return nil
 }
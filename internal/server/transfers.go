@@ -0,0 +1,164 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+type transferIDRequest struct {
+	TransferID uuid.UUID `params:"transferID"`
+}
+
+type ticketIDRequest struct {
+	TicketID uuid.UUID `params:"ticketID"`
+}
+
+// mapTransferError maps transfer errors to HTTP errors.
+func mapTransferError(err error, notFoundMsg string) error {
+	switch {
+	case errors.Is(err, booking.ErrNotFound):
+		return errNotFound(notFoundMsg)
+	case errors.Is(err, booking.ErrInvalidParams):
+		return errBadRequest(err)
+	case errors.Is(err, booking.ErrInvalidStatus):
+		return errConflict(err)
+	default:
+		return err
+	}
+}
+
+func (srv *Server) handleListUserTickets(c *fiber.Ctx) error {
+	var params userIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	items, err := srv.svc.GetUserTickets(c.Context(), params.UserID)
+	if err != nil {
+		return err
+	}
+
+	return sendTickets(c, items)
+}
+
+func (srv *Server) handleGetTicketOwnership(c *fiber.Ctx) error {
+	var params ticketIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	owners, err := srv.svc.GetTicketOwnership(c.Context(), params.TicketID)
+	if err != nil {
+		return mapTransferError(err, "ticket not found")
+	}
+
+	return c.JSON(&OwnershipHistoryResponse{
+		Owners: owners,
+	})
+}
+
+func (srv *Server) handleCreateTransfer(c *fiber.Ctx) error {
+	var body booking.TransferParams
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	transfer, err := srv.svc.CreateTransfer(c.Context(), body)
+	if err != nil {
+		return mapTransferError(err, "ticket not found")
+	}
+
+	return c.JSON(transfer)
+}
+
+func (srv *Server) handleGetTransfer(c *fiber.Ctx) error {
+	var params transferIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	transfer, err := srv.svc.GetTransfer(c.Context(), params.TransferID)
+	if err != nil {
+		return mapTransferError(err, "transfer not found")
+	}
+
+	return c.JSON(transfer)
+}
+
+func (srv *Server) handleAcceptTransfer(c *fiber.Ctx) error {
+	var params transferIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	var body booking.TransferAcceptParams
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	body.TransferID = params.TransferID
+	transfer, err := srv.svc.AcceptTransfer(c.Context(), body)
+	if err != nil {
+		return mapTransferError(err, "transfer not found")
+	}
+
+	return c.JSON(transfer)
+}
+
+func (srv *Server) handleCancelTransfer(c *fiber.Ctx) error {
+	var params transferIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	var body booking.TransferCancelParams
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	body.TransferID = params.TransferID
+	if err := srv.svc.CancelTransfer(c.Context(), body); err != nil {
+		return mapTransferError(err, "transfer not found")
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+func (srv *Server) handleListUserTransfers(c *fiber.Ctx) error {
+	var params userIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	transfers, err := srv.svc.GetUserTransfers(c.Context(), params.UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(&ListTransfersResponse{
+		Transfers: transfers,
+	})
+}
+
+func (srv *Server) handleSetTransfersBlocked(c *fiber.Ctx) error {
+	var params eventIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	var body SetTransfersBlockedRequest
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := srv.svc.SetTransfersBlocked(c.Context(), params.EventID, body.Blocked); err != nil {
+		return mapTransferError(err, "event not found")
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
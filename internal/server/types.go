@@ -4,12 +4,20 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/importer"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/webhook"
 )
 
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
+// ImportErrorResponse is returned when import manifest has invalid rows.
+type ImportErrorResponse struct {
+	Error string               `json:"error"`
+	Rows  []*importer.RowError `json:"rows"`
+}
+
 type ListEventsResponse struct {
 	Events []*booking.Event `json:"events"`
 }
@@ -22,8 +30,65 @@ type ReserveTicketsRequest struct {
 	IdempotencyKey uuid.UUID          `json:"idempotencyKey"`
 	ActorID        uuid.UUID          `json:"actorID"`
 	TicketsCount   map[uuid.UUID]uint `json:"ticketsCount"`
+
+	// Email is optional customer email for notifications.
+	Email string `json:"email,omitempty"`
 }
 
 type ListReservationsResponse struct {
 	Reservations []*booking.ReservationMeta `json:"reservations"`
 }
+
+type PriceHistoryResponse struct {
+	Changes []*booking.PriceChange `json:"changes"`
+}
+
+type ListWaitlistResponse struct {
+	Entries []*booking.WaitlistEntry `json:"entries"`
+}
+
+// QRCode is ticket code QR image.
+type QRCode struct {
+	// PNG is PNG image in data URI format.
+	PNG string `json:"png"`
+
+	// SVG is SVG image markup.
+	SVG string `json:"svg"`
+}
+
+type Ticket struct {
+	*booking.IssuedTicket
+	QR QRCode `json:"qr"`
+}
+
+type ListTicketsResponse struct {
+	Tickets []Ticket `json:"tickets"`
+}
+
+type ListWebhooksResponse struct {
+	Subscriptions []*webhook.Subscription `json:"subscriptions"`
+}
+
+type ListWebhookDeliveriesResponse struct {
+	Deliveries []*webhook.Delivery `json:"deliveries"`
+}
+
+type ListTransfersResponse struct {
+	Transfers []*booking.Transfer `json:"transfers"`
+}
+
+type ListResaleListingsResponse struct {
+	Listings []*booking.ResaleListing `json:"listings"`
+}
+
+type OwnershipHistoryResponse struct {
+	Owners []*booking.OwnershipRecord `json:"owners"`
+}
+
+type SetTransfersBlockedRequest struct {
+	Blocked bool `json:"blocked"`
+}
+
+type CheckInBatchResponse struct {
+	Results []*booking.CheckInResult `json:"results"`
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/webhook"
+)
+
+type subscriptionIDRequest struct {
+	SubscriptionID uuid.UUID `params:"subscriptionID"`
+}
+
+type deliveryIDRequest struct {
+	DeliveryID uuid.UUID `params:"deliveryID"`
+}
+
+func (srv *Server) handleCreateWebhook(c *fiber.Ctx) error {
+	var body webhook.SubscriptionParams
+	if err := c.BodyParser(&body); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	sub, err := srv.hooks.CreateSubscription(c.Context(), body)
+	if err != nil {
+		if errors.Is(err, booking.ErrNotFound) {
+			return errNotFound("event not found")
+		}
+
+		if errors.Is(err, booking.ErrInvalidParams) {
+			return errBadRequest(err)
+		}
+
+		return err
+	}
+
+	return c.JSON(sub)
+}
+
+func (srv *Server) handleListWebhooks(c *fiber.Ctx) error {
+	subs, err := srv.hooks.ListSubscriptions(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(ListWebhooksResponse{
+		Subscriptions: subs,
+	})
+}
+
+func (srv *Server) handleDeleteWebhook(c *fiber.Ctx) error {
+	var params subscriptionIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	err := srv.hooks.DeleteSubscription(c.Context(), params.SubscriptionID)
+	if err != nil {
+		if errors.Is(err, booking.ErrNotFound) {
+			return errNotFound("subscription not found")
+		}
+
+		return err
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+func (srv *Server) handleListWebhookDeliveries(c *fiber.Ctx) error {
+	var filter webhook.DeliveryFilter
+	if err := c.QueryParser(&filter); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	deliveries, err := srv.hooks.ListDeliveries(c.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(ListWebhookDeliveriesResponse{
+		Deliveries: deliveries,
+	})
+}
+
+func (srv *Server) handleReplayWebhookDelivery(c *fiber.Ctx) error {
+	var params deliveryIDRequest
+	if err := c.ParamsParser(&params); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	delivery, err := srv.hooks.ReplayDelivery(c.Context(), params.DeliveryID)
+	if err != nil {
+		if errors.Is(err, booking.ErrNotFound) {
+			return errNotFound("delivery not found")
+		}
+
+		return err
+	}
+
+	return c.JSON(delivery)
+}
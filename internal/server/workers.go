@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/outbox"
+)
+
+const (
+	repriceInterval = 30 * time.Second
+	expireInterval  = 5 * time.Second
+	remindInterval  = 30 * time.Second
+	relayInterval   = 500 * time.Millisecond
+	pruneInterval   = time.Hour
+	webhookInterval = time.Second
+	mailInterval    = time.Second
+	refundInterval  = 10 * time.Second
+
+	// outboxRetention is how long published outbox records are kept for troubleshooting.
+	outboxRetention = 7 * 24 * time.Hour
+)
+
+// startWorkers spawns background maintenance tasks.
+//
+// Workers are stopped when passed context is cancelled.
+func (srv *Server) startWorkers(ctx context.Context) {
+	go srv.runPeriodically(ctx, "reprice tiers", repriceInterval, srv.svc.RepriceTiers)
+	go srv.runPeriodically(ctx, "expire reservations", expireInterval, srv.expireReservations)
+	go srv.runPeriodically(ctx, "remind expiring holds", remindInterval, srv.remindExpiringHolds)
+	go srv.runPeriodically(ctx, "relay outbox", relayInterval, srv.relayOutbox)
+	go srv.runPeriodically(ctx, "prune outbox", pruneInterval, srv.pruneOutbox)
+	go srv.runPeriodically(ctx, "deliver webhooks", webhookInterval, srv.deliverWebhooks)
+	go srv.runPeriodically(ctx, "deliver emails", mailInterval, srv.deliverEmails)
+	go srv.runPeriodically(ctx, "retry refunds", refundInterval, srv.retryRefunds)
+}
+
+func (srv *Server) runPeriodically(ctx context.Context, name string, interval time.Duration, fn func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := fn(ctx); err != nil && ctx.Err() == nil {
+				srv.logger.Errorf("%s: %s", name, err)
+			}
+		}
+	}
+}
+
+func (srv *Server) expireReservations(ctx context.Context) error {
+	n, err := srv.svc.ExpireReservations(ctx)
+	if n > 0 {
+		srv.logger.Debugf("expired %d reservations", n)
+	}
+
+	return err
+}
+
+func (srv *Server) remindExpiringHolds(ctx context.Context) error {
+	n, err := srv.svc.RemindExpiringHolds(ctx)
+	if n > 0 {
+		srv.logger.Debugf("sent %d hold expiration reminders", n)
+	}
+
+	return err
+}
+
+func (srv *Server) relayOutbox(ctx context.Context) error {
+	n, err := srv.relay.Run(ctx)
+	if n > 0 {
+		srv.logger.Debugf("relayed %d outbox records", n)
+	}
+
+	return err
+}
+
+func (srv *Server) pruneOutbox(ctx context.Context) error {
+	n, err := outbox.Prune(ctx, srv.db, outboxRetention)
+	if n > 0 {
+		srv.logger.Debugf("pruned %d outbox records", n)
+	}
+
+	return err
+}
+
+func (srv *Server) deliverWebhooks(ctx context.Context) error {
+	n, err := srv.hooks.DeliverDue(ctx)
+	if n > 0 {
+		srv.logger.Debugf("processed %d webhook deliveries", n)
+	}
+
+	return err
+}
+
+func (srv *Server) deliverEmails(ctx context.Context) error {
+	n, err := srv.mail.DeliverDue(ctx)
+	if n > 0 {
+		srv.logger.Debugf("processed %d email deliveries", n)
+	}
+
+	return err
+}
+
+func (srv *Server) retryRefunds(ctx context.Context) error {
+	n, err := srv.svc.RetryRefunds(ctx)
+	if n > 0 {
+		srv.logger.Debugf("issued %d pending refunds", n)
+	}
+
+	return err
+}
@@ -0,0 +1,92 @@
+// Package ticketcode implements signed ticket codes which can be verified offline by door scanners.
+package ticketcode
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// formatVersion is a first byte of encoded code. Bumped on payload layout change.
+	formatVersion = 1
+
+	// macSize is truncated HMAC-SHA256 size. 128 bits are enough to prevent forgery.
+	macSize = 16
+
+	payloadSize = 1 + 16 + 16
+	codeSize    = payloadSize + macSize
+
+	// MinKeySize is minimal signing key length in bytes.
+	MinKeySize = 32
+)
+
+// ErrInvalidCode is returned when code is malformed or has invalid signature.
+var ErrInvalidCode = errors.New("invalid ticket code")
+
+// Codes are encoded in upper-case base32 without padding,
+// which fits QR alphanumeric mode and keeps QR images small.
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Claims is data embedded into a ticket code.
+type Claims struct {
+	// ID is unique code ID.
+	ID uuid.UUID
+
+	// EventID is event which ticket belongs to.
+	//
+	// Allows scanners to reject tickets of other events without a network call.
+	EventID uuid.UUID
+}
+
+// Signer issues and verifies ticket codes using a shared secret key.
+type Signer struct {
+	key []byte
+}
+
+func NewSigner(key []byte) (*Signer, error) {
+	if len(key) < MinKeySize {
+		return nil, fmt.Errorf("signing key should be at least %d bytes long", MinKeySize)
+	}
+
+	return &Signer{key: key}, nil
+}
+
+// Sign returns signed code for passed claims.
+func (s *Signer) Sign(c Claims) string {
+	buf := make([]byte, 0, codeSize)
+	buf = append(buf, formatVersion)
+	buf = append(buf, c.ID[:]...)
+	buf = append(buf, c.EventID[:]...)
+	buf = append(buf, s.mac(buf)...)
+	return encoding.EncodeToString(buf)
+}
+
+// Verify checks code signature and returns embedded claims.
+func (s *Signer) Verify(code string) (Claims, error) {
+	buf, err := encoding.DecodeString(strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil || len(buf) != codeSize || buf[0] != formatVersion {
+		return Claims{}, ErrInvalidCode
+	}
+
+	payload, sig := buf[:payloadSize], buf[payloadSize:]
+	if !hmac.Equal(sig, s.mac(payload)) {
+		return Claims{}, ErrInvalidCode
+	}
+
+	var c Claims
+	copy(c.ID[:], payload[1:17])
+	copy(c.EventID[:], payload[17:])
+	return c, nil
+}
+
+func (s *Signer) mac(payload []byte) []byte {
+	h := hmac.New(sha256.New, s.key)
+	h.Write(payload)
+	return h.Sum(nil)[:macSize]
+}
@@ -0,0 +1,55 @@
+package ticketcode
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner(t *testing.T) {
+	signer, err := NewSigner([]byte(strings.Repeat("k", MinKeySize)))
+	require.NoError(t, err)
+
+	claims := Claims{
+		ID:      uuid.New(),
+		EventID: uuid.New(),
+	}
+
+	code := signer.Sign(claims)
+	got, err := signer.Verify(code)
+	require.NoError(t, err)
+	require.Equal(t, claims, got)
+
+	// Scanners may submit codes in lower case.
+	got, err = signer.Verify(strings.ToLower(code))
+	require.NoError(t, err)
+	require.Equal(t, claims, got)
+
+	other, err := NewSigner([]byte(strings.Repeat("x", MinKeySize)))
+	require.NoError(t, err)
+	_, err = other.Verify(code)
+	require.ErrorIs(t, err, ErrInvalidCode)
+
+	// Flip a single character in payload.
+	tampered := []byte(code)
+	tampered[5] = 'A' + (tampered[5]-'A'+1)%26
+	_, err = signer.Verify(string(tampered))
+	require.ErrorIs(t, err, ErrInvalidCode)
+
+	for _, bad := range []string{"", "garbage", code[:len(code)-2]} {
+		_, err = signer.Verify(bad)
+		require.ErrorIs(t, err, ErrInvalidCode, bad)
+	}
+
+	_, err = NewSigner([]byte("short"))
+	require.Error(t, err)
+}
+
+func TestRenderSVG(t *testing.T) {
+	svg, err := RenderSVG("HELLO")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(svg, "<svg "))
+	require.True(t, strings.HasSuffix(svg, "</svg>"))
+}
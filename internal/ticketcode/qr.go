@@ -0,0 +1,64 @@
+package ticketcode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// qrRecoveryLevel allows to scan partially damaged or crumpled printouts.
+const qrRecoveryLevel = qrcode.Medium
+
+// RenderPNG returns QR code image of ticket code in PNG format.
+//
+// Size is image width and height in pixels.
+func RenderPNG(code string, size int) ([]byte, error) {
+	png, err := qrcode.Encode(code, qrRecoveryLevel, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render QR code: %w", err)
+	}
+
+	return png, nil
+}
+
+// RenderSVG returns QR code image of ticket code in SVG format.
+//
+// Image is scalable, one QR module is one unit of a view box.
+func RenderSVG(code string) (string, error) {
+	qr, err := qrcode.New(code, qrRecoveryLevel)
+	if err != nil {
+		return "", fmt.Errorf("failed to render QR code: %w", err)
+	}
+
+	bitmap := qr.Bitmap()
+	size := len(bitmap)
+
+	sb := &strings.Builder{}
+	fmt.Fprintf(
+		sb,
+		`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %[1]d %[1]d" shape-rendering="crispEdges">`,
+		size,
+	)
+	fmt.Fprintf(sb, `<rect width="%[1]d" height="%[1]d" fill="#fff"/><path fill="#000" d="`, size)
+
+	// Adjacent dark modules in a row are merged into a single rectangle to keep output small.
+	for y, row := range bitmap {
+		for x := 0; x < len(row); {
+			if !row[x] {
+				x++
+				continue
+			}
+
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+
+			fmt.Fprintf(sb, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+
+	sb.WriteString(`"/></svg>`)
+	return sb.String(), nil
+}
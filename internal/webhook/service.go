@@ -0,0 +1,384 @@
+// Package webhook delivers booking domain events to partner HTTP endpoints.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+const (
+	// deliveryBatchSize is max number of deliveries sent concurrently by a single run.
+	deliveryBatchSize = 20
+
+	// maxListedDeliveries is max number of deliveries returned by list method.
+	maxListedDeliveries = 100
+
+	minSecretLength = 16
+	maxErrorLength  = 512
+
+	pgForeignKeyViolation = "23503"
+)
+
+// Config is webhook delivery config.
+type Config struct {
+	// MaxAttempts is number of failed attempts after which delivery is dead-lettered.
+	MaxAttempts int
+
+	// RetryBase is delay before the first retry. Delay is doubled on each next retry.
+	RetryBase time.Duration
+
+	// RetryMax is max delay between retries.
+	RetryMax time.Duration
+
+	// Timeout is partner endpoint request timeout.
+	Timeout time.Duration
+
+	// AllowPrivateTargets allows endpoints on loopback, private and link-local addresses.
+	// Should be enabled only for development and tests.
+	AllowPrivateTargets bool
+}
+
+// Backoff returns delay before the next attempt after specified number of failed attempts.
+func (cfg Config) Backoff(attempts int) time.Duration {
+	delay := cfg.RetryBase
+	for i := 1; i < attempts && delay < cfg.RetryMax; i++ {
+		delay *= 2
+	}
+
+	return min(delay, cfg.RetryMax)
+}
+
+// Service manages webhook subscriptions and delivers events to partners.
+//
+// Delivery is at-least-once, partners should deduplicate payloads by event ID.
+type Service struct {
+	db     *pgxpool.Pool
+	client *http.Client
+	cfg    Config
+}
+
+func NewService(db *pgxpool.Pool, cfg Config) *Service {
+	return &Service{
+		db:     db,
+		client: newHTTPClient(cfg),
+		cfg:    cfg,
+	}
+}
+
+// CreateSubscription creates a new subscription to event or organizer events.
+func (svc *Service) CreateSubscription(ctx context.Context, params SubscriptionParams) (*Subscription, error) {
+	if err := svc.validateSubscription(ctx, params); err != nil {
+		return nil, err
+	}
+
+	secret := params.Secret
+	if secret == "" {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate secret: %w", err)
+		}
+
+		secret = hex.EncodeToString(key)
+	}
+
+	eventTypes := make([]string, 0, len(params.EventTypes))
+	for _, t := range params.EventTypes {
+		eventTypes = append(eventTypes, string(t))
+	}
+
+	sub := &Subscription{}
+	err := pgxscan.Get(ctx, svc.db, sub, `
+		INSERT INTO webhook_subscriptions (id, url, secret, event_id, organizer_id, event_types)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, url, event_id, organizer_id, event_types, created_at
+	`, uuid.New(), params.URL, secret, params.EventID, params.OrganizerID, eventTypes)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+			return nil, fmt.Errorf("%w: event not found", booking.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+
+	sub.Secret = secret
+	return sub, nil
+}
+
+func (svc *Service) validateSubscription(ctx context.Context, params SubscriptionParams) error {
+	u, err := url.Parse(params.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("%w: url should be absolute HTTP(S) URL", booking.ErrInvalidParams)
+	}
+
+	if !svc.cfg.AllowPrivateTargets {
+		if err := checkHost(ctx, net.DefaultResolver, u.Hostname()); err != nil {
+			return fmt.Errorf("%w: %s", booking.ErrInvalidParams, err)
+		}
+	}
+
+	if (params.EventID == nil) == (params.OrganizerID == nil) {
+		return fmt.Errorf("%w: either event or organizer ID should be set", booking.ErrInvalidParams)
+	}
+
+	if params.Secret != "" && len(params.Secret) < minSecretLength {
+		return fmt.Errorf("%w: secret should be at least %d characters", booking.ErrInvalidParams, minSecretLength)
+	}
+
+	for _, t := range params.EventTypes {
+		if !t.IsValid() {
+			return fmt.Errorf("%w: unknown event type %q", booking.ErrInvalidParams, t)
+		}
+	}
+
+	return nil
+}
+
+// ListSubscriptions returns all subscriptions without secrets.
+func (svc *Service) ListSubscriptions(ctx context.Context) ([]*Subscription, error) {
+	result := []*Subscription{}
+	err := pgxscan.Select(ctx, svc.db, &result, `
+		SELECT id, url, event_id, organizer_id, event_types, created_at
+		FROM webhook_subscriptions
+		ORDER BY created_at
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscriptions: %w", err)
+	}
+
+	return result, nil
+}
+
+// DeleteSubscription removes subscription with all its deliveries.
+func (svc *Service) DeleteSubscription(ctx context.Context, subscriptionID uuid.UUID) error {
+	tag, err := svc.db.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return booking.ErrNotFound
+	}
+
+	return nil
+}
+
+// Enqueue schedules event delivery to all matching subscriptions.
+//
+// Enqueue is idempotent, so repeated calls for the same event don't create duplicate deliveries.
+func (svc *Service) Enqueue(ctx context.Context, e booking.DomainEvent) error {
+	// Customer contact details are not shared with partners.
+	e.Email = ""
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	_, err = svc.db.Exec(ctx, `
+		INSERT INTO webhook_deliveries (id, subscription_id, event_id, event_type, payload)
+		SELECT gen_random_uuid(), s.id, $2, $3, $4
+		FROM webhook_subscriptions s
+		WHERE (s.event_id = $1 OR s.organizer_id = (SELECT organizer_id FROM events WHERE id = $1))
+			AND (cardinality(s.event_types) = 0 OR $3::TEXT = ANY(s.event_types))
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`, e.EventID, e.ID, string(e.Type), payload)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeliveries returns latest deliveries matching filter.
+func (svc *Service) ListDeliveries(ctx context.Context, filter DeliveryFilter) ([]*Delivery, error) {
+	var subscriptionID *uuid.UUID
+	if filter.SubscriptionID != uuid.Nil {
+		subscriptionID = &filter.SubscriptionID
+	}
+
+	var status *DeliveryStatus
+	if filter.Status != "" {
+		status = &filter.Status
+	}
+
+	result := []*Delivery{}
+	err := pgxscan.Select(ctx, svc.db, &result, `
+		SELECT id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+			last_status_code, last_error, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE ($1::UUID IS NULL OR subscription_id = $1) AND ($2::TEXT IS NULL OR status = $2)
+		ORDER BY created_at DESC
+		LIMIT $3
+	`, subscriptionID, status, maxListedDeliveries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deliveries: %w", err)
+	}
+
+	return result, nil
+}
+
+// ReplayDelivery schedules delivery for immediate redelivery with a fresh attempts budget.
+func (svc *Service) ReplayDelivery(ctx context.Context, deliveryID uuid.UUID) (*Delivery, error) {
+	d := &Delivery{}
+	err := pgxscan.Get(ctx, svc.db, d, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = 0, next_attempt_at = now(), delivered_at = NULL
+		WHERE id = $1
+		RETURNING id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+			last_status_code, last_error, created_at, delivered_at
+	`, deliveryID, DeliveryPending)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, booking.ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to replay delivery: %w", err)
+	}
+
+	return d, nil
+}
+
+type dueDelivery struct {
+	ID        uuid.UUID               `db:"id"`
+	EventType booking.DomainEventType `db:"event_type"`
+	Payload   json.RawMessage         `db:"payload"`
+	Attempts  int                     `db:"attempts"`
+	URL       string                  `db:"url"`
+	Secret    string                  `db:"secret"`
+}
+
+// DeliverDue sends due deliveries to partners.
+//
+// Method is called periodically by a background worker. Returns number of processed deliveries.
+func (svc *Service) DeliverDue(ctx context.Context) (int, error) {
+	// Deliveries are leased by moving next attempt time, so concurrent workers don't pick them
+	// and lease expires if worker dies during delivery.
+	lease := 2 * svc.cfg.Timeout
+	var due []dueDelivery
+	err := pgxscan.Select(ctx, svc.db, &due, `
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = now() + $3 * INTERVAL '1 second'
+		FROM webhook_subscriptions s
+		WHERE s.id = d.subscription_id AND d.id IN (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE status = $1 AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			FOR UPDATE SKIP LOCKED
+			LIMIT $2
+		)
+		RETURNING d.id, d.event_type, d.payload, d.attempts, s.url, s.secret
+	`, DeliveryPending, deliveryBatchSize, int(lease.Seconds()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to query due deliveries: %w", err)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, d := range due {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := svc.deliver(ctx, d); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	return len(due), errors.Join(errs...)
+}
+
+func (svc *Service) deliver(ctx context.Context, d dueDelivery) error {
+	statusCode, sendErr := svc.send(ctx, d)
+	var code *int
+	if statusCode != 0 {
+		code = &statusCode
+	}
+
+	if sendErr == nil {
+		_, err := svc.db.Exec(ctx, `
+			UPDATE webhook_deliveries
+			SET status = $2, attempts = attempts + 1, delivered_at = now(), last_status_code = $3, last_error = NULL
+			WHERE id = $1
+		`, d.ID, DeliveryDelivered, code)
+		if err != nil {
+			return fmt.Errorf("failed to update delivery %s: %w", d.ID, err)
+		}
+
+		return nil
+	}
+
+	attempts := d.Attempts + 1
+	status := DeliveryPending
+	if attempts >= svc.cfg.MaxAttempts {
+		status = DeliveryDead
+	}
+
+	errMsg := sendErr.Error()
+	if len(errMsg) > maxErrorLength {
+		errMsg = errMsg[:maxErrorLength]
+	}
+
+	_, err := svc.db.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = now() + $4 * INTERVAL '1 millisecond',
+			last_status_code = $5, last_error = $6
+		WHERE id = $1
+	`, d.ID, status, attempts, svc.cfg.Backoff(attempts).Milliseconds(), code, errMsg)
+	if err != nil {
+		return fmt.Errorf("failed to update delivery %s: %w", d.ID, err)
+	}
+
+	return nil
+}
+
+// send posts signed payload to partner endpoint and returns response status code.
+func (svc *Service) send(ctx context.Context, d dueDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeliveryHeader, d.ID.String())
+	req.Header.Set(EventTypeHeader, string(d.EventType))
+	req.Header.Set(SignatureHeader, Sign(d.Secret, time.Now(), d.Payload))
+
+	rsp, err := svc.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rsp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(rsp.Body, 64<<10))
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		return rsp.StatusCode, fmt.Errorf("unexpected response status %d", rsp.StatusCode)
+	}
+
+	return rsp.StatusCode, nil
+}
@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SignatureHeader contains request timestamp and payload signature in "t=<unix>,v1=<hex>" format.
+	SignatureHeader = "X-Webhook-Signature"
+
+	// DeliveryHeader contains delivery ID. Retries of the same delivery have the same ID.
+	DeliveryHeader = "X-Webhook-Delivery"
+
+	// EventTypeHeader contains domain event type.
+	EventTypeHeader = "X-Webhook-Event"
+
+	signatureVersion = "v1"
+)
+
+// ErrInvalidSignature is returned when webhook signature doesn't match payload.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Sign returns signature header value of a payload.
+//
+// Signature is HMAC-SHA256 of "<unix timestamp>.<payload>" string using subscription secret.
+// Timestamp is signed to prevent replay of captured requests.
+func Sign(secret string, ts time.Time, payload []byte) string {
+	unix := strconv.FormatInt(ts.Unix(), 10)
+	return "t=" + unix + "," + signatureVersion + "=" + computeSignature(secret, unix, payload)
+}
+
+// Verify checks signature header value of a payload.
+//
+// Signatures older than tolerance are rejected. Zero tolerance disables timestamp check.
+func Verify(secret, header string, payload []byte, now time.Time, tolerance time.Duration) error {
+	var unix, sig string
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			unix = v
+		case signatureVersion:
+			sig = v
+		}
+	}
+
+	ts, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil || sig == "" {
+		return fmt.Errorf("%w: malformed header", ErrInvalidSignature)
+	}
+
+	expect := computeSignature(secret, unix, payload)
+	if !hmac.Equal([]byte(sig), []byte(expect)) {
+		return ErrInvalidSignature
+	}
+
+	if tolerance > 0 && now.Sub(time.Unix(ts, 0)).Abs() > tolerance {
+		return fmt.Errorf("%w: timestamp is outside of tolerance", ErrInvalidSignature)
+	}
+
+	return nil
+}
+
+func computeSignature(secret, unix string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte{'.'})
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
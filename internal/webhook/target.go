@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+const dialTimeout = 10 * time.Second
+
+// errForbiddenTarget is returned when endpoint address is not a public unicast address.
+var errForbiddenTarget = errors.New("endpoint address is not public")
+
+// deniedPrefixes are special-purpose ranges which aren't covered by [netip.Addr] checks.
+var deniedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"),  // carrier-grade NAT, often used inside cloud networks
+	netip.MustParsePrefix("198.18.0.0/15"),  // benchmarking
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64, maps to IPv4 space
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local-use NAT64
+}
+
+// isPublicAddr reports whether address can be used as partner endpoint.
+//
+// Loopback, private, link-local (including cloud metadata) and other special addresses are rejected,
+// so subscriptions can't be used to reach internal services.
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || !addr.IsGlobalUnicast() || addr.IsPrivate() || addr.IsLoopback() ||
+		addr.IsLinkLocalUnicast() || addr.IsUnspecified() {
+		return false
+	}
+
+	for _, prefix := range deniedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkHost resolves endpoint host and checks that all its addresses are public.
+func checkHost(ctx context.Context, resolver *net.Resolver, host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !isPublicAddr(addr) {
+			return errForbiddenTarget
+		}
+
+		return nil
+	}
+
+	addrs, err := resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve host %q", host)
+	}
+
+	for _, addr := range addrs {
+		if !isPublicAddr(addr) {
+			return errForbiddenTarget
+		}
+	}
+
+	return nil
+}
+
+// newHTTPClient returns partner endpoint client.
+//
+// Unless private targets are allowed, the dialer refuses connections to non-public addresses,
+// which also covers redirects and hosts re-resolved to a different address after subscription was created.
+func newHTTPClient(cfg Config) *http.Client {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	if !cfg.AllowPrivateTargets {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+
+			if !isPublicAddr(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", errForbiddenTarget, addrPort.Addr())
+			}
+
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: transport,
+	}
+}
@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+// Subscription is partner webhook subscription.
+//
+// Subscription covers either a single event or all events of an organizer.
+type Subscription struct {
+	ID  uuid.UUID `json:"id" db:"id"`
+	URL string    `json:"url" db:"url"`
+
+	// Secret is payload signing key. Returned only on creation.
+	Secret string `json:"secret,omitempty" db:"-"`
+
+	EventID     *uuid.UUID `json:"eventID,omitempty" db:"event_id"`
+	OrganizerID *uuid.UUID `json:"organizerID,omitempty" db:"organizer_id"`
+
+	// EventTypes is a list of delivered event types. Empty list means all types.
+	EventTypes []booking.DomainEventType `json:"eventTypes" db:"event_types"`
+
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+type SubscriptionParams struct {
+	URL string `json:"url"`
+
+	// Secret is optional signing key, at least 16 characters. Random key is generated if empty.
+	Secret string `json:"secret,omitempty"`
+
+	EventID     *uuid.UUID                `json:"eventID,omitempty"`
+	OrganizerID *uuid.UUID                `json:"organizerID,omitempty"`
+	EventTypes  []booking.DomainEventType `json:"eventTypes,omitempty"`
+}
+
+// DeliveryStatus is webhook delivery status.
+type DeliveryStatus string
+
+const (
+	// DeliveryPending means that delivery is waiting for the first or next attempt.
+	DeliveryPending DeliveryStatus = "pending"
+
+	// DeliveryDelivered means that partner accepted the payload.
+	DeliveryDelivered DeliveryStatus = "delivered"
+
+	// DeliveryDead means that all attempts failed. Dead deliveries can be replayed manually.
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+// Delivery is a delivery of a domain event to a subscription.
+type Delivery struct {
+	ID             uuid.UUID               `json:"id" db:"id"`
+	SubscriptionID uuid.UUID               `json:"subscriptionID" db:"subscription_id"`
+	EventID        uuid.UUID               `json:"eventID" db:"event_id"`
+	EventType      booking.DomainEventType `json:"eventType" db:"event_type"`
+	Payload        json.RawMessage         `json:"payload" db:"payload"`
+	Status         DeliveryStatus          `json:"status" db:"status"`
+	Attempts       int                     `json:"attempts" db:"attempts"`
+	NextAttemptAt  time.Time               `json:"nextAttemptAt" db:"next_attempt_at"`
+	LastStatusCode *int                    `json:"lastStatusCode,omitempty" db:"last_status_code"`
+	LastError      *string                 `json:"lastError,omitempty" db:"last_error"`
+	CreatedAt      time.Time               `json:"createdAt" db:"created_at"`
+	DeliveredAt    *time.Time              `json:"deliveredAt,omitempty" db:"delivered_at"`
+}
+
+// DeliveryFilter is deliveries list filter. Zero fields are ignored.
+type DeliveryFilter struct {
+	SubscriptionID uuid.UUID      `query:"subscriptionID"`
+	Status         DeliveryStatus `query:"status"`
+}
@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignature(t *testing.T) {
+	payload := []byte(`{"type":"payment.succeeded"}`)
+	now := time.Unix(1_760_000_000, 0)
+	header := Sign("partner-secret-key", now, payload)
+
+	require.NoError(t, Verify("partner-secret-key", header, payload, now, time.Minute))
+	require.ErrorIs(t, Verify("other-secret-key!!", header, payload, now, time.Minute), ErrInvalidSignature)
+	require.ErrorIs(t, Verify("partner-secret-key", header, []byte(`{}`), now, time.Minute), ErrInvalidSignature)
+	require.ErrorIs(t, Verify("partner-secret-key", "garbage", payload, now, time.Minute), ErrInvalidSignature)
+
+	// Stale signatures are rejected to prevent replays
+	later := now.Add(10 * time.Minute)
+	require.ErrorIs(t, Verify("partner-secret-key", header, payload, later, time.Minute), ErrInvalidSignature)
+	require.NoError(t, Verify("partner-secret-key", header, payload, later, 0))
+}
+
+func TestBackoff(t *testing.T) {
+	cfg := Config{
+		RetryBase: 10 * time.Second,
+		RetryMax:  time.Minute,
+	}
+
+	require.Equal(t, 10*time.Second, cfg.Backoff(1))
+	require.Equal(t, 20*time.Second, cfg.Backoff(2))
+	require.Equal(t, 40*time.Second, cfg.Backoff(3))
+	require.Equal(t, time.Minute, cfg.Backoff(4))
+	require.Equal(t, time.Minute, cfg.Backoff(100))
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	cases := map[string]bool{
+		"93.184.215.14":        true,
+		"2606:4700::1111":      true,
+		"127.0.0.1":            false,
+		"10.1.2.3":             false,
+		"172.16.0.1":           false,
+		"192.168.1.1":          false,
+		"169.254.169.254":      false,
+		"0.0.0.0":              false,
+		"224.0.0.1":            false,
+		"::1":                  false,
+		"fe80::1":              false,
+		"fd00::1":              false,
+		"::ffff:127.0.0.1":     false,
+		"::ffff:93.184.215.14": true,
+		"0.1.2.3":              false,
+		"100.64.0.1":           false,
+		"100.127.255.254":      false,
+		"100.128.0.1":          true,
+		"198.18.0.1":           false,
+		"198.19.255.254":       false,
+		"198.20.0.1":           true,
+		"64:ff9b::a00:1":       false,
+		"64:ff9b::5db8:d70e":   false,
+		"64:ff9b:1::1":         false,
+		"::ffff:100.64.0.1":    false,
+	}
+
+	for addr, want := range cases {
+		require.Equal(t, want, isPublicAddr(netip.MustParseAddr(addr)), addr)
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, checkHost(ctx, net.DefaultResolver, "93.184.215.14"))
+	require.ErrorIs(t, checkHost(ctx, net.DefaultResolver, "169.254.169.254"), errForbiddenTarget)
+	require.ErrorIs(t, checkHost(ctx, net.DefaultResolver, "::1"), errForbiddenTarget)
+	require.ErrorIs(t, checkHost(ctx, net.DefaultResolver, "localhost"), errForbiddenTarget)
+	require.ErrorIs(t, checkHost(ctx, net.DefaultResolver, "100.100.100.200"), errForbiddenTarget)
+	require.ErrorIs(t, checkHost(ctx, net.DefaultResolver, "64:ff9b::a9fe:a9fe"), errForbiddenTarget)
+}
+
+func TestDialForbiddenTarget(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	// Loopback endpoint is refused at dial time, even if it passed validation
+	_, err := newHTTPClient(Config{Timeout: time.Second}).Get(srv.URL)
+	require.ErrorIs(t, err, errForbiddenTarget)
+
+	rsp, err := newHTTPClient(Config{Timeout: time.Second, AllowPrivateTargets: true}).Get(srv.URL)
+	require.NoError(t, err)
+	require.NoError(t, rsp.Body.Close())
+}
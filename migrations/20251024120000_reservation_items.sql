@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Line items captured at reservation time.
+-- Unit price is a snapshot of tier price at the moment tickets were held,
+-- so price changes between reserve and pay don't affect the charged amount.
+CREATE TABLE reservation_items (
+  reservation_id   UUID NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+  tier_id          UUID NOT NULL REFERENCES ticket_tiers(id) ON DELETE RESTRICT,
+  quantity         INTEGER NOT NULL CHECK (quantity > 0),
+  unit_price_cents INTEGER NOT NULL CHECK (unit_price_cents >= 0),
+  PRIMARY KEY (reservation_id, tier_id)
+);
+
+ALTER TABLE reservations
+  ADD COLUMN total_cents INTEGER NOT NULL DEFAULT 0 CHECK (total_cents >= 0);
+
+-- Backfill items for reservations which still hold tickets.
+-- Tickets of paid reservations don't reference reservation anymore, so they can't be restored.
+INSERT INTO reservation_items (reservation_id, tier_id, quantity, unit_price_cents)
+SELECT r.id, tt.id, COUNT(*), tt.price_cents
+FROM reservations r
+JOIN tickets t ON t.hold_token = r.id
+JOIN ticket_tiers tt ON tt.id = t.tier_id
+GROUP BY r.id, tt.id, tt.price_cents;
+
+UPDATE reservations r
+SET total_cents = items.total
+FROM (
+  SELECT reservation_id, SUM(quantity * unit_price_cents) AS total
+  FROM reservation_items
+  GROUP BY reservation_id
+) items
+WHERE items.reservation_id = r.id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE reservations DROP COLUMN IF EXISTS total_cents;
+DROP TABLE IF EXISTS reservation_items;
+-- +goose StatementEnd
@@ -0,0 +1,59 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ticket_tiers.price_cents now holds the current effective price computed by pricing rules
+-- from base price and clamped by floor and ceiling.
+ALTER TABLE ticket_tiers
+  ADD COLUMN base_price_cents INTEGER CHECK (base_price_cents >= 0),
+  ADD COLUMN min_price_cents  INTEGER CHECK (min_price_cents >= 0),
+  ADD COLUMN max_price_cents  INTEGER CHECK (max_price_cents >= 0),
+  ADD CONSTRAINT chk_price_limits CHECK (
+    min_price_cents IS NULL OR max_price_cents IS NULL OR min_price_cents <= max_price_cents
+  );
+
+UPDATE ticket_tiers SET base_price_cents = price_cents;
+ALTER TABLE ticket_tiers ALTER COLUMN base_price_cents SET NOT NULL;
+
+CREATE TABLE pricing_rules (
+  id             UUID PRIMARY KEY DEFAULT uuidv4(),
+  tier_id        UUID NOT NULL REFERENCES ticket_tiers(id) ON DELETE CASCADE,
+  kind           TEXT NOT NULL CHECK (kind IN ('sold_percent', 'time_window')),
+  adjustment_bps INTEGER NOT NULL CHECK (adjustment_bps > -10000),
+  sold_percent   INTEGER CHECK (sold_percent > 0 AND sold_percent <= 100),
+  active_from    TIMESTAMPTZ,
+  active_until   TIMESTAMPTZ,
+  created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT chk_rule_params CHECK (
+    (kind = 'sold_percent' AND sold_percent IS NOT NULL AND active_from IS NULL AND active_until IS NULL)
+    OR (kind = 'time_window' AND sold_percent IS NULL AND (active_from IS NOT NULL OR active_until IS NOT NULL))
+  )
+);
+
+CREATE INDEX idx_pricing_rules_tier
+  ON pricing_rules (tier_id);
+
+CREATE TABLE tier_price_history (
+  id              BIGSERIAL PRIMARY KEY,
+  tier_id         UUID NOT NULL REFERENCES ticket_tiers(id) ON DELETE CASCADE,
+  old_price_cents INTEGER NOT NULL,
+  new_price_cents INTEGER NOT NULL,
+  reason          TEXT NOT NULL,
+  changed_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_tier_price_history_tier
+  ON tier_price_history (tier_id, changed_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS tier_price_history;
+DROP TABLE IF EXISTS pricing_rules;
+
+ALTER TABLE ticket_tiers
+  DROP CONSTRAINT IF EXISTS chk_price_limits,
+  DROP COLUMN IF EXISTS max_price_cents,
+  DROP COLUMN IF EXISTS min_price_cents,
+  DROP COLUMN IF EXISTS base_price_cents;
+-- +goose StatementEnd
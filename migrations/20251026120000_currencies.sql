@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Settlement currency (ISO 4217). Tier prices are denominated in event currency.
+ALTER TABLE events
+  ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE ticket_tiers
+  ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE reservations
+  ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+-- Exchange rates used to convert settlement amounts to presentment currency.
+-- Rate is amount of quote currency major units for one base currency unit.
+CREATE TABLE exchange_rates (
+  base       CHAR(3) NOT NULL,
+  quote      CHAR(3) NOT NULL,
+  rate       NUMERIC(24, 12) NOT NULL CHECK (rate > 0),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (base, quote)
+);
+
+CREATE TABLE payments (
+  tx_id                UUID PRIMARY KEY,
+  reservation_id       UUID NOT NULL REFERENCES reservations(id) ON DELETE RESTRICT,
+  settlement_amount    BIGINT NOT NULL CHECK (settlement_amount >= 0),
+  settlement_currency  CHAR(3) NOT NULL,
+  presentment_amount   BIGINT NOT NULL CHECK (presentment_amount >= 0),
+  presentment_currency CHAR(3) NOT NULL,
+  exchange_rate        NUMERIC(24, 12) NOT NULL,
+  created_at           TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_payments_reservation
+  ON payments (reservation_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS payments;
+DROP TABLE IF EXISTS exchange_rates;
+
+ALTER TABLE reservations DROP COLUMN IF EXISTS currency;
+ALTER TABLE ticket_tiers DROP COLUMN IF EXISTS currency;
+ALTER TABLE events DROP COLUMN IF EXISTS currency;
+-- +goose StatementEnd
@@ -0,0 +1,69 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Reservation lifecycle:
+--   pending -> paid -> refunded
+--   pending -> cancelled
+--   pending -> expired (set by expiry sweeper)
+ALTER TABLE reservations
+  ADD COLUMN status TEXT NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'paid', 'cancelled', 'expired', 'refunded'));
+
+UPDATE reservations SET status = 'paid' WHERE is_paid;
+UPDATE reservations SET status = 'expired' WHERE NOT is_paid AND expires_at < now();
+
+CREATE INDEX idx_reservations_pending_expiry
+  ON reservations (expires_at) WHERE status = 'pending';
+
+-- Reservation which owns a sold ticket. Used to release tickets on refund.
+ALTER TABLE tickets
+  ADD COLUMN reservation_id UUID REFERENCES reservations(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_tickets_reservation
+  ON tickets (reservation_id);
+
+ALTER TABLE payments
+  ADD COLUMN refunded_at TIMESTAMPTZ;
+
+-- Waitlist of sold-out tiers.
+-- Released tickets are offered to waiting actors in FIFO order
+-- as an exclusive hold (pending reservation referenced by offer_reservation_id).
+CREATE TABLE waitlist_entries (
+  id                   UUID PRIMARY KEY,
+  event_id             UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  tier_id              UUID NOT NULL REFERENCES ticket_tiers(id) ON DELETE CASCADE,
+  actor_id             UUID NOT NULL,
+  quantity             INTEGER NOT NULL CHECK (quantity > 0),
+  status               TEXT NOT NULL DEFAULT 'waiting'
+    CHECK (status IN ('waiting', 'offered', 'fulfilled', 'expired', 'cancelled')),
+  offer_reservation_id UUID REFERENCES reservations(id) ON DELETE SET NULL,
+  created_at           TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at           TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_waitlist_queue
+  ON waitlist_entries (tier_id, created_at, id) WHERE status = 'waiting';
+
+CREATE INDEX idx_waitlist_actor
+  ON waitlist_entries (actor_id);
+
+CREATE UNIQUE INDEX idx_waitlist_offer_reservation
+  ON waitlist_entries (offer_reservation_id);
+
+-- Actor can have only one active entry per tier.
+CREATE UNIQUE INDEX idx_waitlist_active_actor
+  ON waitlist_entries (tier_id, actor_id) WHERE status IN ('waiting', 'offered');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS waitlist_entries;
+
+ALTER TABLE payments DROP COLUMN IF EXISTS refunded_at;
+
+DROP INDEX IF EXISTS idx_tickets_reservation;
+ALTER TABLE tickets DROP COLUMN IF EXISTS reservation_id;
+
+DROP INDEX IF EXISTS idx_reservations_pending_expiry;
+ALTER TABLE reservations DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Scannable codes issued for sold tickets.
+-- Code is HMAC-signed and embeds code ID and event ID, so scanners can verify it offline.
+-- Codes are revoked (but kept) on refund, to distinguish revoked codes from forged ones.
+CREATE TABLE ticket_codes (
+  id             UUID PRIMARY KEY,
+  ticket_id      UUID NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+  reservation_id UUID NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+  event_id       UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  code           TEXT NOT NULL UNIQUE,
+  issued_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+  revoked_at     TIMESTAMPTZ
+);
+
+CREATE INDEX idx_ticket_codes_reservation
+  ON ticket_codes (reservation_id);
+
+-- Ticket can have only one valid code.
+CREATE UNIQUE INDEX idx_ticket_codes_active_ticket
+  ON ticket_codes (ticket_id) WHERE revoked_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS ticket_codes;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Check-in state. Earliest scan wins when gates sync offline scans.
+ALTER TABLE ticket_codes
+  ADD COLUMN checked_in_at   TIMESTAMPTZ,
+  ADD COLUMN checked_in_gate TEXT;
+
+CREATE INDEX idx_ticket_codes_event
+  ON ticket_codes (event_id) WHERE revoked_at IS NULL;
+
+-- Audit log of all scans made by door devices, including rejected ones.
+-- scan_id is generated by device and makes offline batch upload idempotent.
+CREATE TABLE checkin_scans (
+  id          BIGSERIAL PRIMARY KEY,
+  scan_id     UUID UNIQUE,
+  code_id     UUID REFERENCES ticket_codes(id) ON DELETE CASCADE,
+  gate_id     TEXT NOT NULL,
+  status      TEXT NOT NULL
+    CHECK (status IN ('accepted', 'duplicate', 'revoked', 'invalid', 'wrong_event')),
+  is_offline  BOOLEAN NOT NULL DEFAULT FALSE,
+  scanned_at  TIMESTAMPTZ NOT NULL,
+  received_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_checkin_scans_code
+  ON checkin_scans (code_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS checkin_scans;
+
+DROP INDEX IF EXISTS idx_ticket_codes_event;
+ALTER TABLE ticket_codes
+  DROP COLUMN IF EXISTS checked_in_gate,
+  DROP COLUMN IF EXISTS checked_in_at;
+-- +goose StatementEnd
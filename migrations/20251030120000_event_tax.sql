@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Sales tax (VAT) rate in basis points. Tier prices are tax-inclusive.
+ALTER TABLE events
+  ADD COLUMN tax_rate_bps INTEGER NOT NULL DEFAULT 0
+    CHECK (tax_rate_bps >= 0 AND tax_rate_bps <= 10000);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events DROP COLUMN IF EXISTS tax_rate_bps;
+-- +goose StatementEnd
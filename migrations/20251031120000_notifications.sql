@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Optional customer email used for notifications.
+ALTER TABLE reservations
+  ADD COLUMN contact_email    TEXT,
+  ADD COLUMN reminder_sent_at TIMESTAMPTZ;
+
+ALTER TABLE events
+  ADD COLUMN cancelled_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events DROP COLUMN IF EXISTS cancelled_at;
+
+ALTER TABLE reservations
+  DROP COLUMN IF EXISTS reminder_sent_at,
+  DROP COLUMN IF EXISTS contact_email;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Domain events written in the same transaction as the state change.
+-- Relay publishes unpublished records in id order and marks them as published.
+-- aggregate_id is reservation ID and defines ordering key for consumers.
+CREATE TABLE outbox (
+  id           BIGSERIAL PRIMARY KEY,
+  event_id     UUID NOT NULL UNIQUE,
+  event_type   TEXT NOT NULL,
+  aggregate_id UUID NOT NULL,
+  payload      JSONB NOT NULL,
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+  published_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_outbox_unpublished
+  ON outbox (id) WHERE published_at IS NULL;
+
+CREATE INDEX idx_outbox_published_at
+  ON outbox (published_at) WHERE published_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outbox;
+-- +goose StatementEnd
@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Optional organizer (promoter) which owns an event.
+ALTER TABLE events
+  ADD COLUMN organizer_id UUID;
+
+CREATE INDEX idx_events_organizer
+  ON events (organizer_id) WHERE organizer_id IS NOT NULL;
+
+-- Partner webhook subscription to a single event or to all events of an organizer.
+-- Empty event_types means all event types.
+CREATE TABLE webhook_subscriptions (
+  id           UUID PRIMARY KEY,
+  url          TEXT NOT NULL,
+  secret       TEXT NOT NULL,
+  event_id     UUID REFERENCES events(id) ON DELETE CASCADE,
+  organizer_id UUID,
+  event_types  TEXT[] NOT NULL DEFAULT '{}',
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT chk_webhook_scope CHECK ((event_id IS NULL) <> (organizer_id IS NULL))
+);
+
+CREATE INDEX idx_webhook_subscriptions_event
+  ON webhook_subscriptions (event_id) WHERE event_id IS NOT NULL;
+
+CREATE INDEX idx_webhook_subscriptions_organizer
+  ON webhook_subscriptions (organizer_id) WHERE organizer_id IS NOT NULL;
+
+-- Delivery of a domain event to a subscription.
+-- Unique key makes enqueue idempotent, as outbox relay may publish the same event more than once.
+CREATE TABLE webhook_deliveries (
+  id               UUID PRIMARY KEY,
+  subscription_id  UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+  event_id         UUID NOT NULL,
+  event_type       TEXT NOT NULL,
+  payload          JSONB NOT NULL,
+  status           TEXT NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'delivered', 'dead')),
+  attempts         INTEGER NOT NULL DEFAULT 0,
+  next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_status_code INTEGER,
+  last_error       TEXT,
+  created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+  delivered_at     TIMESTAMPTZ,
+  UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX idx_webhook_deliveries_due
+  ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
+
+DROP INDEX IF EXISTS idx_events_organizer;
+ALTER TABLE events DROP COLUMN IF EXISTS organizer_id;
+-- +goose StatementEnd
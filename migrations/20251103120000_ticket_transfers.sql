@@ -0,0 +1,77 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Organizer can forbid ticket transfers for an event.
+ALTER TABLE events
+  ADD COLUMN transfers_blocked BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Ticket owner is bound to a code. Transfer revokes owner's code and issues a new one for recipient,
+-- so codes of a ticket form its ownership history.
+ALTER TABLE ticket_codes
+  ADD COLUMN owner_id    UUID,
+  ADD COLUMN transfer_id UUID;
+
+UPDATE ticket_codes c
+SET owner_id = r.actor_id
+FROM reservations r
+WHERE r.id = c.reservation_id;
+
+ALTER TABLE ticket_codes
+  ALTER COLUMN owner_id SET NOT NULL;
+
+CREATE INDEX idx_ticket_codes_owner
+  ON ticket_codes (owner_id) WHERE revoked_at IS NULL;
+
+CREATE INDEX idx_ticket_codes_ticket
+  ON ticket_codes (ticket_id);
+
+-- Transfer of tickets initiated by owner. Recipient is addressed either by actor ID or by email.
+CREATE TABLE ticket_transfers (
+  id            UUID PRIMARY KEY,
+  event_id      UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  from_actor_id UUID NOT NULL,
+  to_actor_id   UUID,
+  to_email      TEXT,
+  status        TEXT NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'accepted', 'cancelled')),
+  accepted_by   UUID,
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+  resolved_at   TIMESTAMPTZ,
+
+  CONSTRAINT chk_transfer_recipient CHECK ((to_actor_id IS NULL) <> (to_email IS NULL))
+);
+
+CREATE INDEX idx_ticket_transfers_from
+  ON ticket_transfers (from_actor_id);
+
+CREATE INDEX idx_ticket_transfers_to
+  ON ticket_transfers (to_actor_id) WHERE to_actor_id IS NOT NULL;
+
+CREATE TABLE ticket_transfer_items (
+  transfer_id UUID NOT NULL REFERENCES ticket_transfers(id) ON DELETE CASCADE,
+  ticket_id   UUID NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+  PRIMARY KEY (transfer_id, ticket_id)
+);
+
+CREATE INDEX idx_ticket_transfer_items_ticket
+  ON ticket_transfer_items (ticket_id);
+
+ALTER TABLE ticket_codes
+  ADD CONSTRAINT fk_ticket_codes_transfer FOREIGN KEY (transfer_id) REFERENCES ticket_transfers(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE ticket_codes DROP CONSTRAINT IF EXISTS fk_ticket_codes_transfer;
+
+DROP TABLE IF EXISTS ticket_transfer_items;
+DROP TABLE IF EXISTS ticket_transfers;
+
+DROP INDEX IF EXISTS idx_ticket_codes_ticket;
+DROP INDEX IF EXISTS idx_ticket_codes_owner;
+ALTER TABLE ticket_codes
+  DROP COLUMN IF EXISTS transfer_id,
+  DROP COLUMN IF EXISTS owner_id;
+
+ALTER TABLE events DROP COLUMN IF EXISTS transfers_blocked;
+-- +goose StatementEnd
@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Fan-to-fan resale listing of paid tickets of a single tier.
+-- Listing is held by a buyer reservation the same way as tickets (hold_token, hold_expires_at),
+-- so it's hidden from other buyers while the hold is active.
+CREATE TABLE resale_listings (
+  id                   UUID PRIMARY KEY,
+  event_id             UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  tier_id              UUID NOT NULL REFERENCES ticket_tiers(id) ON DELETE CASCADE,
+  seller_id            UUID NOT NULL,
+  currency             CHAR(3) NOT NULL,
+  price_cents          INTEGER NOT NULL CHECK (price_cents > 0),
+  face_value_cents     INTEGER NOT NULL CHECK (face_value_cents >= 0),
+  status               TEXT NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'sold', 'cancelled')),
+  hold_token           UUID,
+  hold_expires_at      TIMESTAMPTZ,
+  buyer_reservation_id UUID REFERENCES reservations(id) ON DELETE SET NULL,
+  fee_cents            INTEGER,
+  payout_cents         INTEGER,
+  payout_tx_id         UUID,
+  created_at           TIMESTAMPTZ NOT NULL DEFAULT now(),
+  resolved_at          TIMESTAMPTZ
+);
+
+CREATE INDEX idx_resale_listings_event
+  ON resale_listings (event_id, price_cents) WHERE status = 'active';
+
+CREATE INDEX idx_resale_listings_seller
+  ON resale_listings (seller_id);
+
+CREATE INDEX idx_resale_listings_hold
+  ON resale_listings (hold_token) WHERE hold_token IS NOT NULL;
+
+CREATE TABLE resale_listing_items (
+  listing_id UUID NOT NULL REFERENCES resale_listings(id) ON DELETE CASCADE,
+  ticket_id  UUID NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+  PRIMARY KEY (listing_id, ticket_id)
+);
+
+CREATE INDEX idx_resale_listing_items_ticket
+  ON resale_listing_items (ticket_id);
+
+-- Reservation made by a resale buyer. Such reservation holds a listing instead of inventory tickets.
+ALTER TABLE reservations
+  ADD COLUMN resale_listing_id UUID REFERENCES resale_listings(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE reservations DROP COLUMN IF EXISTS resale_listing_id;
+
+DROP TABLE IF EXISTS resale_listing_items;
+DROP TABLE IF EXISTS resale_listings;
+-- +goose StatementEnd
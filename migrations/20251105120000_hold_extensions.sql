@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Reservation hold duration of an event. Holds can be extended by the same duration.
+ALTER TABLE events
+  ADD COLUMN hold_ttl_seconds INTEGER NOT NULL DEFAULT 900
+    CHECK (hold_ttl_seconds > 0);
+
+-- Number of times reservation hold was extended.
+ALTER TABLE reservations
+  ADD COLUMN extensions_count INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE reservations DROP COLUMN IF EXISTS extensions_count;
+ALTER TABLE events DROP COLUMN IF EXISTS hold_ttl_seconds;
+-- +goose StatementEnd
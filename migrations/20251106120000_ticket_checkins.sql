@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Check-in state belongs to a ticket rather than to a code, so a ticket admitted under
+-- a code which was reissued later (transfer or resale) can't be admitted again under the new code.
+-- State is reset when ticket is refunded and returned to inventory.
+ALTER TABLE tickets
+  ADD COLUMN checked_in_at   TIMESTAMPTZ,
+  ADD COLUMN checked_in_gate TEXT;
+
+UPDATE tickets t
+SET checked_in_at = c.checked_in_at, checked_in_gate = c.checked_in_gate
+FROM ticket_codes c
+WHERE c.ticket_id = t.id AND c.revoked_at IS NULL AND c.checked_in_at IS NOT NULL;
+
+-- Reissued code was revoked because ticket got a new owner, it's still valid for scans made before revocation.
+ALTER TABLE ticket_codes
+  ADD COLUMN reissued BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Reissue revokes the old code and issues a new one in the same transaction.
+UPDATE ticket_codes c
+SET reissued = TRUE
+WHERE c.revoked_at IS NOT NULL AND EXISTS (
+  SELECT 1 FROM ticket_codes n WHERE n.ticket_id = c.ticket_id AND n.issued_at = c.revoked_at
+);
+
+ALTER TABLE ticket_codes
+  DROP COLUMN checked_in_at,
+  DROP COLUMN checked_in_gate;
+
+-- Accepted scan is superseded when an earlier offline scan of the same ticket is uploaded.
+ALTER TABLE checkin_scans
+  DROP CONSTRAINT checkin_scans_status_check,
+  ADD CONSTRAINT checkin_scans_status_check
+    CHECK (status IN ('accepted', 'superseded', 'duplicate', 'revoked', 'invalid', 'wrong_event'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE checkin_scans SET status = 'duplicate' WHERE status = 'superseded';
+
+ALTER TABLE checkin_scans
+  DROP CONSTRAINT checkin_scans_status_check,
+  ADD CONSTRAINT checkin_scans_status_check
+    CHECK (status IN ('accepted', 'duplicate', 'revoked', 'invalid', 'wrong_event'));
+
+ALTER TABLE ticket_codes
+  ADD COLUMN checked_in_at   TIMESTAMPTZ,
+  ADD COLUMN checked_in_gate TEXT,
+  DROP COLUMN IF EXISTS reissued;
+
+UPDATE ticket_codes c
+SET checked_in_at = t.checked_in_at, checked_in_gate = t.checked_in_gate
+FROM tickets t
+WHERE c.ticket_id = t.id AND c.revoked_at IS NULL;
+
+ALTER TABLE tickets
+  DROP COLUMN IF EXISTS checked_in_gate,
+  DROP COLUMN IF EXISTS checked_in_at;
+-- +goose StatementEnd
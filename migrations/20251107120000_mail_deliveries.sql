@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Customer email queued from a relayed domain event.
+-- Event ID is the key, so enqueue is idempotent when outbox relay publishes the same event more than once.
+CREATE TABLE mail_deliveries (
+  event_id        UUID PRIMARY KEY,
+  event_type      TEXT NOT NULL,
+  payload         JSONB NOT NULL,
+  status          TEXT NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'delivered', 'dead')),
+  attempts        INTEGER NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_error      TEXT,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+  delivered_at    TIMESTAMPTZ
+);
+
+CREATE INDEX idx_mail_deliveries_due
+  ON mail_deliveries (next_attempt_at) WHERE status = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS mail_deliveries;
+-- +goose StatementEnd
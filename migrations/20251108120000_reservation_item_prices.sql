@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Tickets added to a pending reservation keep the price locked when they were held,
+-- so a tier may have several lines with different unit prices.
+ALTER TABLE reservation_items
+  DROP CONSTRAINT reservation_items_pkey,
+  ADD PRIMARY KEY (reservation_id, tier_id, unit_price_cents);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- Lines of the same tier are merged at the weighted average price, rounded down.
+CREATE TEMPORARY TABLE merged_reservation_items ON COMMIT DROP AS
+SELECT reservation_id, tier_id, SUM(quantity) AS quantity, SUM(quantity * unit_price_cents) / SUM(quantity) AS unit_price_cents
+FROM reservation_items
+GROUP BY reservation_id, tier_id
+HAVING COUNT(*) > 1;
+
+DELETE FROM reservation_items ri
+USING merged_reservation_items m
+WHERE ri.reservation_id = m.reservation_id AND ri.tier_id = m.tier_id;
+
+INSERT INTO reservation_items (reservation_id, tier_id, quantity, unit_price_cents)
+SELECT reservation_id, tier_id, quantity, unit_price_cents FROM merged_reservation_items;
+
+ALTER TABLE reservation_items
+  DROP CONSTRAINT reservation_items_pkey,
+  ADD PRIMARY KEY (reservation_id, tier_id);
+-- +goose StatementEnd
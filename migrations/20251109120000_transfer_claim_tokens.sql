@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Transfer addressed by email is claimed by a secret token sent only to that email.
+ALTER TABLE ticket_transfers
+  ADD COLUMN claim_token TEXT;
+
+-- Recipients of pending email transfers never received a token, so senders should transfer tickets again.
+UPDATE ticket_transfers
+SET status = 'cancelled', resolved_at = now()
+WHERE status = 'pending' AND to_email IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE ticket_transfers DROP COLUMN IF EXISTS claim_token;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Refund is committed before payment provider is called.
+-- Refunds which were not confirmed by provider stay here and are retried.
+CREATE TABLE pending_refunds (
+  tx_id           UUID PRIMARY KEY REFERENCES payments(tx_id) ON DELETE RESTRICT,
+  reservation_id  UUID NOT NULL REFERENCES reservations(id) ON DELETE RESTRICT,
+  attempts        INT NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_error      TEXT,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_pending_refunds_due
+  ON pending_refunds (next_attempt_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS pending_refunds;
+-- +goose StatementEnd
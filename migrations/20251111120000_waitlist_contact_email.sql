@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Optional email used to notify actor about waitlist offer. Copied to offer reservation.
+ALTER TABLE waitlist_entries
+  ADD COLUMN contact_email TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE waitlist_entries DROP COLUMN IF EXISTS contact_email;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+// Package migrations contains database schema migrations.
+//
+// Migrations are embedded into binaries, so they can be applied without the repository checkout.
+package migrations
+
+import "embed"
+
+// FS contains goose SQL migrations.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,296 @@
+package tests
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/server"
+)
+
+// buyTickets reserves and pays tickets of a single tier and returns issued tickets.
+func buyTickets(t *testing.T, eventID, tierID uuid.UUID, count uint) (uuid.UUID, []server.Ticket) {
+	t.Helper()
+	return buyTicketsAs(t, uuid.New(), eventID, tierID, count)
+}
+
+// buyTicketsAs buys tickets on behalf of an actor.
+func buyTicketsAs(t *testing.T, actorID, eventID, tierID uuid.UUID, count uint) (uuid.UUID, []server.Ticket) {
+	t.Helper()
+	rsp, err := client.ReserveTickets(eventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        actorID,
+		TicketsCount:   map[uuid.UUID]uint{tierID: count},
+	})
+	require.NoError(t, err)
+
+	_, err = client.PayReservation(rsp.ReservationID, booking.PaymentParams{
+		ReservationID: rsp.ReservationID,
+		CardNumber:    booking.KnownFakeCard,
+	})
+	require.NoError(t, err)
+
+	tickets, err := client.GetReservationTickets(rsp.ReservationID)
+	require.NoError(t, err)
+	require.Len(t, tickets.Tickets, int(count))
+	return rsp.ReservationID, tickets.Tickets
+}
+
+func TestCheckIn(t *testing.T) {
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("CheckInTest-%v", time.Now().UnixNano()),
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   10_00,
+				TicketsCount: 10,
+			},
+		},
+	})
+
+	eventID := createRsp.EventID
+	reservationID, tickets := buyTickets(t, eventID, createRsp.Tiers["GA"], 2)
+
+	result := client.CheckIn(t, booking.CheckInParams{
+		Code:    tickets[0].Code,
+		GateID:  "north-1",
+		EventID: eventID,
+	})
+	require.Equal(t, booking.CheckInAccepted, result.Status)
+	require.Equal(t, tickets[0].TicketID, *result.TicketID)
+	require.Equal(t, "GA", result.TierName)
+
+	// Second scan at another gate is a duplicate which refers to the first check-in
+	result = client.CheckIn(t, booking.CheckInParams{
+		Code:   tickets[0].Code,
+		GateID: "south-1",
+	})
+	require.Equal(t, booking.CheckInDuplicate, result.Status)
+	require.Equal(t, "north-1", result.GateID)
+
+	// Ticket of other event
+	result = client.CheckIn(t, booking.CheckInParams{
+		Code:    tickets[1].Code,
+		GateID:  "north-1",
+		EventID: uuid.New(),
+	})
+	require.Equal(t, booking.CheckInWrongEvent, result.Status)
+
+	// Forged code
+	forged := []byte(tickets[1].Code)
+	forged[3] ^= 1
+	result = client.CheckIn(t, booking.CheckInParams{
+		Code:   string(forged),
+		GateID: "north-1",
+	})
+	require.Equal(t, booking.CheckInInvalid, result.Status)
+
+	stats := client.GetCheckInStats(t, eventID)
+	require.Equal(t, 2, stats.Issued)
+	require.Equal(t, 1, stats.CheckedIn)
+	require.Equal(t, map[string]int{"north-1": 1}, stats.Gates)
+
+	// Refunded tickets are revoked
+	_, err := client.RefundReservation(reservationID)
+	require.NoError(t, err)
+
+	result = client.CheckIn(t, booking.CheckInParams{
+		Code:   tickets[1].Code,
+		GateID: "north-1",
+	})
+	require.Equal(t, booking.CheckInRevoked, result.Status)
+}
+
+func TestCheckInOfflineSync(t *testing.T) {
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("CheckInSyncTest-%v", time.Now().UnixNano()),
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   10_00,
+				TicketsCount: 10,
+			},
+		},
+	})
+
+	eventID := createRsp.EventID
+	_, tickets := buyTickets(t, eventID, createRsp.Tiers["GA"], 2)
+
+	// Online gate admits the ticket
+	result := client.CheckIn(t, booking.CheckInParams{
+		Code:   tickets[0].Code,
+		GateID: "online",
+	})
+	require.Equal(t, booking.CheckInAccepted, result.Status)
+
+	// Offline gate admitted the same ticket earlier, so its scan wins.
+	// Second ticket was admitted twice by offline gate.
+	now := time.Now()
+	batch := booking.CheckInBatchParams{
+		GateID:  "offline",
+		EventID: eventID,
+		Scans: []booking.OfflineScan{
+			{ScanID: uuid.New(), Code: tickets[1].Code, ScannedAt: now.Add(-time.Minute)},
+			{ScanID: uuid.New(), Code: tickets[0].Code, ScannedAt: now.Add(-time.Hour)},
+			{ScanID: uuid.New(), Code: tickets[1].Code, ScannedAt: now.Add(-2 * time.Minute)},
+		},
+	}
+
+	rsp := client.CheckInBatch(t, batch)
+	require.Len(t, rsp.Results, 3)
+	require.Equal(t, booking.CheckInDuplicate, rsp.Results[0].Status)
+	require.Equal(t, booking.CheckInAccepted, rsp.Results[1].Status)
+	require.Equal(t, booking.CheckInAccepted, rsp.Results[2].Status)
+	for i, r := range rsp.Results {
+		require.Equal(t, batch.Scans[i].ScanID, *r.ScanID)
+		require.Equal(t, "offline", r.GateID)
+	}
+
+	// Repeated upload returns the same results
+	retry := client.CheckInBatch(t, batch)
+	for i, r := range retry.Results {
+		require.Equal(t, rsp.Results[i].Status, r.Status)
+	}
+
+	// Online scan is reported as duplicate of the earlier offline one
+	result = client.CheckIn(t, booking.CheckInParams{
+		Code:   tickets[0].Code,
+		GateID: "online",
+	})
+	require.Equal(t, booking.CheckInDuplicate, result.Status)
+	require.Equal(t, "offline", result.GateID)
+
+	stats := client.GetCheckInStats(t, eventID)
+	require.Equal(t, 2, stats.CheckedIn)
+	require.Equal(t, map[string]int{"offline": 2}, stats.Gates)
+}
+
+func TestCheckInConcurrentUploads(t *testing.T) {
+	const uploads = 20
+
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("CheckInConcurrentTest-%v", time.Now().UnixNano()),
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   10_00,
+				TicketsCount: 10,
+			},
+		},
+	})
+
+	eventID := createRsp.EventID
+	_, tickets := buyTickets(t, eventID, createRsp.Tiers["GA"], 1)
+
+	// Gate retries the same batch while previous uploads are still in flight.
+	batch := booking.CheckInBatchParams{
+		GateID:  "offline",
+		EventID: eventID,
+		Scans: []booking.OfflineScan{
+			{ScanID: uuid.New(), Code: tickets[0].Code, ScannedAt: time.Now().Add(-time.Minute)},
+		},
+	}
+
+	var (
+		wg      sync.WaitGroup
+		results = make([]*booking.CheckInResult, uploads)
+	)
+	for i := range uploads {
+		wg.Go(func() {
+			rsp, err := client.PostCheckInBatch(batch)
+			if err != nil {
+				t.Errorf("upload %d failed: %s", i, err)
+				return
+			}
+
+			results[i] = rsp.Results[0]
+		})
+	}
+
+	wg.Wait()
+	for i, r := range results {
+		require.NotNil(t, r, "upload %d", i)
+		require.Equal(t, booking.CheckInAccepted, r.Status, "upload %d", i)
+		require.Equal(t, batch.Scans[0].ScanID, *r.ScanID)
+	}
+
+	var scans int
+	err := db.QueryRow(
+		t.Context(), `SELECT COUNT(*) FROM checkin_scans WHERE scan_id = $1`, batch.Scans[0].ScanID,
+	).Scan(&scans)
+	require.NoError(t, err)
+	require.Equal(t, 1, scans)
+	require.Equal(t, 1, client.GetCheckInStats(t, eventID).CheckedIn)
+}
+
+func TestCheckInReissuedCode(t *testing.T) {
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("CheckInReissueTest-%v", time.Now().UnixNano()),
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   10_00,
+				TicketsCount: 10,
+			},
+		},
+	})
+
+	eventID := createRsp.EventID
+	ownerID, recipientID := uuid.New(), uuid.New()
+	_, tickets := buyTicketsAs(t, ownerID, eventID, createRsp.Tiers["GA"], 1)
+	oldCode := tickets[0].Code
+	scannedBeforeTransfer := time.Now().Add(-time.Minute)
+
+	transfer, err := client.CreateTransfer(booking.TransferParams{
+		ActorID:   ownerID,
+		TicketIDs: []uuid.UUID{tickets[0].TicketID},
+		ToActorID: &recipientID,
+	})
+	require.NoError(t, err)
+	_, err = client.AcceptTransfer(transfer.ID, booking.TransferAcceptParams{ActorID: recipientID})
+	require.NoError(t, err)
+
+	received := client.GetUserTickets(t, recipientID).Tickets
+	require.Len(t, received, 1)
+
+	result := client.CheckIn(t, booking.CheckInParams{Code: received[0].Code, GateID: "online"})
+	require.Equal(t, booking.CheckInAccepted, result.Status)
+
+	// Offline gate admitted the ticket under its old code before transfer
+	rsp := client.CheckInBatch(t, booking.CheckInBatchParams{
+		GateID:  "offline",
+		EventID: eventID,
+		Scans: []booking.OfflineScan{
+			{ScanID: uuid.New(), Code: oldCode, ScannedAt: scannedBeforeTransfer},
+			{ScanID: uuid.New(), Code: oldCode, ScannedAt: time.Now()},
+		},
+	})
+	require.Len(t, rsp.Results, 2)
+	require.Equal(t, booking.CheckInAccepted, rsp.Results[0].Status)
+	require.Equal(t, booking.CheckInRevoked, rsp.Results[1].Status)
+
+	result = client.CheckIn(t, booking.CheckInParams{Code: received[0].Code, GateID: "online"})
+	require.Equal(t, booking.CheckInDuplicate, result.Status)
+	require.Equal(t, "offline", result.GateID)
+
+	stats := client.GetCheckInStats(t, eventID)
+	require.Equal(t, 1, stats.CheckedIn)
+	require.Equal(t, map[string]int{"offline": 1}, stats.Gates)
+
+	// Scans log keeps only one accepted scan of the ticket
+	var statuses []booking.CheckInStatus
+	err = db.QueryRow(t.Context(), `
+		SELECT array_agg(s.status ORDER BY s.id)
+		FROM checkin_scans s
+		INNER JOIN ticket_codes c ON c.id = s.code_id
+		WHERE c.ticket_id = $1
+	`, tickets[0].TicketID).Scan(&statuses)
+	require.NoError(t, err)
+	require.Equal(t, []booking.CheckInStatus{
+		booking.CheckInSuperseded,
+		booking.CheckInAccepted,
+		booking.CheckInRevoked,
+		booking.CheckInDuplicate,
+	}, statuses)
+}
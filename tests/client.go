@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -14,12 +16,18 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/importer"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/reporting"
 	"github.com/x1unix/thoughtly-ticket-booking/internal/server"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/webhook"
 )
 
 type Client struct {
 	addr       string
 	httpClient *http.Client
+
+	// adminToken is bearer token sent with requests, if set.
+	adminToken string
 }
 
 func NewClient(addr string) (*Client, error) {
@@ -62,29 +70,90 @@ func (c *Client) WaitForServer(retryCount int, interval time.Duration) error {
 	return lastErr
 }
 
+// WithHTTPClient returns a copy of client which uses a specified HTTP client.
+func (c *Client) WithHTTPClient(httpClient *http.Client) *Client {
+	return &Client{
+		addr:       c.addr,
+		httpClient: httpClient,
+		adminToken: c.adminToken,
+	}
+}
+
+// WithAdminToken returns a copy of client which authorizes requests with admin token.
+func (c *Client) WithAdminToken(token string) *Client {
+	return &Client{
+		addr:       c.addr,
+		httpClient: c.httpClient,
+		adminToken: token,
+	}
+}
+
 func (c *Client) CreateEvent(t *testing.T, body booking.EventCreateParams) *booking.EventCreateResult {
 	t.Helper()
-	req, err := c.newJSONRequest("/api/events", body)
+	rsp, err := c.PostEvent(body)
 	require.NoError(t, err)
+	return rsp
+}
+
+func (c *Client) PostEvent(body booking.EventCreateParams) (*booking.EventCreateResult, error) {
+	req, err := c.newJSONRequest("/api/events", body)
+	if err != nil {
+		return nil, err
+	}
 
 	rsp := &booking.EventCreateResult{}
-	require.NoError(t, c.doRequest(req, rsp))
-	return rsp
+	if err := c.doRequest(req, rsp); err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
 }
 
 func (c *Client) GetEvents(t *testing.T) *server.ListEventsResponse {
 	t.Helper()
-	req, err := c.newGetRequest("/api/events")
+	rsp, err := c.ListEvents()
 	require.NoError(t, err)
+	return rsp
+}
+
+func (c *Client) ListEvents() (*server.ListEventsResponse, error) {
+	req, err := c.newGetRequest("/api/events")
+	if err != nil {
+		return nil, err
+	}
 
 	rsp := &server.ListEventsResponse{}
-	require.NoError(t, c.doRequest(req, rsp))
-	return rsp
+	if err := c.doRequest(req, rsp); err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
 }
 
 func (c *Client) GetTicketTiers(t *testing.T, eventID uuid.UUID) *server.ListTiersResponse {
 	t.Helper()
+	rsp, err := c.ListTicketTiers(eventID)
+	require.NoError(t, err)
+	return rsp
+}
+
+func (c *Client) ListTicketTiers(eventID uuid.UUID) (*server.ListTiersResponse, error) {
 	req, err := c.newGetRequest("/api/events/", eventID.String(), "/tiers")
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &server.ListTiersResponse{}
+	if err := c.doRequest(req, rsp); err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
+}
+
+func (c *Client) GetTicketTiersInCurrency(t *testing.T, eventID uuid.UUID, currency booking.Currency) *server.ListTiersResponse {
+	t.Helper()
+	req, err := c.newGetRequest("/api/events/", eventID.String(), "/tiers?currency=", string(currency))
 	require.NoError(t, err)
 
 	rsp := &server.ListTiersResponse{}
@@ -132,6 +201,440 @@ func (c *Client) PayReservation(reservationID uuid.UUID, params booking.PaymentP
 	return rsp, nil
 }
 
+func (c *Client) GetTierPriceHistory(t *testing.T, tierID uuid.UUID) *server.PriceHistoryResponse {
+	t.Helper()
+	req, err := c.newGetRequest("/api/admin/tiers/", tierID.String(), "/price-history")
+	require.NoError(t, err)
+
+	rsp := &server.PriceHistoryResponse{}
+	require.NoError(t, c.doRequest(req, rsp))
+	return rsp
+}
+
+func (c *Client) SetTierPricing(t *testing.T, tierID uuid.UUID, params booking.TierPricingParams) *booking.TierPricing {
+	t.Helper()
+	req, err := c.newJSONRequest(fmt.Sprintf("/api/admin/tiers/%s/pricing", tierID), params)
+	require.NoError(t, err)
+
+	req.Method = http.MethodPut
+	rsp := &booking.TierPricing{}
+	require.NoError(t, c.doRequest(req, rsp))
+	return rsp
+}
+
+func (c *Client) ModifyReservation(reservationID uuid.UUID, params booking.ReservationModifyParams) (*booking.ReservationMeta, error) {
+	rpath := fmt.Sprintf("/api/reservations/%s", reservationID)
+	req, err := c.newJSONRequest(rpath, params)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Method = http.MethodPatch
+	rsp := &booking.ReservationMeta{}
+	if err := c.doRequest(req, rsp); err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
+}
+
+func (c *Client) ExtendReservation(reservationID uuid.UUID) (*booking.ExtensionResult, error) {
+	rpath := fmt.Sprintf("/api/reservations/%s/extend", reservationID)
+	req, err := c.newJSONRequest(rpath, struct{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &booking.ExtensionResult{}
+	if err := c.doRequest(req, rsp); err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
+}
+
+func (c *Client) CancelReservation(reservationID uuid.UUID) error {
+	rpath := fmt.Sprintf("/api/reservations/%s/cancel", reservationID)
+	req, err := c.newJSONRequest(rpath, struct{}{})
+	if err != nil {
+		return err
+	}
+
+	return c.doRequest(req, nil)
+}
+
+func (c *Client) RefundReservation(reservationID uuid.UUID) (*booking.RefundResult, error) {
+	rpath := fmt.Sprintf("/api/reservations/%s/refund", reservationID)
+	req, err := c.newJSONRequest(rpath, struct{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &booking.RefundResult{}
+	if err := c.doRequest(req, rsp); err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
+}
+
+func (c *Client) GetReservationTickets(reservationID uuid.UUID) (*server.ListTicketsResponse, error) {
+	req, err := c.newGetRequest("/api/reservations/", reservationID.String(), "/tickets")
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &server.ListTicketsResponse{}
+	if err := c.doRequest(req, rsp); err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
+}
+
+func (c *Client) CheckIn(t *testing.T, params booking.CheckInParams) *booking.CheckInResult {
+	t.Helper()
+	req, err := c.newJSONRequest("/api/checkin", params)
+	require.NoError(t, err)
+
+	rsp := &booking.CheckInResult{}
+	require.NoError(t, c.doRequest(req, rsp))
+	return rsp
+}
+
+func (c *Client) CheckInBatch(t *testing.T, params booking.CheckInBatchParams) *server.CheckInBatchResponse {
+	t.Helper()
+	rsp, err := c.PostCheckInBatch(params)
+	require.NoError(t, err)
+	return rsp
+}
+
+func (c *Client) PostCheckInBatch(params booking.CheckInBatchParams) (*server.CheckInBatchResponse, error) {
+	req, err := c.newJSONRequest("/api/checkin/batch", params)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &server.CheckInBatchResponse{}
+	if err := c.doRequest(req, rsp); err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
+}
+
+func (c *Client) GetCheckInStats(t *testing.T, eventID uuid.UUID) *booking.CheckInStats {
+	t.Helper()
+	req, err := c.newGetRequest("/api/events/", eventID.String(), "/checkins")
+	require.NoError(t, err)
+
+	rsp := &booking.CheckInStats{}
+	require.NoError(t, c.doRequest(req, rsp))
+	return rsp
+}
+
+// GetReservationDocument downloads reservation PDF document, e.g. "tickets.pdf" or "receipt.pdf".
+func (c *Client) GetReservationDocument(reservationID uuid.UUID, name string) ([]byte, error) {
+	req, err := c.newGetRequest("/api/reservations/", reservationID.String(), "/", name)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp, err := c.send(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q: failed to send request: %w", req.Method, req.URL, err)
+	}
+
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return nil, tryReadError(req, rsp)
+	}
+
+	if ctype := rsp.Header.Get("Content-Type"); ctype != "application/pdf" {
+		return nil, fmt.Errorf("%q: unexpected content type %q", req.URL, ctype)
+	}
+
+	return io.ReadAll(rsp.Body)
+}
+
+func (c *Client) JoinWaitlist(eventID, tierID uuid.UUID, params booking.WaitlistJoinParams) (*booking.WaitlistEntry, error) {
+	rpath := fmt.Sprintf("/api/events/%s/tiers/%s/waitlist", eventID, tierID)
+	req, err := c.newJSONRequest(rpath, params)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &booking.WaitlistEntry{}
+	if err := c.doRequest(req, rsp); err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
+}
+
+func (c *Client) GetWaitlistEntries(t *testing.T, userID uuid.UUID) *server.ListWaitlistResponse {
+	t.Helper()
+	req, err := c.newGetRequest("/api/users/", userID.String(), "/waitlist")
+	require.NoError(t, err)
+
+	rsp := &server.ListWaitlistResponse{}
+	require.NoError(t, c.doRequest(req, rsp))
+	return rsp
+}
+
+func (c *Client) LeaveWaitlist(entryID uuid.UUID) error {
+	uri := fmt.Sprintf("%s/api/waitlist/%s", c.addr, entryID)
+	req, err := http.NewRequest(http.MethodDelete, uri, nil)
+	if err != nil {
+		return fmt.Errorf("%s %q: cannot create request: %w", http.MethodDelete, uri, err)
+	}
+
+	return c.doRequest(req, nil)
+}
+
+func (c *Client) CancelEvent(eventID uuid.UUID) error {
+	rpath := fmt.Sprintf("/api/admin/events/%s/cancel", eventID)
+	req, err := c.newJSONRequest(rpath, struct{}{})
+	if err != nil {
+		return err
+	}
+
+	return c.doRequest(req, nil)
+}
+
+func (c *Client) CreateWebhook(params webhook.SubscriptionParams) (*webhook.Subscription, error) {
+	req, err := c.newJSONRequest("/api/admin/webhooks", params)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &webhook.Subscription{}
+	if err := c.doRequest(req, rsp); err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
+}
+
+func (c *Client) GetWebhookDeliveries(t *testing.T, subscriptionID uuid.UUID) *server.ListWebhookDeliveriesResponse {
+	t.Helper()
+	req, err := c.newGetRequest("/api/admin/webhook-deliveries?subscriptionID=", subscriptionID.String())
+	require.NoError(t, err)
+
+	rsp := &server.ListWebhookDeliveriesResponse{}
+	require.NoError(t, c.doRequest(req, rsp))
+	return rsp
+}
+
+func (c *Client) ReplayWebhookDelivery(t *testing.T, deliveryID uuid.UUID) *webhook.Delivery {
+	t.Helper()
+	rpath := fmt.Sprintf("/api/admin/webhook-deliveries/%s/replay", deliveryID)
+	req, err := c.newJSONRequest(rpath, struct{}{})
+	require.NoError(t, err)
+
+	rsp := &webhook.Delivery{}
+	require.NoError(t, c.doRequest(req, rsp))
+	return rsp
+}
+
+func (c *Client) GetUserTickets(t *testing.T, userID uuid.UUID) *server.ListTicketsResponse {
+	t.Helper()
+	req, err := c.newGetRequest("/api/users/", userID.String(), "/tickets")
+	require.NoError(t, err)
+
+	rsp := &server.ListTicketsResponse{}
+	require.NoError(t, c.doRequest(req, rsp))
+	return rsp
+}
+
+func (c *Client) GetTicketOwnership(t *testing.T, ticketID uuid.UUID) *server.OwnershipHistoryResponse {
+	t.Helper()
+	req, err := c.newGetRequest("/api/tickets/", ticketID.String(), "/ownership")
+	require.NoError(t, err)
+
+	rsp := &server.OwnershipHistoryResponse{}
+	require.NoError(t, c.doRequest(req, rsp))
+	return rsp
+}
+
+func (c *Client) CreateTransfer(params booking.TransferParams) (*booking.Transfer, error) {
+	req, err := c.newJSONRequest("/api/transfers", params)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &booking.Transfer{}
+	if err := c.doRequest(req, rsp); err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
+}
+
+func (c *Client) AcceptTransfer(transferID uuid.UUID, params booking.TransferAcceptParams) (*booking.Transfer, error) {
+	rpath := fmt.Sprintf("/api/transfers/%s/accept", transferID)
+	req, err := c.newJSONRequest(rpath, params)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &booking.Transfer{}
+	if err := c.doRequest(req, rsp); err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
+}
+
+func (c *Client) CancelTransfer(transferID uuid.UUID, params booking.TransferCancelParams) error {
+	rpath := fmt.Sprintf("/api/transfers/%s/cancel", transferID)
+	req, err := c.newJSONRequest(rpath, params)
+	if err != nil {
+		return err
+	}
+
+	return c.doRequest(req, nil)
+}
+
+func (c *Client) SetTransfersBlocked(eventID uuid.UUID, blocked bool) error {
+	rpath := fmt.Sprintf("/api/admin/events/%s/transfers", eventID)
+	req, err := c.newJSONRequest(rpath, server.SetTransfersBlockedRequest{Blocked: blocked})
+	if err != nil {
+		return err
+	}
+
+	req.Method = http.MethodPut
+	return c.doRequest(req, nil)
+}
+
+func (c *Client) CreateResaleListing(params booking.ResaleListingParams) (*booking.ResaleListing, error) {
+	req, err := c.newJSONRequest("/api/resale/listings", params)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &booking.ResaleListing{}
+	if err := c.doRequest(req, rsp); err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
+}
+
+func (c *Client) GetResaleListing(t *testing.T, listingID uuid.UUID) *booking.ResaleListing {
+	t.Helper()
+	req, err := c.newGetRequest("/api/resale/listings/", listingID.String())
+	require.NoError(t, err)
+
+	rsp := &booking.ResaleListing{}
+	require.NoError(t, c.doRequest(req, rsp))
+	return rsp
+}
+
+func (c *Client) GetResaleListings(t *testing.T, eventID uuid.UUID) *server.ListResaleListingsResponse {
+	t.Helper()
+	req, err := c.newGetRequest("/api/events/", eventID.String(), "/resale")
+	require.NoError(t, err)
+
+	rsp := &server.ListResaleListingsResponse{}
+	require.NoError(t, c.doRequest(req, rsp))
+	return rsp
+}
+
+func (c *Client) ReserveResaleListing(listingID uuid.UUID, params booking.ResaleReserveParams) (*booking.ReservationResult, error) {
+	rpath := fmt.Sprintf("/api/resale/listings/%s/reserve", listingID)
+	req, err := c.newJSONRequest(rpath, params)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &booking.ReservationResult{}
+	if err := c.doRequest(req, rsp); err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
+}
+
+func (c *Client) CancelResaleListing(listingID uuid.UUID, params booking.ResaleCancelParams) error {
+	rpath := fmt.Sprintf("/api/resale/listings/%s/cancel", listingID)
+	req, err := c.newJSONRequest(rpath, params)
+	if err != nil {
+		return err
+	}
+
+	return c.doRequest(req, nil)
+}
+
+func (c *Client) GetEventReport(eventID uuid.UUID, query url.Values) (*reporting.EventReport, error) {
+	req, err := c.newGetRequest("/api/admin/events/", eventID.String(), "/report?", query.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &reporting.EventReport{}
+	if err := c.doRequest(req, rsp); err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
+}
+
+func (c *Client) GetOrganizerReport(t *testing.T, organizerID uuid.UUID) *reporting.OrganizerReport {
+	t.Helper()
+	req, err := c.newGetRequest("/api/admin/organizers/", organizerID.String(), "/report")
+	require.NoError(t, err)
+
+	rsp := &reporting.OrganizerReport{}
+	require.NoError(t, c.doRequest(req, rsp))
+	return rsp
+}
+
+// Export downloads event export, e.g. "reservations" or "tickets".
+func (c *Client) Export(eventID uuid.UUID, dataset string, query url.Values) ([]byte, error) {
+	req, err := c.newGetRequest("/api/admin/events/", eventID.String(), "/exports/", dataset, "?", query.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	rsp, err := c.send(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q: failed to send request: %w", req.Method, req.URL, err)
+	}
+
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return nil, tryReadError(req, rsp)
+	}
+
+	format := reporting.ExportFormat(query.Get("format"))
+	if format == "" {
+		format = reporting.FormatCSV
+	}
+
+	if ctype := rsp.Header.Get("Content-Type"); ctype != format.ContentType() {
+		return nil, fmt.Errorf("%q: unexpected content type %q", req.URL, ctype)
+	}
+
+	return io.ReadAll(rsp.Body)
+}
+
+func (c *Client) Import(manifest, contentType string, dryRun bool) (*importer.Result, error) {
+	uri := fmt.Sprintf("%s/api/admin/imports?dryRun=%t", c.addr, dryRun)
+	req, err := http.NewRequest(http.MethodPost, uri, strings.NewReader(manifest))
+	if err != nil {
+		return nil, fmt.Errorf("%s %q: cannot create request: %w", http.MethodPost, uri, err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	rsp := &importer.Result{}
+	if err := c.doRequest(req, rsp); err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
+}
+
 func (c *Client) newGetRequest(parts ...string) (*http.Request, error) {
 	uri := c.addr + strings.Join(parts, "")
 	req, err := http.NewRequest(http.MethodGet, uri, nil)
@@ -159,18 +662,26 @@ func (c *Client) newJSONRequest(rpath string, body any) (*http.Request, error) {
 	return req, nil
 }
 
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if c.adminToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.adminToken)
+	}
+
+	return c.httpClient.Do(req)
+}
+
 func (c *Client) doRequest(req *http.Request, out any) error {
-	rsp, err := c.httpClient.Do(req)
+	rsp, err := c.send(req)
 	if err != nil {
 		return fmt.Errorf("%s %q: failed to send request: %w", req.Method, req.URL, err)
 	}
 
 	defer rsp.Body.Close()
-	if rsp.StatusCode != http.StatusOK {
+	if rsp.StatusCode != http.StatusOK && rsp.StatusCode != http.StatusNoContent {
 		return tryReadError(req, rsp)
 	}
 
-	if out == nil {
+	if out == nil || rsp.StatusCode == http.StatusNoContent {
 		return nil
 	}
 
@@ -0,0 +1,503 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/server"
+)
+
+const (
+	// stressCapacity is a small tier capacity which makes buyers compete for the same tickets.
+	stressCapacity = 20
+
+	// stressBuyers is number of concurrent buyers.
+	stressBuyers = 200
+)
+
+// newStressClient returns API client which keeps enough idle connections for concurrent requests.
+func newStressClient() *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = stressBuyers
+	return client.WithHTTPClient(&http.Client{Transport: transport, Timeout: 30 * time.Second})
+}
+
+func newStressEvent(t *testing.T, tiers ...string) *booking.EventCreateResult {
+	t.Helper()
+	params := booking.EventCreateParams{
+		EventName: fmt.Sprintf("StressTest-%v", time.Now().UnixNano()),
+		Tiers:     make(map[string]booking.CreateTierParams, len(tiers)),
+	}
+
+	for i, name := range tiers {
+		params.Tiers[name] = booking.CreateTierParams{
+			PriceCents:   (i + 1) * 10_00,
+			TicketsCount: stressCapacity,
+		}
+	}
+
+	return client.CreateEvent(t, params)
+}
+
+// isSoldOut reports whether reservation was rejected because there are not enough free tickets.
+func isSoldOut(err error) bool {
+	var rspErr *ResponseError
+	return errors.As(err, &rspErr) && rspErr.Code == http.StatusBadRequest &&
+		strings.Contains(rspErr.Response.Error, "not enough tickets available")
+}
+
+func isStatusCode(err error, code int) bool {
+	var rspErr *ResponseError
+	return errors.As(err, &rspErr) && rspErr.Code == code
+}
+
+func payReservation(c *Client, reservationID uuid.UUID) (*booking.PaymentResult, error) {
+	return c.PayReservation(reservationID, booking.PaymentParams{
+		ReservationID: reservationID,
+		CardNumber:    booking.KnownFakeCard,
+	})
+}
+
+// TestConcurrentReserveAndPay fires concurrent reservations and payments at small tiers
+// and checks that no ticket is sold twice.
+func TestConcurrentReserveAndPay(t *testing.T) {
+	c := newStressClient()
+	event := newStressEvent(t, "Hot", "Warm")
+	hot, warm := event.Tiers["Hot"], event.Tiers["Warm"]
+
+	var (
+		wg      sync.WaitGroup
+		paid    = make([]uuid.UUID, stressBuyers)
+		soldOut = make([]bool, stressBuyers)
+	)
+
+	for i := range stressBuyers {
+		wg.Go(func() {
+			// Every third buyer books both tiers, so partial holds would be visible in inventory.
+			tickets := map[uuid.UUID]uint{hot: uint(1 + i%3)}
+			if i%3 == 0 {
+				tickets[warm] = 2
+			}
+
+			res, err := c.ReserveTickets(event.EventID, server.ReserveTicketsRequest{
+				IdempotencyKey: uuid.New(),
+				ActorID:        uuid.New(),
+				TicketsCount:   tickets,
+			})
+			if err != nil {
+				if !isSoldOut(err) {
+					t.Errorf("buyer %d: unexpected reservation error: %s", i, err)
+				}
+
+				soldOut[i] = true
+				return
+			}
+
+			if _, err := payReservation(c, res.ReservationID); err != nil {
+				t.Errorf("buyer %d: failed to pay reservation %s: %s", i, res.ReservationID, err)
+				return
+			}
+
+			paid[i] = res.ReservationID
+		})
+	}
+
+	wg.Wait()
+	require.Contains(t, soldOut, true, "buyers should compete for tickets")
+
+	paidIDs := make([]uuid.UUID, 0, stressBuyers)
+	for _, id := range paid {
+		if id != uuid.Nil {
+			paidIDs = append(paidIDs, id)
+		}
+	}
+
+	require.NotEmpty(t, paidIDs)
+	requireUniqueTickets(t, c, paidIDs)
+	checkBookingInvariants(t, event.EventID)
+}
+
+// TestConcurrentIdempotentRetries retries the same reservation request concurrently with competing buyers
+// and then pays the reservation from several clients at once.
+func TestConcurrentIdempotentRetries(t *testing.T) {
+	const (
+		retries = 50
+		held    = 3
+	)
+
+	c := newStressClient()
+	event := newStressEvent(t, "Hot")
+	hot := event.Tiers["Hot"]
+
+	req := server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		TicketsCount:   map[uuid.UUID]uint{hot: held},
+	}
+
+	var (
+		wg  sync.WaitGroup
+		ids = make([]uuid.UUID, retries)
+	)
+
+	for i := range retries {
+		wg.Go(func() {
+			res, err := c.ReserveTickets(event.EventID, req)
+			if err != nil {
+				t.Errorf("retry %d failed: %s", i, err)
+				return
+			}
+
+			ids[i] = res.ReservationID
+		})
+	}
+
+	// Competing buyers request exactly the rest of tickets,
+	// so any extra hold taken by a retry leaves one of them without a ticket.
+	for i := range stressCapacity - held {
+		wg.Go(func() {
+			_, err := c.ReserveTickets(event.EventID, server.ReserveTicketsRequest{
+				IdempotencyKey: uuid.New(),
+				ActorID:        uuid.New(),
+				TicketsCount:   map[uuid.UUID]uint{hot: 1},
+			})
+			if err != nil {
+				t.Errorf("buyer %d: unexpected reservation error: %s", i, err)
+			}
+		})
+	}
+
+	wg.Wait()
+	reservationID := ids[0]
+	for _, id := range ids {
+		require.Equal(t, reservationID, id, "retries should return the same reservation")
+	}
+
+	var reservations int
+	err := db.QueryRow(
+		t.Context(), `SELECT COUNT(*) FROM reservations WHERE idempotency_key = $1`, req.IdempotencyKey,
+	).Scan(&reservations)
+	require.NoError(t, err)
+	require.Equal(t, 1, reservations)
+
+	// Only one of concurrent payments is charged, the rest see reservation already paid.
+	payments := make([]error, retries)
+	for i := range retries {
+		wg.Go(func() {
+			_, payments[i] = payReservation(c, reservationID)
+		})
+	}
+
+	wg.Wait()
+	succeeded := 0
+	for _, err := range payments {
+		if err == nil {
+			succeeded++
+			continue
+		}
+
+		require.True(t, isStatusCode(err, http.StatusConflict), "unexpected payment error: %s", err)
+	}
+
+	require.Equal(t, 1, succeeded)
+
+	var charges int
+	err = db.QueryRow(
+		t.Context(), `SELECT COUNT(*) FROM payments WHERE reservation_id = $1`, reservationID,
+	).Scan(&charges)
+	require.NoError(t, err)
+	require.Equal(t, 1, charges)
+
+	tickets, err := c.GetReservationTickets(reservationID)
+	require.NoError(t, err)
+	require.Len(t, tickets.Tickets, held)
+	checkBookingInvariants(t, event.EventID)
+}
+
+// TestConcurrentExpiry races payments of expired holds with new buyers which take released tickets
+// and with background expiry.
+func TestConcurrentExpiry(t *testing.T) {
+	c := newStressClient()
+	svc := newTestService(t)
+	event := newStressEvent(t, "Hot")
+	hot := event.Tiers["Hot"]
+
+	// Fill the tier with holds and move them to the past.
+	stale := make([]uuid.UUID, 0, stressCapacity)
+	for range stressCapacity {
+		res, err := c.ReserveTickets(event.EventID, server.ReserveTicketsRequest{
+			IdempotencyKey: uuid.New(),
+			ActorID:        uuid.New(),
+			TicketsCount:   map[uuid.UUID]uint{hot: 1},
+		})
+		require.NoError(t, err)
+		stale = append(stale, res.ReservationID)
+	}
+
+	_, err := db.Exec(
+		t.Context(), `UPDATE reservations SET expires_at = now() - INTERVAL '1 minute' WHERE id = ANY($1)`, stale,
+	)
+	require.NoError(t, err)
+	_, err = db.Exec(
+		t.Context(), `UPDATE tickets SET hold_expires_at = now() - INTERVAL '1 minute' WHERE hold_token = ANY($1)`, stale,
+	)
+	require.NoError(t, err)
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		paid []uuid.UUID
+	)
+
+	for i, reservationID := range stale {
+		wg.Go(func() {
+			_, err := payReservation(c, reservationID)
+			if err == nil {
+				t.Errorf("expired reservation %s was paid", reservationID)
+				return
+			}
+
+			if !isStatusCode(err, http.StatusBadRequest) && !isStatusCode(err, http.StatusConflict) {
+				t.Errorf("unexpected payment error of expired reservation %s: %s", reservationID, err)
+			}
+		})
+
+		wg.Go(func() {
+			if _, err := svc.ExpireReservations(t.Context()); err != nil {
+				t.Errorf("sweep %d failed: %s", i, err)
+			}
+		})
+	}
+
+	for i := range stressBuyers {
+		wg.Go(func() {
+			res, err := c.ReserveTickets(event.EventID, server.ReserveTicketsRequest{
+				IdempotencyKey: uuid.New(),
+				ActorID:        uuid.New(),
+				TicketsCount:   map[uuid.UUID]uint{hot: 1},
+			})
+			if err != nil {
+				if !isSoldOut(err) {
+					t.Errorf("buyer %d: unexpected reservation error: %s", i, err)
+				}
+
+				return
+			}
+
+			if _, err := payReservation(c, res.ReservationID); err != nil {
+				t.Errorf("buyer %d: failed to pay reservation %s: %s", i, res.ReservationID, err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			paid = append(paid, res.ReservationID)
+		})
+	}
+
+	wg.Wait()
+
+	// Expired holds don't block inventory, so every ticket is either sold to a new buyer or available.
+	require.NotEmpty(t, paid)
+	requireUniqueTickets(t, c, paid)
+	tiers := client.GetTicketTiers(t, event.EventID)
+	require.Len(t, tiers.Tiers, 1)
+	require.Equal(t, stressCapacity-len(paid), tiers.Tiers[0].AvailableCount)
+
+	_, err = svc.ExpireReservations(t.Context())
+	require.NoError(t, err)
+
+	var pending int
+	err = db.QueryRow(
+		t.Context(), `SELECT COUNT(*) FROM reservations WHERE id = ANY($1) AND status <> $2`, stale, booking.ReservationExpired,
+	).Scan(&pending)
+	require.NoError(t, err)
+	require.Zero(t, pending, "stale reservations should be expired")
+	checkBookingInvariants(t, event.EventID)
+}
+
+// TestConcurrentPayAndExpire races payment of active holds with operator expiry and cancellation.
+func TestConcurrentPayAndExpire(t *testing.T) {
+	c := newStressClient()
+	svc := newTestService(t)
+	event := newStressEvent(t, "Hot")
+	hot := event.Tiers["Hot"]
+
+	reservations := make([]uuid.UUID, 0, stressCapacity)
+	for range stressCapacity {
+		res, err := c.ReserveTickets(event.EventID, server.ReserveTicketsRequest{
+			IdempotencyKey: uuid.New(),
+			ActorID:        uuid.New(),
+			TicketsCount:   map[uuid.UUID]uint{hot: 1},
+		})
+		require.NoError(t, err)
+		reservations = append(reservations, res.ReservationID)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		payErrs  = make([]error, len(reservations))
+		closeErr = make([]error, len(reservations))
+	)
+
+	for i, reservationID := range reservations {
+		wg.Go(func() {
+			_, payErrs[i] = payReservation(c, reservationID)
+		})
+
+		wg.Go(func() {
+			if i%2 == 0 {
+				closeErr[i] = svc.ExpireReservation(t.Context(), reservationID)
+			} else {
+				closeErr[i] = c.CancelReservation(reservationID)
+			}
+		})
+	}
+
+	wg.Wait()
+
+	var paid []uuid.UUID
+	for i, reservationID := range reservations {
+		// Exactly one of concurrent operations wins.
+		if payErrs[i] == nil {
+			require.Error(t, closeErr[i], "reservation %s was paid and closed", reservationID)
+			paid = append(paid, reservationID)
+			continue
+		}
+
+		require.NoError(t, closeErr[i], "reservation %s was neither paid nor closed: %s", reservationID, payErrs[i])
+	}
+
+	requireUniqueTickets(t, c, paid)
+	checkBookingInvariants(t, event.EventID)
+}
+
+// requireUniqueTickets checks that paid reservations don't share tickets.
+func requireUniqueTickets(t *testing.T, c *Client, reservationIDs []uuid.UUID) {
+	t.Helper()
+	owners := make(map[uuid.UUID]uuid.UUID)
+	for _, reservationID := range reservationIDs {
+		rsp, err := c.GetReservationTickets(reservationID)
+		require.NoError(t, err)
+
+		for _, ticket := range rsp.Tickets {
+			owner, ok := owners[ticket.TicketID]
+			require.False(t, ok, "ticket %s is sold to %s and %s", ticket.TicketID, owner, reservationID)
+			owners[ticket.TicketID] = reservationID
+		}
+	}
+}
+
+// checkBookingInvariants checks event inventory against reservations:
+//   - sold tickets of a tier never exceed its capacity;
+//   - paid reservation owns exactly the tickets it paid for;
+//   - active pending reservation holds exactly requested tickets, so no ticket is claimed by two holds;
+//   - closed reservations neither hold nor own tickets;
+//   - each sold ticket has exactly one valid code.
+func checkBookingInvariants(t *testing.T, eventID uuid.UUID) {
+	t.Helper()
+	ctx := t.Context()
+
+	type tierInventory struct {
+		TierID   uuid.UUID
+		Capacity int
+		Sold     int
+	}
+
+	var tiers []tierInventory
+	rows, err := db.Query(ctx, `
+		SELECT tier_id, COUNT(*), COUNT(*) FILTER (WHERE is_sold)
+		FROM tickets
+		WHERE event_id = $1
+		GROUP BY tier_id
+	`, eventID)
+	require.NoError(t, err)
+	for rows.Next() {
+		var tier tierInventory
+		require.NoError(t, rows.Scan(&tier.TierID, &tier.Capacity, &tier.Sold))
+		tiers = append(tiers, tier)
+	}
+	require.NoError(t, rows.Err())
+
+	for _, tier := range tiers {
+		require.Equal(t, stressCapacity, tier.Capacity, "tier %s capacity changed", tier.TierID)
+		require.LessOrEqual(t, tier.Sold, tier.Capacity, "tier %s is oversold", tier.TierID)
+	}
+
+	rows, err = db.Query(ctx, `
+		SELECT
+			r.id,
+			r.status,
+			r.status = $2 AND r.expires_at >= now() AS active,
+			ri.tier_id,
+			ri.quantity,
+			COUNT(t.id) FILTER (WHERE t.is_sold AND t.reservation_id = r.id) AS sold,
+			COUNT(t.id) FILTER (
+				WHERE NOT t.is_sold AND t.hold_token = r.id AND t.hold_expires_at >= now()
+			) AS held
+		FROM reservations r
+		INNER JOIN reservation_items ri ON ri.reservation_id = r.id
+		LEFT JOIN tickets t ON t.tier_id = ri.tier_id AND (t.reservation_id = r.id OR t.hold_token = r.id)
+		WHERE r.event_id = $1
+		GROUP BY r.id, r.status, r.expires_at, ri.tier_id, ri.quantity
+	`, eventID, booking.ReservationPending)
+	require.NoError(t, err)
+
+	soldByTier := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var (
+			reservationID, tierID uuid.UUID
+			status                booking.ReservationStatus
+			active                bool
+			quantity, sold, held  int
+		)
+		require.NoError(t, rows.Scan(&reservationID, &status, &active, &tierID, &quantity, &sold, &held))
+
+		switch {
+		case status == booking.ReservationPaid:
+			require.Equal(t, quantity, sold, "paid reservation %s owns wrong number of tier %s tickets", reservationID, tierID)
+			require.Zero(t, held, "paid reservation %s still holds tickets", reservationID)
+			soldByTier[tierID] += sold
+		case active:
+			require.Equal(t, quantity, held, "reservation %s holds wrong number of tier %s tickets", reservationID, tierID)
+			require.Zero(t, sold, "pending reservation %s owns tickets", reservationID)
+		default:
+			require.Zero(t, sold, "%s reservation %s owns tickets", status, reservationID)
+			require.Zero(t, held, "%s reservation %s holds tickets", status, reservationID)
+		}
+	}
+	require.NoError(t, rows.Err())
+
+	for _, tier := range tiers {
+		require.Equal(t, tier.Sold, soldByTier[tier.TierID], "tier %s has sold tickets without paid reservation", tier.TierID)
+	}
+
+	var orphans int
+	err = db.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM tickets
+		WHERE event_id = $1 AND is_sold AND (hold_token IS NOT NULL OR reservation_id IS NULL)
+	`, eventID).Scan(&orphans)
+	require.NoError(t, err)
+	require.Zero(t, orphans, "sold tickets should belong to a reservation and have no hold")
+
+	var badCodes int
+	err = db.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM tickets t
+		WHERE t.event_id = $1 AND t.is_sold AND (
+			SELECT COUNT(*) FROM ticket_codes c WHERE c.ticket_id = t.id AND c.revoked_at IS NULL
+		) <> 1
+	`, eventID).Scan(&badCodes)
+	require.NoError(t, err)
+	require.Zero(t, badCodes, "each sold ticket should have exactly one valid code")
+}
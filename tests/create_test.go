@@ -34,8 +34,9 @@ func TestTicketsCreate(t *testing.T) {
 
 	eventsRsp := client.GetEvents(t)
 	require.Contains(t, eventsRsp.Events, &booking.Event{
-		ID:   createRsp.EventID,
-		Name: eventName,
+		ID:       createRsp.EventID,
+		Name:     eventName,
+		Currency: booking.DefaultCurrency,
 	})
 
 	tiersRsp := client.GetTicketTiers(t, createRsp.EventID)
@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/server"
+)
+
+func TestPresentmentCurrency(t *testing.T) {
+	_, err := db.Exec(t.Context(), `
+		INSERT INTO exchange_rates (base, quote, rate) VALUES ('EUR', 'JPY', 162.5)
+		ON CONFLICT (base, quote) DO UPDATE SET rate = EXCLUDED.rate
+	`)
+	require.NoError(t, err)
+
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("CurrencyTest-%v", time.Now().UnixNano()),
+		Currency:  "EUR",
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   10_99,
+				TicketsCount: 10,
+			},
+		},
+	})
+
+	// Tier prices are displayed in both settlement and presentment currencies.
+	tiersRsp := client.GetTicketTiersInCurrency(t, createRsp.EventID, "JPY")
+	require.Len(t, tiersRsp.Tiers, 1)
+	require.Equal(t, booking.Currency("EUR"), tiersRsp.Tiers[0].Currency)
+	require.Equal(t, &booking.Money{Amount: 1786, Currency: "JPY"}, tiersRsp.Tiers[0].PresentmentPrice)
+
+	rsp, err := client.ReserveTickets(createRsp.EventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		TicketsCount: map[uuid.UUID]uint{
+			createRsp.Tiers["GA"]: 3,
+		},
+	})
+	require.NoError(t, err)
+
+	// Total is converted once: 32.97 EUR * 162.5 = 5357.625 JPY
+	payResult, err := client.PayReservation(rsp.ReservationID, booking.PaymentParams{
+		ReservationID: rsp.ReservationID,
+		CardNumber:    booking.KnownFakeCard,
+		Currency:      "JPY",
+	})
+	require.NoError(t, err)
+	require.Equal(t, booking.NewMoney(3*10_99, "EUR"), payResult.Settlement)
+	require.Equal(t, booking.NewMoney(5358, "JPY"), payResult.Presentment)
+	require.Equal(t, "162.5", payResult.ExchangeRate)
+}
+
+func TestUnknownPresentmentCurrency(t *testing.T) {
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("CurrencyTest-%v", time.Now().UnixNano()),
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   10_00,
+				TicketsCount: 10,
+			},
+		},
+	})
+
+	rsp, err := client.ReserveTickets(createRsp.EventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		TicketsCount: map[uuid.UUID]uint{
+			createRsp.Tiers["GA"]: 1,
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = client.PayReservation(rsp.ReservationID, booking.PaymentParams{
+		ReservationID: rsp.ReservationID,
+		CardNumber:    booking.KnownFakeCard,
+		Currency:      "XYZ",
+	})
+	requireStatusCode(t, err, http.StatusBadRequest)
+	require.Contains(t, err.Error(), "unknown currency")
+
+	_, err = client.PayReservation(rsp.ReservationID, booking.PaymentParams{
+		ReservationID: rsp.ReservationID,
+		CardNumber:    booking.KnownFakeCard,
+		Currency:      "CHF",
+	})
+	requireStatusCode(t, err, http.StatusBadRequest)
+	require.Contains(t, err.Error(), "exchange rate not found")
+}
@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/server"
+)
+
+func TestReservationDocuments(t *testing.T) {
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName:  fmt.Sprintf("DocumentsTest-%v", time.Now().UnixNano()),
+		TaxRateBps: 2000,
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   12_00,
+				TicketsCount: 10,
+			},
+		},
+	})
+
+	rsp, err := client.ReserveTickets(createRsp.EventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		TicketsCount:   map[uuid.UUID]uint{createRsp.Tiers["GA"]: 2},
+	})
+	require.NoError(t, err)
+
+	// Documents are available only after payment
+	for _, name := range []string{"tickets.pdf", "receipt.pdf"} {
+		_, err = client.GetReservationDocument(rsp.ReservationID, name)
+		requireStatusCode(t, err, http.StatusConflict)
+	}
+
+	_, err = client.PayReservation(rsp.ReservationID, booking.PaymentParams{
+		ReservationID: rsp.ReservationID,
+		CardNumber:    booking.KnownFakeCard,
+	})
+	require.NoError(t, err)
+
+	for _, name := range []string{"tickets.pdf", "receipt.pdf"} {
+		pdf, err := client.GetReservationDocument(rsp.ReservationID, name)
+		require.NoError(t, err, name)
+		require.True(t, bytes.HasPrefix(pdf, []byte("%PDF-")), name)
+	}
+
+	_, err = client.GetReservationDocument(uuid.New(), "receipt.pdf")
+	requireStatusCode(t, err, http.StatusNotFound)
+}
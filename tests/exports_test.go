@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/reporting"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/server"
+)
+
+// readCSVExport parses CSV export and returns records without header.
+func readCSVExport(t *testing.T, data []byte, header ...string) [][]string {
+	t.Helper()
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	require.NoError(t, err)
+	require.NotEmpty(t, records)
+	require.Equal(t, header, records[0][:len(header)])
+	return records[1:]
+}
+
+func TestExports(t *testing.T) {
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("ExportsTest-%v", time.Now().UnixNano()),
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   25_00,
+				TicketsCount: 10,
+			},
+		},
+	})
+
+	tierID := createRsp.Tiers["GA"]
+	paidID, paidTickets := buyTickets(t, createRsp.EventID, tierID, 3)
+	refundedID, _ := buyTickets(t, createRsp.EventID, tierID, 1)
+	_, err := client.RefundReservation(refundedID)
+	require.NoError(t, err)
+
+	pendingRsp, err := client.ReserveTickets(createRsp.EventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		TicketsCount:   map[uuid.UUID]uint{tierID: 2},
+		Email:          "pending@example.com",
+	})
+	require.NoError(t, err)
+
+	scan := client.CheckIn(t, booking.CheckInParams{
+		Code:   paidTickets[0].Code,
+		GateID: "gate-a",
+	})
+	require.Equal(t, booking.CheckInAccepted, scan.Status)
+
+	// Reservations
+	data, err := client.Export(createRsp.EventID, "reservations", nil)
+	require.NoError(t, err)
+	records := readCSVExport(t, data, "id", "actor_id", "email", "status", "tickets")
+	require.Len(t, records, 3)
+
+	statuses := make(map[string]string, len(records))
+	for _, r := range records {
+		statuses[r[0]] = r[3]
+	}
+	require.Equal(t, map[string]string{
+		paidID.String():                   string(booking.ReservationPaid),
+		refundedID.String():               string(booking.ReservationRefunded),
+		pendingRsp.ReservationID.String(): string(booking.ReservationPending),
+	}, statuses)
+
+	data, err = client.Export(createRsp.EventID, "reservations", url.Values{
+		"status": {string(booking.ReservationPending)},
+		"format": {string(reporting.FormatNDJSON)},
+	})
+	require.NoError(t, err)
+
+	var reservation reporting.ReservationRecord
+	require.NoError(t, json.Unmarshal(bytes.TrimSpace(data), &reservation))
+	require.Equal(t, pendingRsp.ReservationID, reservation.ID)
+	require.Equal(t, 2, reservation.Tickets)
+	require.Equal(t, int64(50_00), reservation.TotalCents)
+	require.NotNil(t, reservation.Email)
+	require.Equal(t, "pending@example.com", *reservation.Email)
+
+	// Payments
+	data, err = client.Export(createRsp.EventID, "payments", url.Values{
+		"status": {reporting.PaymentRefunded},
+		"format": {string(reporting.FormatNDJSON)},
+	})
+	require.NoError(t, err)
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var payments []reporting.PaymentRecord
+	for dec.More() {
+		var p reporting.PaymentRecord
+		require.NoError(t, dec.Decode(&p))
+		payments = append(payments, p)
+	}
+	require.Len(t, payments, 1)
+	require.Equal(t, refundedID, payments[0].ReservationID)
+	require.Equal(t, int64(25_00), payments[0].SettlementAmount)
+	require.NotNil(t, payments[0].RefundedAt)
+
+	// Date range excludes all payments
+	data, err = client.Export(createRsp.EventID, "payments", url.Values{
+		"to": {time.Now().Add(-time.Hour).Format(time.RFC3339)},
+	})
+	require.NoError(t, err)
+	require.Empty(t, readCSVExport(t, data, "tx_id"))
+
+	// Issued tickets
+	data, err = client.Export(createRsp.EventID, "tickets", nil)
+	require.NoError(t, err)
+	records = readCSVExport(t, data, "ticket_id", "code_id", "tier_name", "reservation_id", "owner_id", "email", "status")
+	require.Len(t, records, 4)
+
+	data, err = client.Export(createRsp.EventID, "tickets", url.Values{"status": {reporting.TicketCheckedIn}})
+	require.NoError(t, err)
+	records = readCSVExport(t, data, "ticket_id")
+	require.Len(t, records, 1)
+	require.Equal(t, paidTickets[0].TicketID.String(), records[0][0])
+	require.Equal(t, "gate-a", records[0][10])
+
+	// Refunded reservation codes are revoked
+	data, err = client.Export(createRsp.EventID, "tickets", url.Values{"status": {reporting.TicketRevoked}})
+	require.NoError(t, err)
+	records = readCSVExport(t, data, "ticket_id")
+	require.Len(t, records, 1)
+	require.Equal(t, refundedID.String(), records[0][3])
+
+	_, err = client.Export(createRsp.EventID, "attendees", nil)
+	requireStatusCode(t, err, http.StatusBadRequest)
+
+	_, err = client.Export(createRsp.EventID, "payments", url.Values{"status": {"pending"}})
+	requireStatusCode(t, err, http.StatusBadRequest)
+
+	_, err = client.Export(createRsp.EventID, "payments", url.Values{"format": {"xlsx"}})
+	requireStatusCode(t, err, http.StatusBadRequest)
+
+	_, err = client.Export(uuid.New(), "payments", nil)
+	requireStatusCode(t, err, http.StatusNotFound)
+}
@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/server"
+)
+
+func TestReservationHoldExtension(t *testing.T) {
+	const holdTTL = 2 * time.Minute
+
+	// Hold TTL is limited.
+	_, err := client.PostEvent(booking.EventCreateParams{
+		EventName:      fmt.Sprintf("HoldTTLTest-%v", time.Now().UnixNano()),
+		HoldTTLSeconds: 3600,
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {PriceCents: 10_00, TicketsCount: 1},
+		},
+	})
+	requireStatusCode(t, err, http.StatusBadRequest)
+
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName:      fmt.Sprintf("HoldExtensionTest-%v", time.Now().UnixNano()),
+		HoldTTLSeconds: uint(holdTTL.Seconds()),
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {PriceCents: 10_00, TicketsCount: 10},
+		},
+	})
+
+	ga := createRsp.Tiers["GA"]
+	reserve := func() *booking.ReservationResult {
+		t.Helper()
+		rsp, err := client.ReserveTickets(createRsp.EventID, server.ReserveTicketsRequest{
+			IdempotencyKey: uuid.New(),
+			ActorID:        uuid.New(),
+			TicketsCount:   map[uuid.UUID]uint{ga: 2},
+		})
+		require.NoError(t, err)
+		return rsp
+	}
+
+	res := reserve()
+	require.WithinDuration(t, time.Now().Add(holdTTL), res.ExpiresAt, 10*time.Second, "event hold TTL should be used")
+
+	// Reservation and all held tickets are moved forward together.
+	ext, err := client.ExtendReservation(res.ReservationID)
+	require.NoError(t, err)
+	require.True(t, ext.ExpiresAt.After(res.ExpiresAt))
+	require.EqualValues(t, 2, ext.ExtensionsLeft)
+	requireHoldExpiresAt(t, res.ReservationID, ext.ExpiresAt)
+
+	_, err = client.ExtendReservation(res.ReservationID)
+	require.NoError(t, err)
+	_, err = client.ExtendReservation(res.ReservationID)
+	require.NoError(t, err)
+
+	// Number of extensions is limited.
+	_, err = client.ExtendReservation(res.ReservationID)
+	requireStatusCode(t, err, http.StatusConflict)
+
+	_, err = payReservation(client, res.ReservationID)
+	require.NoError(t, err, "extended reservation should be payable")
+
+	// Total hold time is capped regardless of remaining extensions.
+	capped := reserve()
+	setHold(t, capped.ReservationID, `now() - INTERVAL '59 minutes'`, `now() + INTERVAL '30 seconds'`)
+
+	ext, err = client.ExtendReservation(capped.ReservationID)
+	require.NoError(t, err)
+	require.True(t, ext.ExpiresAt.Before(time.Now().Add(time.Minute+time.Second)), "hold should not exceed max duration")
+	requireHoldExpiresAt(t, capped.ReservationID, ext.ExpiresAt)
+
+	_, err = client.ExtendReservation(capped.ReservationID)
+	requireStatusCode(t, err, http.StatusConflict)
+
+	// Lapsed hold can't be extended even if it wasn't swept yet.
+	lapsed := reserve()
+	setHold(t, lapsed.ReservationID, `created_at`, `now() - INTERVAL '1 second'`)
+
+	_, err = client.ExtendReservation(lapsed.ReservationID)
+	requireStatusCode(t, err, http.StatusBadRequest)
+
+	cancelled := reserve()
+	require.NoError(t, client.CancelReservation(cancelled.ReservationID))
+	_, err = client.ExtendReservation(cancelled.ReservationID)
+	requireStatusCode(t, err, http.StatusConflict)
+
+	_, err = client.ExtendReservation(uuid.New())
+	requireStatusCode(t, err, http.StatusNotFound)
+}
+
+// setHold moves reservation creation and hold expiration times to simulate an older hold.
+func setHold(t *testing.T, reservationID uuid.UUID, createdAt, expiresAt string) {
+	t.Helper()
+	_, err := db.Exec(t.Context(), fmt.Sprintf(`
+		WITH r AS (
+			UPDATE reservations SET created_at = %s, expires_at = %s WHERE id = $1 RETURNING expires_at
+		)
+		UPDATE tickets SET hold_expires_at = (SELECT expires_at FROM r) WHERE hold_token = $1
+	`, createdAt, expiresAt), reservationID)
+	require.NoError(t, err)
+}
+
+// requireHoldExpiresAt checks that reservation and all its held tickets expire at the same time.
+func requireHoldExpiresAt(t *testing.T, reservationID uuid.UUID, want time.Time) {
+	t.Helper()
+
+	var (
+		reservationExpiresAt time.Time
+		ticketsExpiresAt     []time.Time
+	)
+	err := db.QueryRow(
+		t.Context(), `SELECT expires_at FROM reservations WHERE id = $1`, reservationID,
+	).Scan(&reservationExpiresAt)
+	require.NoError(t, err)
+	require.WithinDuration(t, want, reservationExpiresAt, time.Millisecond)
+
+	rows, err := db.Query(t.Context(), `SELECT hold_expires_at FROM tickets WHERE hold_token = $1`, reservationID)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	for rows.Next() {
+		var ts time.Time
+		require.NoError(t, rows.Scan(&ts))
+		ticketsExpiresAt = append(ticketsExpiresAt, ts)
+	}
+
+	require.NoError(t, rows.Err())
+	require.NotEmpty(t, ticketsExpiresAt)
+	for _, ts := range ticketsExpiresAt {
+		require.True(t, ts.Equal(reservationExpiresAt), "ticket hold %s should match reservation %s", ts, reservationExpiresAt)
+	}
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+func TestImport(t *testing.T) {
+	suffix := time.Now().UnixNano()
+	manifest := fmt.Sprintf(`event,currency,tax_rate_bps,tier,price_cents,tickets_count,pricing_rules
+ImportTest-A-%[1]d,EUR,2000,VIP,20000,2,
+ImportTest-A-%[1]d,,,GA,5000,5,sold_percent 60 5000
+ImportTest-B-%[1]d,,,GA,1500,3,
+`, suffix)
+
+	dryRun, err := client.Import(manifest, "text/csv", true)
+	require.NoError(t, err)
+	require.True(t, dryRun.DryRun)
+	require.Len(t, dryRun.Events, 2)
+	require.Equal(t, 7, dryRun.Events[0].Tickets)
+	require.Nil(t, dryRun.Events[0].EventID)
+
+	result, err := client.Import(manifest, "text/csv", false)
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Created)
+	require.Zero(t, result.Failed)
+
+	first := result.Events[0]
+	require.NotNil(t, first.EventID)
+	tiers := client.GetTicketTiers(t, *first.EventID)
+	require.Len(t, tiers.Tiers, 2)
+
+	available := make(map[string]int, len(tiers.Tiers))
+	for _, tier := range tiers.Tiers {
+		require.Equal(t, booking.Currency("EUR"), tier.Currency)
+		available[tier.Name] = tier.AvailableCount
+		if tier.Name == "GA" {
+			require.NotNil(t, tier.NextThreshold)
+			require.Equal(t, 75_00, tier.NextThreshold.PriceCents)
+			require.Equal(t, 3, *tier.NextThreshold.TicketsLeft)
+		}
+	}
+	require.Equal(t, map[string]int{"VIP": 2, "GA": 5}, available)
+
+	// Re-running the same manifest doesn't create duplicates
+	dryRun, err = client.Import(manifest, "text/csv", true)
+	require.NoError(t, err)
+	require.Equal(t, 2, dryRun.Existing)
+	require.True(t, dryRun.Events[0].Exists)
+	require.Equal(t, *first.EventID, *dryRun.Events[0].EventID)
+
+	rerun, err := client.Import(manifest, "text/csv", false)
+	require.NoError(t, err)
+	require.Zero(t, rerun.Created)
+	require.Equal(t, 2, rerun.Existing)
+
+	names := 0
+	for _, e := range client.GetEvents(t).Events {
+		if e.Name == first.Name {
+			names++
+		}
+	}
+	require.Equal(t, 1, names)
+
+	// Nothing is created when any row is invalid.
+	invalid := fmt.Sprintf(`
+events:
+  - name: ImportTest-C-%[1]d
+    tiers:
+      - name: GA
+        priceCents: 1000
+        ticketsCount: 10
+  - name: ImportTest-D-%[1]d
+    tiers:
+      - name: GA
+        priceCents: -1
+        ticketsCount: 10
+`, suffix)
+	_, err = client.Import(invalid, "application/yaml", false)
+	requireStatusCode(t, err, http.StatusBadRequest)
+
+	for _, e := range client.GetEvents(t).Events {
+		require.NotEqual(t, fmt.Sprintf("ImportTest-C-%d", suffix), e.Name)
+	}
+
+	_, err = client.Import(manifest, "application/xml", false)
+	requireStatusCode(t, err, http.StatusBadRequest)
+}
@@ -7,11 +7,35 @@ import (
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+
 	"github.com/x1unix/thoughtly-ticket-booking/internal/config"
 	"github.com/x1unix/thoughtly-ticket-booking/internal/server"
 )
 
-var client *Client
+var (
+	client *Client
+
+	// mailbox collects emails sent by server.
+	mailbox *smtpSink
+
+	// rdb is a direct Redis connection used to read published events.
+	rdb redis.UniversalClient
+
+	// eventsStream is Redis stream where server publishes domain events.
+	eventsStream = fmt.Sprintf("test:booking:events:%d", time.Now().UnixNano())
+
+	// db is a direct database connection used to manipulate state in tests.
+	db *pgxpool.Pool
+)
+
+// adminToken is admin API token used by test client.
+const adminToken = "test-admin-token"
+
+// testSigningKey is ticket codes signing key of test server.
+const testSigningKey = "integration-tests-ticket-signing-key"
 
 func TestMain(m *testing.M) {
 	code, err := runTests(m)
@@ -26,11 +50,11 @@ func runTests(m *testing.M) (int, error) {
 	ctx, cancelFn := context.WithCancel(context.Background())
 	defer cancelFn()
 
-	srv, err := initServer(ctx)
+	env, err := initServer(ctx)
 	if err != nil {
 		return 1, fmt.Errorf("failed to init test environment: %w", err)
 	}
-	defer srv.Close()
+	defer env.Close()
 
 	if err := client.WaitForServer(3, 300*time.Millisecond); err != nil {
 		return 1, fmt.Errorf("failed to ping server: %w", err)
@@ -39,62 +63,125 @@ func runTests(m *testing.M) (int, error) {
 	return m.Run(), nil
 }
 
-func initServer(ctx context.Context) (*server.Server, error) {
+// testEnv is a hermetic test environment: server on a random port
+// backed by scratch database and in-memory Redis.
+type testEnv struct {
+	srv     *server.Server
+	scratch *scratchDB
+	redis   *miniredis.Miniredis
+}
+
+func (env *testEnv) Close() {
+	if env.srv != nil {
+		env.srv.Close()
+	}
+
+	if db != nil {
+		db.Close()
+	}
+
+	if rdb != nil {
+		_ = rdb.Close()
+	}
+
+	if env.scratch != nil {
+		if err := env.scratch.Close(); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+		}
+	}
+
+	if env.redis != nil {
+		env.redis.Close()
+	}
+}
+
+func initServer(ctx context.Context) (_ *testEnv, err error) {
+	env := &testEnv{}
+	defer func() {
+		if err != nil {
+			env.Close()
+		}
+	}()
+
 	if err := config.LoadEnvFile(); err != nil {
 		return nil, err
 	}
 
+	// Redis is only used as domain events sink, so in-memory stand-in is enough.
+	env.redis, err = miniredis.Run()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start Redis stand-in: %w", err)
+	}
+
+	if err := os.Setenv("APP_REDIS_URL", "redis://"+env.redis.Addr()); err != nil {
+		return nil, err
+	}
+
 	cfg, err := config.FromEnv()
 	if err != nil {
 		return nil, err
 	}
 
+	mailbox, err = startSMTPSink(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.HTTP.ListenAddress = "127.0.0.1:0"
+	cfg.SMTP.Addr = mailbox.Addr()
+	cfg.Outbox.Sink = "redis"
+	cfg.Outbox.Stream = eventsStream
+
+	// Speed up webhook and email retries
+	cfg.Webhooks.MaxAttempts = 3
+	cfg.Webhooks.RetryBase = 100 * time.Millisecond
+	cfg.Webhooks.RetryMax = time.Second
+	cfg.SMTP.MaxAttempts = 3
+	cfg.SMTP.RetryBase = 100 * time.Millisecond
+	cfg.SMTP.RetryMax = time.Second
+	cfg.Webhooks.AllowPrivateTargets = true
+	cfg.Reporting.FeeBps = 500
+	cfg.Admin.Token = adminToken
+	cfg.Tickets.SigningKey = testSigningKey
 	cfg.Log.IsProduction = false
 	logger, err := cfg.Log.BuildZapLogger()
 	if err != nil {
 		return nil, err
 	}
 
-	client, err = NewClient(cfg.HTTP.ListenAddress)
+	// Each test run gets its own database, so tests never destroy developer data
+	// and test packages can run in parallel.
+	env.scratch, err = newScratchDB(ctx, cfg.DB)
 	if err != nil {
 		return nil, err
 	}
 
-	// TODO: create a scratch DB instead of truncating main one
-	if err := truncateDB(ctx, cfg.DB); err != nil {
+	cfg.DB = env.scratch.Config
+	db, err = cfg.DB.NewPgxPool(ctx)
+	if err != nil {
 		return nil, err
 	}
 
-	// TODO: spawn server at a random port (:0)
-	srv, err := server.NewServer(ctx, logger, cfg)
+	rdb, err = cfg.Redis.NewRedisClient(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create server")
+		return nil, err
 	}
 
-	srv.Listen(ctx)
-	return srv, nil
-}
-
-func truncateDB(ctx context.Context, cfg config.DBConfig) error {
-	db, err := cfg.NewPgxPool(ctx)
+	env.srv, err = server.NewServer(ctx, logger, cfg)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to create server: %w", err)
 	}
 
-	defer db.Close()
-	queries := []string{
-		`TRUNCATE TABLE tickets CASCADE`,
-		`TRUNCATE TABLE ticket_tiers CASCADE`,
-		`TRUNCATE TABLE events CASCADE`,
-		`TRUNCATE TABLE reservations CASCADE`,
+	if err := env.srv.Listen(ctx); err != nil {
+		return nil, err
 	}
 
-	for _, q := range queries {
-		_, err := db.Exec(ctx, q)
-		if err != nil {
-			return fmt.Errorf("failed to exec %q: %w", q, err)
-		}
+	client, err = NewClient(env.srv.Addr().String())
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	client = client.WithAdminToken(adminToken)
+
+	return env, nil
 }
@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/server"
+)
+
+func TestReservationModify(t *testing.T) {
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("ModifyReservationTest-%v", time.Now().UnixNano()),
+		Tiers: map[string]booking.CreateTierParams{
+			"VIP": {PriceCents: 100_00, TicketsCount: 2},
+			"GA":  {PriceCents: 10_00, TicketsCount: 5},
+		},
+	})
+
+	eventID := createRsp.EventID
+	vip, ga := createRsp.Tiers["VIP"], createRsp.Tiers["GA"]
+
+	res, err := client.ReserveTickets(eventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		TicketsCount:   map[uuid.UUID]uint{ga: 2},
+	})
+	require.NoError(t, err)
+
+	modify := func(tickets map[uuid.UUID]uint) (*booking.ReservationMeta, error) {
+		return client.ModifyReservation(res.ReservationID, booking.ReservationModifyParams{TicketsCount: tickets})
+	}
+
+	available := func() map[uuid.UUID]int {
+		result := make(map[uuid.UUID]int)
+		for _, tier := range client.GetTicketTiers(t, eventID).Tiers {
+			result[tier.TierID] = tier.AvailableCount
+		}
+
+		return result
+	}
+
+	// Add tickets to the same tier
+	meta, err := modify(map[uuid.UUID]uint{ga: 3})
+	require.NoError(t, err)
+	require.Len(t, meta.Items, 1)
+	require.EqualValues(t, 3, meta.Items[0].Quantity)
+	require.EqualValues(t, 3*10_00, meta.TotalCents)
+	require.WithinDuration(t, res.ExpiresAt, meta.ExpiresAt, time.Millisecond, "expiry should not change")
+	require.Equal(t, map[uuid.UUID]int{vip: 2, ga: 2}, available())
+
+	// Switch tiers
+	meta, err = modify(map[uuid.UUID]uint{ga: 0, vip: 2})
+	require.NoError(t, err)
+	require.Len(t, meta.Items, 1)
+	require.Equal(t, vip, meta.Items[0].TierID)
+	require.EqualValues(t, 2*100_00, meta.TotalCents)
+	require.Equal(t, map[uuid.UUID]int{vip: 0, ga: 5}, available())
+
+	// Removed tickets are offered to tier waitlist first
+	waitingID := uuid.New()
+	_, err = client.JoinWaitlist(eventID, vip, booking.WaitlistJoinParams{ActorID: waitingID, Quantity: 1})
+	require.NoError(t, err)
+
+	_, err = modify(map[uuid.UUID]uint{vip: 1})
+	require.NoError(t, err)
+
+	entries := client.GetWaitlistEntries(t, waitingID).Entries
+	require.Len(t, entries, 1)
+	require.Equal(t, booking.WaitlistOffered, entries[0].Status)
+
+	// Change is all-or-nothing
+	_, err = modify(map[uuid.UUID]uint{ga: 2, vip: 2})
+	requireStatusCode(t, err, http.StatusBadRequest)
+	require.Equal(t, map[uuid.UUID]int{vip: 0, ga: 5}, available())
+
+	var held map[string]int
+	err = db.QueryRow(t.Context(), `
+		SELECT jsonb_object_agg(tier_id, n) FROM (
+			SELECT tier_id, COUNT(*) AS n FROM tickets WHERE hold_token = $1 GROUP BY tier_id
+		) h
+	`, res.ReservationID).Scan(&held)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{vip.String(): 1}, held)
+
+	_, err = modify(map[uuid.UUID]uint{vip: 0})
+	requireStatusCode(t, err, http.StatusBadRequest)
+
+	_, err = client.ModifyReservation(uuid.New(), booking.ReservationModifyParams{
+		TicketsCount: map[uuid.UUID]uint{ga: 1},
+	})
+	requireStatusCode(t, err, http.StatusNotFound)
+
+	payment, err := payReservation(client, res.ReservationID)
+	require.NoError(t, err)
+	require.EqualValues(t, 100_00, payment.AmountCents)
+
+	_, err = modify(map[uuid.UUID]uint{vip: 1, ga: 1})
+	requireStatusCode(t, err, http.StatusConflict)
+
+	var stored []booking.DomainEventType
+	err = db.QueryRow(t.Context(), `
+		SELECT array_agg(event_type ORDER BY id) FROM outbox WHERE aggregate_id = $1
+	`, res.ReservationID).Scan(&stored)
+	require.NoError(t, err)
+	require.Equal(t, []booking.DomainEventType{
+		booking.ReservationCreatedEvent,
+		booking.ReservationModifiedEvent,
+		booking.ReservationModifiedEvent,
+		booking.ReservationModifiedEvent,
+		booking.PaymentSucceededEvent,
+	}, stored)
+}
+
+func TestReservationModifyKeepsLockedPrice(t *testing.T) {
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("ModifyLockedPriceTest-%v", time.Now().UnixNano()),
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {PriceCents: 10_00, TicketsCount: 10},
+		},
+	})
+
+	ga := createRsp.Tiers["GA"]
+	res, err := client.ReserveTickets(createRsp.EventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		TicketsCount:   map[uuid.UUID]uint{ga: 2},
+	})
+	require.NoError(t, err)
+
+	// Price goes up after tickets were held
+	client.SetTierPricing(t, ga, booking.TierPricingParams{BasePriceCents: 15_00})
+
+	// Added tickets are charged by the new price, held tickets keep the old one
+	meta, err := client.ModifyReservation(res.ReservationID, booking.ReservationModifyParams{
+		TicketsCount: map[uuid.UUID]uint{ga: 3},
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 2*10_00+15_00, meta.TotalCents)
+	require.Len(t, meta.Items, 2)
+	require.EqualValues(t, 15_00, meta.Items[0].UnitPriceCents)
+	require.EqualValues(t, 1, meta.Items[0].Quantity)
+	require.EqualValues(t, 10_00, meta.Items[1].UnitPriceCents)
+	require.EqualValues(t, 2, meta.Items[1].Quantity)
+
+	// Removed tickets are taken from the most expensive line first
+	meta, err = client.ModifyReservation(res.ReservationID, booking.ReservationModifyParams{
+		TicketsCount: map[uuid.UUID]uint{ga: 1},
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 10_00, meta.TotalCents)
+	require.Len(t, meta.Items, 1)
+	require.EqualValues(t, 10_00, meta.Items[0].UnitPriceCents)
+
+	payment, err := client.PayReservation(res.ReservationID, booking.PaymentParams{
+		ReservationID: res.ReservationID,
+		CardNumber:    booking.KnownFakeCard,
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 10_00, payment.AmountCents)
+}
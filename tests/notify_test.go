@@ -0,0 +1,160 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/server"
+)
+
+func TestNotifications(t *testing.T) {
+	eventName := fmt.Sprintf("NotifyTest-%v", time.Now().UnixNano())
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: eventName,
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   25_00,
+				TicketsCount: 5,
+			},
+		},
+	})
+
+	eventID := createRsp.EventID
+	tierID := createRsp.Tiers["GA"]
+
+	_, err := client.ReserveTickets(eventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		TicketsCount:   map[uuid.UUID]uint{tierID: 1},
+		Email:          "not an email",
+	})
+	requireStatusCode(t, err, http.StatusBadRequest)
+
+	email := fmt.Sprintf("buyer-%s@example.com", uuid.NewString())
+	rsp, err := client.ReserveTickets(eventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		TicketsCount:   map[uuid.UUID]uint{tierID: 2},
+		Email:          email,
+	})
+	require.NoError(t, err)
+	requireMail(t, email, "Your reservation for "+eventName)
+
+	_, err = client.PayReservation(rsp.ReservationID, booking.PaymentParams{
+		ReservationID: rsp.ReservationID,
+		CardNumber:    booking.KnownFakeCard,
+	})
+	require.NoError(t, err)
+
+	paid := requireMail(t, email, "Your tickets for "+eventName)
+	require.Equal(t, []string{"tickets.pdf"}, paid.Attachments)
+
+	// Cancelled event rejects new reservations and notifies ticket holders
+	require.NoError(t, client.CancelEvent(eventID))
+	requireMail(t, email, eventName+" is cancelled")
+
+	_, err = client.ReserveTickets(eventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		TicketsCount:   map[uuid.UUID]uint{tierID: 1},
+	})
+	requireStatusCode(t, err, http.StatusConflict)
+
+	err = client.CancelEvent(eventID)
+	requireStatusCode(t, err, http.StatusConflict)
+
+	err = client.CancelEvent(uuid.New())
+	requireStatusCode(t, err, http.StatusNotFound)
+}
+
+func TestNotificationRetries(t *testing.T) {
+	eventName := fmt.Sprintf("NotifyRetryTest-%v", time.Now().UnixNano())
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: eventName,
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   25_00,
+				TicketsCount: 5,
+			},
+		},
+	})
+
+	// Failed sends are retried until mail server accepts the message
+	email := fmt.Sprintf("buyer-%s@example.com", uuid.NewString())
+	mailbox.Reject(email, 2)
+	rsp, err := client.ReserveTickets(createRsp.EventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		TicketsCount:   map[uuid.UUID]uint{createRsp.Tiers["GA"]: 1},
+		Email:          email,
+	})
+	require.NoError(t, err)
+	requireMail(t, email, "Your reservation for "+eventName)
+
+	var (
+		status   string
+		attempts int
+	)
+	require.Eventually(t, func() bool {
+		err := db.QueryRow(t.Context(), `
+			SELECT d.status, d.attempts
+			FROM mail_deliveries d
+			JOIN outbox o ON o.event_id = d.event_id
+			WHERE o.aggregate_id = $1 AND d.event_type = $2
+		`, rsp.ReservationID, booking.ReservationCreatedEvent).Scan(&status, &attempts)
+		return err == nil && status == "delivered"
+	}, 5*time.Second, 50*time.Millisecond)
+	require.Equal(t, 3, attempts)
+	require.Len(t, mailbox.Find(email), 1)
+
+	// Email is dead-lettered after all attempts fail
+	email = fmt.Sprintf("buyer-%s@example.com", uuid.NewString())
+	mailbox.Reject(email, 3)
+	rsp, err = client.ReserveTickets(createRsp.EventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		TicketsCount:   map[uuid.UUID]uint{createRsp.Tiers["GA"]: 1},
+		Email:          email,
+	})
+	require.NoError(t, err)
+
+	var lastError *string
+	require.Eventually(t, func() bool {
+		err := db.QueryRow(t.Context(), `
+			SELECT d.status, d.attempts, d.last_error
+			FROM mail_deliveries d
+			JOIN outbox o ON o.event_id = d.event_id
+			WHERE o.aggregate_id = $1 AND d.event_type = $2
+		`, rsp.ReservationID, booking.ReservationCreatedEvent).Scan(&status, &attempts, &lastError)
+		return err == nil && status == "dead"
+	}, 5*time.Second, 50*time.Millisecond)
+	require.Equal(t, 3, attempts)
+	require.NotNil(t, lastError)
+	require.Empty(t, mailbox.Find(email))
+}
+
+// requireMail waits for an email with a subject to be delivered to a recipient.
+func requireMail(t *testing.T, to, subject string) receivedMail {
+	t.Helper()
+
+	var result receivedMail
+	require.Eventuallyf(t, func() bool {
+		for _, m := range mailbox.Find(to) {
+			if strings.Contains(m.Subject, subject) {
+				result = m
+				return true
+			}
+		}
+
+		return false
+	}, 5*time.Second, 50*time.Millisecond, "no mail %q sent to %s", subject, to)
+
+	return result
+}
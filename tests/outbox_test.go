@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/server"
+)
+
+func TestOutbox(t *testing.T) {
+	ctx := context.Background()
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("OutboxTest-%v", time.Now().UnixNano()),
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   10_00,
+				TicketsCount: 2,
+			},
+		},
+	})
+
+	eventID := createRsp.EventID
+	tierID := createRsp.Tiers["GA"]
+	rsp, err := client.ReserveTickets(eventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		TicketsCount:   map[uuid.UUID]uint{tierID: 2},
+	})
+	require.NoError(t, err)
+
+	_, err = client.PayReservation(rsp.ReservationID, booking.PaymentParams{
+		ReservationID: rsp.ReservationID,
+		CardNumber:    booking.KnownFakeCard,
+	})
+	require.NoError(t, err)
+
+	_, err = client.RefundReservation(rsp.ReservationID)
+	require.NoError(t, err)
+
+	// Failed state change should not leave records
+	_, err = client.RefundReservation(rsp.ReservationID)
+	require.Error(t, err)
+
+	var stored []booking.DomainEventType
+	err = db.QueryRow(ctx, `
+		SELECT array_agg(event_type ORDER BY id) FROM outbox WHERE aggregate_id = $1
+	`, rsp.ReservationID).Scan(&stored)
+	require.NoError(t, err)
+
+	expect := []booking.DomainEventType{
+		booking.ReservationCreatedEvent,
+		booking.PaymentSucceededEvent,
+		booking.ReservationRefundedEvent,
+	}
+	require.Equal(t, expect, stored)
+
+	// Relay publishes records to a stream preserving per-reservation order
+	var published []booking.DomainEventType
+	require.Eventually(t, func() bool {
+		entries, err := rdb.XRange(ctx, eventsStream, "-", "+").Result()
+		if err != nil {
+			return false
+		}
+
+		published = published[:0]
+		for _, entry := range entries {
+			if entry.Values["aggregate_id"] == rsp.ReservationID.String() {
+				published = append(published, booking.DomainEventType(entry.Values["type"].(string)))
+			}
+		}
+
+		return len(published) == len(expect)
+	}, 5*time.Second, 50*time.Millisecond)
+	require.Equal(t, expect, published)
+
+	// Records are marked as published after publish to a sink
+	require.Eventually(t, func() bool {
+		var pending int
+		err := db.QueryRow(ctx, `
+			SELECT count(*) FROM outbox WHERE aggregate_id = $1 AND published_at IS NULL
+		`, rsp.ReservationID).Scan(&pending)
+		return err == nil && pending == 0
+	}, 5*time.Second, 50*time.Millisecond)
+}
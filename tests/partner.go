@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/webhook"
+)
+
+// partnerServer is a local HTTP server which stands in for partner webhook endpoint.
+//
+// Requests with invalid signature are rejected and not recorded.
+type partnerServer struct {
+	*httptest.Server
+	secret string
+
+	// failing makes server respond with internal error.
+	failing atomic.Bool
+
+	mu       sync.Mutex
+	received []booking.DomainEvent
+	attempts int
+}
+
+func newPartnerServer(secret string) *partnerServer {
+	p := &partnerServer{secret: secret}
+	p.Server = httptest.NewServer(http.HandlerFunc(p.handle))
+	return p
+}
+
+// Events returns accepted events.
+func (p *partnerServer) Events() []booking.DomainEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]booking.DomainEvent(nil), p.received...)
+}
+
+// Attempts returns number of received requests with valid signature.
+func (p *partnerServer) Attempts() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.attempts
+}
+
+func (p *partnerServer) handle(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = webhook.Verify(p.secret, r.Header.Get(webhook.SignatureHeader), body, time.Now(), time.Minute)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.attempts++
+	if p.failing.Load() {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var e booking.DomainEvent
+	if err := json.Unmarshal(body, &e); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	p.received = append(p.received, e)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/pricing"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/server"
+)
+
+func TestDynamicPricing(t *testing.T) {
+	soldPercent := 50
+	maxPrice := 14_00
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("DynamicPricingTest-%v", time.Now().UnixNano()),
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   10_00,
+				TicketsCount: 10,
+				Limits: pricing.Limits{
+					MaxPriceCents: &maxPrice,
+				},
+				PricingRules: []pricing.Rule{
+					{
+						Kind:          pricing.RuleSoldPercent,
+						SoldPercent:   &soldPercent,
+						AdjustmentBps: 5000,
+					},
+				},
+			},
+		},
+	})
+
+	eventID := createRsp.EventID
+	tierID := createRsp.Tiers["GA"]
+
+	// Price step should be capped by ceiling.
+	tiersRsp := client.GetTicketTiers(t, eventID)
+	require.Len(t, tiersRsp.Tiers, 1)
+	require.Equal(t, 10_00, tiersRsp.Tiers[0].PriceCents)
+	require.NotNil(t, tiersRsp.Tiers[0].NextThreshold)
+	require.Equal(t, maxPrice, tiersRsp.Tiers[0].NextThreshold.PriceCents)
+	require.Equal(t, soldPercent, *tiersRsp.Tiers[0].NextThreshold.SoldPercent)
+	require.Equal(t, 5, *tiersRsp.Tiers[0].NextThreshold.TicketsLeft)
+
+	// Reservation which reaches the threshold is charged by previous price.
+	userID := uuid.New()
+	_, err := client.ReserveTickets(eventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        userID,
+		TicketsCount: map[uuid.UUID]uint{
+			tierID: 5,
+		},
+	})
+	require.NoError(t, err)
+
+	tiersRsp = client.GetTicketTiers(t, eventID)
+	require.Equal(t, maxPrice, tiersRsp.Tiers[0].PriceCents)
+	require.Nil(t, tiersRsp.Tiers[0].NextThreshold)
+
+	_, err = client.ReserveTickets(eventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        userID,
+		TicketsCount: map[uuid.UUID]uint{
+			tierID: 1,
+		},
+	})
+	require.NoError(t, err)
+
+	totals := map[uint]bool{}
+	for _, r := range client.GetReservations(t, userID).Reservations {
+		totals[r.TotalCents] = true
+	}
+	require.Equal(t, map[uint]bool{5 * 10_00: true, uint(maxPrice): true}, totals)
+
+	history := client.GetTierPriceHistory(t, tierID)
+	require.Len(t, history.Changes, 1)
+	require.Equal(t, 10_00, history.Changes[0].OldPriceCents)
+	require.Equal(t, maxPrice, history.Changes[0].NewPriceCents)
+}
+
+func TestDynamicPricingRelease(t *testing.T) {
+	soldPercent := 50
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("DynamicPricingReleaseTest-%v", time.Now().UnixNano()),
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   10_00,
+				TicketsCount: 10,
+				PricingRules: []pricing.Rule{
+					{
+						Kind:          pricing.RuleSoldPercent,
+						SoldPercent:   &soldPercent,
+						AdjustmentBps: 5000,
+					},
+				},
+			},
+		},
+	})
+
+	eventID := createRsp.EventID
+	tierID := createRsp.Tiers["GA"]
+	reserve := func() *booking.ReservationResult {
+		t.Helper()
+		res, err := client.ReserveTickets(eventID, server.ReserveTicketsRequest{
+			IdempotencyKey: uuid.New(),
+			ActorID:        uuid.New(),
+			TicketsCount:   map[uuid.UUID]uint{tierID: 5},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 15_00, client.GetTicketTiers(t, eventID).Tiers[0].PriceCents)
+		return res
+	}
+
+	// Price goes back down when tickets are released.
+	require.NoError(t, client.CancelReservation(reserve().ReservationID))
+	require.Equal(t, 10_00, client.GetTicketTiers(t, eventID).Tiers[0].PriceCents)
+
+	history := client.GetTierPriceHistory(t, tierID)
+	require.Len(t, history.Changes, 2)
+	require.Equal(t, 15_00, history.Changes[1].OldPriceCents)
+	require.Equal(t, 10_00, history.Changes[1].NewPriceCents)
+	require.Equal(t, "release", history.Changes[1].Reason)
+
+	// The same happens when hold expires.
+	res := reserve()
+	setHold(t, res.ReservationID, `now() - INTERVAL '20 minutes'`, `now() - INTERVAL '1 second'`)
+	require.Eventually(t, func() bool {
+		return client.GetTicketTiers(t, eventID).Tiers[0].PriceCents == 10_00
+	}, 15*time.Second, 200*time.Millisecond)
+	require.Len(t, client.GetTierPriceHistory(t, tierID).Changes, 4)
+}
@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+// recordingPayer records refunds and fails them while failing flag is set.
+type recordingPayer struct {
+	booking.MockPayer
+
+	mu        sync.Mutex
+	failing   bool
+	refunds   []uuid.UUID
+	attempted int
+}
+
+func (p *recordingPayer) Rollback(txID uuid.UUID) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.attempted++
+	if p.failing {
+		return errors.New("payment provider is unavailable")
+	}
+
+	p.refunds = append(p.refunds, txID)
+	return nil
+}
+
+func (p *recordingPayer) setFailing(failing bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failing = failing
+}
+
+func (p *recordingPayer) stats() (attempted int, refunds []uuid.UUID) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.attempted, append([]uuid.UUID(nil), p.refunds...)
+}
+
+func TestRefundCommitFailure(t *testing.T) {
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("RefundCommitTest-%v", time.Now().UnixNano()),
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {PriceCents: 10_00, TicketsCount: 10},
+		},
+	})
+
+	reservationID, tickets := buyTickets(t, createRsp.EventID, createRsp.Tiers["GA"], 2)
+	payer := &recordingPayer{}
+	svc := newTestService(t).WithPayer(payer)
+
+	// Deferred constraint trigger fails commit of reservation refund.
+	trigger := "fail_refund_" + uuid.New().String()[:8]
+	_, err := db.Exec(t.Context(), fmt.Sprintf(`
+		CREATE FUNCTION %[1]s() RETURNS trigger LANGUAGE plpgsql AS $$
+		BEGIN
+			RAISE EXCEPTION 'refund commit failed';
+		END
+		$$;
+
+		CREATE CONSTRAINT TRIGGER %[1]s
+		AFTER UPDATE ON reservations
+		DEFERRABLE INITIALLY DEFERRED
+		FOR EACH ROW WHEN (NEW.id = '%[2]s' AND NEW.status = 'refunded')
+		EXECUTE FUNCTION %[1]s();
+	`, trigger, reservationID))
+	require.NoError(t, err)
+
+	dropTrigger := func() {
+		_, err := db.Exec(t.Context(), fmt.Sprintf(
+			`DROP TRIGGER IF EXISTS %[1]s ON reservations; DROP FUNCTION IF EXISTS %[1]s();`, trigger,
+		))
+		require.NoError(t, err)
+	}
+	t.Cleanup(dropTrigger)
+
+	// Money is not returned if refund is not committed.
+	_, err = svc.RefundReservation(t.Context(), reservationID)
+	require.ErrorContains(t, err, "refund commit failed")
+	attempted, _ := payer.stats()
+	require.Zero(t, attempted)
+
+	owned, err := client.GetReservationTickets(reservationID)
+	require.NoError(t, err)
+	require.Len(t, owned.Tickets, len(tickets))
+
+	var status booking.ReservationStatus
+	err = db.QueryRow(t.Context(), `SELECT status FROM reservations WHERE id = $1`, reservationID).Scan(&status)
+	require.NoError(t, err)
+	require.Equal(t, booking.ReservationPaid, status)
+
+	// Provider failure after commit leaves refund pending until it's retried.
+	dropTrigger()
+	payer.setFailing(true)
+	result, err := svc.RefundReservation(t.Context(), reservationID)
+	require.NoError(t, err)
+	require.True(t, result.IsPending)
+
+	var attempts int
+	err = db.QueryRow(
+		t.Context(), `SELECT attempts FROM pending_refunds WHERE tx_id = $1`, result.TxID,
+	).Scan(&attempts)
+	require.NoError(t, err)
+	require.Equal(t, 1, attempts)
+
+	payer.setFailing(false)
+	_, err = db.Exec(t.Context(), `UPDATE pending_refunds SET next_attempt_at = now() WHERE tx_id = $1`, result.TxID)
+	require.NoError(t, err)
+
+	issued, err := svc.RetryRefunds(t.Context())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, issued, 1)
+
+	_, refunds := payer.stats()
+	require.Equal(t, []uuid.UUID{result.TxID}, refunds)
+
+	var pending bool
+	err = db.QueryRow(
+		t.Context(), `SELECT EXISTS (SELECT 1 FROM pending_refunds WHERE tx_id = $1)`, result.TxID,
+	).Scan(&pending)
+	require.NoError(t, err)
+	require.False(t, pending)
+}
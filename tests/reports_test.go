@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/reporting"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/server"
+)
+
+func TestReports(t *testing.T) {
+	organizerID := uuid.New()
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName:   fmt.Sprintf("ReportsTest-%v", time.Now().UnixNano()),
+		OrganizerID: &organizerID,
+		TaxRateBps:  2000,
+		Tiers: map[string]booking.CreateTierParams{
+			"VIP": {
+				PriceCents:   100_00,
+				TicketsCount: 3,
+			},
+			"GA": {
+				PriceCents:   20_00,
+				TicketsCount: 5,
+			},
+		},
+	})
+	emptyRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName:   fmt.Sprintf("ReportsTestEmpty-%v", time.Now().UnixNano()),
+		OrganizerID: &organizerID,
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   10_00,
+				TicketsCount: 2,
+			},
+		},
+	})
+
+	vipID := createRsp.Tiers["VIP"]
+	gaID := createRsp.Tiers["GA"]
+	start := time.Now().UTC()
+
+	buyTickets(t, createRsp.EventID, vipID, 2)
+	refundedID, _ := buyTickets(t, createRsp.EventID, gaID, 1)
+	_, err := client.RefundReservation(refundedID)
+	require.NoError(t, err)
+
+	_, err = client.ReserveTickets(createRsp.EventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		TicketsCount:   map[uuid.UUID]uint{gaID: 2},
+	})
+	require.NoError(t, err)
+
+	report, err := client.GetEventReport(createRsp.EventID, url.Values{
+		"bucket": {"hour"},
+		"from":   {start.Add(-time.Hour).Format(time.RFC3339)},
+		"to":     {time.Now().Add(time.Hour).Format(time.RFC3339)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, reporting.BucketHour, report.Bucket)
+	require.Len(t, report.Tiers, 2)
+
+	vip, ga := report.Tiers[0], report.Tiers[1]
+	require.Equal(t, reporting.TierReport{
+		TierID: vipID, Name: "VIP", PriceCents: 100_00,
+		Capacity: 3, Sold: 2, Held: 0, Available: 1, SalesCents: 200_00,
+	}, *vip)
+	require.Equal(t, reporting.TierReport{
+		TierID: gaID, Name: "GA", PriceCents: 20_00,
+		Capacity: 5, Sold: 0, Held: 2, Available: 3, SalesCents: 0,
+	}, *ga)
+
+	// Fee is 5% of sales net of refunds, see test server config.
+	usd := func(amount int64) booking.Money {
+		return booking.NewMoney(amount, booking.DefaultCurrency)
+	}
+	expectRevenue := reporting.Revenue{
+		Gross:   usd(220_00),
+		Refunds: usd(20_00),
+		Fees:    usd(10_00),
+		Net:     usd(190_00),
+		Tax:     usd(200_00).IncludedTax(2000),
+	}
+	require.Equal(t, expectRevenue, report.Revenue)
+
+	// Series covers the whole range and includes refunded payments.
+	require.GreaterOrEqual(t, len(report.Sales), 2)
+	var ticketsSold int
+	var grossCents int64
+	for _, p := range report.Sales {
+		ticketsSold += p.TicketsSold
+		grossCents += p.GrossCents
+	}
+	require.Equal(t, 3, ticketsSold)
+	require.Equal(t, int64(220_00), grossCents)
+
+	_, err = client.GetEventReport(createRsp.EventID, url.Values{"bucket": {"week"}})
+	requireStatusCode(t, err, http.StatusBadRequest)
+
+	_, err = client.GetEventReport(createRsp.EventID, url.Values{"from": {"yesterday"}})
+	requireStatusCode(t, err, http.StatusBadRequest)
+
+	_, err = client.GetEventReport(uuid.New(), nil)
+	requireStatusCode(t, err, http.StatusNotFound)
+
+	orgReport := client.GetOrganizerReport(t, organizerID)
+	require.Len(t, orgReport.Events, 2)
+	require.Equal(t, createRsp.EventID, orgReport.Events[0].EventID)
+	require.Equal(t, 8, orgReport.Events[0].Capacity)
+	require.Equal(t, 2, orgReport.Events[0].Sold)
+	require.Equal(t, 2, orgReport.Events[0].Held)
+	require.Equal(t, 4, orgReport.Events[0].Available)
+	require.Equal(t, expectRevenue, orgReport.Events[0].Revenue)
+
+	require.Equal(t, emptyRsp.EventID, orgReport.Events[1].EventID)
+	require.Equal(t, 2, orgReport.Events[1].Available)
+	require.Zero(t, orgReport.Events[1].Revenue.Gross.Amount)
+
+	require.Len(t, orgReport.Totals, 1)
+	require.Equal(t, expectRevenue, *orgReport.Totals[0])
+}
@@ -0,0 +1,38 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking/bookingtest"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/ticketcode"
+)
+
+// TestPostgresRepository checks that Postgres-backed service conforms to the same contract as memory store.
+func TestPostgresRepository(t *testing.T) {
+	svc := newTestService(t)
+	bookingtest.RunRepositoryTests(t, func(t *testing.T) booking.Repository {
+		return svc
+	})
+}
+
+// TestPostgresRepositoryModel checks random reservation lifecycle sequences against reference model.
+func TestPostgresRepositoryModel(t *testing.T) {
+	bookingtest.RunLifecycleModel(t, func(t *testing.T) booking.Repository {
+		return newTestService(t)
+	})
+}
+
+// newTestService returns booking service connected to test database.
+//
+// Used to call operations which are not exposed by API, e.g. reservation expiry.
+func newTestService(t *testing.T) *booking.Service {
+	t.Helper()
+	codes, err := ticketcode.NewSigner([]byte("postgres-repository-test-signing-key"))
+	require.NoError(t, err)
+
+	store := booking.NewPostgresStore(db)
+	return booking.NewService(store, rdb, booking.NewDBRates(db), codes, booking.DefaultResalePolicy)
+}
@@ -0,0 +1,223 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+func TestResale(t *testing.T) {
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("ResaleTest-%v", time.Now().UnixNano()),
+		Tiers: map[string]booking.CreateTierParams{
+			"VIP": {
+				PriceCents:   100_00,
+				TicketsCount: 5,
+			},
+			"GA": {
+				PriceCents:   20_00,
+				TicketsCount: 5,
+			},
+		},
+	})
+
+	eventID := createRsp.EventID
+	sellerID, buyerID := uuid.New(), uuid.New()
+	sellerReservationID, tickets := buyTicketsAs(t, sellerID, eventID, createRsp.Tiers["VIP"], 3)
+	_, gaTickets := buyTicketsAs(t, sellerID, eventID, createRsp.Tiers["GA"], 1)
+
+	// Price is capped at 110% of face value
+	_, err := client.CreateResaleListing(booking.ResaleListingParams{
+		ActorID:    sellerID,
+		TicketIDs:  []uuid.UUID{tickets[0].TicketID},
+		PriceCents: 110_01,
+	})
+	requireStatusCode(t, err, http.StatusBadRequest)
+
+	// Listing can contain only tickets of a single tier
+	_, err = client.CreateResaleListing(booking.ResaleListingParams{
+		ActorID:    sellerID,
+		TicketIDs:  []uuid.UUID{tickets[0].TicketID, gaTickets[0].TicketID},
+		PriceCents: 20_00,
+	})
+	requireStatusCode(t, err, http.StatusBadRequest)
+
+	// Only owner can list tickets
+	_, err = client.CreateResaleListing(booking.ResaleListingParams{
+		ActorID:    buyerID,
+		TicketIDs:  []uuid.UUID{tickets[0].TicketID},
+		PriceCents: 100_00,
+	})
+	requireStatusCode(t, err, http.StatusBadRequest)
+
+	listing, err := client.CreateResaleListing(booking.ResaleListingParams{
+		ActorID:    sellerID,
+		TicketIDs:  []uuid.UUID{tickets[0].TicketID, tickets[1].TicketID},
+		PriceCents: 110_00,
+	})
+	require.NoError(t, err)
+	require.Equal(t, booking.ResaleActive, listing.Status)
+	require.EqualValues(t, 100_00, listing.FaceValueCents)
+	require.Len(t, listing.TicketIDs, 2)
+
+	// Listed tickets can't be listed again or transferred
+	_, err = client.CreateResaleListing(booking.ResaleListingParams{
+		ActorID:    sellerID,
+		TicketIDs:  []uuid.UUID{tickets[1].TicketID},
+		PriceCents: 100_00,
+	})
+	requireStatusCode(t, err, http.StatusConflict)
+
+	_, err = client.CreateTransfer(booking.TransferParams{
+		ActorID:   sellerID,
+		TicketIDs: []uuid.UUID{tickets[0].TicketID},
+		ToActorID: &buyerID,
+	})
+	requireStatusCode(t, err, http.StatusConflict)
+
+	require.Len(t, client.GetResaleListings(t, eventID).Listings, 1)
+
+	_, err = client.ReserveResaleListing(listing.ID, booking.ResaleReserveParams{
+		IdempotencyKey: uuid.New(),
+		ActorID:        sellerID,
+	})
+	requireStatusCode(t, err, http.StatusBadRequest)
+
+	reserveParams := booking.ResaleReserveParams{
+		IdempotencyKey: uuid.New(),
+		ActorID:        buyerID,
+	}
+	reservation, err := client.ReserveResaleListing(listing.ID, reserveParams)
+	require.NoError(t, err)
+
+	// Retry with the same idempotency key returns the same reservation
+	retried, err := client.ReserveResaleListing(listing.ID, reserveParams)
+	require.NoError(t, err)
+	require.Equal(t, reservation.ReservationID, retried.ReservationID)
+
+	// Held listing disappears from marketplace and can't be taken by another buyer or withdrawn
+	require.Empty(t, client.GetResaleListings(t, eventID).Listings)
+	_, err = client.ReserveResaleListing(listing.ID, booking.ResaleReserveParams{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+	})
+	requireStatusCode(t, err, http.StatusConflict)
+
+	err = client.CancelResaleListing(listing.ID, booking.ResaleCancelParams{ActorID: sellerID})
+	requireStatusCode(t, err, http.StatusConflict)
+
+	payment, err := client.PayReservation(reservation.ReservationID, booking.PaymentParams{
+		ReservationID: reservation.ReservationID,
+		CardNumber:    booking.KnownFakeCard,
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 220_00, payment.AmountCents)
+
+	sold := client.GetResaleListing(t, listing.ID)
+	require.Equal(t, booking.ResaleSold, sold.Status)
+	require.EqualValues(t, 22_00, *sold.FeeCents)
+	require.EqualValues(t, 198_00, *sold.PayoutCents)
+
+	// Buyer got new codes, seller's codes are revoked
+	bought, err := client.GetReservationTickets(reservation.ReservationID)
+	require.NoError(t, err)
+	require.Len(t, bought.Tickets, 2)
+
+	result := client.CheckIn(t, booking.CheckInParams{Code: tickets[0].Code, GateID: "gate-1"})
+	require.Equal(t, booking.CheckInRevoked, result.Status)
+
+	remaining, err := client.GetReservationTickets(sellerReservationID)
+	require.NoError(t, err)
+	require.Len(t, remaining.Tickets, 1)
+
+	// Seller can't refund reservation with resold tickets
+	_, err = client.RefundReservation(sellerReservationID)
+	requireStatusCode(t, err, http.StatusConflict)
+
+	// Buyer can't refund resale purchase, as seller is already paid out
+	_, err = client.RefundReservation(reservation.ReservationID)
+	requireStatusCode(t, err, http.StatusConflict)
+
+	bought, err = client.GetReservationTickets(reservation.ReservationID)
+	require.NoError(t, err)
+	require.Len(t, bought.Tickets, 2)
+	require.Equal(t, booking.ResaleSold, client.GetResaleListing(t, listing.ID).Status)
+
+	// Price cap of resold ticket is based on the original face value
+	_, err = client.CreateResaleListing(booking.ResaleListingParams{
+		ActorID:    buyerID,
+		TicketIDs:  []uuid.UUID{bought.Tickets[0].TicketID},
+		PriceCents: 120_00,
+	})
+	requireStatusCode(t, err, http.StatusBadRequest)
+}
+
+func TestResaleHoldRelease(t *testing.T) {
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("ResaleHoldTest-%v", time.Now().UnixNano()),
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   20_00,
+				TicketsCount: 5,
+			},
+		},
+	})
+
+	eventID := createRsp.EventID
+	sellerID := uuid.New()
+	sellerReservationID, tickets := buyTicketsAs(t, sellerID, eventID, createRsp.Tiers["GA"], 1)
+
+	listing, err := client.CreateResaleListing(booking.ResaleListingParams{
+		ActorID:    sellerID,
+		TicketIDs:  []uuid.UUID{tickets[0].TicketID},
+		PriceCents: 15_00,
+	})
+	require.NoError(t, err)
+
+	reservation, err := client.ReserveResaleListing(listing.ID, booking.ResaleReserveParams{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+	})
+	require.NoError(t, err)
+
+	// Cancelled hold returns listing to marketplace
+	require.NoError(t, client.CancelReservation(reservation.ReservationID))
+	require.Len(t, client.GetResaleListings(t, eventID).Listings, 1)
+
+	// Expired hold returns listing to marketplace as well
+	reservation, err = client.ReserveResaleListing(listing.ID, booking.ResaleReserveParams{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+	})
+	require.NoError(t, err)
+
+	_, err = db.Exec(
+		t.Context(), `UPDATE reservations SET expires_at = now() - INTERVAL '1 minute' WHERE id = $1`,
+		reservation.ReservationID,
+	)
+	require.NoError(t, err)
+	_, err = db.Exec(
+		t.Context(), `UPDATE resale_listings SET hold_expires_at = now() - INTERVAL '1 minute' WHERE id = $1`,
+		listing.ID,
+	)
+	require.NoError(t, err)
+	require.Len(t, client.GetResaleListings(t, eventID).Listings, 1)
+
+	_, err = client.PayReservation(reservation.ReservationID, booking.PaymentParams{
+		ReservationID: reservation.ReservationID,
+		CardNumber:    booking.KnownFakeCard,
+	})
+	requireStatusCode(t, err, http.StatusBadRequest)
+
+	// Refund cancels seller's listings
+	_, err = client.RefundReservation(sellerReservationID)
+	require.NoError(t, err)
+	require.Empty(t, client.GetResaleListings(t, eventID).Listings)
+	require.Equal(t, booking.ResaleCancelled, client.GetResaleListing(t, listing.ID).Status)
+}
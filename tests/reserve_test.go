@@ -119,3 +119,97 @@ func TestTicketsReserveAndPay(t *testing.T) {
 	require.Len(t, reservations.Reservations, 1)
 	require.True(t, reservations.Reservations[0].IsPaid, "reservation should be paid")
 }
+
+func TestTicketsReservePriceLock(t *testing.T) {
+	eventName := fmt.Sprintf("PriceLockTest-%v", time.Now().UnixNano())
+	tiers := map[string]booking.CreateTierParams{
+		"VIP": {
+			PriceCents:   100_00,
+			TicketsCount: 10,
+		},
+		"GA": {
+			PriceCents:   10_00,
+			TicketsCount: 100,
+		},
+	}
+
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: eventName,
+		Tiers:     tiers,
+	})
+
+	userID := uuid.New()
+	tierIDs := createRsp.Tiers
+	rsp, err := client.ReserveTickets(createRsp.EventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        userID,
+		TicketsCount: map[uuid.UUID]uint{
+			tierIDs["VIP"]: 2,
+			tierIDs["GA"]:  3,
+		},
+	})
+	require.NoError(t, err)
+
+	// Reservation should contain line items with prices at the moment of reservation.
+	expectedTotal := uint(2*tiers["VIP"].PriceCents + 3*tiers["GA"].PriceCents)
+	reservations := client.GetReservations(t, userID)
+	require.Len(t, reservations.Reservations, 1)
+	require.Equal(t, expectedTotal, reservations.Reservations[0].TotalCents)
+	require.ElementsMatch(t, []*booking.ReservationItem{
+		{
+			TierID:         tierIDs["VIP"],
+			TierName:       "VIP",
+			Quantity:       2,
+			UnitPriceCents: uint(tiers["VIP"].PriceCents),
+		},
+		{
+			TierID:         tierIDs["GA"],
+			TierName:       "GA",
+			Quantity:       3,
+			UnitPriceCents: uint(tiers["GA"].PriceCents),
+		},
+	}, reservations.Reservations[0].Items)
+
+	// Price change after reservation should not affect charged amount.
+	_, err = db.Exec(t.Context(), `UPDATE ticket_tiers SET price_cents = price_cents * 2 WHERE event_id = $1`, createRsp.EventID)
+	require.NoError(t, err)
+
+	payResult, err := client.PayReservation(rsp.ReservationID, booking.PaymentParams{
+		ReservationID: rsp.ReservationID,
+		CardNumber:    booking.KnownFakeCard,
+	})
+	require.NoError(t, err)
+	require.Equal(t, expectedTotal, payResult.AmountCents)
+}
+
+func TestTicketsReserveIdempotency(t *testing.T) {
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("IdempotencyTest-%v", time.Now().UnixNano()),
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   10_00,
+				TicketsCount: 10,
+			},
+		},
+	})
+
+	req := server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		TicketsCount: map[uuid.UUID]uint{
+			createRsp.Tiers["GA"]: 4,
+		},
+	}
+
+	first, err := client.ReserveTickets(createRsp.EventID, req)
+	require.NoError(t, err)
+
+	// Retry with the same key should return the same reservation and not hold extra tickets.
+	second, err := client.ReserveTickets(createRsp.EventID, req)
+	require.NoError(t, err)
+	require.Equal(t, first.ReservationID, second.ReservationID)
+
+	tiersRsp := client.GetTicketTiers(t, createRsp.EventID)
+	require.Len(t, tiersRsp.Tiers, 1)
+	require.Equal(t, 6, tiersRsp.Tiers[0].AvailableCount)
+}
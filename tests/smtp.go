@@ -0,0 +1,211 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+)
+
+// receivedMail is an email captured by smtpSink.
+type receivedMail struct {
+	To          []string
+	Subject     string
+	Body        string
+	Attachments []string
+}
+
+// smtpSink is a minimal SMTP server which stores all received messages in memory.
+//
+// It doesn't advertise STARTTLS and AUTH extensions, so client sends mail in plain text.
+type smtpSink struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	messages []receivedMail
+
+	// rejects is number of remaining rejected messages per recipient.
+	rejects map[string]int
+}
+
+func startSMTPSink(ctx context.Context) (*smtpSink, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start SMTP sink: %w", err)
+	}
+
+	sink := &smtpSink{listener: l, rejects: map[string]int{}}
+	go func() {
+		<-ctx.Done()
+		_ = l.Close()
+	}()
+
+	go sink.serve()
+	return sink, nil
+}
+
+func (s *smtpSink) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Reject makes sink temporarily reject next n messages to a recipient.
+func (s *smtpSink) Reject(to string, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rejects[to] = n
+}
+
+// Find returns messages sent to a recipient.
+func (s *smtpSink) Find(to string) []receivedMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []receivedMail
+	for _, m := range s.messages {
+		for _, rcpt := range m.To {
+			if rcpt == to {
+				result = append(result, m)
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+func (s *smtpSink) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.handleConn(conn)
+	}
+}
+
+func (s *smtpSink) handleConn(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		_, _ = fmt.Fprintf(conn, "%s\r\n", line)
+	}
+
+	var rcpts []string
+	reply("220 localhost ESMTP sink")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			rcpts = nil
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			addr := strings.Trim(strings.TrimSpace(line[len("RCPT TO:"):]), "<>")
+			if s.reject(addr) {
+				reply("450 Mailbox unavailable")
+				continue
+			}
+
+			rcpts = append(rcpts, addr)
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			data, err := readData(r)
+			if err != nil {
+				return
+			}
+
+			s.store(rcpts, data)
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func (s *smtpSink) reject(rcpt string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rejects[rcpt] == 0 {
+		return false
+	}
+
+	s.rejects[rcpt]--
+	return true
+}
+
+func (s *smtpSink) store(rcpts []string, data string) {
+	m := receivedMail{To: rcpts}
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err == nil {
+		dec := new(mime.WordDecoder)
+		m.Subject, _ = dec.DecodeHeader(msg.Header.Get("Subject"))
+		m.Body, m.Attachments = readParts(msg)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, m)
+}
+
+// readParts returns raw message body and names of attached files.
+func readParts(msg *mail.Message) (string, []string) {
+	var body strings.Builder
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		_, _ = bufio.NewReader(msg.Body).WriteTo(&body)
+		return body.String(), nil
+	}
+
+	var attachments []string
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err != nil {
+			break
+		}
+
+		if name := part.FileName(); name != "" {
+			attachments = append(attachments, name)
+			continue
+		}
+
+		_, _ = bufio.NewReader(part).WriteTo(&body)
+	}
+
+	return body.String(), attachments
+}
+
+func readData(r *bufio.Reader) (string, error) {
+	var sb strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+
+		if line == ".\r\n" || line == ".\n" {
+			return sb.String(), nil
+		}
+
+		// Undo dot-stuffing
+		sb.WriteString(strings.TrimPrefix(line, "."))
+	}
+}
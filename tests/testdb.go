@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/config"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/schema"
+)
+
+// templateLockID is advisory lock key which serializes template database migration and cloning
+// between concurrently running test packages.
+const templateLockID = 0x7e57_db
+
+// scratchDB is a throwaway database cloned from a migrated template database.
+//
+// Configured database is only used to create and drop scratch databases, its data is never touched.
+type scratchDB struct {
+	admin *pgxpool.Pool
+	name  string
+
+	// Config is config of scratch database.
+	Config config.DBConfig
+}
+
+// newScratchDB migrates template database and creates a new database from it.
+//
+// Template database is named after configured database with "_test_template" suffix
+// and is migrated only when migrations change, so each scratch database is created by a cheap copy.
+func newScratchDB(ctx context.Context, cfg config.DBConfig) (_ *scratchDB, err error) {
+	baseURL, err := url.Parse(cfg.URL)
+	if err != nil || (baseURL.Scheme != "postgres" && baseURL.Scheme != "postgresql") {
+		return nil, errors.New("database config should be a postgres:// URL")
+	}
+
+	baseName := strings.TrimPrefix(baseURL.Path, "/")
+	if baseName == "" {
+		return nil, errors.New("database URL should contain database name")
+	}
+
+	admin, err := cfg.NewPgxPool(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			admin.Close()
+		}
+	}()
+
+	conn, err := admin.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	defer conn.Release()
+
+	// Advisory lock is bound to a session, so the same connection is used to lock and unlock.
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, templateLockID); err != nil {
+		return nil, fmt.Errorf("failed to lock template database: %w", err)
+	}
+
+	defer func() {
+		_, _ = conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, templateLockID)
+	}()
+
+	templateName := baseName + "_test_template"
+	if err := migrateTemplate(ctx, conn.Conn(), withDatabase(baseURL, templateName), templateName); err != nil {
+		return nil, err
+	}
+
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	name := fmt.Sprintf("%s_test_%s", baseName, hex.EncodeToString(suffix))
+	_, err = conn.Exec(ctx, fmt.Sprintf(
+		`CREATE DATABASE %s TEMPLATE %s`, pgx.Identifier{name}.Sanitize(), pgx.Identifier{templateName}.Sanitize(),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database %q: %w", name, err)
+	}
+
+	return &scratchDB{
+		admin: admin,
+		name:  name,
+		Config: config.DBConfig{
+			URL: withDatabase(baseURL, name),
+		},
+	}, nil
+}
+
+// migrateTemplate creates template database if it doesn't exist and applies pending migrations.
+func migrateTemplate(ctx context.Context, conn *pgx.Conn, dbURL, name string) error {
+	var exists bool
+	err := conn.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM pg_database WHERE datname = $1)`, name).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check template database: %w", err)
+	}
+
+	if !exists {
+		if _, err := conn.Exec(ctx, `CREATE DATABASE `+pgx.Identifier{name}.Sanitize()); err != nil {
+			return fmt.Errorf("failed to create template database: %w", err)
+		}
+	}
+
+	// Template can't be copied while anyone is connected to it, so pool is closed before cloning.
+	pool, err := config.DBConfig{URL: dbURL}.NewPgxPool(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer pool.Close()
+	migrator, err := schema.NewMigrator(pool)
+	if err != nil {
+		return err
+	}
+
+	defer migrator.Close()
+	if _, err := migrator.Migrate(ctx); err != nil {
+		return fmt.Errorf("failed to migrate template database: %w", err)
+	}
+
+	return nil
+}
+
+// Close drops scratch database.
+func (s *scratchDB) Close() error {
+	defer s.admin.Close()
+
+	// Connections left by server are terminated by FORCE option.
+	_, err := s.admin.Exec(
+		context.Background(), `DROP DATABASE IF EXISTS `+pgx.Identifier{s.name}.Sanitize()+` WITH (FORCE)`,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to drop database %q: %w", s.name, err)
+	}
+
+	return nil
+}
+
+func withDatabase(u *url.URL, name string) string {
+	dbURL := *u
+	dbURL.Path = "/" + name
+	return dbURL.String()
+}
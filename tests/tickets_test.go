@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/server"
+)
+
+func TestTicketIssuance(t *testing.T) {
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("TicketIssuanceTest-%v", time.Now().UnixNano()),
+		Tiers: map[string]booking.CreateTierParams{
+			"VIP": {
+				PriceCents:   100_00,
+				TicketsCount: 10,
+			},
+			"GA": {
+				PriceCents:   10_00,
+				TicketsCount: 10,
+			},
+		},
+	})
+
+	tierIDs := createRsp.Tiers
+	rsp, err := client.ReserveTickets(createRsp.EventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		TicketsCount: map[uuid.UUID]uint{
+			tierIDs["VIP"]: 1,
+			tierIDs["GA"]:  2,
+		},
+	})
+	require.NoError(t, err)
+
+	// Codes are issued only after payment
+	_, err = client.GetReservationTickets(rsp.ReservationID)
+	requireStatusCode(t, err, http.StatusConflict)
+
+	_, err = client.PayReservation(rsp.ReservationID, booking.PaymentParams{
+		ReservationID: rsp.ReservationID,
+		CardNumber:    booking.KnownFakeCard,
+	})
+	require.NoError(t, err)
+
+	tickets, err := client.GetReservationTickets(rsp.ReservationID)
+	require.NoError(t, err)
+	require.Len(t, tickets.Tickets, 3)
+
+	codes := make(map[string]struct{}, len(tickets.Tickets))
+	tierCounts := map[string]int{}
+	for _, ticket := range tickets.Tickets {
+		require.NotEmpty(t, ticket.Code)
+		require.True(t, strings.HasPrefix(ticket.QR.PNG, "data:image/png;base64,"))
+		require.True(t, strings.HasPrefix(ticket.QR.SVG, "<svg "))
+		codes[ticket.Code] = struct{}{}
+		tierCounts[ticket.TierName]++
+	}
+
+	require.Len(t, codes, 3, "codes should be unique")
+	require.Equal(t, map[string]int{"VIP": 1, "GA": 2}, tierCounts)
+
+	// Refunded tickets are revoked
+	_, err = client.RefundReservation(rsp.ReservationID)
+	require.NoError(t, err)
+
+	_, err = client.GetReservationTickets(rsp.ReservationID)
+	requireStatusCode(t, err, http.StatusConflict)
+
+	_, err = client.GetReservationTickets(uuid.New())
+	requireStatusCode(t, err, http.StatusNotFound)
+}
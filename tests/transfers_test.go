@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+)
+
+func TestTicketTransfer(t *testing.T) {
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("TransferTest-%v", time.Now().UnixNano()),
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   20_00,
+				TicketsCount: 10,
+			},
+		},
+	})
+
+	eventID := createRsp.EventID
+	ownerID, recipientID := uuid.New(), uuid.New()
+	reservationID, tickets := buyTicketsAs(t, ownerID, eventID, createRsp.Tiers["GA"], 3)
+
+	_, err := client.CreateTransfer(booking.TransferParams{
+		ActorID:   uuid.New(),
+		TicketIDs: []uuid.UUID{tickets[0].TicketID},
+		ToActorID: &recipientID,
+	})
+	requireStatusCode(t, err, http.StatusBadRequest)
+
+	transfer, err := client.CreateTransfer(booking.TransferParams{
+		ActorID:   ownerID,
+		TicketIDs: []uuid.UUID{tickets[0].TicketID, tickets[1].TicketID},
+		ToActorID: &recipientID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, booking.TransferPending, transfer.Status)
+	require.ElementsMatch(t, []uuid.UUID{tickets[0].TicketID, tickets[1].TicketID}, transfer.TicketIDs)
+
+	// Ticket can't be in two pending transfers
+	_, err = client.CreateTransfer(booking.TransferParams{
+		ActorID:   ownerID,
+		TicketIDs: []uuid.UUID{tickets[1].TicketID},
+		ToEmail:   "friend@example.com",
+	})
+	requireStatusCode(t, err, http.StatusConflict)
+
+	// Only addressed recipient can accept transfer
+	_, err = client.AcceptTransfer(transfer.ID, booking.TransferAcceptParams{ActorID: uuid.New()})
+	requireStatusCode(t, err, http.StatusBadRequest)
+
+	accepted, err := client.AcceptTransfer(transfer.ID, booking.TransferAcceptParams{ActorID: recipientID})
+	require.NoError(t, err)
+	require.Equal(t, booking.TransferAccepted, accepted.Status)
+	require.Equal(t, recipientID, *accepted.AcceptedBy)
+
+	_, err = client.AcceptTransfer(transfer.ID, booking.TransferAcceptParams{ActorID: recipientID})
+	requireStatusCode(t, err, http.StatusConflict)
+
+	// Recipient got new codes, old codes are revoked
+	received := client.GetUserTickets(t, recipientID).Tickets
+	require.Len(t, received, 2)
+	for _, ticket := range received {
+		require.NotEqual(t, tickets[0].Code, ticket.Code)
+		require.NotEqual(t, tickets[1].Code, ticket.Code)
+	}
+
+	result := client.CheckIn(t, booking.CheckInParams{Code: tickets[0].Code, GateID: "gate-1"})
+	require.Equal(t, booking.CheckInRevoked, result.Status)
+
+	result = client.CheckIn(t, booking.CheckInParams{Code: received[0].Code, GateID: "gate-1"})
+	require.Equal(t, booking.CheckInAccepted, result.Status)
+
+	remaining, err := client.GetReservationTickets(reservationID)
+	require.NoError(t, err)
+	require.Len(t, remaining.Tickets, 1)
+	require.Equal(t, tickets[2].TicketID, remaining.Tickets[0].TicketID)
+
+	history := client.GetTicketOwnership(t, received[0].TicketID).Owners
+	require.Len(t, history, 2)
+	require.Equal(t, ownerID, history[0].OwnerID)
+	require.NotNil(t, history[0].ReleasedAt)
+	require.Equal(t, recipientID, history[1].OwnerID)
+	require.Equal(t, transfer.ID, *history[1].TransferID)
+
+	// Checked-in ticket can't be transferred further
+	_, err = client.CreateTransfer(booking.TransferParams{
+		ActorID:   recipientID,
+		TicketIDs: []uuid.UUID{received[0].TicketID},
+		ToActorID: &ownerID,
+	})
+	requireStatusCode(t, err, http.StatusConflict)
+
+	// Reservation with transferred tickets can't be refunded
+	_, err = client.RefundReservation(reservationID)
+	requireStatusCode(t, err, http.StatusConflict)
+}
+
+func TestTicketTransferByEmail(t *testing.T) {
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("TransferEmailTest-%v", time.Now().UnixNano()),
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   20_00,
+				TicketsCount: 10,
+			},
+		},
+	})
+
+	eventID := createRsp.EventID
+	ownerID := uuid.New()
+	_, tickets := buyTicketsAs(t, ownerID, eventID, createRsp.Tiers["GA"], 1)
+
+	email := fmt.Sprintf("friend-%s@example.com", uuid.NewString())
+	transfer, err := client.CreateTransfer(booking.TransferParams{
+		ActorID:   ownerID,
+		TicketIDs: []uuid.UUID{tickets[0].TicketID},
+		ToEmail:   email,
+	})
+	require.NoError(t, err)
+	requireMail(t, email, "You received tickets")
+
+	// Sender can cancel pending transfer
+	require.NoError(t, client.CancelTransfer(transfer.ID, booking.TransferCancelParams{ActorID: ownerID}))
+	_, err = client.AcceptTransfer(transfer.ID, booking.TransferAcceptParams{ActorID: uuid.New()})
+	requireStatusCode(t, err, http.StatusConflict)
+
+	transfer, err = client.CreateTransfer(booking.TransferParams{
+		ActorID:   ownerID,
+		TicketIDs: []uuid.UUID{tickets[0].TicketID},
+		ToEmail:   email,
+	})
+	require.NoError(t, err)
+	claimToken := requireClaimToken(t, email, transfer.ID)
+
+	// Blocked event transfers can't be accepted or created
+	require.NoError(t, client.SetTransfersBlocked(eventID, true))
+	_, err = client.AcceptTransfer(transfer.ID, booking.TransferAcceptParams{ActorID: uuid.New(), ClaimToken: claimToken})
+	requireStatusCode(t, err, http.StatusConflict)
+
+	// Recipient email is not enough, transfer is claimed only by a token sent to it.
+	require.NoError(t, client.SetTransfersBlocked(eventID, false))
+	for _, token := range []string{"", "WRONGTOKEN", email} {
+		_, err = client.AcceptTransfer(transfer.ID, booking.TransferAcceptParams{ActorID: uuid.New(), ClaimToken: token})
+		requireStatusCode(t, err, http.StatusBadRequest)
+	}
+
+	recipientID := uuid.New()
+	_, err = client.AcceptTransfer(transfer.ID, booking.TransferAcceptParams{ActorID: recipientID, ClaimToken: claimToken})
+	require.NoError(t, err)
+	require.Len(t, client.GetUserTickets(t, recipientID).Tickets, 1)
+	require.Empty(t, client.GetUserTickets(t, ownerID).Tickets)
+
+	// Refunded tickets can't be transferred
+	refundedID, refunded := buyTicketsAs(t, ownerID, eventID, createRsp.Tiers["GA"], 1)
+	_, err = client.RefundReservation(refundedID)
+	require.NoError(t, err)
+
+	_, err = client.CreateTransfer(booking.TransferParams{
+		ActorID:   ownerID,
+		TicketIDs: []uuid.UUID{refunded[0].TicketID},
+		ToEmail:   email,
+	})
+	requireStatusCode(t, err, http.StatusConflict)
+}
+
+// requireClaimToken waits for a transfer email and returns claim token from it.
+func requireClaimToken(t *testing.T, to string, transferID uuid.UUID) string {
+	t.Helper()
+
+	var token string
+	require.Eventuallyf(t, func() bool {
+		for _, m := range mailbox.Find(to) {
+			if !strings.Contains(m.Body, transferID.String()) {
+				continue
+			}
+
+			_, after, ok := strings.Cut(m.Body, "Claim token: ")
+			if ok {
+				token = strings.TrimSpace(strings.SplitN(after, "\n", 2)[0])
+				return true
+			}
+		}
+
+		return false
+	}, 5*time.Second, 50*time.Millisecond, "no claim token of transfer %s sent to %s", transferID, to)
+
+	require.NotEmpty(t, token)
+	return token
+}
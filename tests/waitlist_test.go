@@ -0,0 +1,245 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/server"
+)
+
+func TestWaitlist(t *testing.T) {
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("WaitlistTest-%v", time.Now().UnixNano()),
+		Tiers: map[string]booking.CreateTierParams{
+			"VIP": {
+				PriceCents:   100_00,
+				TicketsCount: 3,
+			},
+		},
+	})
+
+	eventID := createRsp.EventID
+	tierID := createRsp.Tiers["VIP"]
+
+	// Sell out the tier
+	buyerID := uuid.New()
+	soldOut, err := client.ReserveTickets(eventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        buyerID,
+		TicketsCount:   map[uuid.UUID]uint{tierID: 3},
+	})
+	require.NoError(t, err)
+
+	// Queue two actors
+	firstID, secondID := uuid.New(), uuid.New()
+	email := fmt.Sprintf("waiting-%s@example.com", uuid.NewString())
+	first, err := client.JoinWaitlist(eventID, tierID, booking.WaitlistJoinParams{
+		ActorID:  firstID,
+		Quantity: 2,
+		Email:    email,
+	})
+	require.NoError(t, err)
+	require.Equal(t, booking.WaitlistWaiting, first.Status)
+
+	second, err := client.JoinWaitlist(eventID, tierID, booking.WaitlistJoinParams{
+		ActorID:  secondID,
+		Quantity: 1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, booking.WaitlistWaiting, second.Status)
+
+	_, err = client.JoinWaitlist(eventID, tierID, booking.WaitlistJoinParams{
+		ActorID:  firstID,
+		Quantity: 1,
+	})
+	requireStatusCode(t, err, http.StatusConflict)
+
+	// Released tickets should be offered to waitlist in FIFO order
+	require.NoError(t, client.CancelReservation(soldOut.ReservationID))
+
+	entries := client.GetWaitlistEntries(t, firstID).Entries
+	require.Len(t, entries, 1)
+	require.Equal(t, booking.WaitlistOffered, entries[0].Status)
+	require.NotNil(t, entries[0].OfferReservationID)
+	offerID := *entries[0].OfferReservationID
+
+	entries = client.GetWaitlistEntries(t, secondID).Entries
+	require.Len(t, entries, 1)
+	require.Equal(t, booking.WaitlistOffered, entries[0].Status)
+
+	// Actor is notified about the offer
+	msg := requireMail(t, email, "are available")
+	require.Contains(t, msg.Body, offerID.String())
+
+	// Offered tickets are exclusively held, so public reservation should fail
+	_, err = client.ReserveTickets(eventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		TicketsCount:   map[uuid.UUID]uint{tierID: 1},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not enough tickets available of tier")
+
+	reservations := client.GetReservations(t, firstID).Reservations
+	require.Len(t, reservations, 1)
+	require.Equal(t, offerID, reservations[0].ID)
+	require.Equal(t, booking.ReservationPending, reservations[0].Status)
+	require.Equal(t, uint(2*100_00), reservations[0].TotalCents)
+
+	// Paying the offer fulfills waitlist entry
+	_, err = client.PayReservation(offerID, booking.PaymentParams{
+		ReservationID: offerID,
+		CardNumber:    booking.KnownFakeCard,
+	})
+	require.NoError(t, err)
+
+	entries = client.GetWaitlistEntries(t, firstID).Entries
+	require.Equal(t, booking.WaitlistFulfilled, entries[0].Status)
+
+	// Cancelled reservation can't be paid or cancelled again
+	err = client.CancelReservation(soldOut.ReservationID)
+	requireStatusCode(t, err, http.StatusConflict)
+}
+
+func TestWaitlistRefund(t *testing.T) {
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("WaitlistRefundTest-%v", time.Now().UnixNano()),
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   10_00,
+				TicketsCount: 2,
+			},
+		},
+	})
+
+	eventID := createRsp.EventID
+	tierID := createRsp.Tiers["GA"]
+	rsp, err := client.ReserveTickets(eventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		TicketsCount:   map[uuid.UUID]uint{tierID: 2},
+	})
+	require.NoError(t, err)
+
+	_, err = client.RefundReservation(rsp.ReservationID)
+	requireStatusCode(t, err, http.StatusConflict)
+
+	_, err = client.PayReservation(rsp.ReservationID, booking.PaymentParams{
+		ReservationID: rsp.ReservationID,
+		CardNumber:    booking.KnownFakeCard,
+	})
+	require.NoError(t, err)
+
+	waiterID := uuid.New()
+	entry, err := client.JoinWaitlist(eventID, tierID, booking.WaitlistJoinParams{
+		ActorID:  waiterID,
+		Quantity: 2,
+	})
+	require.NoError(t, err)
+
+	// Leaving and re-joining the queue is allowed
+	require.NoError(t, client.LeaveWaitlist(entry.ID))
+	_, err = client.JoinWaitlist(eventID, tierID, booking.WaitlistJoinParams{
+		ActorID:  waiterID,
+		Quantity: 2,
+	})
+	require.NoError(t, err)
+
+	refund, err := client.RefundReservation(rsp.ReservationID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2*10_00), refund.Settlement.Amount)
+
+	reservations := client.GetReservations(t, waiterID).Reservations
+	require.Len(t, reservations, 1)
+	require.Equal(t, booking.ReservationPending, reservations[0].Status)
+}
+
+func TestWaitlistUnsatisfiableEntries(t *testing.T) {
+	const holdTTL = 2 * time.Minute
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName:      fmt.Sprintf("WaitlistSkipTest-%v", time.Now().UnixNano()),
+		HoldTTLSeconds: uint(holdTTL.Seconds()),
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   10_00,
+				TicketsCount: 3,
+			},
+		},
+	})
+
+	eventID := createRsp.EventID
+	tierID := createRsp.Tiers["GA"]
+	reserve := func(qty uint) (*booking.ReservationResult, error) {
+		return client.ReserveTickets(eventID, server.ReserveTicketsRequest{
+			IdempotencyKey: uuid.New(),
+			ActorID:        uuid.New(),
+			TicketsCount:   map[uuid.UUID]uint{tierID: qty},
+		})
+	}
+
+	waitlistStatus := func(actorID uuid.UUID) *booking.WaitlistEntry {
+		entries := client.GetWaitlistEntries(t, actorID).Entries
+		require.Len(t, entries, 1)
+		return entries[0]
+	}
+
+	// Tier with free tickets can't be joined
+	_, err := client.JoinWaitlist(eventID, tierID, booking.WaitlistJoinParams{ActorID: uuid.New(), Quantity: 1})
+	requireStatusCode(t, err, http.StatusConflict)
+
+	paid, err := reserve(2)
+	require.NoError(t, err)
+	held, err := reserve(1)
+	require.NoError(t, err)
+
+	_, err = client.JoinWaitlist(eventID, tierID, booking.WaitlistJoinParams{ActorID: uuid.New(), Quantity: 4})
+	requireStatusCode(t, err, http.StatusBadRequest)
+
+	bigID, smallID := uuid.New(), uuid.New()
+	_, err = client.JoinWaitlist(eventID, tierID, booking.WaitlistJoinParams{ActorID: bigID, Quantity: 3})
+	require.NoError(t, err)
+	_, err = client.JoinWaitlist(eventID, tierID, booking.WaitlistJoinParams{ActorID: smallID, Quantity: 1})
+	require.NoError(t, err)
+
+	// Queue head waits for held tickets, so public and next actors wait as well
+	require.NoError(t, client.CancelReservation(held.ReservationID))
+	require.Equal(t, booking.WaitlistWaiting, waitlistStatus(smallID).Status)
+	_, err = reserve(1)
+	require.Error(t, err)
+
+	// Once tickets are sold, head can't be satisfied anymore and is skipped
+	_, err = payReservation(client, paid.ReservationID)
+	require.NoError(t, err)
+	require.Equal(t, booking.WaitlistWaiting, waitlistStatus(bigID).Status)
+
+	small := waitlistStatus(smallID)
+	require.Equal(t, booking.WaitlistOffered, small.Status)
+
+	offers := client.GetReservations(t, smallID).Reservations
+	require.Len(t, offers, 1)
+	require.WithinDuration(t, time.Now().Add(holdTTL), offers[0].ExpiresAt, 10*time.Second, "offer should use event hold TTL")
+
+	// Skipped actor keeps its place and gets tickets after refund
+	_, err = client.RefundReservation(paid.ReservationID)
+	require.NoError(t, err)
+	_, err = reserve(1)
+	require.Error(t, err)
+
+	require.NoError(t, client.CancelReservation(*small.OfferReservationID))
+	require.Equal(t, booking.WaitlistOffered, waitlistStatus(bigID).Status)
+}
+
+func requireStatusCode(t *testing.T, err error, code int) {
+	t.Helper()
+	require.Error(t, err)
+
+	var rspErr *ResponseError
+	require.ErrorAs(t, err, &rspErr)
+	require.Equal(t, code, rspErr.Code)
+}
@@ -0,0 +1,176 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/thoughtly-ticket-booking/internal/booking"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/server"
+	"github.com/x1unix/thoughtly-ticket-booking/internal/webhook"
+)
+
+func TestAdminAuth(t *testing.T) {
+	organizerID := uuid.New()
+	params := webhook.SubscriptionParams{URL: "http://example.com/hook", OrganizerID: &organizerID}
+	_, err := client.WithAdminToken("").CreateWebhook(params)
+	requireStatusCode(t, err, http.StatusUnauthorized)
+
+	_, err = client.WithAdminToken("wrong-token").CreateWebhook(params)
+	requireStatusCode(t, err, http.StatusUnauthorized)
+
+	err = client.WithAdminToken("").CancelEvent(uuid.New())
+	requireStatusCode(t, err, http.StatusUnauthorized)
+
+	err = client.CancelEvent(uuid.New())
+	requireStatusCode(t, err, http.StatusNotFound)
+}
+
+func TestWebhooks(t *testing.T) {
+	const secret = "partner-webhook-secret"
+	partner := newPartnerServer(secret)
+	t.Cleanup(partner.Close)
+
+	organizerID := uuid.New()
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName:   fmt.Sprintf("WebhooksTest-%v", time.Now().UnixNano()),
+		OrganizerID: &organizerID,
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   15_00,
+				TicketsCount: 5,
+			},
+		},
+	})
+
+	_, err := client.CreateWebhook(webhook.SubscriptionParams{
+		URL:         partner.URL,
+		OrganizerID: &organizerID,
+		EventID:     &createRsp.EventID,
+	})
+	requireStatusCode(t, err, http.StatusBadRequest)
+
+	_, err = client.CreateWebhook(webhook.SubscriptionParams{
+		URL:     partner.URL,
+		EventID: &createRsp.EventID,
+		EventTypes: []booking.DomainEventType{
+			"unknown.event",
+		},
+	})
+	requireStatusCode(t, err, http.StatusBadRequest)
+
+	sub, err := client.CreateWebhook(webhook.SubscriptionParams{
+		URL:         partner.URL,
+		Secret:      secret,
+		OrganizerID: &organizerID,
+		EventTypes: []booking.DomainEventType{
+			booking.ReservationCreatedEvent,
+			booking.PaymentSucceededEvent,
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, secret, sub.Secret)
+
+	tierID := createRsp.Tiers["GA"]
+	rsp, err := client.ReserveTickets(createRsp.EventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		TicketsCount:   map[uuid.UUID]uint{tierID: 2},
+		Email:          "buyer@example.com",
+	})
+	require.NoError(t, err)
+
+	_, err = client.PayReservation(rsp.ReservationID, booking.PaymentParams{
+		ReservationID: rsp.ReservationID,
+		CardNumber:    booking.KnownFakeCard,
+	})
+	require.NoError(t, err)
+
+	// Refund is not in subscription event types
+	_, err = client.RefundReservation(rsp.ReservationID)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(partner.Events()) == 2
+	}, 10*time.Second, 50*time.Millisecond)
+
+	events := partner.Events()
+	types := []booking.DomainEventType{events[0].Type, events[1].Type}
+	require.ElementsMatch(t, []booking.DomainEventType{
+		booking.ReservationCreatedEvent, booking.PaymentSucceededEvent,
+	}, types)
+	for _, e := range events {
+		require.Equal(t, rsp.ReservationID, e.ReservationID)
+		require.Empty(t, e.Email, "customer email should not be shared with partners")
+	}
+
+	deliveries := client.GetWebhookDeliveries(t, sub.ID).Deliveries
+	require.Len(t, deliveries, 2)
+	for _, d := range deliveries {
+		require.Equal(t, webhook.DeliveryDelivered, d.Status)
+		require.Equal(t, 1, d.Attempts)
+	}
+}
+
+func TestWebhooksRetry(t *testing.T) {
+	const secret = "partner-webhook-secret"
+	partner := newPartnerServer(secret)
+	partner.failing.Store(true)
+	t.Cleanup(partner.Close)
+
+	createRsp := client.CreateEvent(t, booking.EventCreateParams{
+		EventName: fmt.Sprintf("WebhooksRetryTest-%v", time.Now().UnixNano()),
+		Tiers: map[string]booking.CreateTierParams{
+			"GA": {
+				PriceCents:   15_00,
+				TicketsCount: 5,
+			},
+		},
+	})
+
+	sub, err := client.CreateWebhook(webhook.SubscriptionParams{
+		URL:     partner.URL,
+		Secret:  secret,
+		EventID: &createRsp.EventID,
+	})
+	require.NoError(t, err)
+
+	_, err = client.ReserveTickets(createRsp.EventID, server.ReserveTicketsRequest{
+		IdempotencyKey: uuid.New(),
+		ActorID:        uuid.New(),
+		TicketsCount:   map[uuid.UUID]uint{createRsp.Tiers["GA"]: 1},
+	})
+	require.NoError(t, err)
+
+	// Delivery is dead-lettered after all attempts fail
+	var delivery *webhook.Delivery
+	require.Eventually(t, func() bool {
+		deliveries := client.GetWebhookDeliveries(t, sub.ID).Deliveries
+		if len(deliveries) != 1 || deliveries[0].Status != webhook.DeliveryDead {
+			return false
+		}
+
+		delivery = deliveries[0]
+		return true
+	}, 15*time.Second, 100*time.Millisecond)
+
+	require.Equal(t, 3, delivery.Attempts)
+	require.Equal(t, 3, partner.Attempts())
+	require.NotNil(t, delivery.LastStatusCode)
+	require.Equal(t, http.StatusInternalServerError, *delivery.LastStatusCode)
+
+	// Replayed delivery is sent again
+	partner.failing.Store(false)
+	replayed := client.ReplayWebhookDelivery(t, delivery.ID)
+	require.Equal(t, webhook.DeliveryPending, replayed.Status)
+	require.Zero(t, replayed.Attempts)
+
+	require.Eventually(t, func() bool {
+		return len(partner.Events()) == 1
+	}, 10*time.Second, 50*time.Millisecond)
+	require.Equal(t, booking.ReservationCreatedEvent, partner.Events()[0].Type)
+}